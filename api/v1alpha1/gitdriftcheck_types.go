@@ -0,0 +1,98 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GitDriftCheckSpec declares a pair of git repositories to monitor for drifts independently of
+// any Pattern, so other tooling can request drift monitoring and the configuration survives
+// operator restarts
+type GitDriftCheckSpec struct {
+	// Git repo containing the upstream content. Must use https/http
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	OriginRepo string `json:"originRepo"`
+
+	// Branch, tag or commit in the origin repository. Does not support short-sha's. Default: HEAD
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	OriginRevision string `json:"originRevision,omitempty"`
+
+	// Git repo containing the deployed fork of the content. Must use https/http
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	TargetRepo string `json:"targetRepo"`
+
+	// Branch, tag or commit in the target repository. Does not support short-sha's. Default: HEAD
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	TargetRevision string `json:"targetRevision,omitempty"`
+
+	// Interval in seconds to poll for drifts between the repositories. Default: 180 seconds
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	PollIntervalSeconds int `json:"pollIntervalSeconds,omitempty"`
+
+	// Align drift checks to wall-clock boundaries that are a multiple of the polling interval.
+	// Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	AlignChecks bool `json:"alignChecks,omitempty"`
+}
+
+// GitDriftCheckStatus carries the outcome of the most recent drift check of the pair
+type GitDriftCheckStatus struct {
+	// Drift state observed by the last completed check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Drifted bool `json:"drifted,omitempty"`
+	// Number of checks performed for the pair since it was added
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	CheckCount int64 `json:"checkCount,omitempty"`
+	// Current run of consecutive failed checks
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+	// Completion time of the last check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastCheck metav1.Time `json:"lastCheck,omitempty"`
+	// Error the last check failed with, empty when it completed
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastError string `json:"lastError,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Drifted",type=boolean,JSONPath=`.status.drifted`
+//+kubebuilder:printcolumn:name="Last Check",type=date,JSONPath=`.status.lastCheck`
+
+// GitDriftCheck requests drift monitoring between two git repositories, reconciled by its own
+// controller and scheduled through the same watcher as the pairs declared on Patterns
+type GitDriftCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GitDriftCheckSpec   `json:"spec,omitempty"`
+	Status GitDriftCheckStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GitDriftCheckList contains a list of GitDriftCheck
+type GitDriftCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GitDriftCheck `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GitDriftCheck{}, &GitDriftCheckList{})
+}
@@ -15,8 +15,8 @@ limitations under the License.
 */
 
 // Package v1alpha1 contains API Schema definitions for the gitops v1alpha1 API group
-//+kubebuilder:object:generate=true
-//+groupName=gitops.hybrid-cloud-patterns.io
+// +kubebuilder:object:generate=true
+// +groupName=gitops.hybrid-cloud-patterns.io
 package v1alpha1
 
 import (
@@ -0,0 +1,89 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AsMetaCondition renders the condition in the standard metav1.Condition shape, so tooling built
+// around the upstream condition contract (kstatus, Argo CD health checks) can interpret it. A
+// condition written before reasons were recorded falls back to its type as the reason
+func (c PatternCondition) AsMetaCondition() metav1.Condition {
+	reason := c.Reason
+	if reason == "" {
+		reason = string(c.Type)
+	}
+	return metav1.Condition{
+		Type:               string(c.Type),
+		Status:             metav1.ConditionStatus(c.Status),
+		Reason:             reason,
+		Message:            c.Message,
+		LastTransitionTime: c.LastTransitionTime,
+		ObservedGeneration: c.ObservedGeneration,
+	}
+}
+
+// AsMetaConditions renders the whole conditions list in the standard shape
+func AsMetaConditions(conditions []PatternCondition) []metav1.Condition {
+	result := make([]metav1.Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		result = append(result, condition.AsMetaCondition())
+	}
+	return result
+}
+
+// SetStatusCondition updates the conditions list from a condition expressed in the standard
+// shape, delegating the transition bookkeeping to meta.SetStatusCondition and mapping the result
+// back, so the stored conditions keep the legacy fields existing consumers read while carrying
+// the Reason and ObservedGeneration standard tooling expects. updateTime refreshes the
+// LastUpdateTime of the condition being set; the other conditions keep theirs
+func SetStatusCondition(conditions *[]PatternCondition, condition metav1.Condition, updateTime metav1.Time) {
+	if condition.LastTransitionTime.IsZero() {
+		condition.LastTransitionTime = updateTime
+	}
+	metaConditions := AsMetaConditions(*conditions)
+	meta.SetStatusCondition(&metaConditions, condition)
+	previous := map[PatternConditionType]PatternCondition{}
+	for _, item := range *conditions {
+		previous[item.Type] = item
+	}
+	result := make([]PatternCondition, 0, len(metaConditions))
+	for _, item := range metaConditions {
+		mapped := PatternCondition{
+			Type:               PatternConditionType(item.Type),
+			Status:             v1.ConditionStatus(item.Status),
+			LastUpdateTime:     updateTime,
+			LastTransitionTime: item.LastTransitionTime,
+			Reason:             item.Reason,
+			ObservedGeneration: item.ObservedGeneration,
+			Message:            item.Message,
+		}
+		if item.Type != condition.Type {
+			if kept, found := previous[mapped.Type]; found {
+				mapped.LastUpdateTime = kept.LastUpdateTime
+				// the reason was only backfilled from the type for the comparison above; an
+				// untouched condition keeps whatever it had recorded
+				mapped.Reason = kept.Reason
+			}
+		}
+		result = append(result, mapped)
+	}
+	*conditions = result
+}
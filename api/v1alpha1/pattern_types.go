@@ -38,7 +38,32 @@ type PatternParameter struct {
 	Name string `json:"name"`
 
 	//+operator-sdk:csv:customresourcedefinitions:type=spec
-	Value string `json:"value"`
+	Value string `json:"value,omitempty"`
+
+	// Optional. Resolve the value from a key of a ConfigMap or Secret in the pattern namespace
+	// instead of the inline value; the application is re-rendered when the source object changes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ValueFrom *ParameterSource `json:"valueFrom,omitempty"`
+}
+
+// PatternValueFile is one layered Helm values file
+type PatternValueFile struct {
+	// Path of the values file within the pattern repository
+	Path string `json:"path"`
+	// Optional files may be absent from the repository; a required file that is missing raises
+	// the ValuesFileMissing condition
+	Optional bool `json:"optional,omitempty"`
+}
+
+// ParameterSource references the key of a ConfigMap or Secret a parameter value is read from.
+// Exactly one of the object names must be set
+type ParameterSource struct {
+	// Name of the ConfigMap holding the value
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// Name of the Secret holding the value
+	SecretName string `json:"secretName,omitempty"`
+	// Key to read within the referenced object
+	Key string `json:"key"`
 }
 
 // Future fields...
@@ -69,10 +94,132 @@ type PatternSpec struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec
 	ExtraValueFiles []string `json:"extraValueFiles,omitempty"`
 
+	// Optional. Ordered list of additional Helm values files layered on top of extraValueFiles;
+	// later entries override earlier ones. Files not marked optional must exist in the target
+	// repository, otherwise the ValuesFileMissing condition is raised and the deployment held
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ValueFiles []PatternValueFile `json:"valueFiles,omitempty"`
+
+	// Optional. ArgoCD sync wave the clustergroup application is deployed in, recorded as the
+	// argocd.argoproj.io/sync-wave annotation on the generated application. Lower waves are
+	// applied first, so foundational patterns can land before dependent ones
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SyncWave int `json:"syncWave,omitempty"`
+
+	// Optional. Names of patterns in the same namespace that must be Ready before the
+	// application of this pattern is created
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Optional. Maintenance windows constraining when the application may sync; outside an open
+	// window automated sync is disabled and drift remediation is suppressed
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SyncPolicy *SyncPolicyConfig `json:"syncPolicy,omitempty"`
+
+	// Optional. Propagate the pattern to the ACM managed clusters matching the selector by
+	// wrapping it in a ManifestWork on each spoke cluster namespace of the hub
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	MultiCluster *MultiClusterConfig `json:"multiCluster,omitempty"`
+
+	// Optional. Fan the pattern out through an ArgoCD ApplicationSet built from the declared
+	// generators instead of creating a single clustergroup application, e.g. to install the
+	// pattern on every managed cluster matching a label selector
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ApplicationSet *ApplicationSetConfig `json:"applicationSet,omitempty"`
+
+	// Optional. Bootstrap of the secret backend the pattern relies on: Vault wired into the
+	// cluster through the external secrets operator and seeded from a local values Secret. The
+	// readiness of the store is reported through the SecretStoreReady condition
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SecretStore *SecretStoreConfig `json:"secretStore,omitempty"`
+
+	// Optional. Namespaces, service accounts and role bindings the pattern needs up front. They
+	// are created with the pattern label and pruned when the pattern is deleted, so the
+	// clustergroup chart does not have to bootstrap its own RBAC with cluster-admin
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Provision *ProvisionConfig `json:"provision,omitempty"`
+
 	// Look for external changes every N minutes
 	// ReconcileMinutes int    `json:"reconcileMinutes,omitempty"`
 }
 
+// SyncPolicyConfig constrains when the pattern is allowed to sync
+type SyncPolicyConfig struct {
+	// Windows the sync is constrained by, evaluated against the local time of the operator.
+	// An active deny window always blocks; when allow windows are declared, one of them must be
+	// active for the sync to proceed
+	Windows []SyncWindow `json:"windows,omitempty"`
+}
+
+// SyncWindow is one recurring maintenance window
+type SyncWindow struct {
+	// Kind of the window, either allow or deny
+	Kind SyncWindowKind `json:"kind"`
+	// Schedule of the window start in the standard five-field cron syntax, e.g. "0 22 * * *"
+	Schedule string `json:"schedule"`
+	// Duration the window stays open after each scheduled start, e.g. "1h" or "30m"
+	Duration string `json:"duration"`
+}
+
+type SyncWindowKind string
+
+const (
+	// SyncWindowAllow windows permit syncing while they are active
+	SyncWindowAllow SyncWindowKind = "allow"
+	// SyncWindowDeny windows block syncing while they are active
+	SyncWindowDeny SyncWindowKind = "deny"
+)
+
+// MultiClusterConfig selects the ManagedClusters a pattern is propagated to. The hub keeps
+// deploying the pattern locally; each matching spoke additionally receives a copy through a
+// ManifestWork and reconciles it with its own operator
+type MultiClusterConfig struct {
+	// Labels the ManagedClusters must carry; an empty map selects every managed cluster
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// ApplicationSetConfig switches the operator from creating a single clustergroup application to
+// an ApplicationSet fanning out over the declared generators
+type ApplicationSetConfig struct {
+	// Generators producing the template parameters, applied in order
+	Generators []ApplicationSetGenerator `json:"generators"`
+}
+
+// ApplicationSetGenerator declares one generator of the ApplicationSet. Exactly one of the
+// fields must be set
+type ApplicationSetGenerator struct {
+	// Cluster generator producing one application per cluster registered with Argo CD
+	Clusters *ClusterGenerator `json:"clusters,omitempty"`
+	// List generator producing one application per element
+	List *ListGenerator `json:"list,omitempty"`
+	// Git directory generator producing one application per matching directory of the pattern
+	// repository
+	GitDirectories *GitDirectoryGenerator `json:"gitDirectories,omitempty"`
+}
+
+// ClusterGenerator selects the Argo CD managed clusters the pattern fans out to
+type ClusterGenerator struct {
+	// Labels the cluster secrets must carry; an empty map selects every cluster
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// ListGenerator produces one application per element; by convention each element carries at
+// least the "name" and "server" parameters consumed by the template
+type ListGenerator struct {
+	// Elements the applications are templated from
+	Elements []map[string]string `json:"elements"`
+}
+
+// GitDirectoryGenerator produces one application per directory matching one of the paths
+type GitDirectoryGenerator struct {
+	// Repository holding the directories. Defaults to the target repository of the pattern
+	RepoURL string `json:"repoURL,omitempty"`
+	// Revision to scan. Defaults to the target revision of the pattern
+	Revision string `json:"revision,omitempty"`
+	// Directory globs relative to the repository root, e.g. "applications/*"
+	Paths []string `json:"paths"`
+}
+
 type GitConfig struct {
 	// Optional. FQDN of the git server if automatic parsing from TargetRepo is broken
 	Hostname string `json:"hostname,omitempty"`
@@ -94,6 +241,36 @@ type GitConfig struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec
 	PollInterval int `json:"pollInterval,omitempty"`
 
+	// Align drift checks to wall-clock boundaries that are a multiple of the polling interval
+	// instead of counting the interval from the moment the pattern was reconciled. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	AlignChecks bool `json:"alignChecks,omitempty"`
+
+	// Pause drift monitoring without dropping the rest of the git configuration, e.g. during a
+	// maintenance window or an intentional divergence. A GitDriftMonitoringPaused condition is
+	// recorded while monitoring is paused. The gitops.hybrid-cloud-patterns.io/drift-paused
+	// annotation on the Pattern has the same effect. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	DriftPaused bool `json:"driftPaused,omitempty"`
+
+	// Optional. Minimum number of commits the target must lag behind the origin before the drift
+	// condition flips to GitOutOfSync. Smaller divergences are still reported in the drift status
+	// but do not change the condition. Only applied when the commit distance could be counted
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	DriftMinCommitsBehind int `json:"driftMinCommitsBehind,omitempty"`
+
+	// Optional. Number of consecutive checks that must find the repositories drifted before the
+	// condition flips to GitOutOfSync, debouncing short-lived divergences such as batched merges
+	// propagating. The drift status still reflects every individual check
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	DriftSustainedChecks int `json:"driftSustainedChecks,omitempty"`
+
+	// Optional. Fetch both references into an in-memory repository during drift checks so the
+	// ahead/behind commit counts and the merge base the histories diverged from can be computed
+	// and published in the drift status, at the price of a fetch per check. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ComputeMergeBase bool `json:"computeMergeBase,omitempty"`
+
 	// Git repo containing the pattern to deploy. Must use https/http
 	//+operator-sdk:csv:customresourcedefinitions:type=spec
 	TargetRepo string `json:"targetRepo"`
@@ -101,8 +278,291 @@ type GitConfig struct {
 	// Branch, tag, or commit to deploy.  Does not support short-sha's. Default: HEAD
 	//+operator-sdk:csv:customresourcedefinitions:type=spec
 	TargetRevision string `json:"targetRevision,omitempty"`
+
+	// Optional. Seconds a staged rollout of a changed targetRevision waits for a degraded
+	// application to recover before rolling the revision back. Default: 600
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	UpgradeTimeoutSeconds int `json:"upgradeTimeoutSeconds,omitempty"`
+
+	// Optional. Seconds applications may stay Degraded or OutOfSync on a newly applied revision
+	// before they are reverted to the last known-good revision. Default: 600
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	RollbackDeadlineSeconds int `json:"rollbackDeadlineSeconds,omitempty"`
+
+	// Optional. Name of a Secret in the namespace of the pattern holding the credentials used to
+	// list the origin and target remotes during drift checks. For SSH remotes the Secret carries
+	// the private key under "sshPrivateKey", the remote user under "sshUser" (default "git") and
+	// optionally the host keys under "known_hosts". For HTTPS remotes the Secret carries the
+	// token under "token" and optionally the user under "username"
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// Automatically fast-forward the target repository to the origin commit when drift is
+	// detected, instead of only reporting it. Requires syncCredentialsSecret. A target holding
+	// commits the origin does not is never overwritten; such a drift raises a GitAutoSyncFailed
+	// condition and is left for the user to reconcile. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	AutoSync bool `json:"autoSync,omitempty"`
+
+	// Optional. Name of a Secret in the namespace of the pattern holding credentials with write
+	// access to the target repository, used to push fast-forward updates when autoSync is enabled.
+	// Uses the same keys as credentialsSecret
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SyncCredentialsSecret string `json:"syncCredentialsSecret,omitempty"`
+
+	// Optional. Name of a Secret in the namespace of the pattern holding a values-secret.yaml
+	// document. Its entries are parsed and pushed into the backend selected in the secret store
+	// configuration, and pushed again whenever the document changes, replacing the manual
+	// make-based loading workflow
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SecretsRef string `json:"secretsRef,omitempty"`
+
+	// Optional. API dialect of the forge hosting the repositories, one of GitHub, GitLab, Gitea
+	// or Bitbucket. Enables operations richer than the git protocol offers: divergence counted
+	// through the compare API, a sync pull request when a drifted target cannot be
+	// fast-forwarded, and a status check posted on the drifted target commit. Auto-detected for
+	// the public github.com, gitlab.com and bitbucket.org hosts; self-hosted instances must
+	// declare it
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Forge ForgeType `json:"forge,omitempty"`
+
+	// Optional. Name of a Secret in the namespace of the pattern holding additional CA
+	// certificates, PEM-encoded under "ca.crt", trusted when connecting to the repositories of
+	// this pattern, for git servers using a private CA. Complements the operator-level bundle
+	// read from the file named by the DRIFT_CA_BUNDLE environment variable
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	CABundleSecret string `json:"caBundleSecret,omitempty"`
+
+	// Optional. Skip TLS certificate validation when connecting to the repositories of this
+	// pattern, for lab environments without a usable CA. A Warning event is emitted while the
+	// setting is in effect. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// Optional. Proxy applied to outbound connections made for the repositories of this pattern,
+	// overriding the operator-level proxy taken from the conventional HTTP_PROXY, HTTPS_PROXY and
+	// NO_PROXY environment variables
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Proxy ProxyConfig `json:"proxy,omitempty"`
+
+	// Optional. Behavior when a monitored repository redirects to a different host.
+	// "Follow" raises an informational condition and continues the check, "Refuse" fails the
+	// check until the URL is updated. An empty value disables redirect detection
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	RedirectPolicy RedirectPolicyType `json:"redirectPolicy,omitempty"`
+
+	// Optional. Branch selection policy when a repository does not advertise a symbolic HEAD and
+	// no revision is pinned. Default: Error
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	HeadlessPolicy HeadlessPolicyType `json:"headlessPolicy,omitempty"`
+
+	// Optional. Ordered list of branch names to try when HeadlessPolicy is "PreferenceList"
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	HeadBranchPreferences []string `json:"headBranchPreferences,omitempty"`
+
+	// Optional. Seconds after a new origin commit during which a hash mismatch with the target is
+	// reported as pending synchronization instead of drift, for mirrors that lag by a known
+	// bounded delay. A value of 0 disables the epsilon
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SyncEpsilonSeconds int `json:"syncEpsilonSeconds,omitempty"`
+
+	// Optional. Fixed branch-name mapping for fork workflows where a target branch tracks an
+	// origin branch with a different name. When set, each side resolves by its mapped name and
+	// the mapping takes precedence over OriginRevision and TargetRevision
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	BranchMapping BranchMapping `json:"branchMapping,omitempty"`
+
+	// Optional. Semantics used to compare the resolved origin and target references during drift
+	// checks. Currently only "hash" is supported. Default: hash
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ComparisonMode string `json:"comparisonMode,omitempty"`
+
+	// Optional. Glob filter selecting the target branches to evaluate for drift, e.g. "env/*".
+	// When set, every matching target branch is compared against the origin branch of the same
+	// name and the pair is reported as drifted if any of them differs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	BranchFilter string `json:"branchFilter,omitempty"`
+
+	// Optional. Paths within the repositories that the pattern actually consumes. When set, a hash
+	// mismatch between the resolved references only counts as drift if the trees under at least one
+	// of the paths differ, so changes confined to unrelated files never flip the condition
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	PathFilters []string `json:"pathFilters,omitempty"`
+
+	// Optional. List only the origin remote on each drift check and compare it against the cached
+	// target listing, roughly halving remote traffic when the target rarely moves. The target is
+	// listed again whenever the origin listing changes or the cached listing exceeds its staleness
+	// bound. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	CacheTargetListing bool `json:"cacheTargetListing,omitempty"`
+
+	// Optional. Maximum age in seconds of the cached target listing before the target is listed
+	// again even though the origin has not moved, so a target-side change is never missed
+	// indefinitely. Only meaningful with CacheTargetListing. Default: 600
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	TargetStalenessSeconds int `json:"targetStalenessSeconds,omitempty"`
+
+	// Optional. Include the previous drift state and how long it lasted in the message of the
+	// condition raised on a transition, e.g. "was GitInSync for 3h12m, now GitOutOfSync".
+	// Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	DetailedTransitionMessages bool `json:"detailedTransitionMessages,omitempty"`
+
+	// Optional. Verify on each check that origin and target are genuinely related repositories by
+	// comparing the root commits of their histories, raising a GitRepositoryIdentityMismatch
+	// condition when they share none. Catches targets re-created from scratch. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	VerifyRepositoryIdentity bool `json:"verifyRepositoryIdentity,omitempty"`
+
+	// Optional. Origin branch whose history the commit resolved for the target must belong to.
+	// When set, each check verifies the target commit is reachable from the branch and raises an
+	// UntrustedTargetCommit condition otherwise, guarding against commits injected directly on the
+	// target repository
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	TrustedOriginBranch string `json:"trustedOriginBranch,omitempty"`
+
+	// Optional. How the polling interval adapts while checks for the pair keep failing. "Shrink"
+	// probes more often to detect recovery quickly, "Grow" backs off to reduce noise; both snap
+	// back to the configured interval on the first success. An empty value keeps the interval fixed
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ErrorIntervalPolicy ErrorIntervalPolicyType `json:"errorIntervalPolicy,omitempty"`
+
+	// Optional. Upper bound in seconds on the interval reached through the "Grow" error-interval
+	// policy, replacing the default cap of eight times the configured interval
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ErrorBackoffCapSeconds int `json:"errorBackoffCapSeconds,omitempty"`
+
+	// Optional. Maximum random jitter in seconds added to the interval while checks keep failing,
+	// so retries against a struggling host spread out instead of arriving in lockstep
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ErrorBackoffJitterSeconds int `json:"errorBackoffJitterSeconds,omitempty"`
+
+	// Optional. Allowed clock skew in seconds between the operator and the git servers. The skew
+	// is factored into timestamp-based decisions such as the sync epsilon so a modestly skewed
+	// server does not flip them; skew beyond the tolerance is logged as a warning
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ClockSkewToleranceSeconds int `json:"clockSkewToleranceSeconds,omitempty"`
+
+	// Optional. Reference to an in-cluster resource field publishing the revision the pattern
+	// should be running, e.g. the status of a Release object. When set, drift is evaluated between
+	// origin HEAD and the revision read from the field instead of the target repository references
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	RevisionFrom *RevisionFieldReference `json:"revisionFrom,omitempty"`
+
+	// Optional. URL of an external service returning the revision the pattern should be running.
+	// When set, drift is evaluated between origin HEAD and the revision blessed by the service
+	// instead of the target repository references. Authentication uses the token found in the
+	// EXTERNAL_RESOLVER_TOKEN environment variable when present
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ExternalRevisionEndpoint string `json:"externalRevisionEndpoint,omitempty"`
+
+	// Optional. Name of a GitHub environment whose latest deployment records the deployed commit.
+	// When set, drift is evaluated between origin HEAD and the commit recorded as deployed to the
+	// environment instead of the target repository references. Requires the origin repository to be
+	// hosted on GitHub and authentication for private repositories
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	GithubDeploymentEnvironment string `json:"githubDeploymentEnvironment,omitempty"`
+
+	// Optional. Additional origin/target repository pairs monitored for drifts under this pattern,
+	// e.g. a site-values repository next to the main pattern repository. All pairs share the polling
+	// interval of the pattern and aggregate into the overall GitInSync/GitOutOfSync condition;
+	// per-pair results are published under status.pairDriftStatuses
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	AdditionalPairs []GitRepoPair `json:"additionalPairs,omitempty"`
+}
+
+// GitRepoPair declares an additional origin/target repository pair monitored for drifts alongside
+// the primary pair of the top-level git configuration
+type GitRepoPair struct {
+	// Name identifying the pair in status entries and log lines
+	Name string `json:"name"`
+	// Git repo containing the upstream content of this pair
+	OriginRepo string `json:"originRepo"`
+	// Git repo containing the deployed fork of the content of this pair
+	TargetRepo string `json:"targetRepo"`
+	// Branch, tag or commit in the origin repository. Does not support short-sha's. Default: HEAD
+	OriginRevision string `json:"originRevision,omitempty"`
+	// Branch, tag or commit in the target repository. Does not support short-sha's. Default: HEAD
+	TargetRevision string `json:"targetRevision,omitempty"`
+}
+
+// RevisionFieldReference identifies a field on an in-cluster resource holding the revision a
+// pattern should be running
+type RevisionFieldReference struct {
+	// API version of the referenced resource
+	APIVersion string `json:"apiVersion"`
+	// Kind of the referenced resource
+	Kind string `json:"kind"`
+	// Name of the referenced resource
+	Name string `json:"name"`
+	// Namespace of the referenced resource. Defaults to the namespace of the pattern
+	Namespace string `json:"namespace,omitempty"`
+	// Dot-separated path to the field holding the revision, e.g. status.revision
+	FieldPath string `json:"fieldPath"`
 }
 
+// BranchMapping pairs an origin branch with the target branch meant to track it. Both names must
+// be set for the mapping to take effect
+type BranchMapping struct {
+	// Branch in the origin repository
+	Origin string `json:"origin,omitempty"`
+	// Branch in the target repository tracking the origin branch
+	Target string `json:"target,omitempty"`
+}
+
+// ProxyConfig declares the proxy used for outbound HTTP connections, with the semantics of the
+// conventional HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+type ProxyConfig struct {
+	// URL of the proxy used for plain http connections
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// URL of the proxy used for https connections
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// Comma-separated host names and domain suffixes connected to directly, bypassing the proxy.
+	// A single "*" bypasses the proxy for every host
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+type HeadlessPolicyType string
+
+const (
+	// HeadlessError fails the drift check when the repository advertises no symbolic HEAD (default)
+	HeadlessError HeadlessPolicyType = "Error"
+	// HeadlessLexicalFirst selects the lexically-first branch
+	HeadlessLexicalFirst HeadlessPolicyType = "LexicalFirst"
+	// HeadlessPreferenceList selects the first branch present in HeadBranchPreferences
+	HeadlessPreferenceList HeadlessPolicyType = "PreferenceList"
+	// HeadlessConditionError raises a GitConfigurationError condition in addition to failing the check
+	HeadlessConditionError HeadlessPolicyType = "ConditionError"
+)
+
+type ErrorIntervalPolicyType string
+
+const (
+	// ErrorIntervalShrink halves the interval per consecutive failure, down to a quarter of the
+	// configured value, so recovery is detected quickly
+	ErrorIntervalShrink ErrorIntervalPolicyType = "Shrink"
+	// ErrorIntervalGrow doubles the interval per consecutive failure, up to eight times the
+	// configured value, so a persistently failing pair generates less noise
+	ErrorIntervalGrow ErrorIntervalPolicyType = "Grow"
+)
+
+type RedirectPolicyType string
+
+const (
+	RedirectFollow RedirectPolicyType = "Follow"
+	RedirectRefuse RedirectPolicyType = "Refuse"
+)
+
+// ForgeType identifies the API dialect of the forge hosting a repository
+type ForgeType string
+
+const (
+	ForgeGitHub    ForgeType = "GitHub"
+	ForgeGitLab    ForgeType = "GitLab"
+	ForgeGitea     ForgeType = "Gitea"
+	ForgeBitbucket ForgeType = "Bitbucket"
+)
+
 type ApplyChangeType string
 
 const (
@@ -124,6 +584,10 @@ type GitOpsConfig struct {
 	// Require manual confirmation before installing and upgrading operators. Default: False
 	//+operator-sdk:csv:customresourcedefinitions:type=spec
 	ManualApproval bool `json:"manualApproval,omitempty"`
+	// Approve pending install plans on behalf of the user while ManualApproval is set, keeping
+	// the audit trail of a manual approval strategy without blocking upgrades. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	AutoApproveInstallPlans bool `json:"autoApproveInstallPlans,omitempty"`
 
 	// Specific version of openshift-gitops to deploy.  Requires UseCSV=True
 	//+operator-sdk:csv:customresourcedefinitions:type=spec
@@ -133,10 +597,88 @@ type GitOpsConfig struct {
 	UseCSV bool `json:"useCSV,omitempty"`
 }
 
+// SecretStoreConfig describes the secret backend bootstrap of the pattern: where Vault and the
+// external secrets operator are expected, whether the operator deploys Vault itself and which
+// local Secret seeds the store
+type SecretStoreConfig struct {
+	// Deploy Vault through an ArgoCD application when no instance is found in the configured
+	// namespace, instead of only waiting for one to appear. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ManageVault bool `json:"manageVault,omitempty"`
+	// Namespace the Vault instance serving the pattern runs in. Default: vault
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	VaultNamespace string `json:"vaultNamespace,omitempty"`
+	// Namespace the external secrets operator bridging Vault into the cluster runs in.
+	// Default: external-secrets
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ExternalSecretsNamespace string `json:"externalSecretsNamespace,omitempty"`
+	// Optional. Name of a Secret in the pattern namespace holding a values-secret.yaml whose
+	// entries seed the store once it is ready
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ValuesSecretName string `json:"valuesSecretName,omitempty"`
+	// Optional. Backend the entries of the secretsRef document are pushed to: either Vault,
+	// writing through the KV v2 HTTP API with the token taken from the vault-token Secret in the
+	// vault namespace, or Kubernetes, materializing one Secret per group next to the pattern.
+	// Default: Kubernetes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Backend string `json:"backend,omitempty"`
+}
+
+// ProvisionConfig declares the namespaces, service accounts and role bindings the pattern
+// needs up front
+type ProvisionConfig struct {
+	// Namespaces to create
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Service accounts to create
+	ServiceAccounts []ProvisionedServiceAccount `json:"serviceAccounts,omitempty"`
+	// Role bindings granting a cluster role to one of the service accounts inside a namespace
+	RoleBindings []ProvisionedRoleBinding `json:"roleBindings,omitempty"`
+}
+
+// ProvisionedServiceAccount names a service account to create in one of the provisioned
+// namespaces
+type ProvisionedServiceAccount struct {
+	// Name of the service account
+	Name string `json:"name"`
+	// Namespace it lives in
+	Namespace string `json:"namespace"`
+}
+
+// ProvisionedRoleBinding grants a cluster role to a service account inside one namespace
+type ProvisionedRoleBinding struct {
+	// Name of the binding
+	Name string `json:"name"`
+	// Namespace the binding is created in
+	Namespace string `json:"namespace"`
+	// Name of the ClusterRole granted
+	ClusterRole string `json:"clusterRole"`
+	// Service account the role is granted to
+	ServiceAccount ProvisionedServiceAccount `json:"serviceAccount"`
+}
+
+// PatternUpgradeStatus tracks a staged rollout of a changed target revision over the
+// applications of the pattern
+type PatternUpgradeStatus struct {
+	// Revision the applications were running when the rollout started; the revision field is
+	// rolled back to it when an application stays degraded past the upgrade timeout
+	FromRevision string `json:"fromRevision"`
+	// Revision the rollout is moving the applications to
+	ToRevision string `json:"toRevision"`
+	// Time the rollout started; the upgrade timeout counts from here
+	StartTime metav1.Time `json:"startTime"`
+	// Sync wave currently being rolled out
+	Wave int `json:"wave,omitempty"`
+}
+
 // PatternStatus defines the observed state of Pattern
 type PatternStatus struct {
 	// Observed state of the pattern
 
+	// Generation of the pattern spec this status was computed from, so clients can tell whether
+	// the status already reflects the latest spec edit
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Last action related to the pattern
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	LastStep string `json:"lastStep,omitempty"`
@@ -159,17 +701,98 @@ type PatternStatus struct {
 	ClusterID string `json:"clusterID,omitempty"`
 	//+operator-sdk:csv:customresourcedefinitions:type=status
 	ClusterPlatform string `json:"clusterPlatform,omitempty"`
+	// Processor architecture the cluster nodes run on, as reported by the API server
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterArchitecture string `json:"clusterArchitecture,omitempty"`
+	// Whether the cluster was installed in FIPS mode
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	FIPSEnabled bool `json:"fipsEnabled,omitempty"`
 	//+operator-sdk:csv:customresourcedefinitions:type=status
 	ClusterVersion string `json:"clusterVersion,omitempty"`
+	// Milliseconds the last drift check started late relative to its schedule. A growing delay
+	// points at watcher overload rather than slow git hosts
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastDriftCheckSchedulingDelayMs int64 `json:"lastDriftCheckSchedulingDelayMs,omitempty"`
+	// Milliseconds the last drift check spent comparing the repositories
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastDriftCheckDurationMs int64 `json:"lastDriftCheckDurationMs,omitempty"`
+	// Content hash of the last values-secret.yaml document pushed into the secret backend; the
+	// entries are pushed again when the referenced Secret no longer matches it
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LoadedSecretsVersion string `json:"loadedSecretsVersion,omitempty"`
+	// Target revision the applications of the pattern were last fully rolled out to; staged
+	// rollouts move it once every application is healthy on the new revision
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	AppliedRevision string `json:"appliedRevision,omitempty"`
+	// Last revision every application of the pattern reported healthy and synced on; the
+	// applications are reverted to it when a newly applied revision leaves them Degraded or
+	// OutOfSync past the rollback deadline
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastKnownGoodRevision string `json:"lastKnownGoodRevision,omitempty"`
+	// Time the applications started reporting Degraded or OutOfSync on the applied revision;
+	// the rollback deadline counts from here. Cleared when they recover
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	UnhealthySince *metav1.Time `json:"unhealthySince,omitempty"`
+	// Staged rollout currently in progress, if any
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Upgrade *PatternUpgradeStatus `json:"upgrade,omitempty"`
+	// Attribution of the most recent failed drift check; cleared by the next completed check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastCheckFailure *CheckFailure `json:"lastCheckFailure,omitempty"`
+	// Details of the most recent drift check: the commits both sides resolved to and how far the
+	// target diverges from the origin
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	DriftStatus *DriftStatus `json:"driftStatus,omitempty"`
+	// Retry state while drift checks keep failing: the length of the failure run and when the
+	// backed-off next attempt is scheduled. Cleared by the first completed check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	DriftRetryState *DriftRetryState `json:"driftRetryState,omitempty"`
+	// Outcome of the latest check of each additional repository pair declared on the git
+	// configuration; the primary pair reports through driftStatus
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	PairDriftStatuses []PairDriftStatus `json:"pairDriftStatuses,omitempty"`
+	// Sync and health state of the ArgoCD applications deployed for the pattern, refreshed as
+	// Argo CD reports transitions
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Applications []PatternApplicationStatus `json:"applications,omitempty"`
+	// Propagation state of the pattern on each spoke cluster it was fanned out to, read back
+	// from the ManifestWork conditions on the hub
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Spokes []PatternSpokeStatus `json:"spokes,omitempty"`
 	//+operator-sdk:csv:customerresourcedefinitions:type=conditions
 	Conditions []PatternCondition `json:"conditions,omitempty"`
 }
 
+// PatternApplicationStatus mirrors the sync and health state of one ArgoCD application deployed
+// for the pattern
+type PatternApplicationStatus struct {
+	// Name of the application
+	Name string `json:"name"`
+	// Sync state reported by Argo CD, e.g. Synced or OutOfSync
+	SyncStatus string `json:"syncStatus,omitempty"`
+	// Health state reported by Argo CD, e.g. Healthy or Degraded
+	HealthStatus string `json:"healthStatus,omitempty"`
+	// Revision the application last synced to
+	LastSyncedRevision string `json:"lastSyncedRevision,omitempty"`
+}
+
+// PatternSpokeStatus reports the propagation state of the pattern on one managed cluster
+type PatternSpokeStatus struct {
+	// Name of the managed cluster
+	Name string `json:"name"`
+	// Whether the wrapped pattern has been applied on the spoke, e.g. True or False
+	Applied string `json:"applied,omitempty"`
+	// Whether the applied pattern is still present on the spoke, e.g. True or False
+	Available string `json:"available,omitempty"`
+}
+
 // See: https://book.kubebuilder.io/reference/markers/crd.html
 //      https://sdk.operatorframework.io/docs/building-operators/golang/references/markers/
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 //+kubebuilder:resource:shortName=patt
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 //+kubebuilder:printcolumn:name="Step",type=string,JSONPath=`.status.lastStep`,priority=1
 //+kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.lastError`,priority=2
 //+operator-sdk:csv:customresourcedefinitions:resources={{"Pattern","v1alpha1","patterns"}}
@@ -192,6 +815,61 @@ type PatternList struct {
 	Items           []Pattern `json:"items"`
 }
 
+// CheckFailure attributes a failed drift check to the remote that caused it, so triage can tell
+// which side is broken from the resource alone
+type CheckFailure struct {
+	// Remote the failure is attributed to, either "origin" or "target"
+	Side string `json:"side"`
+	// Host the failing remote belongs to, when the pattern declares one
+	Host string `json:"host,omitempty"`
+	// Coarse class of the failure, e.g. "authentication", "repository-not-found" or "network"
+	Class string `json:"class"`
+	// Message of the underlying error
+	Message string `json:"message,omitempty"`
+}
+
+// DriftRetryState describes why drift checks are currently delayed: how many checks in a row
+// have failed and when the backed-off next attempt is scheduled
+type DriftRetryState struct {
+	// Number of consecutive failed checks
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// When the next attempt is scheduled
+	NextCheck metav1.Time `json:"nextCheck,omitempty"`
+}
+
+// DriftStatus details the outcome of the most recent drift check, complementing the boolean
+// GitInSync/GitOutOfSync condition with the resolved commits and the size of the divergence
+type DriftStatus struct {
+	// Commit the origin reference resolved to
+	OriginSHA string `json:"originSHA,omitempty"`
+	// Commit the target reference resolved to
+	TargetSHA string `json:"targetSHA,omitempty"`
+	// Number of commits on the origin that the target does not have
+	CommitsBehind int `json:"commitsBehind,omitempty"`
+	// Number of commits on the target that the origin does not have
+	CommitsAhead int `json:"commitsAhead,omitempty"`
+	// Commit the origin and target histories diverged from, when the pattern asked for the merge
+	// base to be computed
+	MergeBase string `json:"mergeBase,omitempty"`
+	// When the check completed
+	LastCheck metav1.Time `json:"lastCheck,omitempty"`
+	// Error the check failed with, empty when it completed
+	LastError string `json:"lastError,omitempty"`
+}
+
+// PairDriftStatus carries the outcome of the latest check of one additional repository pair, so
+// the aggregated GitOutOfSync condition can be traced back to the pair that drifted
+type PairDriftStatus struct {
+	// Name of the pair as declared on the git configuration
+	Name string `json:"name"`
+	// Whether the repositories of the pair drifted apart at the last completed check
+	Drifted bool `json:"drifted"`
+	// When the check completed
+	LastCheck metav1.Time `json:"lastCheck,omitempty"`
+	// Error the check failed with, empty when it completed
+	LastError string `json:"lastError,omitempty"`
+}
+
 type PatternCondition struct {
 	// Type of deployment condition.
 	Type PatternConditionType `json:"type"`
@@ -201,6 +879,13 @@ type PatternCondition struct {
 	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
 	// Last time the condition transitioned from one status to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Machine-readable, CamelCase reason for the condition state, aligned with the
+	// metav1.Condition contract
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Generation of the pattern the condition was based upon
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// A human readable message indicating details about the transition.
 	Message string `json:"message,omitempty"`
 }
@@ -208,8 +893,67 @@ type PatternCondition struct {
 type PatternConditionType string
 
 const (
+	// Ready aggregates the gitops deployment state and the drift state into a single answer to
+	// "is this pattern working"; the other conditions carry the detail behind a False status
+	Ready        PatternConditionType = "Ready"
 	GitOutOfSync PatternConditionType = "GitOutOfSync"
 	GitInSync    PatternConditionType = "GitInSync"
+	// GitDefaultBranchChanged reports that the default branch of one of the monitored repositories
+	// has been renamed between two drift checks
+	GitDefaultBranchChanged PatternConditionType = "GitDefaultBranchChanged"
+	// GitRepositoryRedirected reports that one of the monitored repository URLs redirects to a
+	// different host, typically after an organization migration
+	GitRepositoryRedirected PatternConditionType = "GitRepositoryRedirected"
+	// GitConfigurationError reports that the git configuration of the pattern cannot be applied
+	// to the monitored repositories as-is and requires user attention
+	GitConfigurationError PatternConditionType = "GitConfigurationError"
+	// GitSyncPending reports that the origin moved recently and the target mirror is expected to
+	// catch up within the configured epsilon, so the difference is not reported as drift yet
+	GitSyncPending PatternConditionType = "GitSyncPending"
+	// GitExternalResolverError reports that the external revision resolver service could not supply
+	// the blessed revision for the pattern
+	GitExternalResolverError PatternConditionType = "GitExternalResolverError"
+	// GitRevisionSourceError reports that the in-cluster resource field referenced as the revision
+	// source is missing or does not hold a usable revision
+	GitRevisionSourceError PatternConditionType = "GitRevisionSourceError"
+	// UntrustedTargetCommit reports that the commit the target repository resolves to is not part
+	// of the history of the trusted origin branch, i.e. it was not published through the origin
+	UntrustedTargetCommit PatternConditionType = "UntrustedTargetCommit"
+	// GitRepositoryIdentityMismatch reports that the origin and target repositories share no root
+	// commit, i.e. they do not descend from the same initial history
+	GitRepositoryIdentityMismatch PatternConditionType = "GitRepositoryIdentityMismatch"
+	// GitCheckFailed reports that the last drift check could not be completed; the failing remote
+	// is attributed on the condition message and the lastCheckFailure status entry
+	GitCheckFailed PatternConditionType = "GitCheckFailed"
+	// GitDriftMonitoringPaused reports that drift monitoring is intentionally paused through the
+	// driftPaused spec field or the drift-paused annotation; no checks run while it is active
+	GitDriftMonitoringPaused PatternConditionType = "GitDriftMonitoringPaused"
+	// GitAutoSyncFailed reports that the automatic fast-forward of a drifted target could not be
+	// performed, either because the update is not a fast-forward or because the push failed
+	GitAutoSyncFailed PatternConditionType = "GitAutoSyncFailed"
+	// ApplicationDegraded reports that one of the ArgoCD applications deployed for the pattern
+	// has a degraded health state; the per-application detail is under status.applications
+	ApplicationDegraded PatternConditionType = "ApplicationDegraded"
+	// ValuesFileMissing reports that a layered values file not marked optional is absent from the
+	// target repository; the missing paths are listed on the condition message
+	ValuesFileMissing PatternConditionType = "ValuesFileMissing"
+	// SyncWindowClosed reports that the pattern is outside its declared sync windows; automated
+	// sync stays disabled and drift remediation is suppressed until a window opens
+	SyncWindowClosed PatternConditionType = "SyncWindowClosed"
+	// SubscriptionUpgradePending reports that the gitops operator subscription is blocked on a
+	// manual InstallPlan approval; the pending plan is named on the condition message
+	SubscriptionUpgradePending PatternConditionType = "SubscriptionUpgradePending"
+	// SecretStoreReady reports whether the secret backend declared by the pattern is able to
+	// serve secrets: Vault is running, the external secrets operator is up and the local values
+	// Secret is in place. The blockers are listed on the condition message while it is False
+	SecretStoreReady PatternConditionType = "SecretStoreReady"
+	// PatternUpgrading reports that a staged rollout of a new target revision over the
+	// applications of the pattern is in progress
+	PatternUpgrading PatternConditionType = "PatternUpgrading"
+	// UpgradeRolledBack reports that a staged rollout was aborted because an application stayed
+	// degraded past the upgrade timeout; the revision field was rolled back to where the rollout
+	// started
+	UpgradeRolledBack PatternConditionType = "UpgradeRolledBack"
 )
 
 func init() {
@@ -0,0 +1,198 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var patternlog = logf.Log.WithName("pattern-resource")
+
+func (r *Pattern) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-gitops-hybrid-cloud-patterns-io-v1alpha1-pattern,mutating=true,failurePolicy=fail,sideEffects=None,groups=gitops.hybrid-cloud-patterns.io,resources=patterns,verbs=create;update,versions=v1alpha1,name=mpattern.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &Pattern{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+// The values mirror what the controller applies in applyDefaults so that a stored
+// Pattern already shows the configuration it will effectively run with.
+func (r *Pattern) Default() {
+	patternlog.Info("default", "name", r.Name)
+
+	// HEAD, not a branch name: the symbolic revision follows whatever default branch the
+	// repository declares, which is what the controller falls back to as well
+	if r.Spec.GitConfig.TargetRevision == "" {
+		r.Spec.GitConfig.TargetRevision = "HEAD"
+	}
+	// interval cannot be less than 180 seconds to avoid drowning the API server in requests
+	// value of -1 effectivelly disables the watch for this pattern.
+	if r.Spec.GitConfig.PollInterval > -1 && r.Spec.GitConfig.PollInterval < 180 {
+		r.Spec.GitConfig.PollInterval = 180
+	}
+	if r.Spec.ClusterGroupName == "" {
+		r.Spec.ClusterGroupName = "default"
+	}
+	if r.Spec.GitOpsConfig == nil {
+		r.Spec.GitOpsConfig = &GitOpsConfig{}
+	}
+	if r.Spec.GitOpsConfig.OperatorChannel == "" {
+		r.Spec.GitOpsConfig.OperatorChannel = "stable"
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-gitops-hybrid-cloud-patterns-io-v1alpha1-pattern,mutating=false,failurePolicy=fail,sideEffects=None,groups=gitops.hybrid-cloud-patterns.io,resources=patterns,verbs=create;update,versions=v1alpha1,name=vpattern.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Pattern{}
+
+// minPollIntervalSeconds rejects intervals so small they would only hammer the git provider; the
+// controller additionally raises anything below its own floor of 180 seconds
+const minPollIntervalSeconds = 60
+
+// scpLikeRepoURL matches the scp-like ssh spelling of a repository URL, e.g. git@host:org/repo
+var scpLikeRepoURL = regexp.MustCompile(`^[^@/]+@[^:/]+:.+$`)
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Pattern) ValidateCreate() error {
+	patternlog.Info("validate create", "name", r.Name)
+	return r.validatePattern()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Pattern) ValidateUpdate(old runtime.Object) error {
+	patternlog.Info("validate update", "name", r.Name)
+	return r.validatePattern()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Pattern) ValidateDelete() error {
+	return nil
+}
+
+// validatePattern rejects specs that would only fail at runtime with cryptic log errors:
+// malformed repository URLs, unusably small polling intervals, revision spellings the drift
+// watcher does not support and mutually exclusive field combinations
+func (r *Pattern) validatePattern() error {
+	var allErrs field.ErrorList
+	gitPath := field.NewPath("spec").Child("gitSpec")
+	gc := r.Spec.GitConfig
+
+	allErrs = append(allErrs, validateRepositoryURL(gitPath.Child("targetRepo"), gc.TargetRepo, true)...)
+	allErrs = append(allErrs, validateRepositoryURL(gitPath.Child("originRepo"), gc.OriginRepo, false)...)
+	allErrs = append(allErrs, validateRevision(gitPath.Child("targetRevision"), gc.TargetRevision)...)
+	allErrs = append(allErrs, validateRevision(gitPath.Child("originRevision"), gc.OriginRevision)...)
+
+	// -1 is the documented way of disabling the watch and passes defaulting untouched, so it
+	// must pass validation too
+	if gc.PollInterval < -1 {
+		allErrs = append(allErrs, field.Invalid(gitPath.Child("pollInterval"), gc.PollInterval,
+			"the polling interval cannot be negative; -1 disables the watch"))
+	} else if gc.PollInterval > 0 && gc.PollInterval < minPollIntervalSeconds {
+		allErrs = append(allErrs, field.Invalid(gitPath.Child("pollInterval"), gc.PollInterval,
+			fmt.Sprintf("the polling interval must be at least %d seconds; leave it empty for the default", minPollIntervalSeconds)))
+	}
+
+	if (gc.BranchMapping.Origin == "") != (gc.BranchMapping.Target == "") {
+		allErrs = append(allErrs, field.Invalid(gitPath.Child("branchMapping"), gc.BranchMapping,
+			"a branch mapping requires both the origin and the target branch name"))
+	}
+	if gc.BranchFilter != "" && (gc.BranchMapping.Origin != "" || gc.BranchMapping.Target != "") {
+		allErrs = append(allErrs, field.Forbidden(gitPath.Child("branchFilter"),
+			"branchFilter and branchMapping are mutually exclusive"))
+	}
+	if gc.AutoSync && gc.SyncCredentialsSecret == "" {
+		allErrs = append(allErrs, field.Required(gitPath.Child("syncCredentialsSecret"),
+			"autoSync requires credentials with write access to the target repository"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "Pattern"}, r.Name, allErrs)
+}
+
+// validateRepositoryURL accepts the http and https spellings of a repository URL, the only ones
+// the controller supports; ssh and scp-like spellings get a pointed message here instead of the
+// runtime error preValidation would otherwise log
+func validateRepositoryURL(path *field.Path, value string, required bool) field.ErrorList {
+	if value == "" {
+		if required {
+			return field.ErrorList{field.Required(path, "a repository URL is required")}
+		}
+		return nil
+	}
+	if scpLikeRepoURL.MatchString(value) {
+		return field.ErrorList{field.Invalid(path, value,
+			"ssh URLs are not supported, use the https spelling of the repository")}
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, value, fmt.Sprintf("not a valid URL: %s", err))}
+	}
+	switch parsed.Scheme {
+	case "http", "https":
+	default:
+		return field.ErrorList{field.Invalid(path, value,
+			"the URL scheme must be http or https")}
+	}
+	if parsed.Host == "" {
+		return field.ErrorList{field.Invalid(path, value, "the URL does not name a host")}
+	}
+	return nil
+}
+
+// validateRevision rejects the revision spellings the drift watcher does not support: short
+// commit hashes and names that are not valid git references
+func validateRevision(path *field.Path, value string) field.ErrorList {
+	if value == "" {
+		return nil
+	}
+	if isHexString(value) && len(value) >= 4 && len(value) < 40 {
+		return field.ErrorList{field.Invalid(path, value,
+			"short commit hashes are not supported, use the full 40-character hash")}
+	}
+	if strings.ContainsAny(value, " ~^:?*[\\") || strings.Contains(value, "..") ||
+		strings.HasSuffix(value, "/") || strings.HasSuffix(value, ".lock") {
+		return field.ErrorList{field.Invalid(path, value, "not a valid git reference name")}
+	}
+	return nil
+}
+
+func isHexString(value string) bool {
+	for _, r := range value {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') && (r < 'A' || r > 'F') {
+			return false
+		}
+	}
+	return len(value) > 0
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestDefault(t *testing.T) {
+	p := &Pattern{Spec: PatternSpec{GitConfig: GitConfig{TargetRepo: "https://github.com/org/repo"}}}
+	p.Default()
+	if p.Spec.GitConfig.TargetRevision != "HEAD" {
+		t.Errorf("expected the target revision to default to HEAD like the controller, got %q", p.Spec.GitConfig.TargetRevision)
+	}
+	if p.Spec.GitConfig.PollInterval != 180 {
+		t.Errorf("expected the poll interval to default to 180, got %d", p.Spec.GitConfig.PollInterval)
+	}
+	if p.Spec.ClusterGroupName != "default" {
+		t.Errorf("expected the cluster group to default to %q, got %q", "default", p.Spec.ClusterGroupName)
+	}
+	if p.Spec.GitOpsConfig == nil || p.Spec.GitOpsConfig.OperatorChannel != "stable" {
+		t.Errorf("expected the gitops channel to default to stable, got %+v", p.Spec.GitOpsConfig)
+	}
+
+	disabled := &Pattern{Spec: PatternSpec{GitConfig: GitConfig{PollInterval: -1, TargetRevision: "v1.0"}}}
+	disabled.Default()
+	if disabled.Spec.GitConfig.PollInterval != -1 {
+		t.Errorf("expected the -1 disable value to pass defaulting untouched, got %d", disabled.Spec.GitConfig.PollInterval)
+	}
+	if disabled.Spec.GitConfig.TargetRevision != "v1.0" {
+		t.Errorf("expected a declared revision to be kept, got %q", disabled.Spec.GitConfig.TargetRevision)
+	}
+}
+
+func TestValidateRepositoryURL(t *testing.T) {
+	path := field.NewPath("spec").Child("gitSpec").Child("targetRepo")
+	cases := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"https URL", "https://github.com/org/repo", true},
+		{"http URL", "http://git.corp/org/repo", true},
+		{"scp-like ssh URL", "git@github.com:org/repo.git", false},
+		{"ssh scheme", "ssh://git@github.com/org/repo", false},
+		{"git scheme", "git://github.com/org/repo", false},
+		{"missing host", "https:///org/repo", false},
+		{"not a URL", "http://%%", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs := validateRepositoryURL(path, c.value, true)
+			if (len(errs) == 0) != c.valid {
+				t.Errorf("validateRepositoryURL(%q) = %v, expected valid=%t", c.value, errs, c.valid)
+			}
+		})
+	}
+	if errs := validateRepositoryURL(path, "", true); len(errs) == 0 {
+		t.Error("expected an empty required URL to be rejected")
+	}
+	if errs := validateRepositoryURL(path, "", false); len(errs) != 0 {
+		t.Errorf("expected an empty optional URL to be accepted, got %v", errs)
+	}
+}
+
+func TestValidateRevision(t *testing.T) {
+	path := field.NewPath("spec").Child("gitSpec").Child("targetRevision")
+	cases := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"empty", "", true},
+		{"branch name", "main", true},
+		{"full hash", "0123456789012345678901234567890123456789", true},
+		{"short hash", "0123456", false},
+		{"space", "my branch", false},
+		{"double dot", "a..b", false},
+		{"trailing slash", "feature/", false},
+		{"lock suffix", "main.lock", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs := validateRevision(path, c.value)
+			if (len(errs) == 0) != c.valid {
+				t.Errorf("validateRevision(%q) = %v, expected valid=%t", c.value, errs, c.valid)
+			}
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	base := func() *Pattern {
+		return &Pattern{Spec: PatternSpec{GitConfig: GitConfig{TargetRepo: "https://github.com/org/repo"}}}
+	}
+	cases := []struct {
+		name   string
+		mutate func(*Pattern)
+		valid  bool
+	}{
+		{"minimal valid spec", func(p *Pattern) {}, true},
+		{"disabled watch via -1", func(p *Pattern) { p.Spec.GitConfig.PollInterval = -1 }, true},
+		{"negative interval", func(p *Pattern) { p.Spec.GitConfig.PollInterval = -2 }, false},
+		{"interval below the floor", func(p *Pattern) { p.Spec.GitConfig.PollInterval = 30 }, false},
+		{"interval at the floor", func(p *Pattern) { p.Spec.GitConfig.PollInterval = 60 }, true},
+		{"half a branch mapping", func(p *Pattern) { p.Spec.GitConfig.BranchMapping.Origin = "main" }, false},
+		{"branch filter with mapping", func(p *Pattern) {
+			p.Spec.GitConfig.BranchFilter = "release-*"
+			p.Spec.GitConfig.BranchMapping = BranchMapping{Origin: "main", Target: "main"}
+		}, false},
+		{"autoSync without write credentials", func(p *Pattern) { p.Spec.GitConfig.AutoSync = true }, false},
+		{"autoSync with write credentials", func(p *Pattern) {
+			p.Spec.GitConfig.AutoSync = true
+			p.Spec.GitConfig.SyncCredentialsSecret = "push-creds"
+		}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := base()
+			c.mutate(p)
+			err := p.validatePattern()
+			if (err == nil) != c.valid {
+				t.Errorf("validatePattern() = %v, expected valid=%t", err, c.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PatternCatalogSpec points at an index of validated patterns available for installation
+type PatternCatalogSpec struct {
+	// URL of the index: a git repository (ending in .git) holding an index.json at its root, or
+	// an http/https endpoint serving the index document directly
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	IndexURL string `json:"indexURL"`
+
+	// Branch, tag or commit to read the index from when the URL is a git repository. Default: HEAD
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	IndexRevision string `json:"indexRevision,omitempty"`
+
+	// Interval in seconds between catalog syncs. Default: 1800 seconds
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SyncIntervalSeconds int `json:"syncIntervalSeconds,omitempty"`
+}
+
+// PatternCatalogEntry describes one validated pattern published by the index
+type PatternCatalogEntry struct {
+	// Name of the pattern
+	Name string `json:"name"`
+	// Short description of what the pattern deploys
+	Description string `json:"description,omitempty"`
+	// Git repository the pattern installs from
+	Repo string `json:"repo,omitempty"`
+	// Versions of the pattern the index declares as available
+	Versions []string `json:"versions,omitempty"`
+}
+
+// PatternCatalogStatus exposes the patterns the index currently publishes so UIs and the CLI can
+// list what can be installed without fetching the index themselves
+type PatternCatalogStatus struct {
+	// Patterns published by the index at the last completed sync
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Entries []PatternCatalogEntry `json:"entries,omitempty"`
+	// Completion time of the last successful sync
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastSync metav1.Time `json:"lastSync,omitempty"`
+	// Error the last sync failed with, empty when it completed
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastError string `json:"lastError,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Index",type=string,JSONPath=`.spec.indexURL`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSync`
+
+// PatternCatalog subscribes to an index of validated patterns, synced periodically by its own
+// controller
+type PatternCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PatternCatalogSpec   `json:"spec,omitempty"`
+	Status PatternCatalogStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PatternCatalogList contains a list of PatternCatalog
+type PatternCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PatternCatalog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PatternCatalog{}, &PatternCatalogList{})
+}
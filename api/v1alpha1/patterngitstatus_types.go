@@ -0,0 +1,82 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PatternGitStatusSpec identifies the Pattern the companion resource carries drift details for
+type PatternGitStatusSpec struct {
+	// Name of the Pattern in the same namespace this resource mirrors
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	PatternName string `json:"patternName,omitempty"`
+}
+
+// PatternGitStatusStatus carries the rich per-pair drift state maintained by the drift watcher,
+// keeping the status of the Pattern itself lean
+type PatternGitStatusStatus struct {
+	// Drift state observed by the last completed check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Drifted bool `json:"drifted,omitempty"`
+	// Number of checks performed for the pair since it was added
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	CheckCount int64 `json:"checkCount,omitempty"`
+	// Current run of consecutive failed checks
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+	// Completion time of the last check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastCheck metav1.Time `json:"lastCheck,omitempty"`
+	// Primary reference the target resolved to during the last check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	TargetPrimaryRef string `json:"targetPrimaryRef,omitempty"`
+	// Every target reference pointing at the same commit as the primary reference
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	TargetMatchedRefs []string `json:"targetMatchedRefs,omitempty"`
+	// Branches found drifted by the last check when a branch filter is configured
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	DriftedBranches []string `json:"driftedBranches,omitempty"`
+	// Attribution of the most recent failed check; cleared by the next completed check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastCheckFailure *CheckFailure `json:"lastCheckFailure,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// PatternGitStatus is the companion resource carrying the drift details of a Pattern
+type PatternGitStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PatternGitStatusSpec   `json:"spec,omitempty"`
+	Status PatternGitStatusStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PatternGitStatusList contains a list of PatternGitStatus
+type PatternGitStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PatternGitStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PatternGitStatus{}, &PatternGitStatusList{})
+}
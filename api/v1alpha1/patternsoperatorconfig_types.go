@@ -0,0 +1,88 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PatternsOperatorConfigName is the name of the cluster-scoped singleton carrying the
+// operator-wide settings; other instances are ignored
+const PatternsOperatorConfigName = "cluster"
+
+// PatternsOperatorConfigSpec holds operator-wide settings. They are read live on every
+// reconcile, so changing them needs neither an operator restart nor an edit of the Subscription
+// environment. Settings a pattern declares itself always win over these defaults
+type PatternsOperatorConfigSpec struct {
+	// Poll interval in seconds applied to patterns that do not set one. Default: 180
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	DefaultPollIntervalSeconds int `json:"defaultPollIntervalSeconds,omitempty"`
+
+	// Channel the gitops operator is subscribed from when a pattern does not pick one.
+	// Default: stable
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	DefaultGitOpsChannel string `json:"defaultGitOpsChannel,omitempty"`
+
+	// Optional. Proxy applied to the git configuration of patterns that do not declare their own
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+
+	// Disable the anonymized telemetry reporting operator-wide. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	TelemetryOptOut bool `json:"telemetryOptOut,omitempty"`
+
+	// Number of git drift checks allowed to run in parallel, applied to the running worker
+	// pool. Default: 4
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	MaxConcurrentDriftChecks int `json:"maxConcurrentDriftChecks,omitempty"`
+}
+
+// PatternsOperatorConfigStatus records when the settings were last taken into account
+type PatternsOperatorConfigStatus struct {
+	// Generation of the spec the operator last applied
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Time the settings were last applied
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastApplied metav1.Time `json:"lastApplied,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+
+// PatternsOperatorConfig is the cluster-scoped singleton holding the operator-wide settings
+type PatternsOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PatternsOperatorConfigSpec   `json:"spec,omitempty"`
+	Status PatternsOperatorConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PatternsOperatorConfigList contains a list of PatternsOperatorConfig
+type PatternsOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PatternsOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PatternsOperatorConfig{}, &PatternsOperatorConfigList{})
+}
@@ -25,9 +25,167 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetConfig) DeepCopyInto(out *ApplicationSetConfig) {
+	*out = *in
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]ApplicationSetGenerator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetConfig.
+func (in *ApplicationSetConfig) DeepCopy() *ApplicationSetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSetGenerator) DeepCopyInto(out *ApplicationSetGenerator) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = new(ClusterGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.List != nil {
+		in, out := &in.List, &out.List
+		*out = new(ListGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitDirectories != nil {
+		in, out := &in.GitDirectories, &out.GitDirectories
+		*out = new(GitDirectoryGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSetGenerator.
+func (in *ApplicationSetGenerator) DeepCopy() *ApplicationSetGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSetGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BranchMapping) DeepCopyInto(out *BranchMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchMapping.
+func (in *BranchMapping) DeepCopy() *BranchMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(BranchMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckFailure) DeepCopyInto(out *CheckFailure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckFailure.
+func (in *CheckFailure) DeepCopy() *CheckFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterGenerator) DeepCopyInto(out *ClusterGenerator) {
+	*out = *in
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterGenerator.
+func (in *ClusterGenerator) DeepCopy() *ClusterGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftRetryState) DeepCopyInto(out *DriftRetryState) {
+	*out = *in
+	in.NextCheck.DeepCopyInto(&out.NextCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftRetryState.
+func (in *DriftRetryState) DeepCopy() *DriftRetryState {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftRetryState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftStatus) DeepCopyInto(out *DriftStatus) {
+	*out = *in
+	in.LastCheck.DeepCopyInto(&out.LastCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftStatus.
+func (in *DriftStatus) DeepCopy() *DriftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitConfig) DeepCopyInto(out *GitConfig) {
 	*out = *in
+	out.Proxy = in.Proxy
+	out.BranchMapping = in.BranchMapping
+	if in.HeadBranchPreferences != nil {
+		in, out := &in.HeadBranchPreferences, &out.HeadBranchPreferences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PathFilters != nil {
+		in, out := &in.PathFilters, &out.PathFilters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RevisionFrom != nil {
+		in, out := &in.RevisionFrom, &out.RevisionFrom
+		*out = new(RevisionFieldReference)
+		**out = **in
+	}
+	if in.AdditionalPairs != nil {
+		in, out := &in.AdditionalPairs, &out.AdditionalPairs
+		*out = make([]GitRepoPair, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitConfig.
@@ -40,6 +198,174 @@ func (in *GitConfig) DeepCopy() *GitConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitDirectoryGenerator) DeepCopyInto(out *GitDirectoryGenerator) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitDirectoryGenerator.
+func (in *GitDirectoryGenerator) DeepCopy() *GitDirectoryGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(GitDirectoryGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitDriftCheck) DeepCopyInto(out *GitDriftCheck) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitDriftCheck.
+func (in *GitDriftCheck) DeepCopy() *GitDriftCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(GitDriftCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitDriftCheck) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitDriftCheckList) DeepCopyInto(out *GitDriftCheckList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GitDriftCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitDriftCheckList.
+func (in *GitDriftCheckList) DeepCopy() *GitDriftCheckList {
+	if in == nil {
+		return nil
+	}
+	out := new(GitDriftCheckList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitDriftCheckList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitDriftCheckSpec) DeepCopyInto(out *GitDriftCheckSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitDriftCheckSpec.
+func (in *GitDriftCheckSpec) DeepCopy() *GitDriftCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitDriftCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitDriftCheckStatus) DeepCopyInto(out *GitDriftCheckStatus) {
+	*out = *in
+	in.LastCheck.DeepCopyInto(&out.LastCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitDriftCheckStatus.
+func (in *GitDriftCheckStatus) DeepCopy() *GitDriftCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitDriftCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepoPair) DeepCopyInto(out *GitRepoPair) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepoPair.
+func (in *GitRepoPair) DeepCopy() *GitRepoPair {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepoPair)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListGenerator) DeepCopyInto(out *ListGenerator) {
+	*out = *in
+	if in.Elements != nil {
+		in, out := &in.Elements, &out.Elements
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListGenerator.
+func (in *ListGenerator) DeepCopy() *ListGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(ListGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionFieldReference) DeepCopyInto(out *RevisionFieldReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionFieldReference.
+func (in *RevisionFieldReference) DeepCopy() *RevisionFieldReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionFieldReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitOpsConfig) DeepCopyInto(out *GitOpsConfig) {
 	*out = *in
@@ -55,6 +381,21 @@ func (in *GitOpsConfig) DeepCopy() *GitOpsConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreConfig) DeepCopyInto(out *SecretStoreConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreConfig.
+func (in *SecretStoreConfig) DeepCopy() *SecretStoreConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pattern) DeepCopyInto(out *Pattern) {
 	*out = *in
@@ -64,18 +405,264 @@ func (in *Pattern) DeepCopyInto(out *Pattern) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pattern.
-func (in *Pattern) DeepCopy() *Pattern {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pattern.
+func (in *Pattern) DeepCopy() *Pattern {
+	if in == nil {
+		return nil
+	}
+	out := new(Pattern)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Pattern) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiClusterConfig) DeepCopyInto(out *MultiClusterConfig) {
+	*out = *in
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterConfig.
+func (in *MultiClusterConfig) DeepCopy() *MultiClusterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiClusterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PairDriftStatus) DeepCopyInto(out *PairDriftStatus) {
+	*out = *in
+	in.LastCheck.DeepCopyInto(&out.LastCheck)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PairDriftStatus.
+func (in *PairDriftStatus) DeepCopy() *PairDriftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PairDriftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternApplicationStatus) DeepCopyInto(out *PatternApplicationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternApplicationStatus.
+func (in *PatternApplicationStatus) DeepCopy() *PatternApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternCatalog) DeepCopyInto(out *PatternCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternCatalog.
+func (in *PatternCatalog) DeepCopy() *PatternCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PatternCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternCatalogEntry) DeepCopyInto(out *PatternCatalogEntry) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternCatalogEntry.
+func (in *PatternCatalogEntry) DeepCopy() *PatternCatalogEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternCatalogEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternCatalogList) DeepCopyInto(out *PatternCatalogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PatternCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternCatalogList.
+func (in *PatternCatalogList) DeepCopy() *PatternCatalogList {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternCatalogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PatternCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternCatalogSpec) DeepCopyInto(out *PatternCatalogSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternCatalogSpec.
+func (in *PatternCatalogSpec) DeepCopy() *PatternCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternCatalogStatus) DeepCopyInto(out *PatternCatalogStatus) {
+	*out = *in
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]PatternCatalogEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastSync.DeepCopyInto(&out.LastSync)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternCatalogStatus.
+func (in *PatternCatalogStatus) DeepCopy() *PatternCatalogStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternCatalogStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternCondition) DeepCopyInto(out *PatternCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternCondition.
+func (in *PatternCondition) DeepCopy() *PatternCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternGitStatus) DeepCopyInto(out *PatternGitStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternGitStatus.
+func (in *PatternGitStatus) DeepCopy() *PatternGitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternGitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PatternGitStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternGitStatusList) DeepCopyInto(out *PatternGitStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PatternGitStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternGitStatusList.
+func (in *PatternGitStatusList) DeepCopy() *PatternGitStatusList {
 	if in == nil {
 		return nil
 	}
-	out := new(Pattern)
+	out := new(PatternGitStatusList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Pattern) DeepCopyObject() runtime.Object {
+func (in *PatternGitStatusList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -83,18 +670,47 @@ func (in *Pattern) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PatternCondition) DeepCopyInto(out *PatternCondition) {
+func (in *PatternGitStatusSpec) DeepCopyInto(out *PatternGitStatusSpec) {
 	*out = *in
-	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternCondition.
-func (in *PatternCondition) DeepCopy() *PatternCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternGitStatusSpec.
+func (in *PatternGitStatusSpec) DeepCopy() *PatternGitStatusSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PatternCondition)
+	out := new(PatternGitStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternGitStatusStatus) DeepCopyInto(out *PatternGitStatusStatus) {
+	*out = *in
+	in.LastCheck.DeepCopyInto(&out.LastCheck)
+	if in.TargetMatchedRefs != nil {
+		in, out := &in.TargetMatchedRefs, &out.TargetMatchedRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DriftedBranches != nil {
+		in, out := &in.DriftedBranches, &out.DriftedBranches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastCheckFailure != nil {
+		in, out := &in.LastCheckFailure, &out.LastCheckFailure
+		*out = new(CheckFailure)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternGitStatusStatus.
+func (in *PatternGitStatusStatus) DeepCopy() *PatternGitStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternGitStatusStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -131,9 +747,29 @@ func (in *PatternList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterSource) DeepCopyInto(out *ParameterSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterSource.
+func (in *ParameterSource) DeepCopy() *ParameterSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatternParameter) DeepCopyInto(out *PatternParameter) {
 	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ParameterSource)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternParameter.
@@ -149,7 +785,7 @@ func (in *PatternParameter) DeepCopy() *PatternParameter {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatternSpec) DeepCopyInto(out *PatternSpec) {
 	*out = *in
-	out.GitConfig = in.GitConfig
+	in.GitConfig.DeepCopyInto(&out.GitConfig)
 	if in.GitOpsConfig != nil {
 		in, out := &in.GitOpsConfig, &out.GitOpsConfig
 		*out = new(GitOpsConfig)
@@ -158,13 +794,50 @@ func (in *PatternSpec) DeepCopyInto(out *PatternSpec) {
 	if in.ExtraParameters != nil {
 		in, out := &in.ExtraParameters, &out.ExtraParameters
 		*out = make([]PatternParameter, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ExtraValueFiles != nil {
 		in, out := &in.ExtraValueFiles, &out.ExtraValueFiles
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ValueFiles != nil {
+		in, out := &in.ValueFiles, &out.ValueFiles
+		*out = make([]PatternValueFile, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SyncPolicy != nil {
+		in, out := &in.SyncPolicy, &out.SyncPolicy
+		*out = new(SyncPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MultiCluster != nil {
+		in, out := &in.MultiCluster, &out.MultiCluster
+		*out = new(MultiClusterConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApplicationSet != nil {
+		in, out := &in.ApplicationSet, &out.ApplicationSet
+		*out = new(ApplicationSetConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretStore != nil {
+		in, out := &in.SecretStore, &out.SecretStore
+		*out = new(SecretStoreConfig)
+		**out = **in
+	}
+	if in.Provision != nil {
+		in, out := &in.Provision, &out.Provision
+		*out = new(ProvisionConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternSpec.
@@ -177,9 +850,115 @@ func (in *PatternSpec) DeepCopy() *PatternSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncPolicyConfig) DeepCopyInto(out *SyncPolicyConfig) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]SyncWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncPolicyConfig.
+func (in *SyncPolicyConfig) DeepCopy() *SyncPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncWindow) DeepCopyInto(out *SyncWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncWindow.
+func (in *SyncWindow) DeepCopy() *SyncWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternValueFile) DeepCopyInto(out *PatternValueFile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternValueFile.
+func (in *PatternValueFile) DeepCopy() *PatternValueFile {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternValueFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternSpokeStatus) DeepCopyInto(out *PatternSpokeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternSpokeStatus.
+func (in *PatternSpokeStatus) DeepCopy() *PatternSpokeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternSpokeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatternStatus) DeepCopyInto(out *PatternStatus) {
 	*out = *in
+	if in.LastCheckFailure != nil {
+		in, out := &in.LastCheckFailure, &out.LastCheckFailure
+		*out = new(CheckFailure)
+		**out = **in
+	}
+	if in.DriftStatus != nil {
+		in, out := &in.DriftStatus, &out.DriftStatus
+		*out = new(DriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UnhealthySince != nil {
+		in, out := &in.UnhealthySince, &out.UnhealthySince
+		*out = (*in).DeepCopy()
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(PatternUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftRetryState != nil {
+		in, out := &in.DriftRetryState, &out.DriftRetryState
+		*out = new(DriftRetryState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PairDriftStatuses != nil {
+		in, out := &in.PairDriftStatuses, &out.PairDriftStatuses
+		*out = make([]PairDriftStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]PatternApplicationStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Spokes != nil {
+		in, out := &in.Spokes, &out.Spokes
+		*out = make([]PatternSpokeStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]PatternCondition, len(*in))
@@ -198,3 +977,190 @@ func (in *PatternStatus) DeepCopy() *PatternStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternUpgradeStatus) DeepCopyInto(out *PatternUpgradeStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternUpgradeStatus.
+func (in *PatternUpgradeStatus) DeepCopy() *PatternUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternsOperatorConfig) DeepCopyInto(out *PatternsOperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternsOperatorConfig.
+func (in *PatternsOperatorConfig) DeepCopy() *PatternsOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternsOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PatternsOperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternsOperatorConfigList) DeepCopyInto(out *PatternsOperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PatternsOperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternsOperatorConfigList.
+func (in *PatternsOperatorConfigList) DeepCopy() *PatternsOperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternsOperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PatternsOperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternsOperatorConfigSpec) DeepCopyInto(out *PatternsOperatorConfigSpec) {
+	*out = *in
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxyConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternsOperatorConfigSpec.
+func (in *PatternsOperatorConfigSpec) DeepCopy() *PatternsOperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternsOperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternsOperatorConfigStatus) DeepCopyInto(out *PatternsOperatorConfigStatus) {
+	*out = *in
+	in.LastApplied.DeepCopyInto(&out.LastApplied)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternsOperatorConfigStatus.
+func (in *PatternsOperatorConfigStatus) DeepCopy() *PatternsOperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternsOperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisionConfig) DeepCopyInto(out *ProvisionConfig) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]ProvisionedServiceAccount, len(*in))
+		copy(*out, *in)
+	}
+	if in.RoleBindings != nil {
+		in, out := &in.RoleBindings, &out.RoleBindings
+		*out = make([]ProvisionedRoleBinding, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisionConfig.
+func (in *ProvisionConfig) DeepCopy() *ProvisionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisionedRoleBinding) DeepCopyInto(out *ProvisionedRoleBinding) {
+	*out = *in
+	out.ServiceAccount = in.ServiceAccount
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisionedRoleBinding.
+func (in *ProvisionedRoleBinding) DeepCopy() *ProvisionedRoleBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisionedRoleBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisionedServiceAccount) DeepCopyInto(out *ProvisionedServiceAccount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisionedServiceAccount.
+func (in *ProvisionedServiceAccount) DeepCopy() *ProvisionedServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisionedServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyConfig) DeepCopyInto(out *ProxyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfig.
+func (in *ProxyConfig) DeepCopy() *ProxyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
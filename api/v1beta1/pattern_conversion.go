@@ -0,0 +1,551 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// primaryRepositoryNameAnnotation stashes the name of the first repository on the hub object:
+// the v1alpha1 primary pair has no name field, so without the annotation a named primary
+// repository would not survive the round trip through the stored version
+const primaryRepositoryNameAnnotation = "gitops.hybrid-cloud-patterns.io/primary-repository-name"
+
+// ConvertTo converts this Pattern to the Hub version (v1alpha1).
+func (src *Pattern) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Pattern)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.PatternSpec{
+		GitConfig:        convertGitSpecTo(src.Spec.GitSpec),
+		GitOpsConfig:     convertGitOpsConfigTo(src.Spec.GitOpsSpec),
+		ClusterGroupName: src.Spec.ClusterGroupName,
+		ExtraValueFiles:  copyStrings(src.Spec.ExtraValueFiles),
+		SyncWave:         src.Spec.SyncWave,
+		DependsOn:        copyStrings(src.Spec.DependsOn),
+		ApplicationSet:   convertApplicationSetTo(src.Spec.ApplicationSet),
+	}
+	if src.Spec.MultiCluster != nil {
+		dst.Spec.MultiCluster = &v1alpha1.MultiClusterConfig{MatchLabels: src.Spec.MultiCluster.MatchLabels}
+	}
+	if src.Spec.SecretStore != nil {
+		store := v1alpha1.SecretStoreConfig(*src.Spec.SecretStore)
+		dst.Spec.SecretStore = &store
+	}
+	dst.Spec.Provision = convertProvisionTo(src.Spec.Provision)
+	if src.Spec.SyncPolicy != nil {
+		dst.Spec.SyncPolicy = &v1alpha1.SyncPolicyConfig{}
+		for _, window := range src.Spec.SyncPolicy.Windows {
+			dst.Spec.SyncPolicy.Windows = append(dst.Spec.SyncPolicy.Windows, v1alpha1.SyncWindow{
+				Kind:     v1alpha1.SyncWindowKind(window.Kind),
+				Schedule: window.Schedule,
+				Duration: window.Duration,
+			})
+		}
+	}
+	for _, file := range src.Spec.ValueFiles {
+		dst.Spec.ValueFiles = append(dst.Spec.ValueFiles, v1alpha1.PatternValueFile(file))
+	}
+	for _, p := range src.Spec.ExtraParameters {
+		converted := v1alpha1.PatternParameter{Name: p.Name, Value: p.Value}
+		if p.ValueFrom != nil {
+			valueFrom := v1alpha1.ParameterSource(*p.ValueFrom)
+			converted.ValueFrom = &valueFrom
+		}
+		dst.Spec.ExtraParameters = append(dst.Spec.ExtraParameters, converted)
+	}
+	if len(src.Spec.GitSpec.Repositories) > 0 && src.Spec.GitSpec.Repositories[0].Name != "" {
+		dst.Annotations = withAnnotation(dst.Annotations, primaryRepositoryNameAnnotation, src.Spec.GitSpec.Repositories[0].Name)
+	} else {
+		dst.Annotations = withoutAnnotation(dst.Annotations, primaryRepositoryNameAnnotation)
+	}
+	dst.Status = convertStatusTo(src.Status)
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this Pattern.
+func (dst *Pattern) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Pattern)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = PatternSpec{
+		GitSpec:          convertGitSpecFrom(src.Spec.GitConfig),
+		GitOpsSpec:       convertGitOpsConfigFrom(src.Spec.GitOpsConfig),
+		ClusterGroupName: src.Spec.ClusterGroupName,
+		ExtraValueFiles:  copyStrings(src.Spec.ExtraValueFiles),
+		SyncWave:         src.Spec.SyncWave,
+		DependsOn:        copyStrings(src.Spec.DependsOn),
+		ApplicationSet:   convertApplicationSetFrom(src.Spec.ApplicationSet),
+	}
+	if src.Spec.MultiCluster != nil {
+		dst.Spec.MultiCluster = &MultiClusterConfig{MatchLabels: src.Spec.MultiCluster.MatchLabels}
+	}
+	if src.Spec.SecretStore != nil {
+		store := SecretStoreConfig(*src.Spec.SecretStore)
+		dst.Spec.SecretStore = &store
+	}
+	dst.Spec.Provision = convertProvisionFrom(src.Spec.Provision)
+	if src.Spec.SyncPolicy != nil {
+		dst.Spec.SyncPolicy = &SyncPolicyConfig{}
+		for _, window := range src.Spec.SyncPolicy.Windows {
+			dst.Spec.SyncPolicy.Windows = append(dst.Spec.SyncPolicy.Windows, SyncWindow{
+				Kind:     SyncWindowKind(window.Kind),
+				Schedule: window.Schedule,
+				Duration: window.Duration,
+			})
+		}
+	}
+	for _, file := range src.Spec.ValueFiles {
+		dst.Spec.ValueFiles = append(dst.Spec.ValueFiles, PatternValueFile(file))
+	}
+	for _, p := range src.Spec.ExtraParameters {
+		converted := PatternParameter{Name: p.Name, Value: p.Value}
+		if p.ValueFrom != nil {
+			valueFrom := ParameterSource(*p.ValueFrom)
+			converted.ValueFrom = &valueFrom
+		}
+		dst.Spec.ExtraParameters = append(dst.Spec.ExtraParameters, converted)
+	}
+	if name, found := dst.Annotations[primaryRepositoryNameAnnotation]; found {
+		if len(dst.Spec.GitSpec.Repositories) > 0 {
+			dst.Spec.GitSpec.Repositories[0].Name = name
+		}
+		dst.Annotations = withoutAnnotation(dst.Annotations, primaryRepositoryNameAnnotation)
+	}
+	dst.Status = convertStatusFrom(src.Status)
+	return nil
+}
+
+// convertGitSpecTo flattens the v1beta1 git configuration into the v1alpha1 GitConfig: the first
+// repository becomes the top-level pair, the rest become additionalPairs, the structured
+// credential references become secret names and the drift settings move back to the top level
+func convertGitSpecTo(in GitSpec) v1alpha1.GitConfig {
+	out := v1alpha1.GitConfig{
+		Hostname:                    in.Hostname,
+		UpgradeTimeoutSeconds:       in.UpgradeTimeoutSeconds,
+		RollbackDeadlineSeconds:     in.RollbackDeadlineSeconds,
+		Forge:                       v1alpha1.ForgeType(in.Forge),
+		InsecureSkipVerify:          in.TLS.InsecureSkipVerify,
+		Proxy:                       v1alpha1.ProxyConfig(in.Proxy),
+		PollInterval:                in.Drift.PollIntervalSeconds,
+		AlignChecks:                 in.Drift.AlignChecks,
+		DriftPaused:                 in.Drift.Paused,
+		AutoSync:                    in.Drift.AutoSync,
+		RedirectPolicy:              v1alpha1.RedirectPolicyType(in.Drift.RedirectPolicy),
+		HeadlessPolicy:              v1alpha1.HeadlessPolicyType(in.Drift.HeadlessPolicy),
+		HeadBranchPreferences:       copyStrings(in.Drift.HeadBranchPreferences),
+		SyncEpsilonSeconds:          in.Drift.SyncEpsilonSeconds,
+		BranchMapping:               v1alpha1.BranchMapping(in.Drift.BranchMapping),
+		ComparisonMode:              in.Drift.ComparisonMode,
+		BranchFilter:                in.Drift.BranchFilter,
+		PathFilters:                 copyStrings(in.Drift.PathFilters),
+		CacheTargetListing:          in.Drift.CacheTargetListing,
+		TargetStalenessSeconds:      in.Drift.TargetStalenessSeconds,
+		DetailedTransitionMessages:  in.Drift.DetailedTransitionMessages,
+		VerifyRepositoryIdentity:    in.Drift.VerifyRepositoryIdentity,
+		TrustedOriginBranch:         in.Drift.TrustedOriginBranch,
+		ErrorIntervalPolicy:         v1alpha1.ErrorIntervalPolicyType(in.Drift.ErrorIntervalPolicy),
+		ErrorBackoffCapSeconds:      in.Drift.ErrorBackoffCapSeconds,
+		ErrorBackoffJitterSeconds:   in.Drift.ErrorBackoffJitterSeconds,
+		ClockSkewToleranceSeconds:   in.Drift.ClockSkewToleranceSeconds,
+		DriftMinCommitsBehind:       in.Drift.MinCommitsBehind,
+		DriftSustainedChecks:        in.Drift.SustainedChecks,
+		ComputeMergeBase:            in.Drift.ComputeMergeBase,
+		ExternalRevisionEndpoint:    in.Drift.ExternalRevisionEndpoint,
+		GithubDeploymentEnvironment: in.Drift.GithubDeploymentEnvironment,
+	}
+	if in.Credentials.ReadFrom != nil {
+		out.CredentialsSecret = in.Credentials.ReadFrom.Name
+	}
+	if in.Credentials.WriteFrom != nil {
+		out.SyncCredentialsSecret = in.Credentials.WriteFrom.Name
+	}
+	if in.SecretsRef != nil {
+		out.SecretsRef = in.SecretsRef.Name
+	}
+	if in.TLS.CABundleFrom != nil {
+		out.CABundleSecret = in.TLS.CABundleFrom.Name
+	}
+	if in.Drift.RevisionFrom != nil {
+		ref := v1alpha1.RevisionFieldReference(*in.Drift.RevisionFrom)
+		out.RevisionFrom = &ref
+	}
+	if len(in.Repositories) > 0 {
+		primary := in.Repositories[0]
+		out.OriginRepo = primary.OriginRepo
+		out.OriginRevision = primary.OriginRevision
+		out.TargetRepo = primary.TargetRepo
+		out.TargetRevision = primary.TargetRevision
+		for _, repo := range in.Repositories[1:] {
+			out.AdditionalPairs = append(out.AdditionalPairs, v1alpha1.GitRepoPair{
+				Name:           repo.Name,
+				OriginRepo:     repo.OriginRepo,
+				TargetRepo:     repo.TargetRepo,
+				OriginRevision: repo.OriginRevision,
+				TargetRevision: repo.TargetRevision,
+			})
+		}
+	}
+	return out
+}
+
+// convertGitSpecFrom is the inverse of convertGitSpecTo
+func convertGitSpecFrom(in v1alpha1.GitConfig) GitSpec {
+	out := GitSpec{
+		Hostname:                in.Hostname,
+		UpgradeTimeoutSeconds:   in.UpgradeTimeoutSeconds,
+		RollbackDeadlineSeconds: in.RollbackDeadlineSeconds,
+		Forge:                   ForgeType(in.Forge),
+		TLS:                     TLSConfig{InsecureSkipVerify: in.InsecureSkipVerify},
+		Proxy:                   ProxyConfig(in.Proxy),
+		Drift: DriftSpec{
+			PollIntervalSeconds:         in.PollInterval,
+			AlignChecks:                 in.AlignChecks,
+			Paused:                      in.DriftPaused,
+			AutoSync:                    in.AutoSync,
+			RedirectPolicy:              RedirectPolicyType(in.RedirectPolicy),
+			HeadlessPolicy:              HeadlessPolicyType(in.HeadlessPolicy),
+			HeadBranchPreferences:       copyStrings(in.HeadBranchPreferences),
+			SyncEpsilonSeconds:          in.SyncEpsilonSeconds,
+			BranchMapping:               BranchMapping(in.BranchMapping),
+			ComparisonMode:              in.ComparisonMode,
+			BranchFilter:                in.BranchFilter,
+			PathFilters:                 copyStrings(in.PathFilters),
+			CacheTargetListing:          in.CacheTargetListing,
+			TargetStalenessSeconds:      in.TargetStalenessSeconds,
+			DetailedTransitionMessages:  in.DetailedTransitionMessages,
+			VerifyRepositoryIdentity:    in.VerifyRepositoryIdentity,
+			TrustedOriginBranch:         in.TrustedOriginBranch,
+			ErrorIntervalPolicy:         ErrorIntervalPolicyType(in.ErrorIntervalPolicy),
+			ErrorBackoffCapSeconds:      in.ErrorBackoffCapSeconds,
+			ErrorBackoffJitterSeconds:   in.ErrorBackoffJitterSeconds,
+			ClockSkewToleranceSeconds:   in.ClockSkewToleranceSeconds,
+			MinCommitsBehind:            in.DriftMinCommitsBehind,
+			SustainedChecks:             in.DriftSustainedChecks,
+			ComputeMergeBase:            in.ComputeMergeBase,
+			ExternalRevisionEndpoint:    in.ExternalRevisionEndpoint,
+			GithubDeploymentEnvironment: in.GithubDeploymentEnvironment,
+		},
+	}
+	if in.CredentialsSecret != "" {
+		out.Credentials.ReadFrom = &SecretReference{Name: in.CredentialsSecret}
+	}
+	if in.SyncCredentialsSecret != "" {
+		out.Credentials.WriteFrom = &SecretReference{Name: in.SyncCredentialsSecret}
+	}
+	if in.SecretsRef != "" {
+		out.SecretsRef = &SecretReference{Name: in.SecretsRef}
+	}
+	if in.CABundleSecret != "" {
+		out.TLS.CABundleFrom = &SecretReference{Name: in.CABundleSecret}
+	}
+	if in.RevisionFrom != nil {
+		ref := RevisionFieldReference(*in.RevisionFrom)
+		out.Drift.RevisionFrom = &ref
+	}
+	if in.TargetRepo != "" || in.OriginRepo != "" {
+		out.Repositories = append(out.Repositories, GitRepository{
+			OriginRepo:     in.OriginRepo,
+			OriginRevision: in.OriginRevision,
+			TargetRepo:     in.TargetRepo,
+			TargetRevision: in.TargetRevision,
+		})
+	}
+	for _, pair := range in.AdditionalPairs {
+		out.Repositories = append(out.Repositories, GitRepository{
+			Name:           pair.Name,
+			OriginRepo:     pair.OriginRepo,
+			OriginRevision: pair.OriginRevision,
+			TargetRepo:     pair.TargetRepo,
+			TargetRevision: pair.TargetRevision,
+		})
+	}
+	return out
+}
+
+func convertGitOpsConfigTo(in *GitOpsConfig) *v1alpha1.GitOpsConfig {
+	if in == nil {
+		return nil
+	}
+	out := v1alpha1.GitOpsConfig(*in)
+	return &out
+}
+
+func convertGitOpsConfigFrom(in *v1alpha1.GitOpsConfig) *GitOpsConfig {
+	if in == nil {
+		return nil
+	}
+	out := GitOpsConfig(*in)
+	return &out
+}
+
+// convertApplicationSetTo maps the generator declarations; the two versions carry the same shape
+func convertApplicationSetTo(in *ApplicationSetConfig) *v1alpha1.ApplicationSetConfig {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.ApplicationSetConfig{}
+	for _, gen := range in.Generators {
+		converted := v1alpha1.ApplicationSetGenerator{}
+		if gen.Clusters != nil {
+			clusters := v1alpha1.ClusterGenerator{MatchLabels: gen.Clusters.MatchLabels}
+			converted.Clusters = &clusters
+		}
+		if gen.List != nil {
+			list := v1alpha1.ListGenerator{Elements: gen.List.Elements}
+			converted.List = &list
+		}
+		if gen.GitDirectories != nil {
+			git := v1alpha1.GitDirectoryGenerator(*gen.GitDirectories)
+			converted.GitDirectories = &git
+		}
+		out.Generators = append(out.Generators, converted)
+	}
+	return out
+}
+
+// convertApplicationSetFrom is the inverse of convertApplicationSetTo
+func convertApplicationSetFrom(in *v1alpha1.ApplicationSetConfig) *ApplicationSetConfig {
+	if in == nil {
+		return nil
+	}
+	out := &ApplicationSetConfig{}
+	for _, gen := range in.Generators {
+		converted := ApplicationSetGenerator{}
+		if gen.Clusters != nil {
+			clusters := ClusterGenerator{MatchLabels: gen.Clusters.MatchLabels}
+			converted.Clusters = &clusters
+		}
+		if gen.List != nil {
+			list := ListGenerator{Elements: gen.List.Elements}
+			converted.List = &list
+		}
+		if gen.GitDirectories != nil {
+			git := GitDirectoryGenerator(*gen.GitDirectories)
+			converted.GitDirectories = &git
+		}
+		out.Generators = append(out.Generators, converted)
+	}
+	return out
+}
+
+// convertProvisionTo maps the provisioned resource declarations; the two versions carry the
+// same shape
+func convertProvisionTo(in *ProvisionConfig) *v1alpha1.ProvisionConfig {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.ProvisionConfig{Namespaces: copyStrings(in.Namespaces)}
+	for _, sa := range in.ServiceAccounts {
+		out.ServiceAccounts = append(out.ServiceAccounts, v1alpha1.ProvisionedServiceAccount(sa))
+	}
+	for _, binding := range in.RoleBindings {
+		out.RoleBindings = append(out.RoleBindings, v1alpha1.ProvisionedRoleBinding{
+			Name:           binding.Name,
+			Namespace:      binding.Namespace,
+			ClusterRole:    binding.ClusterRole,
+			ServiceAccount: v1alpha1.ProvisionedServiceAccount(binding.ServiceAccount),
+		})
+	}
+	return out
+}
+
+// convertProvisionFrom is the inverse of convertProvisionTo
+func convertProvisionFrom(in *v1alpha1.ProvisionConfig) *ProvisionConfig {
+	if in == nil {
+		return nil
+	}
+	out := &ProvisionConfig{Namespaces: copyStrings(in.Namespaces)}
+	for _, sa := range in.ServiceAccounts {
+		out.ServiceAccounts = append(out.ServiceAccounts, ProvisionedServiceAccount(sa))
+	}
+	for _, binding := range in.RoleBindings {
+		out.RoleBindings = append(out.RoleBindings, ProvisionedRoleBinding{
+			Name:           binding.Name,
+			Namespace:      binding.Namespace,
+			ClusterRole:    binding.ClusterRole,
+			ServiceAccount: ProvisionedServiceAccount(binding.ServiceAccount),
+		})
+	}
+	return out
+}
+
+// convertStatusTo maps the status verbatim; the two versions carry the same fields
+func convertStatusTo(in PatternStatus) v1alpha1.PatternStatus {
+	out := v1alpha1.PatternStatus{
+		ObservedGeneration:              in.ObservedGeneration,
+		LastStep:                        in.LastStep,
+		LastError:                       in.LastError,
+		Version:                         in.Version,
+		ClusterName:                     in.ClusterName,
+		AppClusterDomain:                in.AppClusterDomain,
+		ClusterDomain:                   in.ClusterDomain,
+		ClusterID:                       in.ClusterID,
+		ClusterPlatform:                 in.ClusterPlatform,
+		ClusterArchitecture:             in.ClusterArchitecture,
+		FIPSEnabled:                     in.FIPSEnabled,
+		ClusterVersion:                  in.ClusterVersion,
+		LastDriftCheckSchedulingDelayMs: in.LastDriftCheckSchedulingDelayMs,
+		LastDriftCheckDurationMs:        in.LastDriftCheckDurationMs,
+		LoadedSecretsVersion:            in.LoadedSecretsVersion,
+		AppliedRevision:                 in.AppliedRevision,
+		LastKnownGoodRevision:           in.LastKnownGoodRevision,
+	}
+	if in.UnhealthySince != nil {
+		out.UnhealthySince = in.UnhealthySince.DeepCopy()
+	}
+	if in.Upgrade != nil {
+		upgrade := v1alpha1.PatternUpgradeStatus(*in.Upgrade)
+		out.Upgrade = &upgrade
+	}
+	if in.LastCheckFailure != nil {
+		failure := v1alpha1.CheckFailure(*in.LastCheckFailure)
+		out.LastCheckFailure = &failure
+	}
+	if in.DriftStatus != nil {
+		drift := v1alpha1.DriftStatus(*in.DriftStatus)
+		out.DriftStatus = &drift
+	}
+	if in.DriftRetryState != nil {
+		retry := v1alpha1.DriftRetryState(*in.DriftRetryState)
+		out.DriftRetryState = &retry
+	}
+	for _, pair := range in.PairDriftStatuses {
+		out.PairDriftStatuses = append(out.PairDriftStatuses, v1alpha1.PairDriftStatus(pair))
+	}
+	for _, app := range in.Applications {
+		out.Applications = append(out.Applications, v1alpha1.PatternApplicationStatus(app))
+	}
+	for _, spoke := range in.Spokes {
+		out.Spokes = append(out.Spokes, v1alpha1.PatternSpokeStatus(spoke))
+	}
+	for _, cond := range in.Conditions {
+		out.Conditions = append(out.Conditions, v1alpha1.PatternCondition{
+			Type:               v1alpha1.PatternConditionType(cond.Type),
+			Status:             cond.Status,
+			LastUpdateTime:     cond.LastUpdateTime,
+			LastTransitionTime: cond.LastTransitionTime,
+			Reason:             cond.Reason,
+			ObservedGeneration: cond.ObservedGeneration,
+			Message:            cond.Message,
+		})
+	}
+	return out
+}
+
+// convertStatusFrom is the inverse of convertStatusTo
+func convertStatusFrom(in v1alpha1.PatternStatus) PatternStatus {
+	out := PatternStatus{
+		ObservedGeneration:              in.ObservedGeneration,
+		LastStep:                        in.LastStep,
+		LastError:                       in.LastError,
+		Version:                         in.Version,
+		ClusterName:                     in.ClusterName,
+		AppClusterDomain:                in.AppClusterDomain,
+		ClusterDomain:                   in.ClusterDomain,
+		ClusterID:                       in.ClusterID,
+		ClusterPlatform:                 in.ClusterPlatform,
+		ClusterArchitecture:             in.ClusterArchitecture,
+		FIPSEnabled:                     in.FIPSEnabled,
+		ClusterVersion:                  in.ClusterVersion,
+		LastDriftCheckSchedulingDelayMs: in.LastDriftCheckSchedulingDelayMs,
+		LastDriftCheckDurationMs:        in.LastDriftCheckDurationMs,
+		LoadedSecretsVersion:            in.LoadedSecretsVersion,
+		AppliedRevision:                 in.AppliedRevision,
+		LastKnownGoodRevision:           in.LastKnownGoodRevision,
+	}
+	if in.UnhealthySince != nil {
+		out.UnhealthySince = in.UnhealthySince.DeepCopy()
+	}
+	if in.Upgrade != nil {
+		upgrade := PatternUpgradeStatus(*in.Upgrade)
+		out.Upgrade = &upgrade
+	}
+	if in.LastCheckFailure != nil {
+		failure := CheckFailure(*in.LastCheckFailure)
+		out.LastCheckFailure = &failure
+	}
+	if in.DriftStatus != nil {
+		drift := DriftStatus(*in.DriftStatus)
+		out.DriftStatus = &drift
+	}
+	if in.DriftRetryState != nil {
+		retry := DriftRetryState(*in.DriftRetryState)
+		out.DriftRetryState = &retry
+	}
+	for _, pair := range in.PairDriftStatuses {
+		out.PairDriftStatuses = append(out.PairDriftStatuses, PairDriftStatus(pair))
+	}
+	for _, app := range in.Applications {
+		out.Applications = append(out.Applications, PatternApplicationStatus(app))
+	}
+	for _, spoke := range in.Spokes {
+		out.Spokes = append(out.Spokes, PatternSpokeStatus(spoke))
+	}
+	for _, cond := range in.Conditions {
+		out.Conditions = append(out.Conditions, PatternCondition{
+			Type:               PatternConditionType(cond.Type),
+			Status:             cond.Status,
+			LastUpdateTime:     cond.LastUpdateTime,
+			LastTransitionTime: cond.LastTransitionTime,
+			Reason:             cond.Reason,
+			ObservedGeneration: cond.ObservedGeneration,
+			Message:            cond.Message,
+		})
+	}
+	return out
+}
+
+// withAnnotation returns a copy of the annotations with the given key set. The incoming map is
+// shared with the source object through the ObjectMeta assignment, so it is never mutated
+func withAnnotation(annotations map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// withoutAnnotation returns a copy of the annotations with the given key removed, or the map
+// unchanged when the key is absent
+func withoutAnnotation(annotations map[string]string, key string) map[string]string {
+	if _, found := annotations[key]; !found {
+		return annotations
+	}
+	out := map[string]string{}
+	for k, v := range annotations {
+		if k != key {
+			out[k] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func copyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	copy(out, in)
+	return out
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// fullGitSpec populates every field of the git configuration, so the round trip below catches
+// any field a future conversion change forgets to map
+func fullGitSpec() GitSpec {
+	return GitSpec{
+		Hostname: "github.example.com",
+		Repositories: []GitRepository{
+			{
+				Name:           "primary",
+				OriginRepo:     "https://github.example.com/org/upstream",
+				OriginRevision: "main",
+				TargetRepo:     "https://github.example.com/org/fork",
+				TargetRevision: "deploy",
+			},
+			{
+				Name:           "docs",
+				OriginRepo:     "https://github.example.com/org/docs-upstream",
+				OriginRevision: "v2",
+				TargetRepo:     "https://github.example.com/org/docs",
+				TargetRevision: "v2-deploy",
+			},
+		},
+		Credentials: GitCredentials{
+			ReadFrom:  &SecretReference{Name: "read-creds"},
+			WriteFrom: &SecretReference{Name: "write-creds"},
+		},
+		SecretsRef: &SecretReference{Name: "values-secret"},
+		Forge:      ForgeGitHub,
+		TLS: TLSConfig{
+			CABundleFrom:       &SecretReference{Name: "ca-bundle"},
+			InsecureSkipVerify: true,
+		},
+		Proxy: ProxyConfig{
+			HTTPProxy:  "http://proxy.corp:3128",
+			HTTPSProxy: "http://proxy.corp:3129",
+			NoProxy:    ".corp,localhost",
+		},
+		Drift: DriftSpec{
+			PollIntervalSeconds:         300,
+			AlignChecks:                 true,
+			Paused:                      true,
+			AutoSync:                    true,
+			RedirectPolicy:              RedirectRefuse,
+			HeadlessPolicy:              HeadlessPreferenceList,
+			HeadBranchPreferences:       []string{"main", "master"},
+			SyncEpsilonSeconds:          45,
+			BranchMapping:               BranchMapping{Origin: "main", Target: "deploy"},
+			ComparisonMode:              "hash",
+			BranchFilter:                "env/*",
+			PathFilters:                 []string{"charts/", "values/"},
+			CacheTargetListing:          true,
+			TargetStalenessSeconds:      600,
+			DetailedTransitionMessages:  true,
+			VerifyRepositoryIdentity:    true,
+			TrustedOriginBranch:         "main",
+			ErrorIntervalPolicy:         ErrorIntervalGrow,
+			ErrorBackoffCapSeconds:      900,
+			ErrorBackoffJitterSeconds:   30,
+			ClockSkewToleranceSeconds:   15,
+			MinCommitsBehind:            3,
+			SustainedChecks:             2,
+			ComputeMergeBase:            true,
+			ExternalRevisionEndpoint:    "https://resolver.corp/revision",
+			GithubDeploymentEnvironment: "production",
+			RevisionFrom: &RevisionFieldReference{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       "revision-source",
+				Namespace:  "sources",
+				FieldPath:  "data.revision",
+			},
+		},
+		UpgradeTimeoutSeconds:   900,
+		RollbackDeadlineSeconds: 1200,
+	}
+}
+
+func TestGitSpecRoundTrip(t *testing.T) {
+	original := &Pattern{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "default",
+			Annotations: map[string]string{"kept": "annotation"},
+		},
+		Spec: PatternSpec{GitSpec: fullGitSpec(), ClusterGroupName: "default"},
+	}
+	hub := &v1alpha1.Pattern{}
+	if err := original.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo failed: %s", err)
+	}
+	if hub.Annotations[primaryRepositoryNameAnnotation] != "primary" {
+		t.Errorf("expected the primary repository name to be stashed on the hub, got %q", hub.Annotations[primaryRepositoryNameAnnotation])
+	}
+	restored := &Pattern{}
+	if err := restored.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom failed: %s", err)
+	}
+	if !reflect.DeepEqual(original.Spec.GitSpec, restored.Spec.GitSpec) {
+		t.Errorf("git spec did not survive the round trip:\noriginal: %+v\nrestored: %+v", original.Spec.GitSpec, restored.Spec.GitSpec)
+	}
+	if _, found := restored.Annotations[primaryRepositoryNameAnnotation]; found {
+		t.Error("expected the stash annotation to be stripped from the restored spoke")
+	}
+	if restored.Annotations["kept"] != "annotation" {
+		t.Errorf("expected unrelated annotations to be preserved, got %v", restored.Annotations)
+	}
+	if original.Annotations["kept"] != "annotation" || len(original.Annotations) != 1 {
+		t.Errorf("expected the annotations of the source object to stay untouched, got %v", original.Annotations)
+	}
+}
+
+func TestGitSpecRoundTripUnnamedPrimary(t *testing.T) {
+	spec := fullGitSpec()
+	spec.Repositories[0].Name = ""
+	original := &Pattern{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec:       PatternSpec{GitSpec: spec},
+	}
+	hub := &v1alpha1.Pattern{}
+	if err := original.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo failed: %s", err)
+	}
+	if _, found := hub.Annotations[primaryRepositoryNameAnnotation]; found {
+		t.Error("expected no stash annotation for an unnamed primary repository")
+	}
+	restored := &Pattern{}
+	if err := restored.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom failed: %s", err)
+	}
+	if !reflect.DeepEqual(original.Spec.GitSpec, restored.Spec.GitSpec) {
+		t.Errorf("git spec did not survive the round trip:\noriginal: %+v\nrestored: %+v", original.Spec.GitSpec, restored.Spec.GitSpec)
+	}
+}
@@ -0,0 +1,783 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+//  https://pkg.go.dev/encoding/json#Marshal
+
+type PatternParameter struct {
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Value string `json:"value,omitempty"`
+
+	// Optional. Resolve the value from a key of a ConfigMap or Secret in the pattern namespace
+	// instead of the inline value; the application is re-rendered when the source object changes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ValueFrom *ParameterSource `json:"valueFrom,omitempty"`
+}
+
+// PatternValueFile is one layered Helm values file
+type PatternValueFile struct {
+	// Path of the values file within the pattern repository
+	Path string `json:"path"`
+	// Optional files may be absent from the repository; a required file that is missing raises
+	// the ValuesFileMissing condition
+	Optional bool `json:"optional,omitempty"`
+}
+
+// ParameterSource references the key of a ConfigMap or Secret a parameter value is read from.
+// Exactly one of the object names must be set
+type ParameterSource struct {
+	// Name of the ConfigMap holding the value
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// Name of the Secret holding the value
+	SecretName string `json:"secretName,omitempty"`
+	// Key to read within the referenced object
+	Key string `json:"key"`
+}
+
+// PatternSpec defines the desired state of Pattern.
+//
+// Compared to v1alpha1 the git configuration is reorganized: the repositories are a list instead
+// of a primary pair plus additionalPairs, the credential secrets are structured references, and
+// the knobs steering drift monitoring are grouped under gitSpec.drift
+type PatternSpec struct {
+	GitSpec    GitSpec       `json:"gitSpec"`
+	GitOpsSpec *GitOpsConfig `json:"gitOpsSpec,omitempty"`
+
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ClusterGroupName string `json:"clusterGroupName"`
+
+	// .Name is dot separated per the helm --set syntax, such as:
+	//   global.something.field
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ExtraParameters []PatternParameter `json:"extraParameters,omitempty"`
+
+	// URLs to additional Helm parameter files
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ExtraValueFiles []string `json:"extraValueFiles,omitempty"`
+
+	// Optional. Ordered list of additional Helm values files layered on top of extraValueFiles;
+	// later entries override earlier ones. Files not marked optional must exist in the target
+	// repository, otherwise the ValuesFileMissing condition is raised and the deployment held
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ValueFiles []PatternValueFile `json:"valueFiles,omitempty"`
+
+	// Optional. ArgoCD sync wave the clustergroup application is deployed in; lower waves are
+	// applied first
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SyncWave int `json:"syncWave,omitempty"`
+
+	// Optional. Names of patterns in the same namespace that must be Ready before the
+	// application of this pattern is created
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Optional. Maintenance windows constraining when the application may sync; outside an open
+	// window automated sync is disabled and drift remediation is suppressed
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SyncPolicy *SyncPolicyConfig `json:"syncPolicy,omitempty"`
+
+	// Optional. Propagate the pattern to the ACM managed clusters matching the selector by
+	// wrapping it in a ManifestWork on each spoke cluster namespace of the hub
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	MultiCluster *MultiClusterConfig `json:"multiCluster,omitempty"`
+
+	// Optional. Fan the pattern out through an ArgoCD ApplicationSet built from the declared
+	// generators instead of creating a single clustergroup application
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ApplicationSet *ApplicationSetConfig `json:"applicationSet,omitempty"`
+
+	// Optional. Bootstrap of the secret backend the pattern relies on; the readiness of the
+	// store is reported through the SecretStoreReady condition
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SecretStore *SecretStoreConfig `json:"secretStore,omitempty"`
+
+	// Optional. Namespaces, service accounts and role bindings the pattern needs up front,
+	// created with the pattern label and pruned when the pattern is deleted
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Provision *ProvisionConfig `json:"provision,omitempty"`
+}
+
+// SecretStoreConfig describes the secret backend bootstrap of the pattern. It carries the same
+// fields as the v1alpha1 configuration
+type SecretStoreConfig struct {
+	// Deploy Vault through an ArgoCD application when no instance is found in the configured
+	// namespace. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ManageVault bool `json:"manageVault,omitempty"`
+	// Namespace the Vault instance serving the pattern runs in. Default: vault
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	VaultNamespace string `json:"vaultNamespace,omitempty"`
+	// Namespace the external secrets operator runs in. Default: external-secrets
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ExternalSecretsNamespace string `json:"externalSecretsNamespace,omitempty"`
+	// Optional. Name of a Secret in the pattern namespace holding a values-secret.yaml whose
+	// entries seed the store once it is ready
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ValuesSecretName string `json:"valuesSecretName,omitempty"`
+	// Optional. Backend the entries of the secretsRef document are pushed to, either Vault or
+	// Kubernetes. Default: Kubernetes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Backend string `json:"backend,omitempty"`
+}
+
+// ProvisionConfig declares the namespaces, service accounts and role bindings the pattern
+// needs up front. It carries the same fields as the v1alpha1 configuration
+type ProvisionConfig struct {
+	// Namespaces to create
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Service accounts to create
+	ServiceAccounts []ProvisionedServiceAccount `json:"serviceAccounts,omitempty"`
+	// Role bindings granting a cluster role to one of the service accounts inside a namespace
+	RoleBindings []ProvisionedRoleBinding `json:"roleBindings,omitempty"`
+}
+
+// ProvisionedServiceAccount names a service account to create in one of the provisioned
+// namespaces
+type ProvisionedServiceAccount struct {
+	// Name of the service account
+	Name string `json:"name"`
+	// Namespace it lives in
+	Namespace string `json:"namespace"`
+}
+
+// ProvisionedRoleBinding grants a cluster role to a service account inside one namespace
+type ProvisionedRoleBinding struct {
+	// Name of the binding
+	Name string `json:"name"`
+	// Namespace the binding is created in
+	Namespace string `json:"namespace"`
+	// Name of the ClusterRole granted
+	ClusterRole string `json:"clusterRole"`
+	// Service account the role is granted to
+	ServiceAccount ProvisionedServiceAccount `json:"serviceAccount"`
+}
+
+// PatternUpgradeStatus tracks a staged rollout of a changed target revision over the
+// applications of the pattern. It carries the same fields as the v1alpha1 status
+type PatternUpgradeStatus struct {
+	// Revision the applications were running when the rollout started
+	FromRevision string `json:"fromRevision"`
+	// Revision the rollout is moving the applications to
+	ToRevision string `json:"toRevision"`
+	// Time the rollout started; the upgrade timeout counts from here
+	StartTime metav1.Time `json:"startTime"`
+	// Sync wave currently being rolled out
+	Wave int `json:"wave,omitempty"`
+}
+
+// SyncPolicyConfig constrains when the pattern is allowed to sync
+type SyncPolicyConfig struct {
+	// Windows the sync is constrained by, evaluated against the local time of the operator.
+	// An active deny window always blocks; when allow windows are declared, one of them must be
+	// active for the sync to proceed
+	Windows []SyncWindow `json:"windows,omitempty"`
+}
+
+// SyncWindow is one recurring maintenance window
+type SyncWindow struct {
+	// Kind of the window, either allow or deny
+	Kind SyncWindowKind `json:"kind"`
+	// Schedule of the window start in the standard five-field cron syntax, e.g. "0 22 * * *"
+	Schedule string `json:"schedule"`
+	// Duration the window stays open after each scheduled start, e.g. "1h" or "30m"
+	Duration string `json:"duration"`
+}
+
+type SyncWindowKind string
+
+const (
+	// SyncWindowAllow windows permit syncing while they are active
+	SyncWindowAllow SyncWindowKind = "allow"
+	// SyncWindowDeny windows block syncing while they are active
+	SyncWindowDeny SyncWindowKind = "deny"
+)
+
+// MultiClusterConfig selects the ManagedClusters a pattern is propagated to. The hub keeps
+// deploying the pattern locally; each matching spoke additionally receives a copy through a
+// ManifestWork and reconciles it with its own operator
+type MultiClusterConfig struct {
+	// Labels the ManagedClusters must carry; an empty map selects every managed cluster
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// ApplicationSetConfig switches the operator from creating a single clustergroup application to
+// an ApplicationSet fanning out over the declared generators
+type ApplicationSetConfig struct {
+	// Generators producing the template parameters, applied in order
+	Generators []ApplicationSetGenerator `json:"generators"`
+}
+
+// ApplicationSetGenerator declares one generator of the ApplicationSet. Exactly one of the
+// fields must be set
+type ApplicationSetGenerator struct {
+	// Cluster generator producing one application per cluster registered with Argo CD
+	Clusters *ClusterGenerator `json:"clusters,omitempty"`
+	// List generator producing one application per element
+	List *ListGenerator `json:"list,omitempty"`
+	// Git directory generator producing one application per matching directory of the pattern
+	// repository
+	GitDirectories *GitDirectoryGenerator `json:"gitDirectories,omitempty"`
+}
+
+// ClusterGenerator selects the Argo CD managed clusters the pattern fans out to
+type ClusterGenerator struct {
+	// Labels the cluster secrets must carry; an empty map selects every cluster
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// ListGenerator produces one application per element; by convention each element carries at
+// least the "name" and "server" parameters consumed by the template
+type ListGenerator struct {
+	// Elements the applications are templated from
+	Elements []map[string]string `json:"elements"`
+}
+
+// GitDirectoryGenerator produces one application per directory matching one of the paths
+type GitDirectoryGenerator struct {
+	// Repository holding the directories. Defaults to the primary repository of the pattern
+	RepoURL string `json:"repoURL,omitempty"`
+	// Revision to scan. Defaults to the target revision of the primary repository
+	Revision string `json:"revision,omitempty"`
+	// Directory globs relative to the repository root, e.g. "applications/*"
+	Paths []string `json:"paths"`
+}
+
+// GitSpec declares the repositories the pattern deploys from and how they are monitored
+type GitSpec struct {
+	// Optional. FQDN of the git server if automatic parsing from the repository URLs is broken
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Hostname string `json:"hostname,omitempty"`
+
+	// Repositories monitored for drifts. The first entry is the primary repository the pattern
+	// deploys from; it maps to the top-level originRepo/targetRepo of v1alpha1 while the remaining
+	// entries map to additionalPairs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Repositories []GitRepository `json:"repositories,omitempty"`
+
+	// Optional. Credentials used when talking to the repositories
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Credentials GitCredentials `json:"credentials,omitempty"`
+
+	// Optional. Secret in the namespace of the pattern holding a values-secret.yaml document
+	// whose entries are pushed into the configured secret backend and pushed again on change
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	SecretsRef *SecretReference `json:"secretsRef,omitempty"`
+
+	// Optional. API dialect of the forge hosting the repositories, one of GitHub, GitLab, Gitea
+	// or Bitbucket. Auto-detected for the public github.com, gitlab.com and bitbucket.org hosts;
+	// self-hosted instances must declare it
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Forge ForgeType `json:"forge,omitempty"`
+
+	// Optional. TLS settings applied when connecting to the repositories
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	TLS TLSConfig `json:"tls,omitempty"`
+
+	// Optional. Proxy applied to outbound connections made for the repositories of this pattern,
+	// overriding the operator-level proxy taken from the conventional HTTP_PROXY, HTTPS_PROXY and
+	// NO_PROXY environment variables
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Proxy ProxyConfig `json:"proxy,omitempty"`
+
+	// Optional. Settings steering how drifts between the origin and target repositories are
+	// detected and reported
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	Drift DriftSpec `json:"drift,omitempty"`
+
+	// Optional. Seconds a staged rollout of a changed target revision waits for a degraded
+	// application to recover before rolling the revision back. Default: 600
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	UpgradeTimeoutSeconds int `json:"upgradeTimeoutSeconds,omitempty"`
+
+	// Optional. Seconds applications may stay Degraded or OutOfSync on a newly applied revision
+	// before they are reverted to the last known-good revision. Default: 600
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	RollbackDeadlineSeconds int `json:"rollbackDeadlineSeconds,omitempty"`
+}
+
+// GitRepository declares one origin/target repository pair monitored for drifts
+type GitRepository struct {
+	// Name identifying the pair in status entries and log lines. Empty for the primary repository
+	Name string `json:"name,omitempty"`
+	// Upstream git repo containing the pattern content. Used when an in-cluster fork points to an
+	// upstream pattern repository
+	OriginRepo string `json:"originRepo,omitempty"`
+	// Branch, tag or commit in the origin repository. Does not support short-sha's. Default: HEAD
+	OriginRevision string `json:"originRevision,omitempty"`
+	// Git repo containing the pattern content to deploy
+	TargetRepo string `json:"targetRepo"`
+	// Branch, tag or commit in the target repository. Does not support short-sha's. Default: HEAD
+	TargetRevision string `json:"targetRevision,omitempty"`
+}
+
+// GitCredentials references the secrets holding the credentials used when talking to the
+// repositories of a pattern
+type GitCredentials struct {
+	// Optional. Secret in the namespace of the pattern holding the credentials used to list the
+	// origin and target remotes during drift checks. For SSH remotes the Secret carries the
+	// private key under "sshPrivateKey", the remote user under "sshUser" (default "git") and
+	// optionally the host keys under "known_hosts". For HTTPS remotes the Secret carries the
+	// token under "token" and optionally the user under "username"
+	ReadFrom *SecretReference `json:"readFrom,omitempty"`
+	// Optional. Secret in the namespace of the pattern holding credentials with write access to
+	// the target repository, used to push fast-forward updates when drift.autoSync is enabled.
+	// Uses the same keys as readFrom
+	WriteFrom *SecretReference `json:"writeFrom,omitempty"`
+}
+
+// SecretReference names a Secret in the namespace of the pattern
+type SecretReference struct {
+	// Name of the Secret
+	Name string `json:"name"`
+}
+
+// TLSConfig declares the TLS settings applied when connecting to the repositories of a pattern
+type TLSConfig struct {
+	// Optional. Secret in the namespace of the pattern holding additional CA certificates,
+	// PEM-encoded under "ca.crt", trusted when connecting to the repositories of this pattern.
+	// Complements the operator-level bundle read from the file named by the DRIFT_CA_BUNDLE
+	// environment variable
+	CABundleFrom *SecretReference `json:"caBundleFrom,omitempty"`
+	// Optional. Skip TLS certificate validation, for lab environments without a usable CA. A
+	// Warning event is emitted while the setting is in effect. Default: False
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// DriftSpec groups the settings steering how drifts between the origin and target repositories
+// are detected and reported. The individual fields keep the semantics of their v1alpha1
+// counterparts on GitConfig
+type DriftSpec struct {
+	// Interval in seconds to poll for drifts between origin and target repositories. A value of
+	// -1 disables drift monitoring for this pattern. Default: 180 seconds
+	PollIntervalSeconds int `json:"pollIntervalSeconds,omitempty"`
+
+	// Align drift checks to wall-clock boundaries that are a multiple of the polling interval
+	// instead of counting the interval from the moment the pattern was reconciled. Default: False
+	AlignChecks bool `json:"alignChecks,omitempty"`
+
+	// Pause drift monitoring without dropping the rest of the git configuration. Default: False
+	Paused bool `json:"paused,omitempty"`
+
+	// Automatically fast-forward the target repository to the origin commit when drift is
+	// detected, instead of only reporting it. Requires credentials.writeFrom. Default: False
+	AutoSync bool `json:"autoSync,omitempty"`
+
+	// Optional. Behavior when a monitored repository redirects to a different host. "Follow"
+	// raises an informational condition and continues the check, "Refuse" fails the check until
+	// the URL is updated. An empty value disables redirect detection
+	RedirectPolicy RedirectPolicyType `json:"redirectPolicy,omitempty"`
+
+	// Optional. Branch selection policy when a repository does not advertise a symbolic HEAD and
+	// no revision is pinned. Default: Error
+	HeadlessPolicy HeadlessPolicyType `json:"headlessPolicy,omitempty"`
+
+	// Optional. Ordered list of branch names to try when HeadlessPolicy is "PreferenceList"
+	HeadBranchPreferences []string `json:"headBranchPreferences,omitempty"`
+
+	// Optional. Seconds after a new origin commit during which a hash mismatch with the target is
+	// reported as pending synchronization instead of drift. A value of 0 disables the epsilon
+	SyncEpsilonSeconds int `json:"syncEpsilonSeconds,omitempty"`
+
+	// Optional. Fixed branch-name mapping for fork workflows where a target branch tracks an
+	// origin branch with a different name
+	BranchMapping BranchMapping `json:"branchMapping,omitempty"`
+
+	// Optional. Semantics used to compare the resolved origin and target references during drift
+	// checks. Currently only "hash" is supported. Default: hash
+	ComparisonMode string `json:"comparisonMode,omitempty"`
+
+	// Optional. Glob filter selecting the target branches to evaluate for drift, e.g. "env/*"
+	BranchFilter string `json:"branchFilter,omitempty"`
+
+	// Optional. Paths within the repositories that the pattern actually consumes. When set, a
+	// hash mismatch only counts as drift if the trees under at least one of the paths differ
+	PathFilters []string `json:"pathFilters,omitempty"`
+
+	// Optional. List only the origin remote on each drift check and compare it against the cached
+	// target listing. Default: False
+	CacheTargetListing bool `json:"cacheTargetListing,omitempty"`
+
+	// Optional. Maximum age in seconds of the cached target listing before the target is listed
+	// again. Only meaningful with CacheTargetListing. Default: 600
+	TargetStalenessSeconds int `json:"targetStalenessSeconds,omitempty"`
+
+	// Optional. Include the previous drift state and how long it lasted in the message of the
+	// condition raised on a transition. Default: False
+	DetailedTransitionMessages bool `json:"detailedTransitionMessages,omitempty"`
+
+	// Optional. Verify on each check that origin and target are genuinely related repositories by
+	// comparing the root commits of their histories. Default: False
+	VerifyRepositoryIdentity bool `json:"verifyRepositoryIdentity,omitempty"`
+
+	// Optional. Origin branch whose history the commit resolved for the target must belong to
+	TrustedOriginBranch string `json:"trustedOriginBranch,omitempty"`
+
+	// Optional. How the polling interval adapts while checks for the pair keep failing. "Shrink"
+	// probes more often, "Grow" backs off; an empty value keeps the interval fixed
+	ErrorIntervalPolicy ErrorIntervalPolicyType `json:"errorIntervalPolicy,omitempty"`
+
+	// Optional. Upper bound in seconds on the interval reached through the "Grow" policy
+	ErrorBackoffCapSeconds int `json:"errorBackoffCapSeconds,omitempty"`
+
+	// Optional. Maximum random jitter in seconds added to the interval while checks keep failing
+	ErrorBackoffJitterSeconds int `json:"errorBackoffJitterSeconds,omitempty"`
+
+	// Optional. Allowed clock skew in seconds between the operator and the git servers
+	ClockSkewToleranceSeconds int `json:"clockSkewToleranceSeconds,omitempty"`
+
+	// Optional. Minimum number of commits the target must lag behind the origin before the drift
+	// condition flips to GitOutOfSync
+	MinCommitsBehind int `json:"minCommitsBehind,omitempty"`
+
+	// Optional. Number of consecutive checks that must find the repositories drifted before the
+	// condition flips to GitOutOfSync
+	SustainedChecks int `json:"sustainedChecks,omitempty"`
+
+	// Optional. Fetch both references into an in-memory repository during drift checks so the
+	// ahead/behind commit counts and the merge base can be computed and published in the status
+	ComputeMergeBase bool `json:"computeMergeBase,omitempty"`
+
+	// Optional. Reference to an in-cluster resource field publishing the revision the pattern
+	// should be running. When set, drift is evaluated between origin HEAD and the revision read
+	// from the field instead of the target repository references
+	RevisionFrom *RevisionFieldReference `json:"revisionFrom,omitempty"`
+
+	// Optional. URL of an external service returning the revision the pattern should be running
+	ExternalRevisionEndpoint string `json:"externalRevisionEndpoint,omitempty"`
+
+	// Optional. Name of a GitHub environment whose latest deployment records the deployed commit
+	GithubDeploymentEnvironment string `json:"githubDeploymentEnvironment,omitempty"`
+}
+
+// RevisionFieldReference identifies a field on an in-cluster resource holding the revision a
+// pattern should be running
+type RevisionFieldReference struct {
+	// API version of the referenced resource
+	APIVersion string `json:"apiVersion"`
+	// Kind of the referenced resource
+	Kind string `json:"kind"`
+	// Name of the referenced resource
+	Name string `json:"name"`
+	// Namespace of the referenced resource. Defaults to the namespace of the pattern
+	Namespace string `json:"namespace,omitempty"`
+	// Dot-separated path to the field holding the revision, e.g. status.revision
+	FieldPath string `json:"fieldPath"`
+}
+
+// BranchMapping pairs an origin branch with the target branch meant to track it. Both names must
+// be set for the mapping to take effect
+type BranchMapping struct {
+	// Branch in the origin repository
+	Origin string `json:"origin,omitempty"`
+	// Branch in the target repository tracking the origin branch
+	Target string `json:"target,omitempty"`
+}
+
+// ProxyConfig declares the proxy used for outbound HTTP connections, with the semantics of the
+// conventional HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+type ProxyConfig struct {
+	// URL of the proxy used for plain http connections
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// URL of the proxy used for https connections
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// Comma-separated host names and domain suffixes connected to directly, bypassing the proxy.
+	// A single "*" bypasses the proxy for every host
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+type HeadlessPolicyType string
+
+const (
+	// HeadlessError fails the drift check when the repository advertises no symbolic HEAD (default)
+	HeadlessError HeadlessPolicyType = "Error"
+	// HeadlessLexicalFirst selects the lexically-first branch
+	HeadlessLexicalFirst HeadlessPolicyType = "LexicalFirst"
+	// HeadlessPreferenceList selects the first branch present in HeadBranchPreferences
+	HeadlessPreferenceList HeadlessPolicyType = "PreferenceList"
+	// HeadlessConditionError raises a GitConfigurationError condition in addition to failing the check
+	HeadlessConditionError HeadlessPolicyType = "ConditionError"
+)
+
+type ErrorIntervalPolicyType string
+
+const (
+	// ErrorIntervalShrink halves the interval per consecutive failure, down to a quarter of the
+	// configured value, so recovery is detected quickly
+	ErrorIntervalShrink ErrorIntervalPolicyType = "Shrink"
+	// ErrorIntervalGrow doubles the interval per consecutive failure, up to eight times the
+	// configured value, so a persistently failing pair generates less noise
+	ErrorIntervalGrow ErrorIntervalPolicyType = "Grow"
+)
+
+type RedirectPolicyType string
+
+const (
+	RedirectFollow RedirectPolicyType = "Follow"
+	RedirectRefuse RedirectPolicyType = "Refuse"
+)
+
+// ForgeType identifies the API dialect of the forge hosting a repository
+type ForgeType string
+
+const (
+	ForgeGitHub    ForgeType = "GitHub"
+	ForgeGitLab    ForgeType = "GitLab"
+	ForgeGitea     ForgeType = "Gitea"
+	ForgeBitbucket ForgeType = "Bitbucket"
+)
+
+type GitOpsConfig struct {
+	// Channel to deploy openshift-gitops from. Default: stable
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	OperatorChannel string `json:"operatorChannel,omitempty"`
+	// Source to deploy openshift-gitops from. Default: redhat-operators
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	OperatorSource string `json:"operatorSource,omitempty"`
+
+	// Require manual intervention before Argo will sync new content. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ManualSync bool `json:"manualSync,omitempty"`
+	// Require manual confirmation before installing and upgrading operators. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	ManualApproval bool `json:"manualApproval,omitempty"`
+	// Approve pending install plans on behalf of the user while ManualApproval is set. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	AutoApproveInstallPlans bool `json:"autoApproveInstallPlans,omitempty"`
+
+	// Specific version of openshift-gitops to deploy.  Requires UseCSV=True
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	OperatorCSV string `json:"operatorCSV,omitempty"`
+	// Dangerous. Force a specific version to be installed. Default: False
+	//+operator-sdk:csv:customresourcedefinitions:type=spec
+	UseCSV bool `json:"useCSV,omitempty"`
+}
+
+// PatternStatus defines the observed state of Pattern. It carries the same information as the
+// v1alpha1 status
+type PatternStatus struct {
+	// Generation of the pattern spec this status was computed from, so clients can tell whether
+	// the status already reflects the latest spec edit
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Last action related to the pattern
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	LastStep string `json:"lastStep,omitempty"`
+
+	// Last error encountered by the pattern
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastError string `json:"lastError,omitempty"`
+
+	// Number of updates to the pattern
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Version int `json:"version,omitempty"`
+
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterName string `json:"clusterName,omitempty"`
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	AppClusterDomain string `json:"appClusterDomain,omitempty"`
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterDomain string `json:"clusterDomain,omitempty"`
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterID string `json:"clusterID,omitempty"`
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterPlatform string `json:"clusterPlatform,omitempty"`
+	// Processor architecture the cluster nodes run on, as reported by the API server
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterArchitecture string `json:"clusterArchitecture,omitempty"`
+	// Whether the cluster was installed in FIPS mode
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	FIPSEnabled bool `json:"fipsEnabled,omitempty"`
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterVersion string `json:"clusterVersion,omitempty"`
+	// Milliseconds the last drift check started late relative to its schedule
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastDriftCheckSchedulingDelayMs int64 `json:"lastDriftCheckSchedulingDelayMs,omitempty"`
+	// Milliseconds the last drift check spent comparing the repositories
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastDriftCheckDurationMs int64 `json:"lastDriftCheckDurationMs,omitempty"`
+	// Content hash of the last values-secret.yaml document pushed into the secret backend
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LoadedSecretsVersion string `json:"loadedSecretsVersion,omitempty"`
+	// Target revision the applications of the pattern were last fully rolled out to
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	AppliedRevision string `json:"appliedRevision,omitempty"`
+	// Last revision every application of the pattern reported healthy and synced on
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastKnownGoodRevision string `json:"lastKnownGoodRevision,omitempty"`
+	// Time the applications started reporting Degraded or OutOfSync on the applied revision
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	UnhealthySince *metav1.Time `json:"unhealthySince,omitempty"`
+	// Staged rollout currently in progress, if any
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Upgrade *PatternUpgradeStatus `json:"upgrade,omitempty"`
+	// Attribution of the most recent failed drift check; cleared by the next completed check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastCheckFailure *CheckFailure `json:"lastCheckFailure,omitempty"`
+	// Details of the most recent drift check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	DriftStatus *DriftStatus `json:"driftStatus,omitempty"`
+	// Retry state while drift checks keep failing. Cleared by the first completed check
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	DriftRetryState *DriftRetryState `json:"driftRetryState,omitempty"`
+	// Outcome of the latest check of each additional repository pair
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	PairDriftStatuses []PairDriftStatus `json:"pairDriftStatuses,omitempty"`
+	// Sync and health state of the ArgoCD applications deployed for the pattern
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Applications []PatternApplicationStatus `json:"applications,omitempty"`
+	// Propagation state of the pattern on each spoke cluster it was fanned out to
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Spokes []PatternSpokeStatus `json:"spokes,omitempty"`
+	//+operator-sdk:csv:customerresourcedefinitions:type=conditions
+	Conditions []PatternCondition `json:"conditions,omitempty"`
+}
+
+// PatternApplicationStatus mirrors the sync and health state of one ArgoCD application deployed
+// for the pattern
+type PatternApplicationStatus struct {
+	// Name of the application
+	Name string `json:"name"`
+	// Sync state reported by Argo CD, e.g. Synced or OutOfSync
+	SyncStatus string `json:"syncStatus,omitempty"`
+	// Health state reported by Argo CD, e.g. Healthy or Degraded
+	HealthStatus string `json:"healthStatus,omitempty"`
+	// Revision the application last synced to
+	LastSyncedRevision string `json:"lastSyncedRevision,omitempty"`
+}
+
+// PatternSpokeStatus reports the propagation state of the pattern on one managed cluster
+type PatternSpokeStatus struct {
+	// Name of the managed cluster
+	Name string `json:"name"`
+	// Whether the wrapped pattern has been applied on the spoke, e.g. True or False
+	Applied string `json:"applied,omitempty"`
+	// Whether the applied pattern is still present on the spoke, e.g. True or False
+	Available string `json:"available,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=patt
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Step",type=string,JSONPath=`.status.lastStep`,priority=1
+//+kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.lastError`,priority=2
+
+// Pattern is the Schema for the patterns API
+type Pattern struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PatternSpec   `json:"spec,omitempty"`
+	Status PatternStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PatternList contains a list of Pattern
+type PatternList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Pattern `json:"items"`
+}
+
+// CheckFailure attributes a failed drift check to the remote that caused it
+type CheckFailure struct {
+	// Remote the failure is attributed to, either "origin" or "target"
+	Side string `json:"side"`
+	// Host the failing remote belongs to, when the pattern declares one
+	Host string `json:"host,omitempty"`
+	// Coarse class of the failure, e.g. "authentication", "repository-not-found" or "network"
+	Class string `json:"class"`
+	// Message of the underlying error
+	Message string `json:"message,omitempty"`
+}
+
+// DriftRetryState describes why drift checks are currently delayed
+type DriftRetryState struct {
+	// Number of consecutive failed checks
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// When the next attempt is scheduled
+	NextCheck metav1.Time `json:"nextCheck,omitempty"`
+}
+
+// DriftStatus details the outcome of the most recent drift check
+type DriftStatus struct {
+	// Commit the origin reference resolved to
+	OriginSHA string `json:"originSHA,omitempty"`
+	// Commit the target reference resolved to
+	TargetSHA string `json:"targetSHA,omitempty"`
+	// Number of commits on the origin that the target does not have
+	CommitsBehind int `json:"commitsBehind,omitempty"`
+	// Number of commits on the target that the origin does not have
+	CommitsAhead int `json:"commitsAhead,omitempty"`
+	// Commit the origin and target histories diverged from, when the pattern asked for the merge
+	// base to be computed
+	MergeBase string `json:"mergeBase,omitempty"`
+	// When the check completed
+	LastCheck metav1.Time `json:"lastCheck,omitempty"`
+	// Error the check failed with, empty when it completed
+	LastError string `json:"lastError,omitempty"`
+}
+
+// PairDriftStatus carries the outcome of the latest check of one additional repository pair
+type PairDriftStatus struct {
+	// Name of the pair as declared on the git configuration
+	Name string `json:"name"`
+	// Whether the repositories of the pair drifted apart at the last completed check
+	Drifted bool `json:"drifted"`
+	// When the check completed
+	LastCheck metav1.Time `json:"lastCheck,omitempty"`
+	// Error the check failed with, empty when it completed
+	LastError string `json:"lastError,omitempty"`
+}
+
+type PatternCondition struct {
+	// Type of deployment condition.
+	Type PatternConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status v1.ConditionStatus `json:"status"`
+	// The last time this condition was updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+	// Last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Machine-readable, CamelCase reason for the condition state, aligned with the
+	// metav1.Condition contract
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Generation of the pattern the condition was based upon
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// A human readable message indicating details about the transition.
+	Message string `json:"message,omitempty"`
+}
+
+// PatternConditionType names a condition on a Pattern; the values are shared with v1alpha1
+type PatternConditionType string
+
+func init() {
+	SchemeBuilder.Register(&Pattern{}, &PatternList{})
+}
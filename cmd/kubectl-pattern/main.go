@@ -0,0 +1,332 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-pattern is a kubectl plugin for working with Pattern resources from the command line:
+// installing a pattern, reading its status and drift details, pausing drift monitoring and
+// upgrading it to a new revision
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	// to ensure the plugin works against managed clusters.
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// driftPausedAnnotation mirrors the annotation the operator honours to pause drift monitoring
+// for a single pattern without editing its spec
+const driftPausedAnnotation = "gitops.hybrid-cloud-patterns.io/drift-paused"
+
+var scheme = k8sruntime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(api.AddToScheme(scheme))
+}
+
+func newClient() (client.Client, error) {
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(config, client.Options{Scheme: scheme})
+}
+
+func getPattern(c client.Client, name, namespace string) (*api.Pattern, error) {
+	pattern := &api.Pattern{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, pattern); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("pattern %q not found in namespace %q", name, namespace)
+		}
+		return nil, err
+	}
+	return pattern, nil
+}
+
+// readyState condenses the Ready condition into one table cell
+func readyState(p *api.Pattern) (string, string) {
+	for _, condition := range p.Status.Conditions {
+		if condition.Type == api.Ready {
+			return string(condition.Status), condition.Message
+		}
+	}
+	return "Unknown", ""
+}
+
+func formatTime(t metav1.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func newInstallCmd() *cobra.Command {
+	var repo, revision, clusterGroup, namespace string
+	cmd := &cobra.Command{
+		Use:   "install NAME",
+		Short: "Install a pattern from a git repository",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			pattern := &api.Pattern{
+				ObjectMeta: metav1.ObjectMeta{Name: args[0], Namespace: namespace},
+				Spec: api.PatternSpec{
+					ClusterGroupName: clusterGroup,
+					GitConfig: api.GitConfig{
+						TargetRepo:     repo,
+						TargetRevision: revision,
+					},
+				},
+			}
+			if err := c.Create(context.Background(), pattern); err != nil {
+				return err
+			}
+			fmt.Printf("pattern %s created in namespace %s\n", args[0], namespace)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&repo, "repo", "", "git repository the pattern installs from (required)")
+	cmd.Flags().StringVar(&revision, "revision", "", "branch, tag or commit to deploy")
+	cmd.Flags().StringVar(&clusterGroup, "cluster-group", "default", "cluster group of this cluster")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "openshift-operators", "namespace to create the pattern in")
+	_ = cmd.MarkFlagRequired("repo")
+	return cmd
+}
+
+func newStatusCmd() *cobra.Command {
+	var namespace string
+	cmd := &cobra.Command{
+		Use:   "status [NAME]",
+		Short: "Show the deployment state of the installed patterns",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			var patterns []api.Pattern
+			if len(args) == 1 {
+				pattern, err := getPattern(c, args[0], namespace)
+				if err != nil {
+					return err
+				}
+				patterns = []api.Pattern{*pattern}
+			} else {
+				var list api.PatternList
+				if err := c.List(context.Background(), &list, client.InNamespace(namespace)); err != nil {
+					return err
+				}
+				patterns = list.Items
+			}
+			writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+			fmt.Fprintln(writer, "NAME\tREADY\tSTEP\tREVISION\tMESSAGE")
+			for i := range patterns {
+				pattern := &patterns[i]
+				ready, message := readyState(pattern)
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+					pattern.Name, ready, pattern.Status.LastStep, pattern.Spec.GitConfig.TargetRevision, message)
+			}
+			writer.Flush()
+			if len(args) == 1 {
+				printDetails(&patterns[0])
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "openshift-operators", "namespace to read patterns from")
+	return cmd
+}
+
+// printDetails renders the conditions and per-application state of a single pattern below the
+// summary row
+func printDetails(p *api.Pattern) {
+	if len(p.Status.Conditions) > 0 {
+		fmt.Println("\nConditions:")
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "TYPE\tSTATUS\tLAST UPDATE\tMESSAGE")
+		for _, condition := range p.Status.Conditions {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+				condition.Type, condition.Status, formatTime(condition.LastUpdateTime), condition.Message)
+		}
+		writer.Flush()
+	}
+	if len(p.Status.Applications) > 0 {
+		fmt.Println("\nApplications:")
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAME\tSYNC\tHEALTH\tREVISION")
+		for _, app := range p.Status.Applications {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", app.Name, app.SyncStatus, app.HealthStatus, app.LastSyncedRevision)
+		}
+		writer.Flush()
+	}
+}
+
+func newDriftCmd() *cobra.Command {
+	var namespace string
+	cmd := &cobra.Command{
+		Use:   "drift [NAME]",
+		Short: "Show the git drift state of the installed patterns",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			var patterns []api.Pattern
+			if len(args) == 1 {
+				pattern, err := getPattern(c, args[0], namespace)
+				if err != nil {
+					return err
+				}
+				patterns = []api.Pattern{*pattern}
+			} else {
+				var list api.PatternList
+				if err := c.List(context.Background(), &list, client.InNamespace(namespace)); err != nil {
+					return err
+				}
+				patterns = list.Items
+			}
+			writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+			fmt.Fprintln(writer, "NAME\tPAIR\tDRIFTED\tBEHIND\tAHEAD\tLAST CHECK\tERROR")
+			for i := range patterns {
+				pattern := &patterns[i]
+				drift := pattern.Status.DriftStatus
+				if drift == nil {
+					fmt.Fprintf(writer, "%s\t%s\t%s\t\t\t%s\t%s\n", pattern.Name, "-", "-", "-", "not monitored")
+					continue
+				}
+				drifted := pattern.Status.DriftStatus.OriginSHA != pattern.Status.DriftStatus.TargetSHA
+				fmt.Fprintf(writer, "%s\t%s\t%t\t%d\t%d\t%s\t%s\n", pattern.Name, "-", drifted,
+					drift.CommitsBehind, drift.CommitsAhead, formatTime(drift.LastCheck), drift.LastError)
+				for _, pair := range pattern.Status.PairDriftStatuses {
+					fmt.Fprintf(writer, "%s\t%s\t%t\t\t\t%s\t%s\n", pattern.Name, pair.Name, pair.Drifted,
+						formatTime(pair.LastCheck), pair.LastError)
+				}
+			}
+			writer.Flush()
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "openshift-operators", "namespace to read patterns from")
+	return cmd
+}
+
+func newPauseCmd() *cobra.Command {
+	var namespace string
+	var resume bool
+	cmd := &cobra.Command{
+		Use:   "pause NAME",
+		Short: "Pause drift monitoring for a pattern (--resume restores it)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			pattern, err := getPattern(c, args[0], namespace)
+			if err != nil {
+				return err
+			}
+			if resume {
+				delete(pattern.Annotations, driftPausedAnnotation)
+			} else {
+				if pattern.Annotations == nil {
+					pattern.Annotations = map[string]string{}
+				}
+				pattern.Annotations[driftPausedAnnotation] = "true"
+			}
+			if err := c.Update(context.Background(), pattern); err != nil {
+				return err
+			}
+			if resume {
+				fmt.Printf("drift monitoring resumed for %s\n", args[0])
+			} else {
+				fmt.Printf("drift monitoring paused for %s\n", args[0])
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "openshift-operators", "namespace of the pattern")
+	cmd.Flags().BoolVar(&resume, "resume", false, "resume a paused pattern instead of pausing it")
+	return cmd
+}
+
+func newUpgradeCmd() *cobra.Command {
+	var namespace, revision string
+	cmd := &cobra.Command{
+		Use:   "upgrade NAME",
+		Short: "Move a pattern to a new revision of its repository",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			pattern, err := getPattern(c, args[0], namespace)
+			if err != nil {
+				return err
+			}
+			if pattern.Spec.GitConfig.TargetRevision == revision {
+				fmt.Printf("pattern %s is already at revision %s\n", args[0], revision)
+				return nil
+			}
+			pattern.Spec.GitConfig.TargetRevision = revision
+			if err := c.Update(context.Background(), pattern); err != nil {
+				return err
+			}
+			fmt.Printf("pattern %s moved to revision %s\n", args[0], revision)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "openshift-operators", "namespace of the pattern")
+	cmd.Flags().StringVar(&revision, "revision", "", "branch, tag or commit to move to (required)")
+	_ = cmd.MarkFlagRequired("revision")
+	return cmd
+}
+
+func main() {
+	root := &cobra.Command{
+		Use:           "kubectl-pattern",
+		Short:         "Work with Hybrid Cloud Patterns from the command line",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.AddCommand(newInstallCmd(), newStatusCmd(), newDriftCmd(), newPauseCmd(), newUpgradeCmd())
+	if err := root.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
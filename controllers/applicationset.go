@@ -0,0 +1,196 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// The vendored ArgoCD client predates the typed ApplicationSet API, so the ApplicationSet is
+// managed as an unstructured object through the dynamic client
+var applicationSetGVR = schema.GroupVersionResource{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "applicationsets",
+}
+
+// newApplicationSetGenerators renders the generators declared on the pattern into the ArgoCD
+// ApplicationSet shapes. The second return reports whether a git directory generator is present,
+// which changes how the template names and sources the applications
+func newApplicationSetGenerators(p api.Pattern) ([]interface{}, bool) {
+	var generators []interface{}
+	hasGitGenerator := false
+
+	for _, gen := range p.Spec.ApplicationSet.Generators {
+		switch {
+		case gen.Clusters != nil:
+			clusters := map[string]interface{}{}
+			if len(gen.Clusters.MatchLabels) > 0 {
+				labels := map[string]interface{}{}
+				for key, value := range gen.Clusters.MatchLabels {
+					labels[key] = value
+				}
+				clusters["selector"] = map[string]interface{}{"matchLabels": labels}
+			}
+			generators = append(generators, map[string]interface{}{"clusters": clusters})
+
+		case gen.List != nil:
+			var elements []interface{}
+			for _, element := range gen.List.Elements {
+				fields := map[string]interface{}{}
+				for key, value := range element {
+					fields[key] = value
+				}
+				elements = append(elements, fields)
+			}
+			generators = append(generators, map[string]interface{}{
+				"list": map[string]interface{}{"elements": elements},
+			})
+
+		case gen.GitDirectories != nil:
+			hasGitGenerator = true
+			repoURL := gen.GitDirectories.RepoURL
+			if repoURL == "" {
+				repoURL = p.Spec.GitConfig.TargetRepo
+			}
+			revision := gen.GitDirectories.Revision
+			if revision == "" {
+				revision = p.Spec.GitConfig.TargetRevision
+			}
+			var directories []interface{}
+			for _, path := range gen.GitDirectories.Paths {
+				directories = append(directories, map[string]interface{}{"path": path})
+			}
+			generators = append(generators, map[string]interface{}{
+				"git": map[string]interface{}{
+					"repoURL":     repoURL,
+					"revision":    revision,
+					"directories": directories,
+				},
+			})
+		}
+	}
+	return generators, hasGitGenerator
+}
+
+// newApplicationSet renders the ApplicationSet for the pattern. The template reuses the
+// clustergroup application source so the generated applications carry the same value files and
+// parameters; cluster and list generators deploy it to the generated cluster while git directory
+// generators deploy each matched directory in-cluster
+func newApplicationSet(p api.Pattern) (error, *unstructured.Unstructured) {
+	base := newApplication(p)
+	generators, hasGitGenerator := newApplicationSetGenerators(p)
+
+	nameSuffix := "{{name}}"
+	destination := base.Spec.Destination
+	destination.Name = ""
+	destination.Server = "{{server}}"
+	if hasGitGenerator {
+		nameSuffix = "{{path.basename}}"
+		destination = base.Spec.Destination
+		base.Spec.Source.Path = "{{path}}"
+	}
+
+	source, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&base.Spec.Source)
+	if err != nil {
+		return err, nil
+	}
+	destinationMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&destination)
+	if err != nil {
+		return err, nil
+	}
+
+	templateSpec := map[string]interface{}{
+		"project":     base.Spec.Project,
+		"source":      source,
+		"destination": destinationMap,
+	}
+	if base.Spec.SyncPolicy != nil {
+		syncPolicy, err := runtime.DefaultUnstructuredConverter.ToUnstructured(base.Spec.SyncPolicy)
+		if err != nil {
+			return err, nil
+		}
+		templateSpec["syncPolicy"] = syncPolicy
+	}
+
+	appset := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": applicationSetGVR.Group + "/" + applicationSetGVR.Version,
+			"kind":       "ApplicationSet",
+			"metadata": map[string]interface{}{
+				"name":      applicationName(p),
+				"namespace": applicationNamespace,
+			},
+			"spec": map[string]interface{}{
+				"generators": generators,
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name": fmt.Sprintf("%s-%s", applicationName(p), nameSuffix),
+						// carry the pattern label so the generated applications are found by
+						// listPatternApplications for the status roll-up
+						"labels": map[string]interface{}{"pattern": applicationName(p)},
+					},
+					"spec": templateSpec,
+				},
+			},
+		},
+	}
+	return nil, &appset
+}
+
+func getApplicationSet(client dynamic.Interface, name string) (error, *unstructured.Unstructured) {
+	if appset, err := client.Resource(applicationSetGVR).Namespace(applicationNamespace).Get(context.Background(), name, metav1.GetOptions{}); err != nil {
+		return err, nil
+	} else {
+		return nil, appset
+	}
+}
+
+func createApplicationSet(client dynamic.Interface, appset *unstructured.Unstructured) error {
+	_, err := client.Resource(applicationSetGVR).Namespace(applicationNamespace).Create(context.Background(), appset, metav1.CreateOptions{})
+	return err
+}
+
+func updateApplicationSet(client dynamic.Interface, target, current *unstructured.Unstructured) (error, bool) {
+	if current == nil {
+		return fmt.Errorf("current applicationset was nil"), false
+	} else if target == nil {
+		return fmt.Errorf("target applicationset was nil"), false
+	}
+
+	if reflect.DeepEqual(target.Object["spec"], current.Object["spec"]) {
+		return nil, false
+	}
+
+	current.Object["spec"] = target.Object["spec"]
+	_, err := client.Resource(applicationSetGVR).Namespace(applicationNamespace).Update(context.Background(), current, metav1.UpdateOptions{})
+	return err, true
+}
+
+func removeApplicationSet(client dynamic.Interface, name string) error {
+	return client.Resource(applicationSetGVR).Namespace(applicationNamespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -30,6 +31,10 @@ import (
 	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
 )
 
+// syncWaveAnnotation is the standard ArgoCD annotation ordering resources into waves; lower
+// waves are applied before higher ones
+const syncWaveAnnotation = "argocd.argoproj.io/sync-wave"
+
 func newApplicationParameters(p api.Pattern) []argoapi.HelmParameter {
 
 	parameters := []argoapi.HelmParameter{
@@ -70,6 +75,15 @@ func newApplicationParameters(p api.Pattern) []argoapi.HelmParameter {
 			Name:  "global.clusterPlatform",
 			Value: p.Status.ClusterPlatform,
 		},
+		{
+			Name:  "global.clusterArchitecture",
+			Value: p.Status.ClusterArchitecture,
+		},
+		{
+			Name:        "global.fipsEnabled",
+			Value:       fmt.Sprintf("%t", p.Status.FIPSEnabled),
+			ForceString: true,
+		},
 		{
 			Name:  "global.localClusterName",
 			Value: p.Status.ClusterName,
@@ -111,6 +125,11 @@ func newApplicationValueFiles(p api.Pattern) []string {
 		log.Printf("Values file %q added", extra)
 		files = append(files, extra)
 	}
+	// layered value files come last so their values override the earlier layers
+	for _, layered := range p.Spec.ValueFiles {
+		log.Printf("Values file %q added", layered.Path)
+		files = append(files, layered.Path)
+	}
 	return files
 }
 
@@ -204,6 +223,12 @@ func newApplication(p api.Pattern) *argoapi.Application {
 		Spec: spec,
 	}
 
+	// order the application relative to others sharing the cluster through the usual ArgoCD
+	// sync-wave annotation
+	if p.Spec.SyncWave != 0 {
+		app.ObjectMeta.Annotations = map[string]string{syncWaveAnnotation: strconv.Itoa(p.Spec.SyncWave)}
+	}
+
 	controllerutil.AddFinalizer(&app, argoapi.ForegroundPropagationPolicyFinalizer)
 	return &app
 
@@ -236,7 +261,7 @@ func updateApplication(client argoclient.Interface, target, current *argoapi.App
 		return fmt.Errorf("target application was nil"), false
 	}
 
-	if compareSource(target.Spec.Source, current.Spec.Source) {
+	if compareSource(target.Spec.Source, current.Spec.Source) && compareSyncAutomation(target.Spec.SyncPolicy, current.Spec.SyncPolicy) {
 		return nil, false
 	}
 
@@ -248,10 +273,54 @@ func updateApplication(client argoclient.Interface, target, current *argoapi.App
 	return err, true
 }
 
+// listPatternApplications returns the applications labelled for the pattern, covering both the
+// single clustergroup application and the fan-out generated from an ApplicationSet
+func listPatternApplications(client argoclient.Interface, pattern string) (error, []*argoapi.Application) {
+	apps, err := client.ArgoprojV1alpha1().Applications(applicationNamespace).List(context.Background(),
+		metav1.ListOptions{LabelSelector: fmt.Sprintf("pattern=%s", pattern)})
+	if err != nil {
+		return err, nil
+	}
+	found := make([]*argoapi.Application, 0, len(apps.Items))
+	for i := range apps.Items {
+		found = append(found, &apps.Items[i])
+	}
+	return nil, found
+}
+
 func removeApplication(client argoclient.Interface, name string) error {
 	return client.ArgoprojV1alpha1().Applications(applicationNamespace).Delete(context.Background(), name, metav1.DeleteOptions{})
 }
 
+// orphanApplication releases the application from the pattern that created it: the owner
+// reference to the pattern and the cascading-deletion finalizers are dropped, so neither the
+// pattern deletion nor a later removal of the application sweeps away the deployed workloads
+func orphanApplication(client argoclient.Interface, p *api.Pattern, app *argoapi.Application) error {
+	refs := app.OwnerReferences[:0]
+	for _, ref := range app.OwnerReferences {
+		if ref.UID != p.UID {
+			refs = append(refs, ref)
+		}
+	}
+	app.OwnerReferences = refs
+	app.Finalizers = nil
+
+	_, err := client.ArgoprojV1alpha1().Applications(applicationNamespace).Update(context.Background(), app, metav1.UpdateOptions{})
+	return err
+}
+
+// applicationWorkloadNamespaces lists the namespaces the application instantiated, read from its
+// resource status, so teardown can wait for them to be swept away with the application
+func applicationWorkloadNamespaces(app *argoapi.Application) []string {
+	var namespaces []string
+	for _, resource := range app.Status.Resources {
+		if resource.Kind == "Namespace" {
+			namespaces = append(namespaces, resource.Name)
+		}
+	}
+	return namespaces
+}
+
 func compareSource(goal, actual argoapi.ApplicationSource) bool {
 	if goal.RepoURL != actual.RepoURL {
 		log.Printf("RepoURL changed %s -> %s\n", actual.RepoURL, goal.RepoURL)
@@ -271,6 +340,19 @@ func compareSource(goal, actual argoapi.ApplicationSource) bool {
 	return compareHelmSource(*goal.Helm, *actual.Helm)
 
 }
+
+// compareSyncAutomation reports whether the goal and actual sync policies agree on automated
+// sync, so closing or reopening a sync window propagates to the live application
+func compareSyncAutomation(goal, actual *argoapi.SyncPolicy) bool {
+	goalAutomated := goal != nil && goal.Automated != nil
+	actualAutomated := actual != nil && actual.Automated != nil
+	if goalAutomated != actualAutomated {
+		log.Printf("Automated sync changed %t -> %t\n", actualAutomated, goalAutomated)
+		return false
+	}
+	return true
+}
+
 func compareHelmSource(goal, actual argoapi.ApplicationSourceHelm) bool {
 	if !compareHelmValueFiles(goal.ValueFiles, actual.ValueFiles) {
 		return false
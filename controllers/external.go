@@ -0,0 +1,102 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// externalRevisionCacheTTL bounds how long a response from the external resolver service is
+// reused, so repeated checks do not hammer the service while still picking up new blessings
+const externalRevisionCacheTTL = 30 * time.Second
+
+// externalRevisionResolver fetches the revision a pattern should be running from a central
+// "what should be deployed" service exposed over HTTP
+type externalRevisionResolver struct {
+	client   *http.Client
+	cacheTTL time.Duration
+	mutex    sync.Mutex
+	cache    map[string]externalRevisionCacheEntry
+}
+
+type externalRevisionCacheEntry struct {
+	hash    plumbing.Hash
+	expires time.Time
+}
+
+func newExternalRevisionResolver() RevisionResolver {
+	return &externalRevisionResolver{
+		client:   &http.Client{Transport: &http.Transport{Proxy: sharedProxyRegistry.proxyForRequest}},
+		cacheTTL: externalRevisionCacheTTL,
+		cache:    map[string]externalRevisionCacheEntry{}}
+}
+
+// externalRevision is the subset of the resolver service response the resolver consumes
+type externalRevision struct {
+	SHA string `json:"sha"`
+}
+
+// Resolve queries the endpoint configured in the pattern for the blessed revision, identifying
+// the pattern through name and namespace query parameters. Responses are cached briefly and
+// authentication uses the token found in the EXTERNAL_RESOLVER_TOKEN environment variable when
+// present
+func (e *externalRevisionResolver) Resolve(p *api.Pattern) (plumbing.Hash, error) {
+	key := fmt.Sprintf("%s?name=%s&namespace=%s", p.Spec.GitConfig.ExternalRevisionEndpoint, p.Name, p.Namespace)
+	e.mutex.Lock()
+	entry, found := e.cache[key]
+	e.mutex.Unlock()
+	if found && time.Now().Before(entry.expires) {
+		return entry.hash, nil
+	}
+	req, err := http.NewRequest(http.MethodGet, key, nil)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token := os.Getenv("EXTERNAL_RESOLVER_TOKEN"); token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return plumbing.ZeroHash, fmt.Errorf("revision query for %s in %s returned status %d", p.Name, p.Namespace, resp.StatusCode)
+	}
+	var revision externalRevision
+	if err := json.NewDecoder(resp.Body).Decode(&revision); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if revision.SHA == "" {
+		return plumbing.ZeroHash, fmt.Errorf("no revision returned for %s in %s", p.Name, p.Namespace)
+	}
+	hash := plumbing.NewHash(revision.SHA)
+	e.mutex.Lock()
+	e.cache[key] = externalRevisionCacheEntry{hash: hash, expires: time.Now().Add(e.cacheTTL)}
+	e.mutex.Unlock()
+	return hash, nil
+}
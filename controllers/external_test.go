@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+var _ = Describe("External revision resolver", func() {
+
+	var _ = Context("when resolving the blessed revision", func() {
+		var (
+			server   *httptest.Server
+			handler  http.HandlerFunc
+			requests int
+			pattern  *api.Pattern
+			resolver *externalRevisionResolver
+		)
+
+		BeforeEach(func() {
+			requests = 0
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				handler(w, r)
+			}))
+			pattern = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						OriginRepo:               originURL,
+						TargetRepo:               targetURL,
+						ExternalRevisionEndpoint: server.URL}},
+			}
+			resolver = newExternalRevisionResolver().(*externalRevisionResolver)
+			resolver.client = server.Client()
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("returns the SHA blessed by the service for the pattern", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Query().Get("name")).To(Equal(foo))
+				Expect(r.URL.Query().Get("namespace")).To(Equal(defaultNamespace))
+				fmt.Fprintf(w, `{"sha": %q}`, hashCommitMainHead)
+			}
+			hash, err := resolver.Resolve(pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash).To(Equal(plumbing.NewHash(hashCommitMainHead)))
+		})
+
+		It("reuses the cached response for repeated resolutions within the TTL", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"sha": %q}`, hashCommitMainHead)
+			}
+			for i := 0; i < 3; i++ {
+				hash, err := resolver.Resolve(pattern)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hash).To(Equal(plumbing.NewHash(hashCommitMainHead)))
+			}
+			Expect(requests).To(Equal(1))
+		})
+
+		It("does not cache failed resolutions", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				if requests == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				fmt.Fprintf(w, `{"sha": %q}`, hashCommitMainHead)
+			}
+			_, err := resolver.Resolve(pattern)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("returned status 503"))
+			hash, err := resolver.Resolve(pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash).To(Equal(plumbing.NewHash(hashCommitMainHead)))
+			Expect(requests).To(Equal(2))
+		})
+
+		It("errors when the service returns no revision", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{}`)
+			}
+			_, err := resolver.Resolve(pattern)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("no revision returned for %s in %s", foo, defaultNamespace)))
+		})
+	})
+})
@@ -0,0 +1,388 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+//go:generate mockgen -source $GOFILE -package=$GOPACKAGE -destination=mock_$GOFILE
+
+// ForgeClient talks to the API of the forge hosting a repository, for operations the git
+// protocol does not offer. A client is bound to a single repository by its factory
+type ForgeClient interface {
+	// CompareBranches returns how many commits separate two revisions of the repository: behind
+	// counts those only reachable from base, ahead those only reachable from head. Comparing
+	// through the API avoids the in-memory clone the fallback resolver needs
+	CompareBranches(base, head string) (behind, ahead int, err error)
+	// OpenSyncPullRequest opens a pull request proposing the source branch onto the target
+	// branch and returns its URL. The source branch must be visible to the repository; for a
+	// fork use the owner:branch form where the forge supports it
+	OpenSyncPullRequest(sourceBranch, targetBranch, title, body string) (string, error)
+	// PostStatusCheck records a commit status with the given state, context and description on
+	// the commit identified by sha
+	PostStatusCheck(sha, state, statusContext, description string) error
+}
+
+// ForgeClientFactory builds the forge client matching a repository URL. The forge type is taken
+// from the git configuration when declared, otherwise detected from the well-known public hosts
+type ForgeClientFactory interface {
+	NewForgeClient(repoURL string, forge api.ForgeType, token string) (ForgeClient, error)
+}
+
+// forgeRequestTimeout bounds every API call so a stalled forge cannot hold up a check
+const forgeRequestTimeout = 30 * time.Second
+
+type httpForgeClientFactory struct {
+	httpClient *http.Client
+}
+
+func newForgeClientFactory() ForgeClientFactory {
+	return &httpForgeClientFactory{httpClient: &http.Client{
+		Timeout:   forgeRequestTimeout,
+		Transport: &http.Transport{Proxy: sharedProxyRegistry.proxyForRequest}}}
+}
+
+// NewForgeClient parses the repository URL and returns the client speaking the dialect of its
+// forge. An undeclared forge type is detected from the host for the public hosters
+func (f *httpForgeClientFactory) NewForgeClient(repoURL string, forge api.ForgeType, token string) (ForgeClient, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	if forge == "" {
+		forge = detectForge(parsed.Host)
+	}
+	owner, repo, err := splitRepositoryPath(parsed.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive the repository from %s: %w", repoURL, err)
+	}
+	switch forge {
+	case api.ForgeGitHub:
+		apiBase := "https://api.github.com"
+		if parsed.Host != "github.com" {
+			// GitHub Enterprise serves the same API under /api/v3 of the instance host
+			apiBase = parsed.Scheme + "://" + parsed.Host + "/api/v3"
+		}
+		return &githubForgeClient{httpClient: f.httpClient, apiBase: apiBase, owner: owner, repo: repo, token: token}, nil
+	case api.ForgeGitLab:
+		return &gitlabForgeClient{httpClient: f.httpClient, apiBase: parsed.Scheme + "://" + parsed.Host + "/api/v4",
+			project: url.PathEscape(owner + "/" + repo), token: token}, nil
+	case api.ForgeGitea:
+		return &giteaForgeClient{httpClient: f.httpClient, apiBase: parsed.Scheme + "://" + parsed.Host + "/api/v1",
+			owner: owner, repo: repo, token: token}, nil
+	case api.ForgeBitbucket:
+		return &bitbucketForgeClient{httpClient: f.httpClient, apiBase: "https://api.bitbucket.org/2.0",
+			owner: owner, repo: repo, token: token}, nil
+	}
+	return nil, fmt.Errorf("unable to determine the forge type for %s", repoURL)
+}
+
+// detectForge maps the well-known public hosts to their forge type. Self-hosted instances must
+// declare the type in the git configuration
+func detectForge(host string) api.ForgeType {
+	switch host {
+	case "github.com":
+		return api.ForgeGitHub
+	case "gitlab.com":
+		return api.ForgeGitLab
+	case "bitbucket.org":
+		return api.ForgeBitbucket
+	}
+	return ""
+}
+
+// splitRepositoryPath derives the owner and repository name from the path of a repository URL
+func splitRepositoryPath(path string) (owner, repo string, err error) {
+	trimmed := strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(trimmed, "/")
+	if len(parts) < 2 || parts[0] == "" || parts[len(parts)-1] == "" {
+		return "", "", fmt.Errorf("path %q does not name an owner and a repository", path)
+	}
+	// subgrouped projects keep everything but the last segment as the owner, as GitLab allows
+	return strings.Join(parts[:len(parts)-1], "/"), parts[len(parts)-1], nil
+}
+
+// forgeRequest performs one API call, sending the payload as JSON when present and decoding the
+// response into out when asked for. Any status outside the 2xx range is reported as an error
+// carrying the response body
+func forgeRequest(httpClient *http.Client, method, requestURL string, headers map[string]string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+	request, err := http.NewRequest(method, requestURL, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("%s %s returned %s: %s", method, requestURL, response.Status, strings.TrimSpace(string(detail)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(out)
+}
+
+// githubForgeClient speaks the GitHub REST API, covering github.com and GitHub Enterprise
+type githubForgeClient struct {
+	httpClient  *http.Client
+	apiBase     string
+	owner, repo string
+	token       string
+}
+
+func (g *githubForgeClient) headers() map[string]string {
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if g.token != "" {
+		headers["Authorization"] = "token " + g.token
+	}
+	return headers
+}
+
+func (g *githubForgeClient) CompareBranches(base, head string) (int, int, error) {
+	var result struct {
+		BehindBy int `json:"behind_by"`
+		AheadBy  int `json:"ahead_by"`
+	}
+	requestURL := fmt.Sprintf("%s/repos/%s/%s/compare/%s...%s", g.apiBase, g.owner, g.repo, url.PathEscape(base), url.PathEscape(head))
+	if err := forgeRequest(g.httpClient, http.MethodGet, requestURL, g.headers(), nil, &result); err != nil {
+		return 0, 0, err
+	}
+	return result.BehindBy, result.AheadBy, nil
+}
+
+func (g *githubForgeClient) OpenSyncPullRequest(sourceBranch, targetBranch, title, body string) (string, error) {
+	var result struct {
+		HTMLURL string `json:"html_url"`
+	}
+	payload := map[string]string{"title": title, "body": body, "head": sourceBranch, "base": targetBranch}
+	requestURL := fmt.Sprintf("%s/repos/%s/%s/pulls", g.apiBase, g.owner, g.repo)
+	if err := forgeRequest(g.httpClient, http.MethodPost, requestURL, g.headers(), payload, &result); err != nil {
+		return "", err
+	}
+	return result.HTMLURL, nil
+}
+
+func (g *githubForgeClient) PostStatusCheck(sha, state, statusContext, description string) error {
+	payload := map[string]string{"state": state, "context": statusContext, "description": description}
+	requestURL := fmt.Sprintf("%s/repos/%s/%s/statuses/%s", g.apiBase, g.owner, g.repo, sha)
+	return forgeRequest(g.httpClient, http.MethodPost, requestURL, g.headers(), payload, nil)
+}
+
+// gitlabForgeClient speaks the GitLab API, covering gitlab.com and self-hosted instances
+type gitlabForgeClient struct {
+	httpClient *http.Client
+	apiBase    string
+	// project is the URL-encoded full path of the project, subgroups included
+	project string
+	token   string
+}
+
+func (g *gitlabForgeClient) headers() map[string]string {
+	if g.token == "" {
+		return nil
+	}
+	return map[string]string{"PRIVATE-TOKEN": g.token}
+}
+
+// CompareBranches issues one compare per direction, as the GitLab compare endpoint only lists the
+// commits of its to side
+func (g *gitlabForgeClient) CompareBranches(base, head string) (int, int, error) {
+	ahead, err := g.compareCommitCount(base, head)
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := g.compareCommitCount(head, base)
+	if err != nil {
+		return 0, 0, err
+	}
+	return behind, ahead, nil
+}
+
+func (g *gitlabForgeClient) compareCommitCount(from, to string) (int, error) {
+	var result struct {
+		Commits []struct{} `json:"commits"`
+	}
+	requestURL := fmt.Sprintf("%s/projects/%s/repository/compare?from=%s&to=%s", g.apiBase, g.project, url.QueryEscape(from), url.QueryEscape(to))
+	if err := forgeRequest(g.httpClient, http.MethodGet, requestURL, g.headers(), nil, &result); err != nil {
+		return 0, err
+	}
+	return len(result.Commits), nil
+}
+
+func (g *gitlabForgeClient) OpenSyncPullRequest(sourceBranch, targetBranch, title, body string) (string, error) {
+	var result struct {
+		WebURL string `json:"web_url"`
+	}
+	payload := map[string]string{"source_branch": sourceBranch, "target_branch": targetBranch, "title": title, "description": body}
+	requestURL := fmt.Sprintf("%s/projects/%s/merge_requests", g.apiBase, g.project)
+	if err := forgeRequest(g.httpClient, http.MethodPost, requestURL, g.headers(), payload, &result); err != nil {
+		return "", err
+	}
+	return result.WebURL, nil
+}
+
+func (g *gitlabForgeClient) PostStatusCheck(sha, state, statusContext, description string) error {
+	// GitLab names the failed state differently from the other forges
+	if state == "failure" {
+		state = "failed"
+	}
+	payload := map[string]string{"state": state, "context": statusContext, "description": description}
+	requestURL := fmt.Sprintf("%s/projects/%s/statuses/%s", g.apiBase, g.project, sha)
+	return forgeRequest(g.httpClient, http.MethodPost, requestURL, g.headers(), payload, nil)
+}
+
+// giteaForgeClient speaks the Gitea API, always self-hosted
+type giteaForgeClient struct {
+	httpClient  *http.Client
+	apiBase     string
+	owner, repo string
+	token       string
+}
+
+func (g *giteaForgeClient) headers() map[string]string {
+	if g.token == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "token " + g.token}
+}
+
+// CompareBranches issues one compare per direction, as the Gitea compare endpoint only counts the
+// commits of its head side
+func (g *giteaForgeClient) CompareBranches(base, head string) (int, int, error) {
+	ahead, err := g.compareCommitCount(base, head)
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := g.compareCommitCount(head, base)
+	if err != nil {
+		return 0, 0, err
+	}
+	return behind, ahead, nil
+}
+
+func (g *giteaForgeClient) compareCommitCount(base, head string) (int, error) {
+	var result struct {
+		TotalCommits int `json:"total_commits"`
+	}
+	requestURL := fmt.Sprintf("%s/repos/%s/%s/compare/%s...%s", g.apiBase, g.owner, g.repo, url.PathEscape(base), url.PathEscape(head))
+	if err := forgeRequest(g.httpClient, http.MethodGet, requestURL, g.headers(), nil, &result); err != nil {
+		return 0, err
+	}
+	return result.TotalCommits, nil
+}
+
+func (g *giteaForgeClient) OpenSyncPullRequest(sourceBranch, targetBranch, title, body string) (string, error) {
+	var result struct {
+		HTMLURL string `json:"html_url"`
+	}
+	payload := map[string]string{"title": title, "body": body, "head": sourceBranch, "base": targetBranch}
+	requestURL := fmt.Sprintf("%s/repos/%s/%s/pulls", g.apiBase, g.owner, g.repo)
+	if err := forgeRequest(g.httpClient, http.MethodPost, requestURL, g.headers(), payload, &result); err != nil {
+		return "", err
+	}
+	return result.HTMLURL, nil
+}
+
+func (g *giteaForgeClient) PostStatusCheck(sha, state, statusContext, description string) error {
+	payload := map[string]string{"state": state, "context": statusContext, "description": description}
+	requestURL := fmt.Sprintf("%s/repos/%s/%s/statuses/%s", g.apiBase, g.owner, g.repo, sha)
+	return forgeRequest(g.httpClient, http.MethodPost, requestURL, g.headers(), payload, nil)
+}
+
+// bitbucketForgeClient speaks the Bitbucket Cloud API
+type bitbucketForgeClient struct {
+	httpClient  *http.Client
+	apiBase     string
+	owner, repo string
+	token       string
+}
+
+func (b *bitbucketForgeClient) headers() map[string]string {
+	if b.token == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + b.token}
+}
+
+// CompareBranches issues one commit listing per direction, as Bitbucket offers no two-sided
+// compare endpoint. Divergences larger than a page are reported at the page size
+func (b *bitbucketForgeClient) CompareBranches(base, head string) (int, int, error) {
+	ahead, err := b.exclusiveCommitCount(base, head)
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := b.exclusiveCommitCount(head, base)
+	if err != nil {
+		return 0, 0, err
+	}
+	return behind, ahead, nil
+}
+
+func (b *bitbucketForgeClient) exclusiveCommitCount(exclude, include string) (int, error) {
+	var result struct {
+		Values []struct{} `json:"values"`
+	}
+	requestURL := fmt.Sprintf("%s/repositories/%s/%s/commits/%s?exclude=%s&pagelen=100", b.apiBase, b.owner, b.repo, url.PathEscape(include), url.QueryEscape(exclude))
+	if err := forgeRequest(b.httpClient, http.MethodGet, requestURL, b.headers(), nil, &result); err != nil {
+		return 0, err
+	}
+	return len(result.Values), nil
+}
+
+func (b *bitbucketForgeClient) OpenSyncPullRequest(sourceBranch, targetBranch, title, body string) (string, error) {
+	var result struct {
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}
+	payload := map[string]interface{}{
+		"title":       title,
+		"description": body,
+		"source":      map[string]interface{}{"branch": map[string]string{"name": sourceBranch}},
+		"destination": map[string]interface{}{"branch": map[string]string{"name": targetBranch}}}
+	requestURL := fmt.Sprintf("%s/repositories/%s/%s/pullrequests", b.apiBase, b.owner, b.repo)
+	if err := forgeRequest(b.httpClient, http.MethodPost, requestURL, b.headers(), payload, &result); err != nil {
+		return "", err
+	}
+	return result.Links.HTML.Href, nil
+}
+
+func (b *bitbucketForgeClient) PostStatusCheck(sha, state, statusContext, description string) error {
+	// Bitbucket uses upper-case state names
+	switch state {
+	case "failure":
+		state = "FAILED"
+	case "success":
+		state = "SUCCESSFUL"
+	}
+	payload := map[string]string{"state": state, "key": statusContext, "description": description}
+	requestURL := fmt.Sprintf("%s/repositories/%s/%s/commit/%s/statuses/build", b.apiBase, b.owner, b.repo, sha)
+	return forgeRequest(b.httpClient, http.MethodPost, requestURL, b.headers(), payload, nil)
+}
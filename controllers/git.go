@@ -2,19 +2,44 @@ package controllers
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	mathrand "math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	transportclient "github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/go-logr/logr"
 	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+	gossh "golang.org/x/crypto/ssh"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -22,51 +47,242 @@ import (
 
 var (
 	conditionMsgs = map[api.PatternConditionType]string{
-		api.GitOutOfSync: "Git repositories are out of sync",
-		api.GitInSync:    "Git repositories are in sync"}
+		api.GitOutOfSync:             "Git repositories are out of sync",
+		api.GitInSync:                "Git repositories are in sync",
+		api.GitCheckFailed:           "Drift check failed",
+		api.GitDriftMonitoringPaused: "Git drift monitoring is paused"}
 )
 
+// isDriftCondition returns true if the condition type is one of the complementary drift conditions
+func isDriftCondition(conditionType api.PatternConditionType) bool {
+	return conditionType == api.GitInSync || conditionType == api.GitOutOfSync || conditionType == api.GitCheckFailed || conditionType == api.GitDriftMonitoringPaused
+}
+
+// isGitCondition returns true if the condition type is owned by the drift watcher
+func isGitCondition(conditionType api.PatternConditionType) bool {
+	switch conditionType {
+	case api.GitInSync, api.GitOutOfSync, api.GitCheckFailed, api.GitDriftMonitoringPaused, api.GitDefaultBranchChanged, api.GitRepositoryRedirected, api.GitSyncPending, api.GitExternalResolverError, api.GitRevisionSourceError, api.UntrustedTargetCommit, api.GitRepositoryIdentityMismatch, api.GitAutoSyncFailed, api.GitConfigurationError:
+		return true
+	}
+	return false
+}
+
+// comparisonMode selects the semantics used to compare the resolved origin and target references
+type comparisonMode string
+
+const (
+	// compareHash compares the hashes the resolved references point to
+	compareHash comparisonMode = "hash"
+)
+
+// validateComparisonMode resolves the mode selected for a pair, defaulting an empty selector to
+// the hash comparison and rejecting modes this build does not support
+func validateComparisonMode(mode string) (comparisonMode, error) {
+	switch comparisonMode(mode) {
+	case "", compareHash:
+		return compareHash, nil
+	}
+	return "", fmt.Errorf("unknown comparison mode %q", mode)
+}
+
 type repositoryPair struct {
-	gitClient            GitClient
-	kClient              client.Client
-	name, namespace      string
-	interval             time.Duration
+	gitClient           GitClient
+	hostResolver        HostResolver
+	revisionResolver    RevisionResolver
+	externalResolver    RevisionResolver
+	kubeResolver        RevisionResolver
+	commitTimeResolver  CommitTimeResolver
+	ancestryChecker     AncestryChecker
+	identityResolver    IdentityResolver
+	treeComparer        TreeComparer
+	divergenceResolver  DivergenceResolver
+	distanceResolver    DistanceResolver
+	credentialsResolver CredentialsResolver
+	remediator          DriftRemediator
+	forgeFactory        ForgeClientFactory
+	recorder            record.EventRecorder
+	kClient             client.Client
+	name, namespace     string
+	interval            time.Duration
+	// name of the additional pair of the pattern this entry tracks, as declared on the git
+	// configuration; empty for the primary pair
+	pairName string
+	// true when this entry tracks a GitDriftCheck resource instead of a Pattern; outcomes are
+	// reported on that resource and pattern conditions are never written
+	driftCheck           bool
 	lastCheck, nextCheck time.Time
+	// align the next check to the next wall-clock boundary that is a multiple of the interval
+	aligned bool
+	// semantics used to compare the resolved references, validated when the pair is added
+	comparisonMode comparisonMode
+	// correlation ID supplied by an external trigger for the next check. Cleared after the check
+	// runs; scheduled checks without one get a generated ID
+	correlationID string
+	logger        logr.Logger
+	// outcome of the last completed check and the number of checks performed so far
+	drifted    bool
+	checkCount int64
+	// how late the last check started relative to nextCheck and how long hasDrifted took
+	schedulingDelay, executionDuration time.Duration
+	// true while a worker is running the check of this pair, so the scheduler neither dispatches
+	// it twice nor schedules a timer for it
+	inFlight bool
+	// error-interval policy read from the pattern and the current run of failed checks
+	errorPolicy         api.ErrorIntervalPolicyType
+	consecutiveFailures int
+	// cap and jitter applied to the interval while checks keep failing, read from the pattern
+	backoffCap, backoffJitter time.Duration
+	// git host read from the pattern during the last check, used to group pairs for host-wide
+	// outage handling
+	host string
+	// when true the pair never mutates cluster state; checks still run and observability still
+	// emits, but conditions are not written
+	readOnly bool
+	// upstream to mirror mappings applied to the repositories before the check runs, shared with
+	// the watcher
+	gitMirrors mirrorMap
+	// true once the warning event about disabled certificate validation has been emitted, so the
+	// event fires when the setting takes effect rather than on every check
+	insecureWarned bool
+	// default branch resolved from HEAD during the previous check for pairs that do not pin a revision
+	originHeadBranch, targetHeadBranch plumbing.ReferenceName
+	// ref listings retained from the last completed check for on-demand debugging
+	lastOriginRefs, lastTargetRefs []*plumbing.Reference
+	// target listing reused across checks while cached target comparison is enabled, together
+	// with the time it was fetched
+	cachedTargetRefs []*plumbing.Reference
+	targetListedAt   time.Time
+	// names of the filtered branches found drifted during the last check when a branch filter is
+	// configured
+	driftedBranches []string
+	// all refs of the target listing pointing at the resolved commit during the last check, and
+	// the deterministic primary among them used for reporting
+	targetMatchedRefs []plumbing.ReferenceName
+	targetPrimaryRef  plumbing.ReferenceName
+	// commits the origin and target references resolved to during the last check, and the number
+	// of commits separating them in each direction
+	originHash, targetHash      plumbing.Hash
+	commitsBehind, commitsAhead int
+	// merge base the resolved commits diverged from, when the pattern asked for it to be computed
+	mergeBase plumbing.Hash
+	// severity thresholds read from the pattern and the current run of drifted checks, used to
+	// debounce the condition without hiding the raw outcome from the drift status
+	minCommitsBehind, sustainedChecks int
+	consecutiveDrifts                 int
 }
 
-func (r repositoryPair) hasDrifted() (bool, error) {
+func (r *repositoryPair) hasDrifted(ctx context.Context) (bool, error) {
 	p := &api.Pattern{}
-	err := r.kClient.Get(context.Background(), types.NamespacedName{Name: r.name, Namespace: r.namespace}, p)
-	if err != nil {
+	var err error
+	if r.driftCheck {
+		// the pair tracks a standalone GitDriftCheck; synthesize a minimal git configuration from
+		// its spec so the comparison below works unchanged
+		check := &api.GitDriftCheck{}
+		if err = r.kClient.Get(ctx, types.NamespacedName{Name: r.name, Namespace: r.namespace}, check); err != nil {
+			return false, err
+		}
+		p.Spec.GitConfig = api.GitConfig{
+			OriginRepo:     check.Spec.OriginRepo,
+			TargetRepo:     check.Spec.TargetRepo,
+			OriginRevision: check.Spec.OriginRevision,
+			TargetRevision: check.Spec.TargetRevision}
+	} else if err = r.kClient.Get(ctx, types.NamespacedName{Name: r.name, Namespace: r.namespace}, p); err != nil {
 		return false, err
 	}
+	if r.pairName != "" {
+		declared := findAdditionalPair(p.Spec.GitConfig.AdditionalPairs, r.pairName)
+		if declared == nil {
+			return false, fmt.Errorf("pair %q is no longer declared for pattern %s in namespace %s", r.pairName, r.name, r.namespace)
+		}
+		// evaluate this pair against its own repositories and revisions; every other knob is
+		// shared with the primary pair of the pattern
+		p.Spec.GitConfig.OriginRepo = declared.OriginRepo
+		p.Spec.GitConfig.TargetRepo = declared.TargetRepo
+		p.Spec.GitConfig.OriginRevision = declared.OriginRevision
+		p.Spec.GitConfig.TargetRevision = declared.TargetRevision
+	}
 	if p.Spec.GitConfig.OriginRepo == "" || p.Spec.GitConfig.TargetRepo == "" {
 		return false, fmt.Errorf("git config does not contain origin and targer repositories")
 	}
+	if len(r.gitMirrors) > 0 {
+		// in a disconnected environment the upstream is unreachable; the whole check, including
+		// any remediation push, runs against the declared mirrors instead
+		p.Spec.GitConfig.OriginRepo = r.gitMirrors.resolve(p.Spec.GitConfig.OriginRepo)
+		p.Spec.GitConfig.TargetRepo = r.gitMirrors.resolve(p.Spec.GitConfig.TargetRepo)
+	}
+	r.errorPolicy = p.Spec.GitConfig.ErrorIntervalPolicy
+	r.minCommitsBehind = p.Spec.GitConfig.DriftMinCommitsBehind
+	r.sustainedChecks = p.Spec.GitConfig.DriftSustainedChecks
+	r.backoffCap = time.Duration(p.Spec.GitConfig.ErrorBackoffCapSeconds) * time.Second
+	r.backoffJitter = time.Duration(p.Spec.GitConfig.ErrorBackoffJitterSeconds) * time.Second
+	r.host = p.Spec.GitConfig.Hostname
+	r.applyProxyConfig(p.Spec.GitConfig)
+	if err = r.applyTLSConfig(ctx, p.Spec.GitConfig); err != nil {
+		return false, err
+	}
+	var auth transport.AuthMethod
+	if p.Spec.GitConfig.CredentialsSecret != "" && r.credentialsResolver != nil {
+		if auth, err = r.credentialsResolver.Resolve(r.namespace, p.Spec.GitConfig.CredentialsSecret); err != nil {
+			return false, err
+		}
+	}
+	if p.Spec.GitConfig.RedirectPolicy != "" && r.hostResolver != nil {
+		if err := r.checkRedirect("origin", p.Spec.GitConfig.OriginRepo, p.Spec.GitConfig.RedirectPolicy); err != nil {
+			return false, err
+		}
+		if err := r.checkRedirect("target", p.Spec.GitConfig.TargetRepo, p.Spec.GitConfig.RedirectPolicy); err != nil {
+			return false, err
+		}
+	}
 	origin := r.gitClient.NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{p.Spec.GitConfig.OriginRepo}})
 	target := r.gitClient.NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{p.Spec.GitConfig.TargetRepo}})
 
-	originRefs, err := origin.List(&git.ListOptions{})
+	originRefs, err := origin.ListContext(ctx, &git.ListOptions{Auth: auth})
 	if err != nil {
-		return false, err
+		return false, &remoteCheckError{side: "origin", host: p.Spec.GitConfig.Hostname, err: err}
 	}
 	if len(originRefs) == 0 {
 		return false, fmt.Errorf("no references found for origin %s", p.Spec.GitConfig.OriginRepo)
 	}
-	targetRefs, err := target.List(&git.ListOptions{})
+	originRefs = relevantRefs(originRefs)
+	originMoved := !sameRefHashes(r.lastOriginRefs, originRefs)
+	targetRefs, err := r.listTarget(ctx, target, p.Spec.GitConfig, originMoved, auth)
 	if err != nil {
 		return false, err
 	}
-	if len(targetRefs) == 0 {
-		return false, fmt.Errorf("no references found for target %s", p.Spec.GitConfig.TargetRepo)
+	r.lastOriginRefs = originRefs
+	r.lastTargetRefs = targetRefs
+	if r.logger.GetSink() != nil && r.logger.V(2).Enabled() {
+		diff := r.refDiff()
+		r.logger.V(2).Info(fmt.Sprintf("ref listing diff for %s in %s: only origin %v, only target %v, changed %v", r.name, r.namespace, diff.OnlyOrigin, diff.OnlyTarget, diff.Changed))
+	}
+	if filter := p.Spec.GitConfig.BranchFilter; filter != "" {
+		driftedBranches, err := driftedFilteredBranches(originRefs, targetRefs, filter)
+		if err != nil {
+			return false, err
+		}
+		r.driftedBranches = driftedBranches
+		if len(driftedBranches) > 0 {
+			if r.logger.GetSink() != nil {
+				r.logger.Info(fmt.Sprintf("branches matching %s have drifted for %s in %s: %s", filter, r.name, r.namespace, strings.Join(driftedBranches, ", ")))
+			}
+			return true, nil
+		}
+		return false, nil
+	}
+	mapping := p.Spec.GitConfig.BranchMapping
+	if (mapping.Origin == "") != (mapping.Target == "") {
+		return false, fmt.Errorf("branch mapping requires both branch names, got origin %q and target %q", mapping.Origin, mapping.Target)
 	}
 	var originRef *plumbing.Reference
 	originRefName := plumbing.HEAD
-	if p.Spec.GitConfig.OriginRevision != "" {
-		originRefName = plumbing.NewBranchReferenceName(p.Spec.GitConfig.OriginRevision)
-		originRef = getReferenceByName(originRefs, originRefName)
-	} else {
-		originRef = getHeadBranch(originRefs)
+	if mapping.Origin != "" {
+		originRefName = plumbing.NewBranchReferenceName(mapping.Origin)
+		originRef = resolveReference(originRefs, originRefName)
+	} else if p.Spec.GitConfig.OriginRevision != "" {
+		originRef, originRefName = resolveRevision(originRefs, p.Spec.GitConfig.OriginRevision)
+	} else if originRef, err = r.resolveRemoteHead(originRefs, p.Spec.GitConfig, "origin", p.Spec.GitConfig.OriginRepo); err != nil {
+		return false, err
 	}
 	if originRef == nil {
 		return false, fmt.Errorf("unable to find %s for origin %s", originRefName, p.Spec.GitConfig.OriginRepo)
@@ -74,250 +290,3182 @@ func (r repositoryPair) hasDrifted() (bool, error) {
 
 	var targetRef *plumbing.Reference
 	targetRefName := plumbing.HEAD
-	if p.Spec.GitConfig.TargetRevision != "" {
-		targetRefName = plumbing.NewBranchReferenceName(p.Spec.GitConfig.TargetRevision)
-		targetRef = getReferenceByName(targetRefs, targetRefName)
-	} else {
-		targetRef = getHeadBranch(targetRefs)
+	if mapping.Target != "" {
+		targetRefName = plumbing.NewBranchReferenceName(mapping.Target)
+		targetRef = resolveReference(targetRefs, targetRefName)
+	} else if p.Spec.GitConfig.TargetRevision != "" {
+		targetRef, targetRefName = resolveRevision(targetRefs, p.Spec.GitConfig.TargetRevision)
+	} else if targetRef, err = r.resolveRemoteHead(targetRefs, p.Spec.GitConfig, "target", p.Spec.GitConfig.TargetRepo); err != nil {
+		return false, err
 	}
 	if targetRef == nil {
 		return false, fmt.Errorf("unable to find %s for target %s", targetRefName, p.Spec.GitConfig.TargetRepo)
 	}
-	return originRef.Hash() != targetRef.Hash(), nil
-
+	if mapping.Origin == "" && p.Spec.GitConfig.OriginRevision == "" {
+		if err := r.trackHeadBranch(&r.originHeadBranch, originRef.Name(), "origin", p.Spec.GitConfig.OriginRepo); err != nil {
+			return false, err
+		}
+	}
+	if mapping.Target == "" && p.Spec.GitConfig.TargetRevision == "" {
+		if err := r.trackHeadBranch(&r.targetHeadBranch, targetRef.Name(), "target", p.Spec.GitConfig.TargetRepo); err != nil {
+			return false, err
+		}
+	}
+	r.targetMatchedRefs = refsAtHash(targetRefs, targetRef.Hash())
+	r.targetPrimaryRef = primaryRef(r.targetMatchedRefs, targetRef.Name(), p.Spec.GitConfig.HeadBranchPreferences)
+	r.originHash, r.targetHash = originRef.Hash(), targetRef.Hash()
+	if p.Spec.GitConfig.TrustedOriginBranch != "" && r.ancestryChecker != nil {
+		// verify the target deploys a commit that was published through the trusted origin branch
+		branch := plumbing.NewBranchReferenceName(p.Spec.GitConfig.TrustedOriginBranch)
+		trusted, err := r.ancestryChecker.InHistory(p.Spec.GitConfig.OriginRepo, branch, targetRef.Hash())
+		if err != nil {
+			return false, err
+		}
+		if !trusted {
+			message := fmt.Sprintf("target commit %s is not part of the history of trusted origin branch %s", targetRef.Hash(), p.Spec.GitConfig.TrustedOriginBranch)
+			if err := r.persistPairCondition(api.UntrustedTargetCommit, message, time.Now()); err != nil {
+				return false, err
+			}
+		}
+	}
+	if p.Spec.GitConfig.VerifyRepositoryIdentity && r.identityResolver != nil {
+		// assert both sides grew from the same initial history, catching targets re-created from scratch
+		originRoots, err := r.identityResolver.RootCommits(p.Spec.GitConfig.OriginRepo, originRef.Name())
+		if err != nil {
+			return false, err
+		}
+		targetRoots, err := r.identityResolver.RootCommits(p.Spec.GitConfig.TargetRepo, targetRef.Name())
+		if err != nil {
+			return false, err
+		}
+		if !shareRootCommit(originRoots, targetRoots) {
+			message := fmt.Sprintf("origin %s and target %s share no root commit", p.Spec.GitConfig.OriginRepo, p.Spec.GitConfig.TargetRepo)
+			if err := r.persistPairCondition(api.GitRepositoryIdentityMismatch, message, time.Now()); err != nil {
+				return false, err
+			}
+		}
+	}
+	if p.Spec.GitConfig.RevisionFrom != nil && r.kubeResolver != nil {
+		// compare origin HEAD against the revision published on the referenced in-cluster resource
+		desired, err := r.kubeResolver.Resolve(p)
+		if err != nil {
+			if cErr := r.persistPairCondition(api.GitRevisionSourceError, err.Error(), time.Now()); cErr != nil {
+				return false, cErr
+			}
+			return false, err
+		}
+		return originRef.Hash() != desired, nil
+	}
+	if p.Spec.GitConfig.ExternalRevisionEndpoint != "" && r.externalResolver != nil {
+		// compare origin HEAD against the revision blessed by the central resolver service
+		blessed, err := r.externalResolver.Resolve(p)
+		if err != nil {
+			if cErr := r.persistPairCondition(api.GitExternalResolverError, err.Error(), time.Now()); cErr != nil {
+				return false, cErr
+			}
+			return false, err
+		}
+		return originRef.Hash() != blessed, nil
+	}
+	if p.Spec.GitConfig.GithubDeploymentEnvironment != "" && r.revisionResolver != nil {
+		// compare origin HEAD against the commit recorded as deployed to the configured environment
+		deployed, err := r.revisionResolver.Resolve(p)
+		if err != nil {
+			return false, err
+		}
+		return originRef.Hash() != deployed, nil
+	}
+	var drifted bool
+	switch r.comparisonMode {
+	case compareHash, "":
+		drifted = originRef.Hash() != targetRef.Hash()
+	default:
+		return false, fmt.Errorf("unsupported comparison mode %q", r.comparisonMode)
+	}
+	r.commitsBehind, r.commitsAhead = 0, 0
+	r.mergeBase = plumbing.ZeroHash
+	if drifted {
+		// counting the divergence enriches the status but never fails the check; an origin clone
+		// that does not hold the target commit simply reports no counts. The forge API is cheaper
+		// than the clone the fallback resolver needs, so it is preferred when available
+		var behind, ahead int
+		var divErr error
+		counted := false
+		if p.Spec.GitConfig.ComputeMergeBase && r.distanceResolver != nil {
+			// the pattern asked for the merge base, which only a repository holding both histories
+			// can answer; the fetch also yields counts that work when the target holds commits the
+			// origin does not
+			var base plumbing.Hash
+			behind, ahead, base, divErr = r.distanceResolver.Distance(p.Spec.GitConfig.OriginRepo, originRef.Name(), p.Spec.GitConfig.TargetRepo, targetRef.Name(), originRef.Hash(), targetRef.Hash())
+			counted = divErr == nil
+			if counted {
+				r.mergeBase = base
+			}
+		}
+		if forge := r.forgeClient(p.Spec.GitConfig.OriginRepo, p.Spec.GitConfig, auth); !counted && forge != nil {
+			// the compare endpoint counts from base to head, so origin-only commits land in its
+			// ahead side and map to how far the target lags behind
+			ahead, behind, divErr = forge.CompareBranches(targetRef.Hash().String(), originRef.Hash().String())
+			counted = divErr == nil
+		}
+		if !counted && r.divergenceResolver != nil {
+			behind, ahead, divErr = r.divergenceResolver.Divergence(p.Spec.GitConfig.OriginRepo, originRef.Name(), originRef.Hash(), targetRef.Hash())
+			counted = divErr == nil
+		}
+		if counted {
+			r.commitsBehind, r.commitsAhead = behind, ahead
+		} else if divErr != nil && r.logger.GetSink() != nil {
+			r.logger.Info(fmt.Sprintf("unable to count the divergence between %s and %s for %s in %s: %s", originRef.Hash(), targetRef.Hash(), r.name, r.namespace, divErr))
+		}
+	}
+	if drifted && len(p.Spec.GitConfig.PathFilters) > 0 && r.treeComparer != nil {
+		// a divergence confined to files the pattern does not consume is not drift
+		differ, err := r.treeComparer.TreesDiffer(p.Spec.GitConfig.OriginRepo, originRef.Name(), p.Spec.GitConfig.TargetRepo, targetRef.Name(), p.Spec.GitConfig.PathFilters)
+		if err != nil {
+			return false, err
+		}
+		drifted = differ
+	}
+	if drifted && p.Spec.GitConfig.SyncEpsilonSeconds > 0 && r.commitTimeResolver != nil {
+		// a fresh commit on origin may simply not have propagated to the target yet; within the
+		// configured epsilon the mismatch is reported as pending synchronization instead of drift
+		committed, err := r.commitTimeResolver.CommitTime(p.Spec.GitConfig.OriginRepo, originRef.Hash(), originRef.Name())
+		if err != nil {
+			return false, err
+		}
+		epsilon := time.Duration(p.Spec.GitConfig.SyncEpsilonSeconds) * time.Second
+		tolerance := time.Duration(p.Spec.GitConfig.ClockSkewToleranceSeconds) * time.Second
+		within, excessiveSkew := withinFreshnessWindow(time.Now(), committed, epsilon, tolerance)
+		if excessiveSkew && r.logger.GetSink() != nil {
+			r.logger.Info(fmt.Sprintf("commit timestamp from origin %s is further in the future than the %s clock skew tolerance", p.Spec.GitConfig.OriginRepo, tolerance))
+		}
+		if within {
+			message := fmt.Sprintf("origin %s moved to %s at %s, waiting for target %s to catch up",
+				p.Spec.GitConfig.OriginRepo, originRef.Hash(), committed.Format(time.RFC3339), p.Spec.GitConfig.TargetRepo)
+			if err := r.persistPairCondition(api.GitSyncPending, message, time.Now()); err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+	}
+	if drifted && p.Spec.GitConfig.Forge != "" && !r.readOnly {
+		// an explicitly declared forge gets the drift surfaced as a status check on the commit
+		// the target resolved to, where fork owners look first
+		if forge := r.forgeClient(p.Spec.GitConfig.TargetRepo, p.Spec.GitConfig, auth); forge != nil {
+			description := fmt.Sprintf("target has drifted from %s", p.Spec.GitConfig.OriginRepo)
+			if sErr := forge.PostStatusCheck(targetRef.Hash().String(), "failure", "patterns-operator/drift", description); sErr != nil && r.logger.GetSink() != nil {
+				r.logger.Info(fmt.Sprintf("unable to post the drift status check on %s for %s in %s: %s", p.Spec.GitConfig.TargetRepo, r.name, r.namespace, sErr))
+			}
+		}
+	}
+	if drifted && p.Spec.GitConfig.AutoSync && r.remediator != nil && !r.readOnly {
+		// remediate instead of only reporting; the drift is still reported for this check and
+		// clears on the next one once the pushed update has landed. Closed sync windows suspend
+		// the push along with the automated application sync
+		if closed, wErr := syncWindowsClosed(p, time.Now()); wErr == nil && closed {
+			if r.logger.GetSink() != nil {
+				r.logger.Info(fmt.Sprintf("sync windows are closed, not auto-syncing target %s for %s in %s", p.Spec.GitConfig.TargetRepo, r.name, r.namespace))
+			}
+		} else if sErr := r.autoSyncTarget(p.Spec.GitConfig, originRef, targetRef); sErr != nil {
+			if r.logger.GetSink() != nil {
+				r.logger.Info(fmt.Sprintf("unable to auto-sync target %s for %s in %s: %s", p.Spec.GitConfig.TargetRepo, r.name, r.namespace, sErr))
+			}
+			if cErr := r.persistPairCondition(api.GitAutoSyncFailed, sErr.Error(), time.Now()); cErr != nil {
+				return false, cErr
+			}
+		} else if r.logger.GetSink() != nil {
+			r.logger.Info(fmt.Sprintf("pushed fast-forward of origin commit %s to target %s for %s in %s", originRef.Hash(), p.Spec.GitConfig.TargetRepo, r.name, r.namespace))
+		}
+	}
+	return drifted, nil
 }
 
-type repositoryPairs []*repositoryPair
-
-func (r repositoryPairs) Len() int {
-	return len(r)
+// absorbCheckResults copies back into the live pair the fields hasDrifted wrote on the working
+// copy during the unlocked network comparison: the spec-derived knobs it refreshed from the
+// pattern and the observations of the completed check. Everything else on the live pair is owned
+// by the scheduler and only ever mutated under the watcher lock, so concurrent sibling checks
+// and interval updates are never clobbered
+func (r *repositoryPair) absorbCheckResults(working *repositoryPair) {
+	r.errorPolicy = working.errorPolicy
+	r.minCommitsBehind = working.minCommitsBehind
+	r.sustainedChecks = working.sustainedChecks
+	r.backoffCap = working.backoffCap
+	r.backoffJitter = working.backoffJitter
+	r.host = working.host
+	r.insecureWarned = working.insecureWarned
+	r.originHeadBranch = working.originHeadBranch
+	r.targetHeadBranch = working.targetHeadBranch
+	r.lastOriginRefs = working.lastOriginRefs
+	r.lastTargetRefs = working.lastTargetRefs
+	r.cachedTargetRefs = working.cachedTargetRefs
+	r.targetListedAt = working.targetListedAt
+	r.driftedBranches = working.driftedBranches
+	r.targetMatchedRefs = working.targetMatchedRefs
+	r.targetPrimaryRef = working.targetPrimaryRef
+	r.originHash = working.originHash
+	r.targetHash = working.targetHash
+	r.commitsBehind = working.commitsBehind
+	r.commitsAhead = working.commitsAhead
+	r.mergeBase = working.mergeBase
 }
 
-func (r repositoryPairs) Less(i, j int) bool {
-	return r[i].nextCheck.Before(r[j].nextCheck)
+// autoSyncTarget pushes the commit the origin resolved to onto the branch the target resolved to,
+// restoring synchronization without user intervention. Only fast-forward updates are attempted;
+// everything else is refused so work present only on the target is never overwritten
+func (r *repositoryPair) autoSyncTarget(gc api.GitConfig, originRef, targetRef *plumbing.Reference) error {
+	if gc.SyncCredentialsSecret == "" {
+		return fmt.Errorf("autoSync requires syncCredentialsSecret to name a secret with write access to the target")
+	}
+	if r.credentialsResolver == nil {
+		return fmt.Errorf("no credentials resolver available to resolve secret %s", gc.SyncCredentialsSecret)
+	}
+	branch := targetRef.Name()
+	if !branch.IsBranch() {
+		branch = r.targetPrimaryRef
+	}
+	if !branch.IsBranch() {
+		return fmt.Errorf("target %s did not resolve to a branch, refusing to push", gc.TargetRepo)
+	}
+	auth, err := r.credentialsResolver.Resolve(r.namespace, gc.SyncCredentialsSecret)
+	if err != nil {
+		return err
+	}
+	err = r.remediator.FastForward(gc.OriginRepo, originRef.Name(), gc.TargetRepo, branch, originRef.Hash(), targetRef.Hash(), auth)
+	if errors.Is(err, errNonFastForward) {
+		// the target holds work of its own; propose the update through the forge instead of
+		// overwriting it, when a forge client is available for the target
+		if forge := r.forgeClient(gc.TargetRepo, gc, auth); forge != nil {
+			title := fmt.Sprintf("Sync with %s", gc.OriginRepo)
+			body := fmt.Sprintf("Automatic drift remediation: fast-forwarding %s to origin commit %s was refused because the branch holds commits of its own.", branch.Short(), originRef.Hash())
+			prURL, prErr := forge.OpenSyncPullRequest(originRef.Name().Short(), branch.Short(), title, body)
+			if prErr != nil {
+				return fmt.Errorf("%s; opening a sync pull request failed as well: %s", err, prErr)
+			}
+			if r.logger.GetSink() != nil {
+				r.logger.Info(fmt.Sprintf("opened sync pull request %s for %s in %s", prURL, r.name, r.namespace))
+			}
+			return nil
+		}
+	}
+	return err
 }
 
-func (r repositoryPairs) Swap(i, j int) {
-	r[i], r[j] = r[j], r[i]
+// forgeClient returns the API client for the forge hosting the repository, or nil when neither
+// the configuration nor the host reveal the forge type. A token carried by basic-auth credentials
+// is passed on so the API calls are authenticated like the git operations
+func (r *repositoryPair) forgeClient(repoURL string, gc api.GitConfig, auth transport.AuthMethod) ForgeClient {
+	if r.forgeFactory == nil {
+		return nil
+	}
+	token := ""
+	if basic, ok := auth.(*githttp.BasicAuth); ok {
+		token = basic.Password
+	}
+	forge, err := r.forgeFactory.NewForgeClient(repoURL, gc.Forge, token)
+	if err != nil {
+		return nil
+	}
+	return forge
 }
 
-type RemoteClient interface {
-	List(o *git.ListOptions) (rfs []*plumbing.Reference, err error)
+// findAdditionalPair returns the declared additional pair with the given name, or nil when the
+// configuration no longer carries it
+func findAdditionalPair(pairs []api.GitRepoPair, name string) *api.GitRepoPair {
+	for i := range pairs {
+		if pairs[i].Name == name {
+			return &pairs[i]
+		}
+	}
+	return nil
 }
 
-type GitClient interface {
-	NewRemoteClient(c *config.RemoteConfig) RemoteClient
+// defaultTargetStaleness bounds how long a cached target listing may be reused while the origin is
+// stable, so a change made directly on the target is never missed indefinitely
+const defaultTargetStaleness = 10 * time.Minute
+
+// listTarget returns the target references to compare against. While cached target comparison is
+// enabled the listing retained from an earlier check is reused as long as the origin has not moved
+// and the listing is younger than the staleness bound; otherwise the remote is listed again
+func (r *repositoryPair) listTarget(ctx context.Context, target RemoteClient, gc api.GitConfig, originMoved bool, auth transport.AuthMethod) ([]*plumbing.Reference, error) {
+	if gc.CacheTargetListing && len(r.cachedTargetRefs) > 0 && !originMoved && time.Since(r.targetListedAt) < targetStaleness(gc) {
+		return r.cachedTargetRefs, nil
+	}
+	targetRefs, err := target.ListContext(ctx, &git.ListOptions{Auth: auth})
+	if err != nil {
+		return nil, &remoteCheckError{side: "target", host: gc.Hostname, err: err}
+	}
+	if len(targetRefs) == 0 {
+		return nil, fmt.Errorf("no references found for target %s", gc.TargetRepo)
+	}
+	targetRefs = relevantRefs(targetRefs)
+	if gc.CacheTargetListing {
+		r.cachedTargetRefs = targetRefs
+		r.targetListedAt = time.Now()
+	}
+	return targetRefs, nil
 }
 
-type gitClient struct {
+// targetStaleness returns the configured bound on the age of a cached target listing
+func targetStaleness(gc api.GitConfig) time.Duration {
+	if gc.TargetStalenessSeconds > 0 {
+		return time.Duration(gc.TargetStalenessSeconds) * time.Second
+	}
+	return defaultTargetStaleness
 }
 
-func newGitClient() GitClient {
-	return &gitClient{}
+// failure classes surfaced on the lastCheckFailure status entry of a pattern
+const (
+	failureClassAuthentication = "authentication"
+	failureClassNotFound       = "repository-not-found"
+	failureClassNetwork        = "network"
+	failureClassUnknown        = "unknown"
+)
+
+// remoteCheckError attributes a failure during a drift check to the remote that caused it
+type remoteCheckError struct {
+	side string
+	host string
+	err  error
 }
 
-func (c *gitClient) NewRemoteClient(config *config.RemoteConfig) RemoteClient {
-	return git.NewRemote(nil, config)
+func (e *remoteCheckError) Error() string {
+	return fmt.Sprintf("%s: %s", e.side, e.err)
 }
 
-type watcher struct {
-	kClient client.Client
-	//endCh is used to notify the watch routine to exit the loop
-	endCh, updateCh chan interface{}
-	repoPairs       repositoryPairs
-	mutex           *sync.Mutex
-	logger          logr.Logger
-	timer           *time.Timer
-	timerCancelled  bool
-	gitClient       GitClient
+func (e *remoteCheckError) Unwrap() error { return e.err }
+
+// attribution renders the failure as the structured entry persisted on the pattern status
+func (e *remoteCheckError) attribution() *api.CheckFailure {
+	return &api.CheckFailure{Side: e.side, Host: e.host, Class: classifyRemoteError(e.err), Message: e.err.Error()}
 }
 
-func newDriftWatcher(kubeClient client.Client, logger logr.Logger, gitClient GitClient) (driftWatcher, chan interface{}) {
-	d := &watcher{
-		kClient:   kubeClient,
-		logger:    logger,
-		repoPairs: repositoryPairs{},
-		endCh:     make(chan interface{}),
-		mutex:     &sync.Mutex{},
-		gitClient: gitClient}
-	return d, d.watch()
+// classifyRemoteError maps a remote failure onto a coarse class so triage can distinguish a
+// credentials problem from a misconfigured repository or an unreachable host
+func classifyRemoteError(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, transport.ErrAuthenticationRequired), errors.Is(err, transport.ErrAuthorizationFailed):
+		return failureClassAuthentication
+	case errors.Is(err, transport.ErrRepositoryNotFound):
+		return failureClassNotFound
+	case errors.As(err, &netErr):
+		return failureClassNetwork
+	}
+	return failureClassUnknown
 }
 
-type driftWatcher interface {
-	add(name, namespace string, interval int) error
-	updateInterval(name, namespace string, interval int) error
-	remove(name, namespace string) error
-	watch() chan interface{}
-	isWatching(name, namespace string) bool
+// attributeCheckError extracts the structured attribution from a failed check, or nil when the
+// failure cannot be pinned on either remote
+func attributeCheckError(err error) *api.CheckFailure {
+	var remoteErr *remoteCheckError
+	if errors.As(err, &remoteErr) {
+		return remoteErr.attribution()
+	}
+	return nil
 }
 
-// isWatching returns true if the pair name,namespace reference is being monitored for drifts, false otherwise
-func (d *watcher) isWatching(name, namespace string) bool {
-	d.mutex.Lock()
-	defer d.mutex.Unlock()
-	for _, item := range d.repoPairs {
-		if item.name == name && item.namespace == namespace {
-			return true
+// sameRefHashes reports whether two listings resolve the same reference names to the same hashes
+func sameRefHashes(a, b []*plumbing.Reference) bool {
+	aHashes := refHashesByName(a)
+	bHashes := refHashesByName(b)
+	if len(aHashes) != len(bHashes) {
+		return false
+	}
+	for name, hash := range aHashes {
+		if bHashes[name] != hash {
+			return false
 		}
 	}
-	return false
+	return true
 }
 
-// add instructs the client to start monitoring for drifts between two repositories
-func (d *watcher) add(name, namespace string, interval int) error {
-	if d.updateCh == nil {
-		return fmt.Errorf("unable to add %s in %s when watch has not yet started", name, namespace)
+// maxRetainedRefs caps the number of references kept from a remote listing so that repositories
+// with very large ref sets do not pin an unbounded amount of memory across concurrent checks
+const maxRetainedRefs = 10000
+
+// relevantRefs reduces a remote listing to the references resolution can actually consume: HEAD,
+// symbolic refs, branches and tags. CI and pull-request refs (e.g. refs/pull/...) are dropped so
+// the full listing can be collected right after the call
+func relevantRefs(refs []*plumbing.Reference) []*plumbing.Reference {
+	result := make([]*plumbing.Reference, 0, len(refs))
+	for _, ref := range refs {
+		if len(result) == maxRetainedRefs {
+			break
+		}
+		if ref.Name() == plumbing.HEAD || ref.Type() == plumbing.SymbolicReference || ref.Name().IsBranch() || ref.Name().IsTag() {
+			result = append(result, ref)
+		}
 	}
-	d.mutex.Lock()
-	defer d.mutex.Unlock()
-	d.stopTimer()
-	pair := repositoryPair{
-		name:      name,
-		namespace: namespace,
-		kClient:   d.kClient,
-		interval:  time.Duration(interval) * time.Second,
-		nextCheck: time.Now().Add(time.Duration(interval) * time.Second),
-		gitClient: d.gitClient}
-	d.repoPairs = append(d.repoPairs, &pair)
-	sort.Sort(d.repoPairs)
-	// Notify of updates
-	d.updateCh <- struct{}{}
-	return nil
+	return result
 }
 
-// update checks if the new interval differs from the stored one and requeues the reference to ensure the new interval is reflected
-func (d *watcher) updateInterval(name, namespace string, interval int) error {
-	if d.updateCh == nil {
-		return fmt.Errorf("unable to update interval for %s in %s when watch has not yet started", name, namespace)
+// resolveRevision locates the reference named by a revision from the pattern spec: a raw commit
+// SHA stands for itself, a fully-qualified name is looked up directly, and a short name is tried
+// as a branch first and as a tag second. The returned name identifies the revision in messages
+// when no reference matches the listing
+func resolveRevision(refs []*plumbing.Reference, revision string) (*plumbing.Reference, plumbing.ReferenceName) {
+	if plumbing.IsHash(revision) {
+		name := plumbing.ReferenceName(revision)
+		return plumbing.NewHashReference(name, plumbing.NewHash(revision)), name
 	}
-	d.mutex.Lock()
-	defer d.mutex.Unlock()
-	for index, item := range d.repoPairs {
-		if item.name == name && item.namespace == namespace {
-			if item.interval != time.Duration(interval)*time.Second {
-				d.stopTimer()
-				d.logger.V(1).Info(fmt.Sprintf("New interval detected for %s in %s: %d second(s)", name, namespace, interval))
-				pair := repositoryPair{
-					name:      name,
-					namespace: namespace,
-					kClient:   d.kClient,
-					interval:  time.Duration(interval) * time.Second,
-					nextCheck: time.Now().Add(time.Duration(interval) * time.Second),
-					gitClient: d.gitClient}
-				d.repoPairs = append(d.repoPairs[:index], d.repoPairs[index+1:]...)
-				d.repoPairs = append(d.repoPairs, &pair)
-				sort.Sort(d.repoPairs)
-				// Notify of updates
-				d.updateCh <- struct{}{}
-				return nil
-			}
-		}
+	if strings.HasPrefix(revision, "refs/") {
+		name := plumbing.ReferenceName(revision)
+		return resolveReference(refs, name), name
 	}
-	return nil
+	name := plumbing.NewBranchReferenceName(revision)
+	if ref := resolveReference(refs, name); ref != nil {
+		return ref, name
+	}
+	if ref := resolveReference(refs, plumbing.NewTagReferenceName(revision)); ref != nil {
+		return ref, ref.Name()
+	}
+	return nil, name
 }
 
-// remove instructs the client to stop monitoring for drifts for the given resource name and namespace
-func (d *watcher) remove(name, namespace string) error {
-	if d.updateCh == nil {
-		return fmt.Errorf("unable to remove %s in %s when watch has not yet started", name, namespace)
+// maxDiffEntries bounds the number of refs reported per category of a ref-set diff so that
+// repositories with very large listings do not flood the log
+const maxDiffEntries = 20
+
+// refSetDiff is the side-by-side difference between the origin and target ref listings of a pair
+type refSetDiff struct {
+	// refs present on only one of the two sides
+	OnlyOrigin, OnlyTarget []string
+	// refs present on both sides but pointing at different hashes, rendered as
+	// "name origin-hash != target-hash"
+	Changed []string
+	// true when entries were dropped to bound the output size
+	Truncated bool
+}
+
+// diffRefSets computes the set difference between two remote listings, categorizing refs present
+// on one side only and refs with differing hashes. Symbolic refs are skipped as they carry no
+// hash of their own
+func diffRefSets(originRefs, targetRefs []*plumbing.Reference) refSetDiff {
+	originHashes := refHashesByName(originRefs)
+	targetHashes := refHashesByName(targetRefs)
+	diff := refSetDiff{}
+	for _, name := range sortedRefNames(originHashes) {
+		targetHash, found := targetHashes[name]
+		switch {
+		case !found:
+			diff.OnlyOrigin = append(diff.OnlyOrigin, name)
+		case targetHash != originHashes[name]:
+			diff.Changed = append(diff.Changed, fmt.Sprintf("%s %s != %s", name, originHashes[name], targetHash))
+		}
 	}
-	d.mutex.Lock()
-	defer d.mutex.Unlock()
-	for index := range d.repoPairs {
-		if name == d.repoPairs[index].name && namespace == d.repoPairs[index].namespace {
-			d.stopTimer()
-			d.repoPairs = append(d.repoPairs[:index], d.repoPairs[index+1:]...)
-			sort.Sort(d.repoPairs)
-			// Notify of updates
-			d.updateCh <- struct{}{}
-			return nil
+	for _, name := range sortedRefNames(targetHashes) {
+		if _, found := originHashes[name]; !found {
+			diff.OnlyTarget = append(diff.OnlyTarget, name)
+		}
+	}
+	for _, entries := range []*[]string{&diff.OnlyOrigin, &diff.OnlyTarget, &diff.Changed} {
+		if len(*entries) > maxDiffEntries {
+			*entries = (*entries)[:maxDiffEntries]
+			diff.Truncated = true
 		}
 	}
-	return fmt.Errorf("unable to find git remote pair for pattern %s in namespace %s", name, namespace)
+	return diff
 }
 
-func (d *watcher) stopTimer() {
-	// if there is an ongoing timer...
-	if d.timer != nil {
-		// ...stop the timer. Any ongoing timer is no longer valid as there are going to be changes to the slice
-		if d.timer.Stop() {
-			// if the timer function is in progress, at this point is waiting to get the lock. Flag timerCancelled as true to notify the routine to exit as soon as it gets the lock and ensure
-			// that the function does not continue executing, as the order of the slice has changed since the function was triggered and got blocked waiting to get the lock
-			d.timerCancelled = true
+// refDiff returns the structured difference between the origin and target listings fetched by the
+// last check, for debugging unexpected resolution outcomes on demand
+func (r *repositoryPair) refDiff() refSetDiff {
+	return diffRefSets(r.lastOriginRefs, r.lastTargetRefs)
+}
+
+func refHashesByName(refs []*plumbing.Reference) map[string]plumbing.Hash {
+	hashes := map[string]plumbing.Hash{}
+	for _, ref := range refs {
+		if ref.Type() != plumbing.HashReference {
+			continue
 		}
+		hashes[ref.Name().String()] = ref.Hash()
 	}
+	return hashes
 }
 
-func (d *watcher) startNewTimer() {
-	d.mutex.Lock()
-	defer d.mutex.Unlock()
-	if len(d.repoPairs) == 0 {
-		return
+func sortedRefNames(hashes map[string]plumbing.Hash) []string {
+	names := make([]string, 0, len(hashes))
+	for name := range hashes {
+		names = append(names, name)
 	}
-	// slice is already sorted from a previous call to Add or Remove or from a previous timer
-	nextPair := d.repoPairs[0]
-	nextInterval := time.Until(nextPair.nextCheck)
-	if time.Now().After(nextPair.nextCheck) {
-		// In case there is an overdue check, which would result in a negative value, we set it to 0 so that it is triggered right away
-		d.logger.V(1).Info(fmt.Sprintf("Next interval is negative, resetting to 0 %s: %s - %s\n", nextInterval.String(), time.Now().String(), nextPair.nextCheck.String()))
-		nextInterval = 0
-	}
-	// start a timer and execute drift check when timer expires
-	d.timer = time.AfterFunc(nextInterval, func() {
-		d.mutex.Lock()
-		defer d.mutex.Unlock()
-		if d.timerCancelled {
-			// timer has been stopped while the routine was waiting for hold the lock. This means that there has been a change in the order of elements in the slice while it was waiting to obtain the lock
-			// reset the timer cancelled field.
-			d.timerCancelled = false
-			return
-		}
-		if len(d.repoPairs) == 0 {
-			d.updateCh <- struct{}{}
-			return
+	sort.Strings(names)
+	return names
+}
+
+// driftedFilteredBranches evaluates drift across every target branch whose short name matches
+// the glob filter, comparing each against the origin reference of the same name. A matched branch
+// missing from the origin counts as drifted. The names of the drifted branches are returned
+// sorted so reporting is deterministic
+func driftedFilteredBranches(originRefs, targetRefs []*plumbing.Reference, filter string) ([]string, error) {
+	originHashes := refHashesByName(originRefs)
+	drifted := []string{}
+	matched := 0
+	for _, ref := range targetRefs {
+		if ref.Type() != plumbing.HashReference || !ref.Name().IsBranch() {
+			continue
 		}
-		pair := d.repoPairs[0]
-		hasDrifted, err := pair.hasDrifted()
+		name := ref.Name().Short()
+		ok, err := path.Match(filter, name)
 		if err != nil {
-			d.logger.Error(err, "found error while detecting drift")
-		} else {
-			conditionType := api.GitInSync
-			if hasDrifted {
-				d.logger.Info(fmt.Sprintf("git repositories have drifted for resource %s in namespace %s", pair.name, pair.namespace))
-				conditionType = api.GitOutOfSync
-			}
-			err := updatePatternConditions(d.kClient, conditionType, pair.name, pair.namespace, time.Now())
-			if err != nil {
-				d.logger.Error(err, fmt.Sprintf("failed to update pattern condition for %s in namespace %s", pair.name, pair.namespace))
+			return nil, fmt.Errorf("invalid branch filter %q: %w", filter, err)
+		}
+		if !ok {
+			continue
+		}
+		matched++
+		if hash, found := originHashes[ref.Name().String()]; !found || hash != ref.Hash() {
+			drifted = append(drifted, name)
+		}
+	}
+	if matched == 0 {
+		return nil, fmt.Errorf("no target branches match filter %q", filter)
+	}
+	sort.Strings(drifted)
+	return drifted, nil
+}
+
+// refsAtHash returns the names of all hash refs in the listing pointing at the given commit,
+// sorted so the result is deterministic regardless of the listing order
+func refsAtHash(refs []*plumbing.Reference, hash plumbing.Hash) []plumbing.ReferenceName {
+	matches := []plumbing.ReferenceName{}
+	for _, ref := range refs {
+		if ref.Type() == plumbing.HashReference && ref.Hash() == hash {
+			matches = append(matches, ref.Name())
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i] < matches[j] })
+	return matches
+}
+
+// primaryRef selects, deterministically, the reference reported for a commit that several refs
+// point at: the first match in the configured head-branch preferences wins, then the ref
+// resolution actually selected, then the lexically-first match
+func primaryRef(matches []plumbing.ReferenceName, resolved plumbing.ReferenceName, preferences []string) plumbing.ReferenceName {
+	for _, preference := range preferences {
+		name := plumbing.NewBranchReferenceName(preference)
+		for _, match := range matches {
+			if match == name {
+				return match
 			}
 		}
-		pair.lastCheck = time.Now()
-		pair.nextCheck = pair.lastCheck.Add(pair.interval)
-		d.repoPairs[0] = pair
-		// recalculate next timer
-		sort.Sort(d.repoPairs)
-		d.updateCh <- struct{}{}
-	})
-	d.logger.V(1).Info(fmt.Sprintf("New timer started for %s in %s to end on %s", nextPair.name, nextPair.namespace, nextPair.nextCheck.String()))
+	}
+	for _, match := range matches {
+		if match == resolved {
+			return match
+		}
+	}
+	if len(matches) > 0 {
+		return matches[0]
+	}
+	return resolved
 }
 
-// watch starts the process of monitoring the drifts. The call returns a channel to be used to manage
-// the closure of the monitoring routine cleanly.
-func (d *watcher) watch() chan interface{} {
-	if d.updateCh != nil {
-		return d.endCh
+// resolveRemoteHead resolves the branch to compare for a remote that does not pin a revision.
+// When the remote advertises no symbolic HEAD the headless policy is applied; with the
+// ConditionError policy a GitConfigurationError condition is raised in addition to the failure
+// reported by the caller
+func (r *repositoryPair) resolveRemoteHead(refs []*plumbing.Reference, gc api.GitConfig, remote, repoURL string) (*plumbing.Reference, error) {
+	if ref := getHeadBranch(refs); ref != nil {
+		return ref, nil
 	}
-	// ready to start processing notifications
+	if ref := resolveHeadlessBranch(refs, gc); ref != nil {
+		return ref, nil
+	}
+	if gc.HeadlessPolicy == api.HeadlessConditionError {
+		message := fmt.Sprintf("no symbolic HEAD advertised by %s %s and no branch could be selected", remote, repoURL)
+		if err := r.persistPairCondition(api.GitConfigurationError, message, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// resolveHeadlessBranch applies the configured policy to select a branch when the remote does not
+// advertise a symbolic HEAD. The default (and the explicit Error and ConditionError policies)
+// selects nothing, leaving the caller to fail the check
+func resolveHeadlessBranch(refs []*plumbing.Reference, gc api.GitConfig) *plumbing.Reference {
+	switch gc.HeadlessPolicy {
+	case api.HeadlessLexicalFirst:
+		var selected *plumbing.Reference
+		for _, ref := range refs {
+			if !ref.Name().IsBranch() || ref.Type() != plumbing.HashReference {
+				continue
+			}
+			if selected == nil || ref.Name() < selected.Name() {
+				selected = ref
+			}
+		}
+		return selected
+	case api.HeadlessPreferenceList:
+		for _, name := range gc.HeadBranchPreferences {
+			if ref := resolveReference(refs, plumbing.NewBranchReferenceName(name)); ref != nil {
+				return ref
+			}
+		}
+	}
+	return nil
+}
+
+// checkRedirect detects whether the repository URL redirects to a different host and raises an
+// informational condition so that teams notice an organization migration. With the Refuse policy
+// the check is aborted until the URL in the pattern is updated to the canonical location
+func (r *repositoryPair) checkRedirect(remote, repoURL string, policy api.RedirectPolicyType) error {
+	finalHost, err := r.hostResolver.FinalHost(repoURL)
+	if err != nil {
+		return err
+	}
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return err
+	}
+	if finalHost == "" || finalHost == parsed.Host {
+		return nil
+	}
+	message := fmt.Sprintf("%s %s redirected to host %s", remote, repoURL, finalHost)
+	if err := r.persistPairCondition(api.GitRepositoryRedirected, message, time.Now()); err != nil {
+		return err
+	}
+	if policy == api.RedirectRefuse {
+		return fmt.Errorf("refusing drift check per redirect policy: %s", message)
+	}
+	return nil
+}
+
+// applyProxyConfig registers the proxy the pattern declares for the hosts of its repositories
+// with the shared registry consulted by the installed transports, or clears a previous
+// registration when the declaration was removed
+func (r *repositoryPair) applyProxyConfig(gc api.GitConfig) {
+	settings := proxySettings{
+		httpProxy:  gc.Proxy.HTTPProxy,
+		httpsProxy: gc.Proxy.HTTPSProxy,
+		noProxy:    gc.Proxy.NoProxy}
+	for _, repoURL := range []string{gc.OriginRepo, gc.TargetRepo} {
+		parsed, err := url.Parse(repoURL)
+		if err != nil || parsed.Hostname() == "" {
+			continue
+		}
+		if settings.empty() {
+			sharedProxyRegistry.clearHostProxy(parsed.Hostname())
+		} else {
+			sharedProxyRegistry.setHostProxy(parsed.Hostname(), settings)
+		}
+	}
+}
+
+// applyTLSConfig registers the CA bundle and validation settings the pattern declares for the
+// hosts of its repositories, so a git server presenting a certificate signed by a private CA
+// verifies against it. A removed declaration clears the registration. Disabled certificate
+// validation is surfaced through a Warning event when it takes effect
+func (r *repositoryPair) applyTLSConfig(ctx context.Context, gc api.GitConfig) error {
+	settings := tlsSettings{insecureSkipVerify: gc.InsecureSkipVerify}
+	r.warnInsecure(ctx, gc.InsecureSkipVerify)
+	if gc.CABundleSecret != "" {
+		secret := &v1.Secret{}
+		if err := r.kClient.Get(ctx, types.NamespacedName{Name: gc.CABundleSecret, Namespace: r.namespace}, secret); err != nil {
+			return err
+		}
+		pem, found := secret.Data[caBundleSecretKey]
+		if !found || len(pem) == 0 {
+			return fmt.Errorf("secret %s in namespace %s does not hold a CA bundle under %q", gc.CABundleSecret, r.namespace, caBundleSecretKey)
+		}
+		settings.caPEM = pem
+	}
+	for _, repoURL := range []string{gc.OriginRepo, gc.TargetRepo} {
+		parsed, err := url.Parse(repoURL)
+		if err != nil || parsed.Hostname() == "" {
+			continue
+		}
+		if settings.empty() {
+			sharedTLSRegistry.clearHostTLS(parsed.Hostname())
+		} else {
+			sharedTLSRegistry.setHostTLS(parsed.Hostname(), settings)
+		}
+	}
+	return nil
+}
+
+// warnInsecure emits a Warning event when the pattern starts skipping certificate validation for
+// its remotes, once per activation rather than on every check
+func (r *repositoryPair) warnInsecure(ctx context.Context, insecure bool) {
+	if !insecure {
+		r.insecureWarned = false
+		return
+	}
+	if r.insecureWarned || r.recorder == nil {
+		return
+	}
+	r.insecureWarned = true
+	p := &api.Pattern{}
+	if err := r.kClient.Get(ctx, types.NamespacedName{Name: r.name, Namespace: r.namespace}, p); err != nil {
+		return
+	}
+	r.recorder.Event(p, v1.EventTypeWarning, eventReasonInsecureTLS,
+		fmt.Sprintf("TLS certificate validation is disabled for the remotes of pattern %s in namespace %s", r.name, r.namespace))
+}
+
+// trackHeadBranch stores the branch name the remote HEAD resolved to and raises an informational
+// condition when the name differs from the one resolved during the previous check, as a rename of
+// the default branch silently changes which branch is being compared
+func (r *repositoryPair) trackHeadBranch(last *plumbing.ReferenceName, current plumbing.ReferenceName, remote, url string) error {
+	if *last != "" && *last != current {
+		message := fmt.Sprintf("default branch for %s %s changed from %s to %s", remote, url, last.Short(), current.Short())
+		if err := r.persistPairCondition(api.GitDefaultBranchChanged, message, time.Now()); err != nil {
+			return err
+		}
+	}
+	*last = current
+	return nil
+}
+
+type repositoryPairs []*repositoryPair
+
+func (r repositoryPairs) Len() int {
+	return len(r)
+}
+
+func (r repositoryPairs) Less(i, j int) bool {
+	return r[i].nextCheck.Before(r[j].nextCheck)
+}
+
+func (r repositoryPairs) Swap(i, j int) {
+	r[i], r[j] = r[j], r[i]
+}
+
+type RemoteClient interface {
+	ListContext(ctx context.Context, o *git.ListOptions) (rfs []*plumbing.Reference, err error)
+}
+
+type GitClient interface {
+	NewRemoteClient(c *config.RemoteConfig) RemoteClient
+}
+
+// HostResolver returns the host that ultimately serves a repository URL, following any
+// HTTP redirects issued by the git server
+type HostResolver interface {
+	FinalHost(repoURL string) (string, error)
+}
+
+type httpHostResolver struct {
+	client *http.Client
+}
+
+func newHostResolver() HostResolver {
+	return &httpHostResolver{client: &http.Client{Transport: &http.Transport{Proxy: sharedProxyRegistry.proxyForRequest}}}
+}
+
+func (h *httpHostResolver) FinalHost(repoURL string) (string, error) {
+	resp, err := h.client.Head(repoURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	return resp.Request.URL.Host, nil
+}
+
+// CredentialsResolver turns the Secret referenced by a pattern into the transport auth method
+// used to list its remotes
+type CredentialsResolver interface {
+	Resolve(namespace, name string) (transport.AuthMethod, error)
+}
+
+// keys recognised in a credentials Secret
+const (
+	sshPrivateKeySecretKey = "sshPrivateKey"
+	sshUserSecretKey       = "sshUser"
+	knownHostsSecretKey    = "known_hosts"
+	tokenSecretKey         = "token"
+	usernameSecretKey      = "username"
+	// caBundleSecretKey holds the PEM-encoded CA certificates in a Secret referenced through
+	// caBundleSecret
+	caBundleSecretKey = "ca.crt"
+	// defaultSSHUser is the remote user assumed when the Secret does not name one, matching the
+	// convention of the common git hosters
+	defaultSSHUser = "git"
+)
+
+// secretCredentialsResolver reads the credentials from a Secret in the namespace of the pattern
+type secretCredentialsResolver struct {
+	kClient client.Client
+}
+
+func newCredentialsResolver(kubeClient client.Client) CredentialsResolver {
+	return &secretCredentialsResolver{kClient: kubeClient}
+}
+
+// Resolve builds the auth method matching the shape of the Secret: an SSH private key yields
+// public-key auth, a token yields basic auth for HTTPS remotes
+func (s *secretCredentialsResolver) Resolve(namespace, name string) (transport.AuthMethod, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var secret v1.Secret
+	if err := s.kClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &secret); err != nil {
+		return nil, err
+	}
+	if key, found := secret.Data[sshPrivateKeySecretKey]; found {
+		return sshCredentials(&secret, key)
+	}
+	if token, found := secret.Data[tokenSecretKey]; found {
+		user := string(secret.Data[usernameSecretKey])
+		if user == "" {
+			user = defaultSSHUser
+		}
+		return &githttp.BasicAuth{Username: user, Password: string(token)}, nil
+	}
+	return nil, fmt.Errorf("secret %s in namespace %s contains neither %q nor %q", name, namespace, sshPrivateKeySecretKey, tokenSecretKey)
+}
+
+// sshCredentials builds the SSH public-key auth method from the private key held in the Secret.
+// When the Secret also carries known hosts, host key verification is pinned to them; otherwise the
+// default known_hosts files of the environment apply
+func sshCredentials(secret *v1.Secret, key []byte) (transport.AuthMethod, error) {
+	user := string(secret.Data[sshUserSecretKey])
+	if user == "" {
+		user = defaultSSHUser
+	}
+	keys, err := gitssh.NewPublicKeys(user, key, "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the private key in secret %s in namespace %s: %w", secret.Name, secret.Namespace, err)
+	}
+	if hosts, found := secret.Data[knownHostsSecretKey]; found {
+		callback, err := knownHostsCallback(hosts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the known hosts in secret %s in namespace %s: %w", secret.Name, secret.Namespace, err)
+		}
+		keys.HostKeyCallback = callback
+	}
+	return keys, nil
+}
+
+// knownHostsCallback builds the host key verification callback from known_hosts data kept in a
+// Secret, staging it in a temporary file as the underlying parser only consumes files
+func knownHostsCallback(hosts []byte) (gossh.HostKeyCallback, error) {
+	file, err := os.CreateTemp("", "known_hosts")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(hosts); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	return gitssh.NewKnownHostsCallback(file.Name())
+}
+
+// CommitTimeResolver returns the committer timestamp of a commit reachable from a reference on a
+// remote repository
+type CommitTimeResolver interface {
+	CommitTime(repoURL string, hash plumbing.Hash, ref plumbing.ReferenceName) (time.Time, error)
+}
+
+type cloneCommitTimeResolver struct {
+}
+
+func newCommitTimeResolver() CommitTimeResolver {
+	return &cloneCommitTimeResolver{}
+}
+
+// CommitTime performs a shallow in-memory clone of the single branch holding the commit and reads
+// the committer timestamp from the commit object
+func (c *cloneCommitTimeResolver) CommitTime(repoURL string, hash plumbing.Hash, ref plumbing.ReferenceName) (time.Time, error) {
+	repo, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           repoURL,
+		ReferenceName: ref,
+		SingleBranch:  true,
+		Depth:         1,
+		Tags:          git.NoTags})
+	if err != nil {
+		return time.Time{}, err
+	}
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return commit.Committer.When, nil
+}
+
+// AncestryChecker reports whether a commit is part of the history of a branch on a remote
+// repository
+type AncestryChecker interface {
+	InHistory(repoURL string, branch plumbing.ReferenceName, hash plumbing.Hash) (bool, error)
+}
+
+type cloneAncestryChecker struct {
+}
+
+func newAncestryChecker() AncestryChecker {
+	return &cloneAncestryChecker{}
+}
+
+// InHistory clones the branch into memory and walks the ancestry between the commit and the
+// branch head. A commit absent from the clone is reported as outside the history rather than as
+// an error
+func (c *cloneAncestryChecker) InHistory(repoURL string, branch plumbing.ReferenceName, hash plumbing.Hash) (bool, error) {
+	repo, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           repoURL,
+		ReferenceName: branch,
+		SingleBranch:  true,
+		Tags:          git.NoTags})
+	if err != nil {
+		return false, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return false, err
+	}
+	if head.Hash() == hash {
+		return true, nil
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return false, err
+	}
+	commit, err := repo.CommitObject(hash)
+	if err == plumbing.ErrObjectNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return commit.IsAncestor(headCommit)
+}
+
+// IdentityResolver resolves the root commits a branch of a remote repository grew from
+type IdentityResolver interface {
+	RootCommits(repoURL string, branch plumbing.ReferenceName) ([]plumbing.Hash, error)
+}
+
+// TreeComparer reports whether the trees of two remote references differ under any of the given
+// paths
+type TreeComparer interface {
+	TreesDiffer(originRepo string, originRef plumbing.ReferenceName, targetRepo string, targetRef plumbing.ReferenceName, paths []string) (bool, error)
+}
+
+type cloneTreeComparer struct {
+}
+
+func newTreeComparer() TreeComparer {
+	return &cloneTreeComparer{}
+}
+
+// TreesDiffer shallow-clones both references into memory and compares the tree entries under the
+// given paths. A whole subtree is compared through the hash of its entry, and a path absent from
+// both sides contributes no difference
+func (c *cloneTreeComparer) TreesDiffer(originRepo string, originRef plumbing.ReferenceName, targetRepo string, targetRef plumbing.ReferenceName, paths []string) (bool, error) {
+	originTree, err := headTree(originRepo, originRef)
+	if err != nil {
+		return false, err
+	}
+	targetTree, err := headTree(targetRepo, targetRef)
+	if err != nil {
+		return false, err
+	}
+	for _, path := range paths {
+		originHash, originFound, err := treeEntryHash(originTree, path)
+		if err != nil {
+			return false, err
+		}
+		targetHash, targetFound, err := treeEntryHash(targetTree, path)
+		if err != nil {
+			return false, err
+		}
+		if originFound != targetFound || originHash != targetHash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// headTree returns the tree of the commit a reference points at after a shallow in-memory clone
+func headTree(repoURL string, ref plumbing.ReferenceName) (*object.Tree, error) {
+	repo, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           repoURL,
+		ReferenceName: ref,
+		SingleBranch:  true,
+		Depth:         1,
+		Tags:          git.NoTags})
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return commit.Tree()
+}
+
+// FileExistenceChecker reports which of the given paths are absent from the tree of a revision
+// on a remote repository
+type FileExistenceChecker interface {
+	MissingFiles(repoURL string, revision string, paths []string) ([]string, error)
+}
+
+type cloneFileExistenceChecker struct {
+}
+
+func newFileExistenceChecker() FileExistenceChecker {
+	return &cloneFileExistenceChecker{}
+}
+
+// MissingFiles shallow-clones the revision into memory and looks each path up in its tree.
+// Leading slashes are stripped so the helm-style /values-foo.yaml spelling resolves from the
+// repository root
+func (c *cloneFileExistenceChecker) MissingFiles(repoURL string, revision string, paths []string) ([]string, error) {
+	refName := plumbing.HEAD
+	switch {
+	case revision == "" || revision == "HEAD":
+	case strings.HasPrefix(revision, "refs/"):
+		refName = plumbing.ReferenceName(revision)
+	default:
+		refName = plumbing.NewBranchReferenceName(revision)
+	}
+	tree, err := headTree(repoURL, refName)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, path := range paths {
+		if _, err := tree.FindEntry(strings.TrimPrefix(path, "/")); err != nil {
+			missing = append(missing, path)
+		}
+	}
+	return missing, nil
+}
+
+// treeEntryHash returns the hash of the entry at a path, distinguishing a missing entry from an
+// error
+func treeEntryHash(tree *object.Tree, path string) (plumbing.Hash, bool, error) {
+	entry, err := tree.FindEntry(path)
+	if err == object.ErrEntryNotFound || err == object.ErrDirectoryNotFound {
+		return plumbing.ZeroHash, false, nil
+	}
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+	return entry.Hash, true, nil
+}
+
+// DivergenceResolver counts the commits separating two commits of a repository in each direction
+type DivergenceResolver interface {
+	// Divergence returns how many commits lead from the merge base to each of the two commits:
+	// behind counts those only reachable from origin, ahead those only reachable from target
+	Divergence(repoURL string, ref plumbing.ReferenceName, origin, target plumbing.Hash) (behind, ahead int, err error)
+}
+
+type cloneDivergenceResolver struct {
+}
+
+func newDivergenceResolver() DivergenceResolver {
+	return &cloneDivergenceResolver{}
+}
+
+// Divergence clones the reference into memory and counts the commits on each side of the merge
+// base of the two commits. A target commit absent from the clone is reported as an error
+func (c *cloneDivergenceResolver) Divergence(repoURL string, ref plumbing.ReferenceName, origin, target plumbing.Hash) (int, int, error) {
+	if origin == target {
+		return 0, 0, nil
+	}
+	repo, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           repoURL,
+		ReferenceName: ref,
+		SingleBranch:  true,
+		Tags:          git.NoTags})
+	if err != nil {
+		return 0, 0, err
+	}
+	originCommit, err := repo.CommitObject(origin)
+	if err != nil {
+		return 0, 0, err
+	}
+	targetCommit, err := repo.CommitObject(target)
+	if err != nil {
+		return 0, 0, err
+	}
+	bases, err := originCommit.MergeBase(targetCommit)
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := commitsSinceBase(originCommit, bases)
+	if err != nil {
+		return 0, 0, err
+	}
+	ahead, err := commitsSinceBase(targetCommit, bases)
+	if err != nil {
+		return 0, 0, err
+	}
+	return behind, ahead, nil
+}
+
+// DistanceResolver measures how far the resolved commits of the two repositories of a pair have
+// diverged, identifying the merge base their histories split from in addition to the counts
+type DistanceResolver interface {
+	// Distance fetches both references into a shared repository and returns how many commits lead
+	// from the merge base to each of the two commits, along with the merge base itself
+	Distance(originRepo string, originRef plumbing.ReferenceName, targetRepo string, targetRef plumbing.ReferenceName, origin, target plumbing.Hash) (behind, ahead int, base plumbing.Hash, err error)
+}
+
+type fetchDistanceResolver struct {
+}
+
+func newDistanceResolver() DistanceResolver {
+	return &fetchDistanceResolver{}
+}
+
+// Distance fetches the origin and target references into one in-memory repository, so the merge
+// base of the two commits can be computed even when the target holds commits of its own. Equal
+// commits short-circuit without fetching anything
+func (f *fetchDistanceResolver) Distance(originRepo string, originRef plumbing.ReferenceName, targetRepo string, targetRef plumbing.ReferenceName, origin, target plumbing.Hash) (int, int, plumbing.Hash, error) {
+	if origin == target {
+		return 0, 0, origin, nil
+	}
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		return 0, 0, plumbing.ZeroHash, err
+	}
+	sides := []struct {
+		name string
+		url  string
+		ref  plumbing.ReferenceName
+	}{
+		{"origin", originRepo, originRef},
+		{"target", targetRepo, targetRef}}
+	for _, side := range sides {
+		remote, err := repo.CreateRemote(&config.RemoteConfig{Name: side.name, URLs: []string{side.url}})
+		if err != nil {
+			return 0, 0, plumbing.ZeroHash, err
+		}
+		err = remote.Fetch(&git.FetchOptions{
+			RemoteName: side.name,
+			RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:refs/remotes/%s/%s", side.ref, side.name, side.ref.Short()))},
+			Tags:       git.NoTags})
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			return 0, 0, plumbing.ZeroHash, err
+		}
+	}
+	originCommit, err := repo.CommitObject(origin)
+	if err != nil {
+		return 0, 0, plumbing.ZeroHash, err
+	}
+	targetCommit, err := repo.CommitObject(target)
+	if err != nil {
+		return 0, 0, plumbing.ZeroHash, err
+	}
+	bases, err := originCommit.MergeBase(targetCommit)
+	if err != nil {
+		return 0, 0, plumbing.ZeroHash, err
+	}
+	behind, err := commitsSinceBase(originCommit, bases)
+	if err != nil {
+		return 0, 0, plumbing.ZeroHash, err
+	}
+	ahead, err := commitsSinceBase(targetCommit, bases)
+	if err != nil {
+		return 0, 0, plumbing.ZeroHash, err
+	}
+	base := plumbing.ZeroHash
+	if len(bases) > 0 {
+		base = bases[0].Hash
+	}
+	return behind, ahead, base, nil
+}
+
+// errNonFastForward marks a refused remediation: the target holds commits the origin does not,
+// so updating it would discard work. Callers may fall back to proposing the update instead
+var errNonFastForward = errors.New("refusing non-fast-forward update")
+
+// DriftRemediator pushes the state of the origin onto the target repository of a drifted pair
+type DriftRemediator interface {
+	// FastForward updates the target branch to the origin commit, refusing the update when the
+	// target commit is not an ancestor of the origin commit
+	FastForward(originRepo string, originRef plumbing.ReferenceName, targetRepo string, branch plumbing.ReferenceName, origin, target plumbing.Hash, auth transport.AuthMethod) error
+}
+
+type pushDriftRemediator struct {
+}
+
+func newDriftRemediator() DriftRemediator {
+	return &pushDriftRemediator{}
+}
+
+// FastForward clones the origin branch into memory, verifies the target commit is part of its
+// history and pushes the origin commit onto the target branch. A target commit absent from the
+// origin history means the target holds work of its own; such an update is refused rather than
+// forced
+func (p *pushDriftRemediator) FastForward(originRepo string, originRef plumbing.ReferenceName, targetRepo string, branch plumbing.ReferenceName, origin, target plumbing.Hash, auth transport.AuthMethod) error {
+	repo, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           originRepo,
+		ReferenceName: originRef,
+		SingleBranch:  true,
+		Tags:          git.NoTags})
+	if err != nil {
+		return err
+	}
+	originCommit, err := repo.CommitObject(origin)
+	if err != nil {
+		return err
+	}
+	if origin != target {
+		targetCommit, err := repo.CommitObject(target)
+		if err == plumbing.ErrObjectNotFound {
+			return fmt.Errorf("%w: target commit %s is not reachable from origin %s", errNonFastForward, target, originRepo)
+		}
+		if err != nil {
+			return err
+		}
+		ancestor, err := targetCommit.IsAncestor(originCommit)
+		if err != nil {
+			return err
+		}
+		if !ancestor {
+			return fmt.Errorf("%w: target commit %s is not an ancestor of origin commit %s", errNonFastForward, target, origin)
+		}
+	}
+	remote, err := repo.CreateRemote(&config.RemoteConfig{Name: "sync-target", URLs: []string{targetRepo}})
+	if err != nil {
+		return err
+	}
+	err = remote.Push(&git.PushOptions{
+		RemoteName: "sync-target",
+		Auth:       auth,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", origin, branch))}})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
+	return err
+}
+
+// commitsSinceBase counts the commits reachable from a commit but not from any of the merge bases
+func commitsSinceBase(from *object.Commit, bases []*object.Commit) (int, error) {
+	ignore := make([]plumbing.Hash, 0, len(bases))
+	for _, base := range bases {
+		ignore = append(ignore, base.Hash)
+	}
+	count := 0
+	iter := object.NewCommitPreorderIter(from, nil, ignore)
+	err := iter.ForEach(func(*object.Commit) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+type cloneIdentityResolver struct {
+}
+
+func newIdentityResolver() IdentityResolver {
+	return &cloneIdentityResolver{}
+}
+
+// RootCommits clones the branch into memory and walks its history down to the parentless commits
+// the repository started from
+func (c *cloneIdentityResolver) RootCommits(repoURL string, branch plumbing.ReferenceName) ([]plumbing.Hash, error) {
+	repo, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           repoURL,
+		ReferenceName: branch,
+		SingleBranch:  true,
+		Tags:          git.NoTags})
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	roots := []plumbing.Hash{}
+	iter := object.NewCommitPreorderIter(commit, nil, nil)
+	err = iter.ForEach(func(c *object.Commit) error {
+		if c.NumParents() == 0 {
+			roots = append(roots, c.Hash)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return roots, nil
+}
+
+// shareRootCommit returns true when the two sets of root commits have at least one hash in common
+func shareRootCommit(originRoots, targetRoots []plumbing.Hash) bool {
+	for _, origin := range originRoots {
+		for _, target := range targetRoots {
+			if origin == target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type gitClient struct {
+}
+
+// newGitClient installs HTTP transports that route requests through the proxy selected by the
+// shared proxy registry and verify TLS against the trust settings of the shared TLS registry, so
+// both the operator-level environment and per-pattern declarations are honored when listing
+// remotes
+func newGitClient() GitClient {
+	transport := githttp.NewClient(&http.Client{Transport: &http.Transport{
+		Proxy:          sharedProxyRegistry.proxyForRequest,
+		DialTLSContext: sharedTLSRegistry.dialTLS((&net.Dialer{}).DialContext)}})
+	transportclient.InstallProtocol("https", transport)
+	transportclient.InstallProtocol("http", transport)
+	return &gitClient{}
+}
+
+// newGitClientWithDNSCache returns a GitClient whose HTTP transports resolve host names through a
+// short-lived DNS cache, so fleets polling a handful of hosts do not hammer the resolver on every
+// check and ride out brief resolver outages within the TTL
+func newGitClientWithDNSCache(ttl time.Duration) GitClient {
+	cache := newDNSCache(ttl)
+	cachedDial := cache.dialContext((&net.Dialer{}).DialContext)
+	transport := githttp.NewClient(&http.Client{Transport: &http.Transport{
+		Proxy:          sharedProxyRegistry.proxyForRequest,
+		DialContext:    cachedDial,
+		DialTLSContext: sharedTLSRegistry.dialTLS(cachedDial)}})
+	transportclient.InstallProtocol("https", transport)
+	transportclient.InstallProtocol("http", transport)
+	return &gitClient{}
+}
+
+// dnsCache memoizes successful host resolutions for a fixed TTL. Failed lookups are never cached,
+// so a transient resolver error does not pin the failure for the duration of the TTL
+type dnsCache struct {
+	ttl     time.Duration
+	mutex   sync.Mutex
+	entries map[string]dnsCacheEntry
+	lookup  func(ctx context.Context, host string) ([]string, error)
+}
+
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+func newDNSCache(ttl time.Duration) *dnsCache {
+	return &dnsCache{
+		ttl:     ttl,
+		entries: map[string]dnsCacheEntry{},
+		lookup:  net.DefaultResolver.LookupHost}
+}
+
+// lookupHost returns the cached addresses for the host while the entry is within the TTL and
+// resolves afresh otherwise
+func (c *dnsCache) lookupHost(ctx context.Context, host string) ([]string, error) {
+	c.mutex.Lock()
+	entry, found := c.entries[host]
+	c.mutex.Unlock()
+	if found && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+	addrs, err := c.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	c.entries[host] = dnsCacheEntry{addrs: addrs, expires: time.Now().Add(c.ttl)}
+	c.mutex.Unlock()
+	return addrs, nil
+}
+
+// dialContext wraps a dial function so host names are resolved through the cache. The resolved
+// addresses are tried in order and the last dial error is returned when none of them connects
+func (c *dnsCache) dialContext(dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) != nil {
+			return dial(ctx, network, address)
+		}
+		addrs, err := c.lookupHost(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var lastErr error
+		for _, addr := range addrs {
+			conn, err := dial(ctx, network, net.JoinHostPort(addr, port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+func (c *gitClient) NewRemoteClient(config *config.RemoteConfig) RemoteClient {
+	return git.NewRemote(nil, config)
+}
+
+type watcher struct {
+	kClient client.Client
+	//endCh is used to notify the watch routine to exit the loop
+	endCh, updateCh     chan interface{}
+	repoPairs           repositoryPairs
+	mutex               *sync.Mutex
+	logger              logr.Logger
+	timer               *time.Timer
+	timerCancelled      bool
+	gitClient           GitClient
+	hostResolver        HostResolver
+	revisionResolver    RevisionResolver
+	externalResolver    RevisionResolver
+	kubeResolver        RevisionResolver
+	commitTimeResolver  CommitTimeResolver
+	ancestryChecker     AncestryChecker
+	identityResolver    IdentityResolver
+	treeComparer        TreeComparer
+	divergenceResolver  DivergenceResolver
+	distanceResolver    DistanceResolver
+	credentialsResolver CredentialsResolver
+	remediator          DriftRemediator
+	forgeFactory        ForgeClientFactory
+	// optional archival sink for drift reports and the count of reports that could not be
+	// written, updated atomically as the sink is consulted outside the lock
+	driftSink         DriftSink
+	sinkWriteFailures int64
+	// read-optimized immutable view of the pair state, swapped atomically on every mutation so
+	// external readers do not contend with the check loop for the lock
+	snapshot atomic.Value
+	// hosts currently considered to be in outage and the slow cadence their pairs are probed at
+	// until a probe succeeds
+	hostOutages    map[string]bool
+	outageInterval time.Duration
+	// cached maintenance windows declared on namespaces, refreshed lazily so every check does
+	// not hit the API
+	maintenanceCache map[string]maintenanceEntry
+	// name and namespace of the Lease renewed as a heartbeat from the check loop. An empty name
+	// disables the heartbeat
+	leaseName, leaseNamespace string
+	// latest check outcome per pair that could not be persisted because the API was unavailable,
+	// keyed by namespace/name and flushed once the API returns
+	pendingConditions map[string]pendingCondition
+	// when true the watcher acts as a pure observer: checks, metrics, logs and report archival
+	// all run, but no Status update, condition or Lease write ever reaches the cluster
+	readOnly bool
+	// upstream to mirror mappings applied to the repositories of every check, read from the
+	// operator environment for disconnected environments
+	gitMirrors mirrorMap
+	// set atomically when the scheduler goroutine exits, consulted by the health check
+	loopExited int32
+	// tracks the scheduler loop and the check workers so drain can wait for the last in-flight
+	// check to finish, and guards the shutdown sequence against running more than once
+	workers   sync.WaitGroup
+	drainOnce sync.Once
+	// true while the replica is not the elected leader; the pair state is maintained but no
+	// timer is scheduled and no check dispatched
+	suspended bool
+	// how many polling intervals a due check may wait before the health check fails
+	starvationIntervals int
+	// minimum interval between status writes per pattern; 0 disables throttling. Transitions
+	// arriving inside the interval are coalesced, keeping only the latest outcome per pattern
+	// until its write slot reopens
+	statusWriteInterval time.Duration
+	lastStatusWrites    map[string]time.Time
+	throttledConditions map[string]pendingCondition
+	// length of the window during which update notifications are coalesced into a single timer
+	// recompute, plus the upper bound of the random jitter widening it so replicas watching the
+	// same patterns do not recompute at the exact same instant. A zero window disables coalescing
+	coalesceWindow time.Duration
+	coalesceJitter time.Duration
+	// number of timer recomputes performed, updated atomically for observability
+	timerRecomputes int64
+	// size of the bounded worker pool running the checks and the queue due pairs are dispatched
+	// through, so checks run in parallel and a hung remote occupies a single worker instead of
+	// stalling every other pair
+	checkConcurrency int
+	checkQueue       chan *repositoryPair
+	// workers currently alive, tracked so a raised concurrency spawns the missing workers and a
+	// lowered one lets the surplus workers drain after the next check each of them completes
+	runningWorkers int
+	// base context every check inherits from, cancelled on shutdown so in-flight remote calls
+	// abort instead of leaking, and the upper bound a single check may spend on its remote
+	// operations before being cancelled
+	checkCtx     context.Context
+	checkCancel  context.CancelFunc
+	checkTimeout time.Duration
+	// bounds the number of checks per minute against one git host; nil disables rate limiting
+	hostLimiter *hostRateLimiter
+	// bounded per-pattern history of check outcomes served by the /history debug endpoint, and
+	// the number of entries retained per pattern
+	checkHistory map[string][]checkRecord
+	historyLimit int
+	// percentage of the interval added as a random delay when computing the next check, so pairs
+	// sharing an interval drift apart over successive checks instead of hitting the git provider
+	// in lockstep. Zero disables jitter; aligned pairs are never jittered as alignment exists to
+	// hit exact boundaries
+	checkJitterPercent int
+	// when true the watcher maintains a companion PatternGitStatus resource per pair carrying the
+	// rich drift details, keeping the status of the Pattern itself lean
+	companionStatus bool
+	// emits Kubernetes events on the pattern when its drift state transitions; nil disables
+	// event emission
+	recorder record.EventRecorder
+}
+
+// pendingCondition buffers the latest unpersisted check outcome for a pair during an API outage
+type pendingCondition struct {
+	name, namespace string
+	conditionType   api.PatternConditionType
+	driftClass      *driftClassification
+	timestamp       time.Time
+	failure         *api.CheckFailure
+}
+
+const (
+	// defaultCheckConcurrency is the number of drift checks allowed to run in parallel when
+	// DRIFT_CHECK_CONCURRENCY does not override it
+	defaultCheckConcurrency = 4
+	// checkQueueCapacity bounds the number of due pairs waiting for a free worker
+	checkQueueCapacity = 64
+	// defaultCheckTimeout is the deadline of a single check's remote operations when
+	// DRIFT_CHECK_TIMEOUT_SECONDS does not override it, so a hung git server fails the check
+	// instead of occupying a worker forever
+	defaultCheckTimeout = 30 * time.Second
+	// defaultCheckHistoryLimit is the number of check outcomes retained per pattern when
+	// DRIFT_HISTORY_LIMIT does not override it
+	defaultCheckHistoryLimit = 20
+)
+
+func newDriftWatcher(kubeClient client.Client, logger logr.Logger, gitClient GitClient, recorder record.EventRecorder) (driftWatcher, chan interface{}) {
+	d := &watcher{
+		kClient:             kubeClient,
+		logger:              logger,
+		recorder:            recorder,
+		repoPairs:           repositoryPairs{},
+		endCh:               make(chan interface{}),
+		mutex:               &sync.Mutex{},
+		gitClient:           gitClient,
+		hostResolver:        newHostResolver(),
+		revisionResolver:    newGithubDeploymentsResolver(),
+		externalResolver:    newExternalRevisionResolver(),
+		kubeResolver:        newKubeRevisionResolver(kubeClient),
+		commitTimeResolver:  newCommitTimeResolver(),
+		ancestryChecker:     newAncestryChecker(),
+		identityResolver:    newIdentityResolver(),
+		treeComparer:        newTreeComparer(),
+		divergenceResolver:  newDivergenceResolver(),
+		distanceResolver:    newDistanceResolver(),
+		credentialsResolver: newCredentialsResolver(kubeClient),
+		remediator:          newDriftRemediator(),
+		forgeFactory:        newForgeClientFactory(),
+		driftSink:           newObjectStoreSink(),
+		hostOutages:         map[string]bool{},
+		outageInterval:      defaultHostOutageInterval,
+		maintenanceCache:    map[string]maintenanceEntry{},
+		leaseName:           os.Getenv("DRIFT_LEASE_NAME"),
+		leaseNamespace:      os.Getenv("DRIFT_LEASE_NAMESPACE")}
+	if seconds, err := strconv.Atoi(os.Getenv("DRIFT_STATUS_MIN_INTERVAL_SECONDS")); err == nil && seconds > 0 {
+		d.statusWriteInterval = time.Duration(seconds) * time.Second
+	}
+	d.readOnly = os.Getenv("DRIFT_READ_ONLY") == "true"
+	d.gitMirrors = parseMirrorMap(os.Getenv("GIT_MIRRORS"))
+	d.companionStatus = os.Getenv("DRIFT_COMPANION_STATUS") == "true"
+	if ms, err := strconv.Atoi(os.Getenv("DRIFT_COALESCE_WINDOW_MS")); err == nil && ms > 0 {
+		d.coalesceWindow = time.Duration(ms) * time.Millisecond
+	}
+	if ms, err := strconv.Atoi(os.Getenv("DRIFT_COALESCE_JITTER_MS")); err == nil && ms > 0 {
+		d.coalesceJitter = time.Duration(ms) * time.Millisecond
+	}
+	if workers, err := strconv.Atoi(os.Getenv("DRIFT_CHECK_CONCURRENCY")); err == nil && workers > 0 {
+		d.checkConcurrency = workers
+	}
+	if seconds, err := strconv.Atoi(os.Getenv("DRIFT_CHECK_TIMEOUT_SECONDS")); err == nil && seconds > 0 {
+		d.checkTimeout = time.Duration(seconds) * time.Second
+	}
+	if percent, err := strconv.Atoi(os.Getenv("DRIFT_CHECK_JITTER_PERCENT")); err == nil && percent > 0 {
+		d.checkJitterPercent = percent
+	}
+	if rpm, err := strconv.Atoi(os.Getenv("DRIFT_HOST_RATE_LIMIT_RPM")); err == nil && rpm > 0 {
+		d.hostLimiter = newHostRateLimiter(rpm)
+	}
+	if limit, err := strconv.Atoi(os.Getenv("DRIFT_HISTORY_LIMIT")); err == nil && limit > 0 {
+		d.historyLimit = limit
+	}
+	if intervals, err := strconv.Atoi(os.Getenv("DRIFT_HEALTH_STARVATION_INTERVALS")); err == nil && intervals > 0 {
+		d.starvationIntervals = intervals
+	}
+	if addr := os.Getenv("DRIFT_WEBHOOK_ADDR"); addr != "" {
+		go d.serveWebhook(addr)
+	}
+	return d, d.watch()
+}
+
+type driftWatcher interface {
+	add(name, namespace string, interval int, aligned bool, mode string) error
+	updateInterval(name, namespace string, interval int, aligned bool) error
+	remove(name, namespace string) error
+	checkNow(name, namespace, correlationID string) error
+	watch() chan interface{}
+	isWatching(name, namespace string) bool
+	addCheck(name, namespace string, interval int, aligned bool) error
+	updateCheckInterval(name, namespace string, interval int, aligned bool) error
+	removeCheck(name, namespace string) error
+	isWatchingCheck(name, namespace string) bool
+	healthCheck(req *http.Request) error
+	suspend()
+	resume()
+	drain()
+}
+
+// driftWatcherElector gates the check schedule of the drift watcher on leader election: the
+// watcher of a stand-by replica stays suspended, the elected replica resumes it, and losing the
+// leadership or shutting down suspends it again so the next leader takes over cleanly
+type driftWatcherElector struct {
+	watcher driftWatcher
+}
+
+// Start resumes the watcher once the manager hands the leadership to this replica and suspends
+// it again when the context ends
+func (e *driftWatcherElector) Start(ctx context.Context) error {
+	e.watcher.resume()
+	<-ctx.Done()
+	e.watcher.suspend()
+	return nil
+}
+
+// NeedLeaderElection restricts Start to the elected leader
+func (e *driftWatcherElector) NeedLeaderElection() bool {
+	return true
+}
+
+// driftWatcherDrainer ties the watcher to the shutdown of the manager: when the manager stops,
+// the watcher drains its in-flight checks and flushes the buffered outcomes before the process
+// exits, instead of the check goroutines being killed mid status-update
+type driftWatcherDrainer struct {
+	watcher driftWatcher
+}
+
+// Start blocks until the manager shuts down and then drains the watcher
+func (e *driftWatcherDrainer) Start(ctx context.Context) error {
+	<-ctx.Done()
+	e.watcher.drain()
+	return nil
+}
+
+// NeedLeaderElection lets the drainer run on every replica, as any replica may still hold
+// in-flight checks from a leadership it since lost
+func (e *driftWatcherDrainer) NeedLeaderElection() bool {
+	return false
+}
+
+// isWatching returns true if the pair name,namespace reference is being monitored for drifts, false otherwise
+func (d *watcher) isWatching(name, namespace string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, item := range d.repoPairs {
+		if item.name == name && item.namespace == namespace && !item.driftCheck {
+			return true
+		}
+	}
+	return false
+}
+
+// isWatchingCheck returns true if a GitDriftCheck with the given name and namespace is being
+// monitored for drifts, false otherwise
+func (d *watcher) isWatchingCheck(name, namespace string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, item := range d.repoPairs {
+		if item.name == name && item.namespace == namespace && item.driftCheck {
+			return true
+		}
+	}
+	return false
+}
+
+// newPair assembles a repository pair wired to the collaborators of the watcher. An empty pairName
+// denotes the primary pair of the pattern
+func (d *watcher) newPair(name, namespace, pairName string, interval time.Duration, aligned bool, mode comparisonMode) *repositoryPair {
+	firstInterval := interval
+	if !aligned {
+		// spread the first checks of pairs added together, e.g. on operator startup
+		firstInterval = jitterInterval(interval, d.checkJitterPercent)
+	}
+	return &repositoryPair{
+		name:                name,
+		namespace:           namespace,
+		pairName:            pairName,
+		kClient:             d.kClient,
+		interval:            interval,
+		aligned:             aligned,
+		comparisonMode:      mode,
+		nextCheck:           nextCheckTime(time.Now(), firstInterval, aligned),
+		gitClient:           d.gitClient,
+		hostResolver:        d.hostResolver,
+		revisionResolver:    d.revisionResolver,
+		externalResolver:    d.externalResolver,
+		kubeResolver:        d.kubeResolver,
+		commitTimeResolver:  d.commitTimeResolver,
+		ancestryChecker:     d.ancestryChecker,
+		identityResolver:    d.identityResolver,
+		treeComparer:        d.treeComparer,
+		divergenceResolver:  d.divergenceResolver,
+		distanceResolver:    d.distanceResolver,
+		credentialsResolver: d.credentialsResolver,
+		remediator:          d.remediator,
+		forgeFactory:        d.forgeFactory,
+		recorder:            d.recorder,
+		readOnly:            d.readOnly,
+		gitMirrors:          d.gitMirrors,
+		logger:              d.logger}
+}
+
+// declaredPairNames returns the names of all pairs the pattern declares: the primary pair under
+// the empty name plus one entry per additional pair of the git configuration
+func (d *watcher) declaredPairNames(name, namespace string) ([]string, error) {
+	var pattern api.Pattern
+	if err := d.kClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, &pattern); err != nil {
+		if kerrors.IsNotFound(err) {
+			// the watcher never required the pattern to exist when the pair is registered; a
+			// missing pattern has no additional pairs and its checks fail until it appears
+			return []string{""}, nil
+		}
+		return nil, err
+	}
+	names := []string{""}
+	for _, declared := range pattern.Spec.GitConfig.AdditionalPairs {
+		names = append(names, declared.Name)
+	}
+	return names, nil
+}
+
+// persistedLastChecks reads the last-check timestamps recorded on the pattern status during a
+// previous run, keyed by pair name, so the schedule survives an operator restart. A pattern
+// without persisted timestamps yields an empty map and its pairs start from scratch
+func (d *watcher) persistedLastChecks(name, namespace string) map[string]time.Time {
+	persisted := map[string]time.Time{}
+	var pattern api.Pattern
+	if err := d.kClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, &pattern); err != nil {
+		return persisted
+	}
+	if pattern.Status.DriftStatus != nil {
+		persisted[""] = pattern.Status.DriftStatus.LastCheck.Time
+	}
+	for _, status := range pattern.Status.PairDriftStatuses {
+		persisted[status.Name] = status.LastCheck.Time
+	}
+	return persisted
+}
+
+// persistedCheckLastCheck reads the last-check timestamp recorded on the GitDriftCheck status
+// during a previous run
+func (d *watcher) persistedCheckLastCheck(name, namespace string) time.Time {
+	check := &api.GitDriftCheck{}
+	if err := d.kClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, check); err != nil {
+		return time.Time{}
+	}
+	return check.Status.LastCheck.Time
+}
+
+// restorePairSchedule rebuilds the schedule of a freshly added pair from the last check persisted
+// before a restart. A pair that is not yet overdue keeps its original slot; an overdue pair is
+// dispatched shortly, staggered across a quarter of its interval so a restart does not fire every
+// overdue check at once
+func restorePairSchedule(pair *repositoryPair, lastCheck time.Time) {
+	if lastCheck.IsZero() {
+		return
+	}
+	pair.lastCheck = lastCheck
+	next := nextCheckTime(lastCheck, pair.interval, pair.aligned)
+	now := time.Now()
+	if next.After(now) {
+		pair.nextCheck = next
+		return
+	}
+	if stagger := pair.interval / 4; stagger > 0 {
+		now = now.Add(time.Duration(mathrand.Int63n(int64(stagger))))
+	}
+	pair.nextCheck = now
+}
+
+// add instructs the client to start monitoring for drifts between the repositories of the pattern,
+// tracking one pair per declared origin/target combination under the same pattern key
+func (d *watcher) add(name, namespace string, interval int, aligned bool, mode string) error {
+	if d.updateCh == nil {
+		return fmt.Errorf("unable to add %s in %s when watch has not yet started", name, namespace)
+	}
+	validatedMode, err := validateComparisonMode(mode)
+	if err != nil {
+		return err
+	}
+	pairNames, err := d.declaredPairNames(name, namespace)
+	if err != nil {
+		return err
+	}
+	persisted := d.persistedLastChecks(name, namespace)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.stopTimer()
+	for _, pairName := range pairNames {
+		pair := d.newPair(name, namespace, pairName, time.Duration(interval)*time.Second, aligned, validatedMode)
+		restorePairSchedule(pair, persisted[pairName])
+		d.repoPairs = append(d.repoPairs, pair)
+	}
+	sort.Sort(d.repoPairs)
+	d.refreshSnapshot()
+	// Notify of updates
+	d.updateCh <- struct{}{}
+	return nil
+}
+
+// updateInterval checks whether the new interval or alignment differ from the stored ones or the
+// declared set of pairs changed, and requeues the affected pairs to ensure the new scheduling is reflected
+func (d *watcher) updateInterval(name, namespace string, interval int, aligned bool) error {
+	if d.updateCh == nil {
+		return fmt.Errorf("unable to update interval for %s in %s when watch has not yet started", name, namespace)
+	}
+	pairNames, err := d.declaredPairNames(name, namespace)
+	if err != nil {
+		return err
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	duration := time.Duration(interval) * time.Second
+	existing := map[string]*repositoryPair{}
+	remaining := repositoryPairs{}
+	for _, item := range d.repoPairs {
+		if item.name == name && item.namespace == namespace && !item.driftCheck {
+			existing[item.pairName] = item
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	primaryMode := compareHash
+	if item, found := existing[""]; found {
+		primaryMode = item.comparisonMode
+	}
+	changed := false
+	for _, pairName := range pairNames {
+		item, found := existing[pairName]
+		delete(existing, pairName)
+		if found && item.interval == duration && item.aligned == aligned {
+			remaining = append(remaining, item)
+			continue
+		}
+		changed = true
+		pair := d.newPair(name, namespace, pairName, duration, aligned, primaryMode)
+		if found {
+			pair.comparisonMode = item.comparisonMode
+			pair.host = item.host
+		}
+		remaining = append(remaining, pair)
+	}
+	// any entry left was dropped from the configuration
+	if len(existing) > 0 {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	d.stopTimer()
+	d.logger.V(1).Info(fmt.Sprintf("New interval or pair set detected for %s in %s: %d second(s)", name, namespace, interval))
+	d.repoPairs = remaining
+	sort.Sort(d.repoPairs)
+	d.refreshSnapshot()
+	// Notify of updates
+	d.updateCh <- struct{}{}
+	return nil
+}
+
+// remove instructs the client to stop monitoring for drifts for the given resource name and
+// namespace, dropping every pair tracked under the pattern key
+func (d *watcher) remove(name, namespace string) error {
+	if d.updateCh == nil {
+		return fmt.Errorf("unable to remove %s in %s when watch has not yet started", name, namespace)
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	remaining := repositoryPairs{}
+	found := false
+	for _, item := range d.repoPairs {
+		if item.name == name && item.namespace == namespace && !item.driftCheck {
+			found = true
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	if !found {
+		return fmt.Errorf("unable to find git remote pair for pattern %s in namespace %s", name, namespace)
+	}
+	d.stopTimer()
+	d.repoPairs = remaining
+	sort.Sort(d.repoPairs)
+	d.refreshSnapshot()
+	deleteCheckMetrics(name, namespace)
+	delete(d.checkHistory, namespace+"/"+name)
+	if err := d.removeCompanionStatus(name, namespace); err != nil {
+		d.logger.Error(err, fmt.Sprintf("failed to remove companion git status for %s in namespace %s", name, namespace))
+	}
+	// Notify of updates
+	d.updateCh <- struct{}{}
+	return nil
+}
+
+// addCheck instructs the client to start monitoring for drifts between the repositories declared
+// by the GitDriftCheck with the given name and namespace
+func (d *watcher) addCheck(name, namespace string, interval int, aligned bool) error {
+	if d.updateCh == nil {
+		return fmt.Errorf("unable to add check %s in %s when watch has not yet started", name, namespace)
+	}
+	lastCheck := d.persistedCheckLastCheck(name, namespace)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.stopTimer()
+	pair := d.newPair(name, namespace, "", time.Duration(interval)*time.Second, aligned, compareHash)
+	pair.driftCheck = true
+	restorePairSchedule(pair, lastCheck)
+	d.repoPairs = append(d.repoPairs, pair)
+	sort.Sort(d.repoPairs)
+	d.refreshSnapshot()
+	// Notify of updates
+	d.updateCh <- struct{}{}
+	return nil
+}
+
+// updateCheckInterval checks whether the new interval or alignment of a GitDriftCheck differ from
+// the stored ones and requeues the pair to ensure the new scheduling is reflected
+func (d *watcher) updateCheckInterval(name, namespace string, interval int, aligned bool) error {
+	if d.updateCh == nil {
+		return fmt.Errorf("unable to update interval for check %s in %s when watch has not yet started", name, namespace)
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	duration := time.Duration(interval) * time.Second
+	for index, item := range d.repoPairs {
+		if item.name != name || item.namespace != namespace || !item.driftCheck {
+			continue
+		}
+		if item.interval == duration && item.aligned == aligned {
+			return nil
+		}
+		d.stopTimer()
+		d.logger.V(1).Info(fmt.Sprintf("New interval detected for check %s in %s: %d second(s)", name, namespace, interval))
+		pair := d.newPair(name, namespace, "", duration, aligned, item.comparisonMode)
+		pair.driftCheck = true
+		pair.host = item.host
+		d.repoPairs[index] = pair
+		sort.Sort(d.repoPairs)
+		d.refreshSnapshot()
+		// Notify of updates
+		d.updateCh <- struct{}{}
+		return nil
+	}
+	return fmt.Errorf("unable to find git remote pair for check %s in namespace %s", name, namespace)
+}
+
+// removeCheck instructs the client to stop monitoring for drifts for the GitDriftCheck with the
+// given name and namespace
+func (d *watcher) removeCheck(name, namespace string) error {
+	if d.updateCh == nil {
+		return fmt.Errorf("unable to remove check %s in %s when watch has not yet started", name, namespace)
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	remaining := repositoryPairs{}
+	found := false
+	for _, item := range d.repoPairs {
+		if item.name == name && item.namespace == namespace && item.driftCheck {
+			found = true
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	if !found {
+		return fmt.Errorf("unable to find git remote pair for check %s in namespace %s", name, namespace)
+	}
+	d.stopTimer()
+	d.repoPairs = remaining
+	sort.Sort(d.repoPairs)
+	d.refreshSnapshot()
+	deleteCheckMetrics(name, namespace)
+	delete(d.checkHistory, namespace+"/"+name)
+	// Notify of updates
+	d.updateCh <- struct{}{}
+	return nil
+}
+
+// pairSnapshot is an immutable copy of the externally interesting state of a pair, published for
+// readers that must not block behind the check loop
+type pairSnapshot struct {
+	name, namespace      string
+	pairName             string
+	drifted              bool
+	checkCount           int64
+	consecutiveFailures  int
+	lastCheck, nextCheck time.Time
+}
+
+// refreshSnapshot publishes an immutable copy of the pair state for lock-free readers. Must be
+// called with the lock held whenever repoPairs or the per-pair state changes
+func (d *watcher) refreshSnapshot() {
+	pairs := make([]pairSnapshot, 0, len(d.repoPairs))
+	for _, item := range d.repoPairs {
+		pairs = append(pairs, pairSnapshot{
+			name:                item.name,
+			namespace:           item.namespace,
+			pairName:            item.pairName,
+			drifted:             item.drifted,
+			checkCount:          item.checkCount,
+			consecutiveFailures: item.consecutiveFailures,
+			lastCheck:           item.lastCheck,
+			nextCheck:           item.nextCheck})
+	}
+	d.snapshot.Store(pairs)
+}
+
+// snapshotPairs returns the last published view of the pairs without taking the lock
+func (d *watcher) snapshotPairs() []pairSnapshot {
+	pairs, _ := d.snapshot.Load().([]pairSnapshot)
+	return pairs
+}
+
+// newCorrelationID generates a random identifier used to correlate the observability surfaces of
+// a single check
+func newCorrelationID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// checkNow reschedules the pair to be checked immediately, tagging the check with the caller's
+// correlation ID so the resulting log lines and condition update can be traced back to the
+// trigger. An empty ID is replaced with a generated one
+func (d *watcher) checkNow(name, namespace, correlationID string) error {
+	if d.updateCh == nil {
+		return fmt.Errorf("unable to trigger a check for %s in %s when watch has not yet started", name, namespace)
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if correlationID == "" {
+		correlationID = newCorrelationID()
+	}
+	found := false
+	for _, item := range d.repoPairs {
+		if item.name == name && item.namespace == namespace && !item.driftCheck {
+			if !found {
+				d.stopTimer()
+			}
+			found = true
+			item.correlationID = correlationID
+			item.nextCheck = time.Now()
+		}
+	}
+	if !found {
+		return fmt.Errorf("unable to find git remote pair for pattern %s in namespace %s", name, namespace)
+	}
+	sort.Sort(d.repoPairs)
+	d.refreshSnapshot()
+	// Notify of updates
+	d.updateCh <- struct{}{}
+	return nil
+}
+
+// checkTimings derives the scheduling delay and the execution duration of a check from the time
+// it started, the time it was scheduled for and the time it finished. A growing scheduling delay
+// points at watcher overload while a growing execution duration points at slow git hosts
+func checkTimings(started, scheduled, finished time.Time) (schedulingDelay, executionDuration time.Duration) {
+	if started.After(scheduled) {
+		schedulingDelay = started.Sub(scheduled)
+	}
+	return schedulingDelay, finished.Sub(started)
+}
+
+// recordCheckTimings publishes the timings of the last completed check to the pattern status
+func recordCheckTimings(kcli client.Client, name, namespace string, schedulingDelay, executionDuration time.Duration) error {
+	var pattern api.Pattern
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := kcli.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &pattern)
+	if err != nil {
+		return err
+	}
+	pattern.Status.LastDriftCheckSchedulingDelayMs = schedulingDelay.Milliseconds()
+	pattern.Status.LastDriftCheckDurationMs = executionDuration.Milliseconds()
+	return kcli.Status().Update(ctx, &pattern)
+}
+
+// recordDriftStatus publishes the outcome of the latest check on the pattern status, keeping the
+// resolved commits and the size of the divergence visible alongside the boolean condition
+func recordDriftStatus(kcli client.Client, pair *repositoryPair, checkErr error) error {
+	var pattern api.Pattern
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := kcli.Get(ctx, types.NamespacedName{Name: pair.name, Namespace: pair.namespace}, &pattern); err != nil {
+		return err
+	}
+	if pair.pairName != "" {
+		// additional pairs report through the per-pair status list; driftStatus stays owned by
+		// the primary pair
+		entry := api.PairDriftStatus{Name: pair.pairName, Drifted: pair.drifted, LastCheck: metav1.NewTime(pair.lastCheck)}
+		if checkErr != nil {
+			entry.LastError = checkErr.Error()
+		}
+		replaced := false
+		for i := range pattern.Status.PairDriftStatuses {
+			if pattern.Status.PairDriftStatuses[i].Name == pair.pairName {
+				pattern.Status.PairDriftStatuses[i] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			pattern.Status.PairDriftStatuses = append(pattern.Status.PairDriftStatuses, entry)
+		}
+		return kcli.Status().Update(ctx, &pattern)
+	}
+	status := &api.DriftStatus{
+		CommitsBehind: pair.commitsBehind,
+		CommitsAhead:  pair.commitsAhead,
+		LastCheck:     metav1.NewTime(pair.lastCheck)}
+	if !pair.originHash.IsZero() {
+		status.OriginSHA = pair.originHash.String()
+	}
+	if !pair.targetHash.IsZero() {
+		status.TargetSHA = pair.targetHash.String()
+	}
+	if !pair.mergeBase.IsZero() {
+		status.MergeBase = pair.mergeBase.String()
+	}
+	if checkErr != nil {
+		status.LastError = checkErr.Error()
+	}
+	pattern.Status.DriftStatus = status
+	pattern.Status.DriftRetryState = nil
+	if pair.consecutiveFailures > 0 {
+		pattern.Status.DriftRetryState = &api.DriftRetryState{
+			ConsecutiveFailures: pair.consecutiveFailures,
+			NextCheck:           metav1.NewTime(pair.nextCheck)}
+	}
+	return kcli.Status().Update(ctx, &pattern)
+}
+
+// recordGitDriftCheckStatus publishes the outcome of the latest check on the status of the
+// GitDriftCheck resource the pair tracks
+func recordGitDriftCheckStatus(kcli client.Client, pair *repositoryPair, checkErr error) error {
+	var check api.GitDriftCheck
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := kcli.Get(ctx, types.NamespacedName{Name: pair.name, Namespace: pair.namespace}, &check); err != nil {
+		return err
+	}
+	check.Status.Drifted = pair.drifted
+	check.Status.CheckCount = pair.checkCount
+	check.Status.ConsecutiveFailures = pair.consecutiveFailures
+	check.Status.LastCheck = metav1.NewTime(pair.lastCheck)
+	check.Status.LastError = ""
+	if checkErr != nil {
+		check.Status.LastError = checkErr.Error()
+	}
+	return kcli.Status().Update(ctx, &check)
+}
+
+// withinFreshnessWindow reports whether a remote timestamp falls within the window ending at now,
+// widening the window by the allowed clock skew so a modestly skewed git server does not flip the
+// decision. The second return value reports that the observed skew — a timestamp further in the
+// future than the tolerance allows — is excessive and worth warning about. All timestamp-based
+// decisions should funnel through this helper so the tolerance is applied uniformly
+func withinFreshnessWindow(now, timestamp time.Time, window, skewTolerance time.Duration) (within, excessiveSkew bool) {
+	age := now.Sub(timestamp)
+	return age < window+skewTolerance, age < -skewTolerance
+}
+
+// effectiveInterval returns the interval to the next check for the configured error-interval
+// policy and the current run of consecutive failures. Shrink halves the interval per failure down
+// to a quarter of the configured value and Grow doubles it up to eight times the configured
+// value; a run of zero failures snaps straight back to the configured interval
+func effectiveInterval(interval time.Duration, policy api.ErrorIntervalPolicyType, consecutiveFailures int) time.Duration {
+	adjusted := interval
+	for i := 0; i < consecutiveFailures; i++ {
+		switch policy {
+		case api.ErrorIntervalShrink:
+			if adjusted /= 2; adjusted < interval/4 {
+				adjusted = interval / 4
+			}
+		case api.ErrorIntervalGrow:
+			if adjusted *= 2; adjusted > interval*8 {
+				adjusted = interval * 8
+			}
+		default:
+			return interval
+		}
+	}
+	return adjusted
+}
+
+// backoffInterval applies the error-interval policy and, while checks are failing, the configured
+// cap and jitter on top, so retries against a struggling host stay bounded and spread out instead
+// of arriving in lockstep. The first completed check snaps straight back to the plain interval
+func backoffInterval(interval time.Duration, policy api.ErrorIntervalPolicyType, consecutiveFailures int, cap, jitter time.Duration) time.Duration {
+	adjusted := effectiveInterval(interval, policy, consecutiveFailures)
+	if consecutiveFailures == 0 {
+		return adjusted
+	}
+	if cap > 0 && adjusted > cap {
+		adjusted = cap
+	}
+	if jitter > 0 {
+		adjusted += time.Duration(mathrand.Int63n(int64(jitter)))
+	}
+	return adjusted
+}
+
+// jitterInterval extends the interval by a random amount of up to the given percentage of its
+// value, so pairs sharing an interval spread out over successive checks instead of querying the
+// git provider in lockstep. A percentage above 100 is treated as 100
+func jitterInterval(interval time.Duration, percent int) time.Duration {
+	if percent <= 0 || interval <= 0 {
+		return interval
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	span := interval / 100 * time.Duration(percent)
+	if span <= 0 {
+		return interval
+	}
+	return interval + time.Duration(mathrand.Int63n(int64(span)))
+}
+
+// nextCheckTime returns the time of the next drift check. When alignment is requested the check is
+// snapped to the next boundary that is a multiple of the interval, so checks land on predictable
+// wall-clock times regardless of when the pair was added
+func nextCheckTime(now time.Time, interval time.Duration, aligned bool) time.Time {
+	if !aligned || interval <= 0 {
+		return now.Add(interval)
+	}
+	return now.Truncate(interval).Add(interval)
+}
+
+// suspend stops scheduling checks while keeping the pair state, so a replica that lost the
+// leader election goes quiet without forgetting what it watches. Outcomes buffered during an API
+// outage are flushed so nothing is lost on the handover
+func (d *watcher) suspend() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.suspended {
+		return
+	}
+	d.suspended = true
+	d.stopTimer()
+	// flushed under the lock like drain does: workers still finishing a check write into the
+	// same buffer
+	d.flushPendingConditions()
+}
+
+// resume restarts the check schedule after the replica won the leader election
+func (d *watcher) resume() {
+	d.mutex.Lock()
+	if !d.suspended {
+		d.mutex.Unlock()
+		return
+	}
+	d.suspended = false
+	d.mutex.Unlock()
+	d.updateCh <- struct{}{}
+}
+
+// drain shuts the watcher down deterministically on operator termination: in-flight checks are
+// aborted through their context, the scheduler loop and the worker pool exit through endCh, and
+// once the last worker has returned the outcomes still buffered are flushed so no status update
+// is lost to the shutdown
+func (d *watcher) drain() {
+	d.drainOnce.Do(func() {
+		if d.checkCancel != nil {
+			d.checkCancel()
+		}
+		close(d.endCh)
+		d.workers.Wait()
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		d.flushPendingConditions()
+		d.flushThrottledConditions()
+	})
+}
+
+func (d *watcher) stopTimer() {
+	// if there is an ongoing timer...
+	if d.timer != nil {
+		// ...stop the timer. Any ongoing timer is no longer valid as there are going to be changes to the slice
+		if d.timer.Stop() {
+			// if the timer function is in progress, at this point is waiting to get the lock. Flag timerCancelled as true to notify the routine to exit as soon as it gets the lock and ensure
+			// that the function does not continue executing, as the order of the slice has changed since the function was triggered and got blocked waiting to get the lock
+			d.timerCancelled = true
+		}
+	}
+}
+
+func (d *watcher) startNewTimer() {
+	atomic.AddInt64(&d.timerRecomputes, 1)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.suspended {
+		// a stand-by replica keeps its schedule empty until it wins the leader election
+		return
+	}
+	// slice is already sorted from a previous call to Add or Remove or from a previous check, but
+	// pairs whose check is still running are skipped: their workers notify again when they finish
+	var nextPair *repositoryPair
+	for _, pair := range d.repoPairs {
+		if !pair.inFlight {
+			nextPair = pair
+			break
+		}
+	}
+	if nextPair == nil {
+		return
+	}
+	nextInterval := time.Until(nextPair.nextCheck)
+	if time.Now().After(nextPair.nextCheck) {
+		// In case there is an overdue check, which would result in a negative value, we set it to 0 so that it is triggered right away
+		d.logger.V(1).Info(fmt.Sprintf("Next interval is negative, resetting to 0 %s: %s - %s\n", nextInterval.String(), time.Now().String(), nextPair.nextCheck.String()))
+		nextInterval = 0
+	}
+	// start a timer and dispatch the due checks to the worker pool when it expires
+	d.timer = time.AfterFunc(nextInterval, func() {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		if d.timerCancelled {
+			// timer has been stopped while the routine was waiting for hold the lock. This means that there has been a change in the order of elements in the slice while it was waiting to obtain the lock
+			// reset the timer cancelled field.
+			d.timerCancelled = false
+			return
+		}
+		d.dispatchDueChecks()
+		select {
+		case d.updateCh <- struct{}{}:
+		case <-d.endCh:
+		}
+	})
+	d.logger.V(1).Info(fmt.Sprintf("New timer started for %s in %s to end on %s", nextPair.name, nextPair.namespace, nextPair.nextCheck.String()))
+}
+
+// dispatchDueChecks hands every pair whose check is due over to the worker pool. Must be called
+// with the lock held. Pairs already being checked are left alone, and when the queue is saturated
+// the pair is rescheduled shortly after instead of blocking the scheduler while holding the lock
+func (d *watcher) dispatchDueChecks() {
+	now := time.Now()
+	for _, pair := range d.repoPairs {
+		if pair.nextCheck.After(now) {
+			// the slice is sorted by nextCheck, so no later pair is due either
+			break
+		}
+		if pair.inFlight {
+			continue
+		}
+		if d.hostLimiter != nil {
+			if admitted, wait := d.hostLimiter.admit(pair.host, now); !admitted {
+				// the host budget is exhausted; reschedule the check for when a token accrues
+				// rather than failing it
+				pair.nextCheck = now.Add(wait)
+				d.logger.V(1).Info(fmt.Sprintf("rate limit for host %s reached, delaying the check for %s in namespace %s by %s", pair.host, pair.name, pair.namespace, wait))
+				continue
+			}
+		}
+		pair.inFlight = true
+		select {
+		case d.checkQueue <- pair:
+		default:
+			pair.inFlight = false
+			pair.nextCheck = now.Add(time.Second)
+			d.logger.Info(fmt.Sprintf("drift check queue is saturated, delaying the check for %s in namespace %s", pair.name, pair.namespace))
+		}
+	}
+	sort.Sort(d.repoPairs)
+}
+
+// hostRateLimiter is a token-bucket limiter keyed by git host, so a fleet of patterns pointing at
+// the same provider never exceeds the configured budget of checks per minute regardless of how
+// many pairs are due at once. Buckets start full and refill continuously at the budget rate
+type hostRateLimiter struct {
+	mutex   sync.Mutex
+	rpm     int
+	buckets map[string]*hostBucket
+}
+
+type hostBucket struct {
+	tokens   float64
+	lastFill time.Time
+}
+
+func newHostRateLimiter(rpm int) *hostRateLimiter {
+	return &hostRateLimiter{rpm: rpm, buckets: map[string]*hostBucket{}}
+}
+
+// admit consumes a token for the host when one is available; otherwise it returns false together
+// with the time until the next token accrues. An empty host is never limited, as a pair only
+// learns its host from the pattern during its first check
+func (h *hostRateLimiter) admit(host string, now time.Time) (bool, time.Duration) {
+	if host == "" {
+		return true, 0
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	bucket, found := h.buckets[host]
+	if !found {
+		bucket = &hostBucket{tokens: float64(h.rpm), lastFill: now}
+		h.buckets[host] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.lastFill).Minutes() * float64(h.rpm)
+	if bucket.tokens > float64(h.rpm) {
+		bucket.tokens = float64(h.rpm)
+	}
+	bucket.lastFill = now
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - bucket.tokens) / float64(h.rpm) * float64(time.Minute))
+	return false, wait
+}
+
+// runChecks is the loop of a single worker of the bounded pool: it performs the checks dispatched
+// by the scheduler until the watcher is shut down
+func (d *watcher) runChecks() {
+	defer d.workers.Done()
+	for {
+		select {
+		case <-d.endCh:
+			return
+		case pair := <-d.checkQueue:
+			d.performCheck(pair)
+			if d.surplusWorker() {
+				return
+			}
+		}
+	}
+}
+
+// setCheckConcurrency resizes the worker pool without a restart: a raised value spawns the
+// missing workers immediately, a lowered one is honoured lazily by surplusWorker as workers
+// finish their next check. Values below one are ignored; before the pool has started the field
+// alone is updated and watch sizes the pool from it
+func (d *watcher) setCheckConcurrency(concurrency int) {
+	if concurrency <= 0 {
+		return
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.checkConcurrency = concurrency
+	if d.checkQueue == nil {
+		return
+	}
+	for ; d.runningWorkers < concurrency; d.runningWorkers++ {
+		d.workers.Add(1)
+		go d.runChecks()
+	}
+}
+
+// surplusWorker reports whether the calling worker exceeds the configured concurrency and, if
+// so, books its exit, so a lowered concurrency drains the pool one finished check at a time
+func (d *watcher) surplusWorker() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.runningWorkers > d.checkConcurrency {
+		d.runningWorkers--
+		return true
+	}
+	return false
+}
+
+// setCheckTimeout bounds the remote operations of a single check, taking effect with the next
+// check dispatched. Values of zero or below are ignored
+func (d *watcher) setCheckTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	d.mutex.Lock()
+	d.checkTimeout = timeout
+	d.mutex.Unlock()
+}
+
+// checkSettings returns the effective concurrency and timeout of the checks under the lock
+func (d *watcher) checkSettings() (int, time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.checkConcurrency, d.checkTimeout
+}
+
+// performCheck runs the drift check of a single pair and persists its outcome. The network
+// comparison runs on a private copy of the pair without the lock so the checks of other pairs
+// proceed in parallel; its results and the bookkeeping are written back under the lock
+func (d *watcher) performCheck(pair *repositoryPair) {
+	d.mutex.Lock()
+	correlationID := pair.correlationID
+	if correlationID == "" {
+		correlationID = newCorrelationID()
+	}
+	pair.correlationID = ""
+	scheduled := pair.nextCheck
+	timeout := d.checkTimeout
+	// the comparison refreshes spec-derived knobs and records observations on the pair it runs
+	// on; it gets a private copy so nothing mutates the live pair outside the lock while sibling
+	// checks and the scheduler read it
+	working := *pair
+	d.mutex.Unlock()
+	logger := d.logger.WithValues("correlationID", correlationID)
+	ctx, cancel := context.WithTimeout(d.checkCtx, timeout)
+	started := time.Now()
+	hasDrifted, err := working.hasDrifted(ctx)
+	finished := time.Now()
+	cancel()
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if !d.holdsPair(pair) {
+		// the pair was removed while its check was running; its outcome is no longer wanted
+		return
+	}
+	pair.absorbCheckResults(&working)
+	pair.schedulingDelay, pair.executionDuration = checkTimings(started, scheduled, finished)
+	pair.checkCount++
+	if err != nil {
+		pair.consecutiveFailures++
+		logger.Error(err, "found error while detecting drift")
+		if failure := attributeCheckError(err); failure != nil && !pair.driftCheck {
+			if d.underMaintenance(pair.namespace) {
+				logger.Info(fmt.Sprintf("namespace %s declares a maintenance window, suppressing condition updates for %s", pair.namespace, pair.name))
+			} else if cErr := d.persistCondition(pair.name, pair.namespace, api.GitCheckFailed, nil, time.Now(), failure); cErr != nil {
+				logger.Error(cErr, fmt.Sprintf("failed to update pattern condition for %s in namespace %s", pair.name, pair.namespace))
+			}
+		}
+		if d.driftSink != nil {
+			report := DriftReport{
+				Name:          pair.name,
+				Namespace:     pair.namespace,
+				Drifted:       pair.drifted,
+				CorrelationID: correlationID,
+				CheckedAt:     time.Now(),
+				Error:         err.Error()}
+			go d.archiveReport(logger, report)
+		}
+	} else {
+		pair.consecutiveFailures = 0
+		pair.drifted = hasDrifted
+		if hasDrifted {
+			pair.consecutiveDrifts++
+			logger.Info(fmt.Sprintf("git repositories have drifted for resource %s in namespace %s", pair.name, pair.namespace))
+		} else {
+			pair.consecutiveDrifts = 0
+		}
+		// aggregate across every pair of the pattern so one drifted pair flips the shared
+		// condition; standalone checks have no pattern condition to flip
+		if !pair.driftCheck {
+			conditionType := api.GitInSync
+			var class *driftClassification
+			if d.patternDrifted(pair.name, pair.namespace) {
+				conditionType = api.GitOutOfSync
+				class = d.patternDriftClass(pair.name, pair.namespace)
+			}
+			if d.underMaintenance(pair.namespace) {
+				logger.Info(fmt.Sprintf("namespace %s declares a maintenance window, suppressing condition updates for %s", pair.namespace, pair.name))
+			} else if err := d.persistCondition(pair.name, pair.namespace, conditionType, class, time.Now(), nil); err != nil {
+				logger.Error(err, fmt.Sprintf("failed to update pattern condition for %s in namespace %s", pair.name, pair.namespace))
+			}
+		}
+		if !d.readOnly && !pair.driftCheck {
+			if err := recordCheckTimings(d.kClient, pair.name, pair.namespace, pair.schedulingDelay, pair.executionDuration); err != nil {
+				logger.Error(err, fmt.Sprintf("failed to record check timings for %s in namespace %s", pair.name, pair.namespace))
+			}
+		}
+		if d.driftSink != nil {
+			report := DriftReport{
+				Name:          pair.name,
+				Namespace:     pair.namespace,
+				Drifted:       hasDrifted,
+				CorrelationID: correlationID,
+				CheckedAt:     time.Now()}
+			go d.archiveReport(logger, report)
+		}
+	}
+	pair.lastCheck = time.Now()
+	interval := backoffInterval(pair.interval, pair.errorPolicy, pair.consecutiveFailures, pair.backoffCap, pair.backoffJitter)
+	if !pair.aligned {
+		// aligned pairs are not jittered: alignment exists to hit exact wall-clock boundaries
+		interval = jitterInterval(interval, d.checkJitterPercent)
+	}
+	pair.nextCheck = nextCheckTime(pair.lastCheck, interval, pair.aligned)
+	recordCheckMetrics(pair, err)
+	d.appendHistory(pair, correlationID, err)
+	if !d.readOnly {
+		statusWriter := recordDriftStatus
+		if pair.driftCheck {
+			statusWriter = recordGitDriftCheckStatus
+		}
+		if sErr := statusWriter(d.kClient, pair, err); sErr != nil {
+			logger.Error(sErr, fmt.Sprintf("failed to record drift status for %s in namespace %s", pair.name, pair.namespace))
+		}
+	}
+	if !pair.driftCheck {
+		if cErr := d.persistCompanionStatus(pair, attributeCheckError(err)); cErr != nil {
+			logger.Error(cErr, fmt.Sprintf("failed to update companion git status for %s in namespace %s", pair.name, pair.namespace))
+		}
+	}
+	d.trackHostOutage(pair, logger, err == nil)
+	pair.inFlight = false
+	// recalculate next timer, unless the watcher is draining and the scheduler loop is gone
+	sort.Sort(d.repoPairs)
+	d.refreshSnapshot()
+	select {
+	case d.updateCh <- struct{}{}:
+	case <-d.endCh:
+	}
+}
+
+// conditionDrift applies the severity thresholds of the pattern to the raw drift outcome: a
+// divergence smaller than the configured commit distance, or one that has not persisted for the
+// configured number of consecutive checks, does not flip the condition. The raw outcome still
+// reaches the drift status of every check
+func (r *repositoryPair) conditionDrift() bool {
+	if !r.drifted {
+		return false
+	}
+	// the distance threshold only applies when a count is available; suppressing the condition
+	// because the divergence could not be counted would hide real drift
+	if r.minCommitsBehind > 0 && r.commitsBehind+r.commitsAhead > 0 && r.commitsBehind < r.minCommitsBehind {
+		return false
+	}
+	if r.sustainedChecks > 1 && r.consecutiveDrifts < r.sustainedChecks {
+		return false
+	}
+	return true
+}
+
+// patternDrifted aggregates the outcomes of all pairs tracked under a pattern key: the pattern
+// counts as drifted as soon as any of its pairs drifted at its last completed check and cleared
+// the severity thresholds of the pattern. Must be called with the lock held
+func (d *watcher) patternDrifted(name, namespace string) bool {
+	for _, item := range d.repoPairs {
+		if item.name == name && item.namespace == namespace && !item.driftCheck && item.conditionDrift() {
+			return true
+		}
+	}
+	return false
+}
+
+// patternDriftClass sums the ahead/behind counts over every condition-drifted pair of the pattern
+// and classifies the side that moved. Pairs whose divergence could not be counted contribute
+// nothing, so a pattern without counts yields nil and the condition keeps its generic wording.
+// Must be called with the lock held
+func (d *watcher) patternDriftClass(name, namespace string) *driftClassification {
+	behind, ahead := 0, 0
+	for _, item := range d.repoPairs {
+		if item.name == name && item.namespace == namespace && !item.driftCheck && item.conditionDrift() {
+			behind += item.commitsBehind
+			ahead += item.commitsAhead
+		}
+	}
+	return classifyDrift(behind, ahead)
+}
+
+// holdsPair reports whether the pair is still part of the watched slice. Must be called with the
+// lock held
+func (d *watcher) holdsPair(pair *repositoryPair) bool {
+	for _, item := range d.repoPairs {
+		if item == pair {
+			return true
+		}
+	}
+	return false
+}
+
+// driftPausedAnnotation pauses drift monitoring for a single pattern without editing its spec.
+// Any value other than "false" counts as paused
+const driftPausedAnnotation = "gitops.hybrid-cloud-patterns.io/drift-paused"
+
+// driftMonitoringPaused reports whether drift monitoring is intentionally paused for the pattern,
+// either through the driftPaused spec field or the drift-paused annotation
+func driftMonitoringPaused(p *api.Pattern) bool {
+	if p.Spec.GitConfig.DriftPaused {
+		return true
+	}
+	value, found := p.Annotations[driftPausedAnnotation]
+	return found && value != "false"
+}
+
+const (
+	// maintenanceAnnotation declares a maintenance window on a namespace. Its value is the
+	// RFC3339 timestamp the window ends at; drift condition flips for every pattern in the
+	// namespace are suppressed until then
+	maintenanceAnnotation = "gitops.hybrid-cloud-patterns.io/maintenance-until"
+	// maintenanceCacheTTL is how long a namespace lookup is reused before consulting the API again
+	maintenanceCacheTTL = time.Minute
+)
+
+// maintenanceEntry caches the maintenance window declared on a namespace
+type maintenanceEntry struct {
+	until, fetched time.Time
+}
+
+// underMaintenance reports whether the namespace currently declares a maintenance window through
+// the maintenance annotation. A missing namespace or an unparsable timestamp count as no window
+func (d *watcher) underMaintenance(namespace string) bool {
+	now := time.Now()
+	if entry, found := d.maintenanceCache[namespace]; found && now.Sub(entry.fetched) < maintenanceCacheTTL {
+		return now.Before(entry.until)
+	}
+	var ns v1.Namespace
+	until := time.Time{}
+	if err := d.kClient.Get(context.Background(), types.NamespacedName{Name: namespace}, &ns); err == nil {
+		if value, found := ns.Annotations[maintenanceAnnotation]; found {
+			if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+				until = parsed
+			}
+		}
+	}
+	if d.maintenanceCache == nil {
+		d.maintenanceCache = map[string]maintenanceEntry{}
+	}
+	d.maintenanceCache[namespace] = maintenanceEntry{until: until, fetched: now}
+	return now.Before(until)
+}
+
+const (
+	// hostOutageThreshold is the number of pairs on the same host that must be failing together
+	// before the failures are treated as a host-wide outage rather than repository-specific errors
+	hostOutageThreshold = 2
+	// defaultHostOutageInterval is the slow cadence pairs on a host in outage are probed at
+	defaultHostOutageInterval = 10 * time.Minute
+)
+
+// trackHostOutage enters or leaves the backed-off outage mode for the host of a pair after one of
+// its checks completes. When every pair on the host is failing, the whole fleet on that host is
+// rescheduled to a slow probing cadence; the first successful probe restores the normal cadence
+// for all of them. Must be called with the lock held
+func (d *watcher) trackHostOutage(pair *repositoryPair, logger logr.Logger, succeeded bool) {
+	if pair.host == "" {
+		return
+	}
+	if succeeded {
+		if d.hostOutages[pair.host] {
+			delete(d.hostOutages, pair.host)
+			logger.Info(fmt.Sprintf("host %s has recovered, restoring the normal cadence for its pairs", pair.host))
+			d.rescheduleHost(pair.host, 0)
+		}
+		return
+	}
+	if !d.hostOutages[pair.host] && d.hostFailing(pair.host) {
+		if d.hostOutages == nil {
+			d.hostOutages = map[string]bool{}
+		}
+		d.hostOutages[pair.host] = true
+		logger.Info(fmt.Sprintf("host %s appears to be in outage, backing off all pairs on it", pair.host))
+	}
+	if d.hostOutages[pair.host] {
+		d.rescheduleHost(pair.host, d.probeInterval())
+	}
+}
+
+// hostFailing returns true when every pair on the host has a run of failed checks and there are
+// enough of them for the failures to look host-wide
+func (d *watcher) hostFailing(host string) bool {
+	failing := 0
+	for _, item := range d.repoPairs {
+		if item.host != host {
+			continue
+		}
+		if item.consecutiveFailures == 0 {
+			return false
+		}
+		failing++
+	}
+	return failing >= hostOutageThreshold
+}
+
+// rescheduleHost aligns the next check of every pair on the host to the given interval, or back to
+// each pair's own interval when zero, so the pairs back off and recover together
+func (d *watcher) rescheduleHost(host string, interval time.Duration) {
+	now := time.Now()
+	for _, item := range d.repoPairs {
+		if item.host != host {
+			continue
+		}
+		pairInterval := interval
+		if pairInterval == 0 {
+			pairInterval = item.interval
+		}
+		item.nextCheck = nextCheckTime(now, pairInterval, item.aligned)
+	}
+}
+
+func (d *watcher) probeInterval() time.Duration {
+	if d.outageInterval > 0 {
+		return d.outageInterval
+	}
+	return defaultHostOutageInterval
+}
+
+// checkRecord is one entry of the bounded in-memory history of check outcomes kept per pattern,
+// so operators can answer when two repositories actually diverged without trawling logs
+type checkRecord struct {
+	Name          string    `json:"name"`
+	Namespace     string    `json:"namespace"`
+	Pair          string    `json:"pair,omitempty"`
+	CheckedAt     time.Time `json:"checkedAt"`
+	Drifted       bool      `json:"drifted"`
+	OriginSHA     string    `json:"originSHA,omitempty"`
+	TargetSHA     string    `json:"targetSHA,omitempty"`
+	CorrelationID string    `json:"correlationID,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// appendHistory records the outcome of a completed check in the history ring of its pattern,
+// dropping the oldest entries beyond the configured limit. Must be called with the lock held
+func (d *watcher) appendHistory(pair *repositoryPair, correlationID string, checkErr error) {
+	limit := d.historyLimit
+	if limit <= 0 {
+		limit = defaultCheckHistoryLimit
+	}
+	if d.checkHistory == nil {
+		d.checkHistory = map[string][]checkRecord{}
+	}
+	record := checkRecord{
+		Name:          pair.name,
+		Namespace:     pair.namespace,
+		Pair:          pair.pairName,
+		CheckedAt:     pair.lastCheck,
+		Drifted:       pair.drifted,
+		CorrelationID: correlationID}
+	if checkErr != nil {
+		record.Error = checkErr.Error()
+	}
+	if pair.originHash != plumbing.ZeroHash {
+		record.OriginSHA = pair.originHash.String()
+	}
+	if pair.targetHash != plumbing.ZeroHash {
+		record.TargetSHA = pair.targetHash.String()
+	}
+	key := pair.namespace + "/" + pair.name
+	history := append(d.checkHistory[key], record)
+	if overflow := len(history) - limit; overflow > 0 {
+		history = history[overflow:]
+	}
+	d.checkHistory[key] = history
+}
+
+// historySnapshot returns a copy of the recorded check outcomes, restricted to one pattern when
+// name and namespace are given, ordered from oldest to newest
+func (d *watcher) historySnapshot(name, namespace string) []checkRecord {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	records := []checkRecord{}
+	for _, history := range d.checkHistory {
+		for _, record := range history {
+			if name != "" && (record.Name != name || record.Namespace != namespace) {
+				continue
+			}
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].CheckedAt.Before(records[j].CheckedAt) })
+	return records
+}
+
+// writeOpenMetrics renders the current per-pair drift state in OpenMetrics/Prometheus text format
+// into the given writer, so that sidecars that cannot scrape the controller metrics endpoint can
+// push the state to a gateway on demand
+func (d *watcher) writeOpenMetrics(w io.Writer) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if _, err := fmt.Fprint(w,
+		"# HELP patterns_operator_drift_detected Whether the origin and target git repositories of the pair have drifted (1) or not (0)\n",
+		"# TYPE patterns_operator_drift_detected gauge\n"); err != nil {
+		return err
+	}
+	for _, pair := range d.repoPairs {
+		drifted := 0
+		if pair.drifted {
+			drifted = 1
+		}
+		if _, err := fmt.Fprintf(w, "patterns_operator_drift_detected{name=%q,namespace=%q} %d\n", pair.name, pair.namespace, drifted); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprint(w,
+		"# HELP patterns_operator_drift_checks_total Number of drift checks performed for the pair\n",
+		"# TYPE patterns_operator_drift_checks_total counter\n"); err != nil {
+		return err
+	}
+	for _, pair := range d.repoPairs {
+		if _, err := fmt.Fprintf(w, "patterns_operator_drift_checks_total{name=%q,namespace=%q} %d\n", pair.name, pair.namespace, pair.checkCount); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprint(w,
+		"# HELP patterns_operator_drift_last_check_timestamp_seconds Unix timestamp of the last completed drift check for the pair\n",
+		"# TYPE patterns_operator_drift_last_check_timestamp_seconds gauge\n"); err != nil {
+		return err
+	}
+	for _, pair := range d.repoPairs {
+		if _, err := fmt.Fprintf(w, "patterns_operator_drift_last_check_timestamp_seconds{name=%q,namespace=%q} %d\n", pair.name, pair.namespace, pair.lastCheck.Unix()); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprint(w,
+		"# HELP patterns_operator_drift_check_scheduling_delay_seconds How late the last drift check started relative to its schedule\n",
+		"# TYPE patterns_operator_drift_check_scheduling_delay_seconds gauge\n"); err != nil {
+		return err
+	}
+	for _, pair := range d.repoPairs {
+		if _, err := fmt.Fprintf(w, "patterns_operator_drift_check_scheduling_delay_seconds{name=%q,namespace=%q} %g\n", pair.name, pair.namespace, pair.schedulingDelay.Seconds()); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprint(w,
+		"# HELP patterns_operator_drift_check_duration_seconds Time the last drift check spent comparing the repositories\n",
+		"# TYPE patterns_operator_drift_check_duration_seconds gauge\n"); err != nil {
+		return err
+	}
+	for _, pair := range d.repoPairs {
+		if _, err := fmt.Fprintf(w, "patterns_operator_drift_check_duration_seconds{name=%q,namespace=%q} %g\n", pair.name, pair.namespace, pair.executionDuration.Seconds()); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprint(w,
+		"# HELP patterns_operator_drift_report_write_failures_total Number of drift reports that could not be written to the archival sink\n",
+		"# TYPE patterns_operator_drift_report_write_failures_total counter\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "patterns_operator_drift_report_write_failures_total %d\n", atomic.LoadInt64(&d.sinkWriteFailures)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// archiveReport writes the report to the configured sink without blocking the check loop,
+// accounting failed writes so they surface through the metrics output
+func (d *watcher) archiveReport(logger logr.Logger, report DriftReport) {
+	if err := d.driftSink.Archive(report); err != nil {
+		atomic.AddInt64(&d.sinkWriteFailures, 1)
+		logger.Error(err, fmt.Sprintf("failed to archive drift report for %s in namespace %s", report.Name, report.Namespace))
+	}
+}
+
+// persistCompanionStatus creates or updates the companion PatternGitStatus resource of a pair with
+// the rich state of its latest check. A no-op unless companion status is enabled, and never writes
+// in read-only mode
+func (d *watcher) persistCompanionStatus(pair *repositoryPair, failure *api.CheckFailure) error {
+	if !d.companionStatus || d.readOnly {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var companion api.PatternGitStatus
+	err := d.kClient.Get(ctx, types.NamespacedName{Name: pair.name, Namespace: pair.namespace}, &companion)
+	if kerrors.IsNotFound(err) {
+		companion = api.PatternGitStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: pair.name, Namespace: pair.namespace},
+			Spec:       api.PatternGitStatusSpec{PatternName: pair.name}}
+		if err := d.kClient.Create(ctx, &companion); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	matched := make([]string, 0, len(pair.targetMatchedRefs))
+	for _, name := range pair.targetMatchedRefs {
+		matched = append(matched, name.String())
+	}
+	companion.Status = api.PatternGitStatusStatus{
+		Drifted:             pair.drifted,
+		CheckCount:          pair.checkCount,
+		ConsecutiveFailures: pair.consecutiveFailures,
+		LastCheck:           metav1.Time{Time: pair.lastCheck},
+		TargetPrimaryRef:    pair.targetPrimaryRef.String(),
+		TargetMatchedRefs:   matched,
+		DriftedBranches:     pair.driftedBranches,
+		LastCheckFailure:    failure}
+	return d.kClient.Status().Update(ctx, &companion)
+}
+
+// removeCompanionStatus deletes the companion resource of a pair that is no longer watched
+func (d *watcher) removeCompanionStatus(name, namespace string) error {
+	if !d.companionStatus || d.readOnly {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	companion := api.PatternGitStatus{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if err := d.kClient.Delete(ctx, &companion); err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// persistCondition updates the drift conditions for a pair, buffering the outcome when the API is
+// unavailable so the latest result is not lost. Previously buffered outcomes are flushed first, so
+// recovery is detected on the next check of any pair
+func (d *watcher) persistCondition(name, namespace string, conditionType api.PatternConditionType, driftClass *driftClassification, timestamp time.Time, failure *api.CheckFailure) error {
+	if d.readOnly {
+		return nil
+	}
+	d.flushPendingConditions()
+	d.flushThrottledConditions()
+	key := namespace + "/" + name
+	if d.statusWriteInterval > 0 {
+		if last, found := d.lastStatusWrites[key]; found && time.Since(last) < d.statusWriteInterval {
+			// coalesce: remember only the latest outcome until the write slot for the pattern reopens
+			if d.throttledConditions == nil {
+				d.throttledConditions = map[string]pendingCondition{}
+			}
+			d.throttledConditions[key] = pendingCondition{
+				name:          name,
+				namespace:     namespace,
+				conditionType: conditionType,
+				driftClass:    driftClass,
+				timestamp:     timestamp,
+				failure:       failure}
+			return nil
+		}
+	}
+	err := updatePatternConditions(d.kClient, d.recorder, conditionType, driftClass, name, namespace, timestamp, failure)
+	if err == nil {
+		if d.lastStatusWrites == nil {
+			d.lastStatusWrites = map[string]time.Time{}
+		}
+		d.lastStatusWrites[key] = time.Now()
+		delete(d.throttledConditions, key)
+	}
+	if err != nil {
+		if d.pendingConditions == nil {
+			d.pendingConditions = map[string]pendingCondition{}
+		}
+		d.pendingConditions[namespace+"/"+name] = pendingCondition{
+			name:          name,
+			namespace:     namespace,
+			conditionType: conditionType,
+			driftClass:    driftClass,
+			timestamp:     timestamp,
+			failure:       failure}
+	}
+	return err
+}
+
+// flushPendingConditions retries the buffered outcomes. The first failure stops the flush, as it
+// indicates the API is still unavailable and the remaining entries would fail the same way
+func (d *watcher) flushPendingConditions() {
+	for key, pending := range d.pendingConditions {
+		if err := updatePatternConditions(d.kClient, d.recorder, pending.conditionType, pending.driftClass, pending.name, pending.namespace, pending.timestamp, pending.failure); err != nil {
+			return
+		}
+		delete(d.pendingConditions, key)
+	}
+}
+
+// flushThrottledConditions writes the latest coalesced outcome of every pattern whose write slot
+// has reopened, so a throttled pattern still converges to its final state
+func (d *watcher) flushThrottledConditions() {
+	for key, item := range d.throttledConditions {
+		if last, found := d.lastStatusWrites[key]; found && time.Since(last) < d.statusWriteInterval {
+			continue
+		}
+		if err := updatePatternConditions(d.kClient, d.recorder, item.conditionType, item.driftClass, item.name, item.namespace, item.timestamp, item.failure); err == nil {
+			d.lastStatusWrites[key] = time.Now()
+			delete(d.throttledConditions, key)
+		}
+	}
+}
+
+// driftWatcherLeaseHolder identifies the watcher as the holder of the heartbeat Lease
+const driftWatcherLeaseHolder = "patterns-operator-drift-watcher"
+
+// renewLease refreshes the renew time of the heartbeat Lease so external systems can detect a
+// wedged or dead watcher from the Lease alone. The Lease is created on first renewal and renewals
+// happen from the notification loop, so a stalled loop stops renewing
+func (d *watcher) renewLease() error {
+	if d.leaseName == "" || d.readOnly {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := metav1.NewMicroTime(time.Now())
+	var lease coordinationv1.Lease
+	err := d.kClient.Get(ctx, types.NamespacedName{Name: d.leaseName, Namespace: d.leaseNamespace}, &lease)
+	if kerrors.IsNotFound(err) {
+		holder := driftWatcherLeaseHolder
+		lease = coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: d.leaseName, Namespace: d.leaseNamespace},
+			Spec:       coordinationv1.LeaseSpec{HolderIdentity: &holder, RenewTime: &now}}
+		return d.kClient.Create(ctx, &lease)
+	}
+	if err != nil {
+		return err
+	}
+	lease.Spec.RenewTime = &now
+	return d.kClient.Update(ctx, &lease)
+}
+
+// watch starts the process of monitoring the drifts. The call returns a channel to be used to manage
+// the closure of the monitoring routine cleanly.
+func (d *watcher) watch() chan interface{} {
+	if d.updateCh != nil {
+		return d.endCh
+	}
+	// ready to start processing notifications
 	d.updateCh = make(chan interface{})
+	if d.checkConcurrency <= 0 {
+		d.checkConcurrency = defaultCheckConcurrency
+	}
+	if d.checkTimeout <= 0 {
+		d.checkTimeout = defaultCheckTimeout
+	}
+	d.checkCtx, d.checkCancel = context.WithCancel(context.Background())
+	d.checkQueue = make(chan *repositoryPair, checkQueueCapacity)
+	d.workers.Add(d.checkConcurrency)
+	d.runningWorkers = d.checkConcurrency
+	for worker := 0; worker < d.checkConcurrency; worker++ {
+		go d.runChecks()
+	}
+	d.workers.Add(1)
 	go func() {
+		// an exit for any reason leaves the watcher unable to schedule checks; flag it so the
+		// health check reports the operator as wedged
+		defer d.workers.Done()
+		defer atomic.StoreInt32(&d.loopExited, 1)
 		for {
 			select {
 			case <-d.endCh:
 				if d.timer != nil {
 					d.timer.Stop()
 				}
+				d.checkCancel()
 				return
 			case <-d.updateCh:
+				if err := d.renewLease(); err != nil {
+					d.logger.Error(err, "failed to renew drift watcher lease")
+				}
+				if delay := d.coalesceDelay(); delay > 0 {
+					// absorb the rest of the burst so it costs a single timer recompute
+					deadline := time.After(delay)
+				coalesce:
+					for {
+						select {
+						case <-d.endCh:
+							if d.timer != nil {
+								d.timer.Stop()
+							}
+							d.checkCancel()
+							return
+						case <-d.updateCh:
+						case <-deadline:
+							break coalesce
+						}
+					}
+				}
 				go d.startNewTimer()
 			}
 		}
@@ -326,54 +3474,350 @@ func (d *watcher) watch() chan interface{} {
 	return d.endCh
 }
 
-func updatePatternConditions(kcli client.Client, conditionType api.PatternConditionType, name, namespace string, timestamp time.Time) error {
+// coalesceDelay returns the length of the current coalescing window: the configured window plus a
+// random jitter of up to coalesceJitter, so replicas watching the same patterns do not recompute
+// their timers at the exact same instant. A zero window disables coalescing
+func (d *watcher) coalesceDelay() time.Duration {
+	if d.coalesceWindow <= 0 {
+		return 0
+	}
+	delay := d.coalesceWindow
+	if d.coalesceJitter > 0 {
+		delay += time.Duration(mathrand.Int63n(int64(d.coalesceJitter)))
+	}
+	return delay
+}
+
+// transitionMessage renders the message of a drift condition raised on a transition, stating the
+// state it replaces and how long that state lasted, so the condition alone tells how long things
+// were healthy or drifted
+func transitionMessage(previous api.PatternConditionType, held time.Duration, current api.PatternConditionType) string {
+	return fmt.Sprintf("was %s for %s, now %s", previous, formatHeldDuration(held), current)
+}
+
+// formatHeldDuration renders how long a state lasted, dropping the trailing zero components that
+// would only add noise, e.g. "3h12m" rather than "3h12m0s"
+func formatHeldDuration(held time.Duration) string {
+	if held < 0 {
+		held = 0
+	}
+	rendered := held.Round(time.Second).String()
+	if strings.HasSuffix(rendered, "m0s") {
+		rendered = strings.TrimSuffix(rendered, "0s")
+	}
+	if strings.HasSuffix(rendered, "h0m") {
+		rendered = strings.TrimSuffix(rendered, "0m")
+	}
+	return rendered
+}
+
+// failureMessage renders the attribution of a failed check as the condition message, naming the
+// failing remote, its host when known and the class of the failure
+func failureMessage(failure *api.CheckFailure) string {
+	location := failure.Side
+	if failure.Host != "" {
+		location = fmt.Sprintf("%s (host %s)", failure.Side, failure.Host)
+	}
+	return fmt.Sprintf("drift check failed on %s: %s: %s", location, failure.Class, failure.Message)
+}
+
+// reasons of the events emitted on drift condition transitions
+const (
+	eventReasonDriftDetected = "GitDriftDetected"
+	eventReasonBackInSync    = "GitBackInSync"
+	eventReasonCheckFailed   = "DriftCheckFailed"
+	eventReasonInsecureTLS   = "InsecureTLSVerification"
+)
+
+// classification of a drift by the side holding the extra commits; the remediation differs per
+// class, so the condition carries the class as its reason and spells out the fix in its message
+// when the ahead/behind counts of the drifted pairs could be computed
+const (
+	driftClassUpstreamAhead = "UpstreamAhead"
+	driftClassForkAhead     = "ForkAhead"
+	driftClassDiverged      = "Diverged"
+)
+
+// driftClassification names the side holding the extra commits together with the counts it was
+// derived from, so the condition can describe the remediation that resolves the drift
+type driftClassification struct {
+	class         string
+	behind, ahead int
+}
+
+// classifyDrift maps the ahead/behind counts of a drift onto the side that moved; counts that
+// could not be computed yield nil and the generic condition reason and message are kept
+func classifyDrift(behind, ahead int) *driftClassification {
+	c := driftClassification{behind: behind, ahead: ahead}
+	switch {
+	case behind > 0 && ahead == 0:
+		c.class = driftClassUpstreamAhead
+	case ahead > 0 && behind == 0:
+		c.class = driftClassForkAhead
+	case behind > 0 && ahead > 0:
+		c.class = driftClassDiverged
+	default:
+		return nil
+	}
+	return &c
+}
+
+// message renders the remediation-oriented description of the classified drift
+func (c *driftClassification) message() string {
+	switch c.class {
+	case driftClassUpstreamAhead:
+		return fmt.Sprintf("origin is ahead by %d commit(s) the target does not have; syncing the target forward resolves the drift", c.behind)
+	case driftClassForkAhead:
+		return fmt.Sprintf("target holds %d commit(s) of its own; merging or rebasing them onto the origin resolves the drift", c.ahead)
+	}
+	return fmt.Sprintf("histories diverged: origin is ahead by %d commit(s) and target by %d; the branches need to be reconciled", c.behind, c.ahead)
+}
+
+// driftConditionReason maps a drift condition onto the machine-readable reason recorded on it,
+// reusing the event reason vocabulary where one exists
+func driftConditionReason(conditionType api.PatternConditionType) string {
+	if _, reason := transitionEvent(conditionType); reason != "" {
+		return reason
+	}
+	return string(conditionType)
+}
+
+// transitionEvent maps a drift condition onto the type and reason of the event emitted when the
+// pattern transitions into it; condition types without an event mapping return an empty reason
+func transitionEvent(conditionType api.PatternConditionType) (eventType, reason string) {
+	switch conditionType {
+	case api.GitOutOfSync:
+		return v1.EventTypeWarning, eventReasonDriftDetected
+	case api.GitInSync:
+		return v1.EventTypeNormal, eventReasonBackInSync
+	case api.GitCheckFailed:
+		return v1.EventTypeWarning, eventReasonCheckFailed
+	}
+	return "", ""
+}
+
+func updatePatternConditions(kcli client.Client, recorder record.EventRecorder, conditionType api.PatternConditionType, driftClass *driftClassification, name, namespace string, timestamp time.Time, failure *api.CheckFailure) error {
 	var pattern api.Pattern
+	var message string
+	var transitioned bool
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	// fetch the pattern object
-	err := kcli.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &pattern)
+	// the watcher and the reconciler both write the pattern status; on a resource version
+	// conflict the read-modify-write is repeated against the fresh object instead of dropping
+	// the check outcome
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pattern = api.Pattern{}
+		transitioned = false
+		// fetch the pattern object
+		if err := kcli.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &pattern); err != nil {
+			return err
+		}
+		// the patch computed against this copy carries only the fields the watcher touches, so a
+		// status field the reconciler wrote after our read is never reverted by this update
+		base := pattern.DeepCopy()
+		// demote any other active drift condition. Normally there is at most one, but bugs or
+		// external writers could have left both GitInSync and GitOutOfSync at true, so we correct
+		// an inconsistent state rather than building on it
+		var previous *api.PatternCondition
+		for i := range pattern.Status.Conditions {
+			current := &pattern.Status.Conditions[i]
+			if isDriftCondition(current.Type) && current.Type != conditionType && current.Status == v1.ConditionTrue {
+				previous = current
+				// mark the current condition with status false and update timestamp
+				current.Status = v1.ConditionFalse
+				current.LastUpdateTime = metav1.Time{Time: timestamp}
+				current.Reason = conditionReason(current.Type, v1.ConditionFalse)
+			}
+		}
+		message = conditionMsgs[conditionType]
+		reason := driftConditionReason(conditionType)
+		if driftClass != nil && conditionType == api.GitOutOfSync {
+			// the classified message names the side holding the extra commits and the remediation
+			// for it; upstream moving forward calls for a sync, fork-local commits for a merge
+			message = driftClass.message()
+			reason = driftClass.class
+		}
+		if previous != nil && pattern.Spec.GitConfig.DetailedTransitionMessages {
+			message = transitionMessage(previous.Type, timestamp.Sub(previous.LastTransitionTime.Time), conditionType)
+			if driftClass != nil && conditionType == api.GitOutOfSync {
+				message = fmt.Sprintf("%s: %s", message, driftClass.message())
+			}
+		}
+		// record which remote broke the check, or clear the attribution once a check completes again
+		pattern.Status.LastCheckFailure = failure
+		if failure != nil {
+			message = failureMessage(failure)
+		}
+		// get the condition by status
+		index, condition := getPatternConditionByType(pattern.Status.Conditions, conditionType)
+		if condition == nil {
+			// condition not yet found, so we create a new one
+			condition = &api.PatternCondition{
+				Type:               conditionType,
+				Status:             v1.ConditionTrue,
+				LastUpdateTime:     metav1.Time{Time: timestamp},
+				LastTransitionTime: metav1.Time{Time: timestamp},
+				Reason:             reason,
+				ObservedGeneration: pattern.Generation,
+				Message:            message}
+			pattern.Status.Conditions = append(pattern.Status.Conditions, *condition)
+			transitioned = true
+		} else {
+			condition.LastUpdateTime = metav1.Time{Time: timestamp}
+			condition.Reason = reason
+			condition.ObservedGeneration = pattern.Generation
+			if driftClass != nil && conditionType == api.GitOutOfSync {
+				// the counts move with every check, so the classified message is refreshed even
+				// when the condition itself does not transition
+				condition.Message = message
+			}
+			if condition.Status != v1.ConditionTrue {
+				// Not current condition, so we make it so
+				condition.Status = v1.ConditionTrue
+				condition.LastTransitionTime = metav1.Time{Time: timestamp}
+				condition.Message = message
+				transitioned = true
+			}
+			pattern.Status.Conditions[index] = *condition
+		}
+		pattern.Status.Conditions = pruneStaleGitConditions(pattern.Status.Conditions)
+		// the watcher read the pattern fresh above, so its contribution reflects this generation
+		pattern.Status.ObservedGeneration = pattern.Generation
+		// the optimistic lock keeps the conflict detection of the retry loop: two writers racing
+		// on the conditions still conflict instead of silently overwriting each other
+		return kcli.Status().Patch(ctx, &pattern, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	})
 	if err != nil {
 		return err
 	}
-	// get the current active condition
-	currentIndex, currentCondition := getPatternConditionByStatus(pattern.Status.Conditions, v1.ConditionTrue)
-	if currentCondition != nil && currentCondition.Type != conditionType {
-		// mark the current condition with status false and update timestamp
-		currentCondition.Status = v1.ConditionFalse
-		currentCondition.LastUpdateTime = metav1.Time{Time: timestamp}
-		pattern.Status.Conditions[currentIndex] = *currentCondition
-	}
-	// get the condition by status
-	index, condition := getPatternConditionByType(pattern.Status.Conditions, conditionType)
-	if condition == nil {
-		// condition not yet found, so we create a new one
-		condition = &api.PatternCondition{
-			Type:               conditionType,
-			Status:             v1.ConditionTrue,
-			LastUpdateTime:     metav1.Time{Time: timestamp},
-			LastTransitionTime: metav1.Time{Time: timestamp},
-			Message:            conditionMsgs[conditionType]}
-		pattern.Status.Conditions = append(pattern.Status.Conditions, *condition)
-		return kcli.Status().Update(ctx, &pattern)
+	// surface the transition where kubectl describe and event-based tooling can see it
+	if transitioned && recorder != nil {
+		if eventType, reason := transitionEvent(conditionType); reason != "" {
+			recorder.Event(&pattern, eventType, reason, message)
+		}
 	}
-	condition.LastUpdateTime = metav1.Time{Time: timestamp}
-	if condition.Status == v1.ConditionTrue {
-		pattern.Status.Conditions[index] = *condition
-		return kcli.Status().Update(ctx, &pattern)
+	return nil
+}
+
+// pruneStaleGitConditions removes watcher-owned conditions that are no longer active so the
+// conditions list stays tidy as dedicated condition types proliferate. The complementary
+// GitInSync/GitOutOfSync pair is retained regardless of status and conditions managed by other
+// components are never touched
+func pruneStaleGitConditions(conditions []api.PatternCondition) []api.PatternCondition {
+	result := make([]api.PatternCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		if isGitCondition(condition.Type) && !isDriftCondition(condition.Type) && condition.Status != v1.ConditionTrue {
+			continue
+		}
+		result = append(result, condition)
 	}
-	// Not current condition, so we make it so
-	condition.Status = v1.ConditionTrue
-	condition.LastTransitionTime = metav1.Time{Time: timestamp}
-	pattern.Status.Conditions[index] = *condition
-	return kcli.Status().Update(ctx, &pattern)
+	return result
 }
 
-func getHeadBranch(refs []*plumbing.Reference) *plumbing.Reference {
-	headRef := getReferenceByName(refs, plumbing.HEAD)
-	if headRef == nil {
+// persistPairCondition raises an informational condition for the pair unless the watcher runs in
+// read-only mode, in which case the outcome still surfaces through logs and metrics only
+func (r *repositoryPair) persistPairCondition(conditionType api.PatternConditionType, message string, timestamp time.Time) error {
+	if r.readOnly {
 		return nil
 	}
-	return getReferenceByName(refs, headRef.Target())
+	return setPatternCondition(r.kClient, conditionType, r.name, r.namespace, message, timestamp)
+}
+
+// setPatternCondition adds or refreshes an informational condition without touching the drift
+// pair of conditions, with the same write discipline as updatePatternConditions: retried on
+// conflicts against a fresh read, and patched so status fields written by the reconciler after
+// our read are never reverted
+func setPatternCondition(kcli client.Client, conditionType api.PatternConditionType, name, namespace, message string, timestamp time.Time) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var pattern api.Pattern
+		if err := kcli.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &pattern); err != nil {
+			return err
+		}
+		base := pattern.DeepCopy()
+		index, condition := getPatternConditionByType(pattern.Status.Conditions, conditionType)
+		if condition == nil {
+			pattern.Status.Conditions = append(pattern.Status.Conditions, api.PatternCondition{
+				Type:               conditionType,
+				Status:             v1.ConditionTrue,
+				LastUpdateTime:     metav1.Time{Time: timestamp},
+				LastTransitionTime: metav1.Time{Time: timestamp},
+				Reason:             driftConditionReason(conditionType),
+				ObservedGeneration: pattern.Generation,
+				Message:            message})
+		} else {
+			condition.LastUpdateTime = metav1.Time{Time: timestamp}
+			condition.Reason = driftConditionReason(conditionType)
+			condition.ObservedGeneration = pattern.Generation
+			if condition.Status != v1.ConditionTrue {
+				condition.Status = v1.ConditionTrue
+				condition.LastTransitionTime = metav1.Time{Time: timestamp}
+			}
+			condition.Message = message
+			pattern.Status.Conditions[index] = *condition
+		}
+		// the optimistic lock keeps the conflict detection of the retry loop: two writers racing
+		// on the conditions still conflict instead of silently overwriting each other
+		return kcli.Status().Patch(ctx, &pattern, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	})
+}
+
+// kubeRevisionResolver reads the revision a pattern should be running from a field on an
+// in-cluster resource, reusing the client already present on the watcher
+type kubeRevisionResolver struct {
+	kClient client.Client
+}
+
+func newKubeRevisionResolver(kcli client.Client) RevisionResolver {
+	return &kubeRevisionResolver{kClient: kcli}
+}
+
+// Resolve fetches the resource referenced by the pattern and reads the revision from the
+// configured dot-separated field path
+func (k *kubeRevisionResolver) Resolve(p *api.Pattern) (plumbing.Hash, error) {
+	ref := p.Spec.GitConfig.RevisionFrom
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = p.Namespace
+	}
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := k.kClient.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, u); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to fetch revision source %s %s in %s: %w", ref.Kind, ref.Name, namespace, err)
+	}
+	value, found, err := unstructured.NestedString(u.Object, strings.Split(ref.FieldPath, ".")...)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if !found || value == "" {
+		return plumbing.ZeroHash, fmt.Errorf("field %s not found in revision source %s %s in %s", ref.FieldPath, ref.Kind, ref.Name, namespace)
+	}
+	return plumbing.NewHash(value), nil
+}
+
+func getHeadBranch(refs []*plumbing.Reference) *plumbing.Reference {
+	return resolveReference(refs, plumbing.HEAD)
+}
+
+// maxSymrefDepth bounds the length of a symbolic reference chain to protect against reference cycles
+const maxSymrefDepth = 10
+
+// resolveReference follows symbolic references until a hash reference is found, so that revisions
+// pointing to server-side aliases (e.g. a `stable` symref maintained by a mirror) resolve to the
+// hash of the underlying branch
+func resolveReference(refs []*plumbing.Reference, referenceName plumbing.ReferenceName) *plumbing.Reference {
+	ref := getReferenceByName(refs, referenceName)
+	for depth := 0; ref != nil && ref.Type() == plumbing.SymbolicReference; depth++ {
+		if depth == maxSymrefDepth {
+			return nil
+		}
+		ref = getReferenceByName(refs, ref.Target())
+	}
+	return ref
 }
 func getReferenceByName(refs []*plumbing.Reference, referenceName plumbing.ReferenceName) *plumbing.Reference {
 	for _, ref := range refs {
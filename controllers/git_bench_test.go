@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// benchmarkPairReads measures a read strategy against a writer standing in for the check loop,
+// which mutates the pair state and republishes the snapshot the way the watcher does
+func benchmarkPairReads(b *testing.B, read func(*watcher) int) {
+	watch := &watcher{mutex: &sync.Mutex{}, repoPairs: repositoryPairs{}}
+	for i := 0; i < 100; i++ {
+		watch.repoPairs = append(watch.repoPairs, &repositoryPair{
+			name:       fmt.Sprintf("pair-%d", i),
+			namespace:  "default",
+			checkCount: int64(i)})
+	}
+	watch.refreshSnapshot()
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			watch.mutex.Lock()
+			watch.repoPairs[0].checkCount++
+			watch.refreshSnapshot()
+			watch.mutex.Unlock()
+		}
+	}()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			read(watch)
+		}
+	})
+	b.StopTimer()
+	close(stop)
+}
+
+// BenchmarkLockedPairReads is the lock-per-read baseline: every reader contends with the check
+// loop for the watcher lock
+func BenchmarkLockedPairReads(b *testing.B) {
+	benchmarkPairReads(b, func(d *watcher) int {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		total := 0
+		for _, item := range d.repoPairs {
+			total += int(item.checkCount)
+		}
+		return total
+	})
+}
+
+// BenchmarkSnapshotPairReads reads the atomically-swapped immutable view instead of taking the
+// lock
+func BenchmarkSnapshotPairReads(b *testing.B) {
+	benchmarkPairReads(b, func(d *watcher) int {
+		total := 0
+		for _, item := range d.snapshotPairs() {
+			total += int(item.checkCount)
+		}
+		return total
+	})
+}
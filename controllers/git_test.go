@@ -1,23 +1,46 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	gossh "golang.org/x/crypto/ssh"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	v1core "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
@@ -72,6 +95,25 @@ var (
 
 	noCommits    = []*plumbing.Reference{plumbing.NewSymbolicReference(plumbing.HEAD, mainReference)}
 	emptyCommits = []*plumbing.Reference{}
+
+	stableReference   plumbing.ReferenceName = "refs/heads/stable"
+	symrefToMainHeads                        = []*plumbing.Reference{
+		plumbing.NewSymbolicReference(plumbing.HEAD, mainReference),
+		plumbing.NewSymbolicReference(stableReference, mainReference),
+		plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitMainHead))}
+	symrefCycleHeads = []*plumbing.Reference{
+		plumbing.NewSymbolicReference(stableReference, plumbing.NewBranchReferenceName("blessed")),
+		plumbing.NewSymbolicReference(plumbing.NewBranchReferenceName("blessed"), stableReference)}
+
+	noHeadMultiBranchReference = []*plumbing.Reference{
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("zeta"), plumbing.NewHash(hashCommitTestBranch)),
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("alpha"), plumbing.NewHash(hashCommitMainHead)),
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("beta"), plumbing.NewHash(hashCommitBugfixBranch))}
+
+	taggedCommitsReference = []*plumbing.Reference{
+		plumbing.NewSymbolicReference(plumbing.HEAD, mainReference),
+		plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitAmendedHead)),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.0.0"), plumbing.NewHash(hashCommitMainHead))}
 )
 var _ = Describe("Git client", func() {
 
@@ -117,18 +159,18 @@ var _ = Describe("Git client", func() {
 			}
 			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
 			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
-			mockRemoteClientOrigin.EXPECT().List(&git.ListOptions{}).Times(1).Return(originRefs, errOriginList)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(originRefs, errOriginList)
 			if errOriginList == nil {
-				mockRemoteClientTarget.EXPECT().List(&git.ListOptions{}).Times(1).Return(targetRefs, errTargetList)
+				mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(targetRefs, errTargetList)
 			}
 
-			hasDrifted, e := remote.hasDrifted()
+			hasDrifted, e := remote.hasDrifted(context.Background())
 			if e != nil {
 				switch {
 				case errOriginList != nil:
-					Expect(e).To(Equal(errOriginList))
+					Expect(e).To(MatchError(errOriginList))
 				case errTargetList != nil:
-					Expect(e).To(Equal(errTargetList))
+					Expect(e).To(MatchError(errTargetList))
 				case errOriginFilter != nil:
 					Expect(e).To(Equal(errOriginFilter))
 				case errTargetFilter != nil:
@@ -147,6 +189,14 @@ var _ = Describe("Git client", func() {
 			Entry("Multiple commit with head main and branches with different hash", multipleCommitsReference, multipleCommitsWithDifferentHashReference, "", "", true, nil, nil, nil, nil),
 			Entry("One commit with head main and target reference with the same hash", firstCommitReference, multipleCommitsReference, "", "test", false, nil, nil, nil, nil),
 			Entry("One commit with origin reference and target reference with the same hash", firstCommitReference, multipleCommitsReference, "test", "test", false, nil, nil, nil, nil),
+			Entry("Target revision resolving through a custom symbolic ref with the same hash", firstCommitReference, symrefToMainHeads, "", "stable", false, nil, nil, nil, nil),
+			Entry("Origin revision resolving through a custom symbolic ref with a different hash", symrefToMainHeads, firstCommitAmendedReference, "stable", "", true, nil, nil, nil, nil),
+			Entry("Target revision naming a tag with the same hash", firstCommitReference, taggedCommitsReference, "", "v1.0.0", false, nil, nil, nil, nil),
+			Entry("Target revision naming a tag with a different hash", firstCommitAmendedReference, taggedCommitsReference, "", "v1.0.0", true, nil, nil, nil, nil),
+			Entry("Target revision naming a fully-qualified tag ref", firstCommitReference, taggedCommitsReference, "", "refs/tags/v1.0.0", false, nil, nil, nil, nil),
+			Entry("Branch shadowing a tag of the same name wins", taggedCommitsReference, taggedCommitsReference, "main", "v1.0.0", true, nil, nil, nil, nil),
+			Entry("Origin revision pinned to a raw commit SHA with the same hash", firstCommitReference, firstCommitReference, hashCommitMainHead, "", false, nil, nil, nil, nil),
+			Entry("Target revision pinned to a raw commit SHA with a different hash", firstCommitAmendedReference, firstCommitReference, "", hashCommitMainHead, true, nil, nil, nil, nil),
 			// errors
 			Entry("Error while retrieving the origin references", emptyCommits, nil, "", "", false, fmt.Errorf("no references found for origin %s", originURL), nil, nil, nil),
 			Entry("Error while retrieving the target references", firstCommitReference, nil, "", "", false, nil, fmt.Errorf("error while retrieving target references %s", targetURL), nil, nil),
@@ -156,405 +206,3489 @@ var _ = Describe("Git client", func() {
 			Entry("No commits found in target", firstCommitReference, noCommits, "", "", false, nil, nil, nil, fmt.Errorf("unable to find HEAD for target %s", targetURL)),
 			Entry("Reference not found in origin", firstCommitAmendedReference, firstCommitReference, "reference/not/found", "", false, nil, nil, fmt.Errorf("unable to find refs/heads/reference/not/found for origin %s", originURL), nil),
 			Entry("Reference not found in target", firstCommitAmendedReference, firstCommitReference, "", "reference/not/found", false, nil, nil, nil, fmt.Errorf("unable to find refs/heads/reference/not/found for target %s", targetURL)),
+			Entry("Fully-qualified reference not found in target", firstCommitAmendedReference, firstCommitReference, "", "refs/tags/v9.9.9", false, nil, nil, nil, fmt.Errorf("unable to find refs/tags/v9.9.9 for target %s", targetURL)),
 		)
-	})
-	var _ = Context("git reference", func() {
+		DescribeTable("when drifting with a branch mapping", func(originRefs, targetRefs []*plumbing.Reference, mapping api.BranchMapping, expected bool, expectedErr string) {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:      foo,
+						PollInterval:  30,
+						OriginRepo:    originURL,
+						TargetRepo:    targetURL,
+						BranchMapping: mapping}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
 
-		DescribeTable("when retrieving the git reference", func(references []*plumbing.Reference, targetRef plumbing.ReferenceName, expected *plumbing.Reference) {
-			ret := getReferenceByName(references, targetRef)
-			if expected == nil {
-				Expect(ret).To(BeNil())
+			remote := repositoryPair{
+				name:      foo,
+				namespace: defaultNamespace,
+				gitClient: mockGitClient,
+				kClient:   k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(originRefs, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(targetRefs, nil)
+
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			if expectedErr != "" {
+				Expect(e).To(HaveOccurred())
+				Expect(e.Error()).To(ContainSubstring(expectedErr))
 				return
 			}
-			Expect(expected).To(Equal(ret))
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(Equal(expected))
 		},
-			Entry("When filtering for HEAD symbolic link and is found", firstCommitReference, plumbing.HEAD, plumbing.NewSymbolicReference(plumbing.HEAD, mainReference)),
-			Entry("When filtering for ref/heads/main and is found", firstCommitReference, mainReference, plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitMainHead))),
-
-			// errors
-			Entry("When the symbolic link for HEAD is not found", noHeadReference, plumbing.HEAD, nil),
-			Entry("When the reference is not found", noCommits, mainReference, nil),
+			Entry("Mapped branches with the same hash", multipleCommitsReference, multipleCommitsReference, api.BranchMapping{Origin: "main", Target: "bugfix"}, false, ""),
+			Entry("Mapped branches with different hashes", multipleCommitsWithDifferentHashReference, multipleCommitsWithDifferentHashReference, api.BranchMapping{Origin: "test", Target: "bugfix"}, true, ""),
+			Entry("Mapping takes precedence over the advertised HEAD on both sides", multipleCommitsReference, multipleCommitsWithDifferentHashReference, api.BranchMapping{Origin: "test", Target: "test"}, true, ""),
+			Entry("Mapped origin branch missing from the listing", firstCommitReference, multipleCommitsReference, api.BranchMapping{Origin: "release", Target: "main"}, false, fmt.Sprintf("unable to find refs/heads/release for origin %s", originURL)),
+			Entry("Mapped target branch missing from the listing", multipleCommitsReference, firstCommitReference, api.BranchMapping{Origin: "main", Target: "release"}, false, fmt.Sprintf("unable to find refs/heads/release for target %s", targetURL)),
+			Entry("Mapping with only one side set", firstCommitReference, firstCommitReference, api.BranchMapping{Origin: "main"}, false, "branch mapping requires both branch names"),
 		)
+		It("raises a distinct condition when the external resolver service fails", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:                 foo,
+						PollInterval:             30,
+						OriginRepo:               originURL,
+						TargetRepo:               targetURL,
+						ExternalRevisionEndpoint: "https://resolver.url"}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
 
-	})
-	var _ = Context("When interacting with the pair slice", func() {
+			remote := repositoryPair{
+				name:             foo,
+				namespace:        defaultNamespace,
+				gitClient:        mockGitClient,
+				externalResolver: &failingResolver{err: fmt.Errorf("revision query for %s in %s returned status 503", foo, defaultNamespace)},
+				kClient:          k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
 
-		var (
-			one   = &repositoryPair{name: "one", namespace: "default", nextCheck: time.Time{}.Add(time.Second)}
-			three = &repositoryPair{name: "three", namespace: "default", nextCheck: time.Time{}.Add(3 * time.Second)}
-			four  = &repositoryPair{name: "four", namespace: "default", nextCheck: time.Time{}.Add(4 * time.Second)}
-			five  = &repositoryPair{name: "second", namespace: "default", nextCheck: time.Time{}.Add(5 * time.Second)}
-		)
-		It("sorts correctly the order", func() {
-			watch := newWatcher(nil)
-			watch.watch()
-			By("adding four elements")
-			watch.repoPairs = []*repositoryPair{five, three, one, four}
-			sort.Sort(watch.repoPairs)
-			Expect(watch.repoPairs).To(HaveLen(4))
-			Expect(watch.repoPairs[0]).To(BeEquivalentTo(one))
-			Expect(watch.repoPairs[1]).To(BeEquivalentTo(three))
-			Expect(watch.repoPairs[2]).To(BeEquivalentTo(four))
-			Expect(watch.repoPairs[3]).To(BeEquivalentTo(five))
-			By("removing the first element")
-			watch.repoPairs = watch.repoPairs[1:]
-			sort.Sort(watch.repoPairs)
-			Expect(watch.repoPairs[0]).To(BeEquivalentTo(three))
-			Expect(watch.repoPairs[1]).To(BeEquivalentTo(four))
-			Expect(watch.repoPairs[2]).To(BeEquivalentTo(five))
+			_, e = remote.hasDrifted(context.Background())
+			Expect(e).To(HaveOccurred())
+			Expect(e.Error()).To(ContainSubstring("returned status 503"))
+
+			var p api.Pattern
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitExternalResolverError)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("returned status 503"))
 		})
+		It("accepts a target commit that is reachable from the trusted origin branch", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:            foo,
+						PollInterval:        30,
+						OriginRepo:          originURL,
+						TargetRepo:          targetURL,
+						TrustedOriginBranch: "main"}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
 
-	})
+			mockAncestryChecker := NewMockAncestryChecker(gomock.NewController(GinkgoT()))
+			mockAncestryChecker.EXPECT().InHistory(originURL, plumbing.NewBranchReferenceName("main"), plumbing.NewHash(hashCommitAmendedHead)).Times(1).Return(true, nil)
 
-	var _ = Context("When updating the pattern conditions", func() {
+			remote := repositoryPair{
+				name:            foo,
+				namespace:       defaultNamespace,
+				gitClient:       mockGitClient,
+				ancestryChecker: mockAncestryChecker,
+				kClient:         k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil)
 
-		var (
-			ctx     = context.Background()
-			pattern api.Pattern
-		)
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeTrue())
 
-		BeforeEach(func() {
+			var p api.Pattern
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.UntrustedTargetCommit)
+			Expect(condition).To(BeNil())
+		})
+		It("ignores a hash divergence confined to paths outside the filters", func() {
 			pattern = api.Pattern{
 				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
 				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
-				Spec:       api.PatternSpec{GitConfig: api.GitConfig{Hostname: foo, PollInterval: 30}},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:     foo,
+						PollInterval: 30,
+						OriginRepo:   originURL,
+						TargetRepo:   targetURL,
+						PathFilters:  []string{"charts/app"}}},
 			}
-			e := k8sClient.Create(ctx, &pattern)
+			e := k8sClient.Create(context.Background(), &pattern)
 			Expect(e).NotTo(HaveOccurred())
-		})
 
-		AfterEach(func() {
-			e := k8sClient.Delete(ctx, &pattern)
+			mockTreeComparer := NewMockTreeComparer(gomock.NewController(GinkgoT()))
+			mockTreeComparer.EXPECT().TreesDiffer(originURL, mainReference, targetURL, mainReference, []string{"charts/app"}).Times(1).Return(false, nil)
+
+			remote := repositoryPair{
+				name:         foo,
+				namespace:    defaultNamespace,
+				gitClient:    mockGitClient,
+				treeComparer: mockTreeComparer,
+				kClient:      k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil)
+
+			hasDrifted, e := remote.hasDrifted(context.Background())
 			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeFalse())
 		})
-		It("adds the first condition", func() {
-			var p api.Pattern
-			timestamp := time.Time{}.Add(1 * time.Second)
-			By("validating the pattern has no conditions yet")
-			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(p).NotTo(BeNil())
-			Expect(p.Status.Conditions).To(HaveLen(0))
-			By("calling the update pattern conditions to add a new condition")
-			e := updatePatternConditions(k8sClient, api.GitInSync, foo, defaultNamespace, timestamp)
+		It("reports drift when the trees differ under a filtered path", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:     foo,
+						PollInterval: 30,
+						OriginRepo:   originURL,
+						TargetRepo:   targetURL,
+						PathFilters:  []string{"charts/app", "values"}}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
 			Expect(e).NotTo(HaveOccurred())
-			By("retrieving the pattern object once more and validating that it contains the new condition")
-			err = k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(p).NotTo(BeNil())
-			Expect(p.Status.Conditions).To(HaveLen(1))
-			Expect(p.Status.Conditions[0]).To(BeComparableTo(api.PatternCondition{
-				Type:               api.GitInSync,
-				Status:             v1core.ConditionTrue,
-				LastUpdateTime:     v1.Time{Time: timestamp},
-				LastTransitionTime: v1.Time{Time: timestamp},
-				Message:            "Git repositories are in sync",
-			}))
+
+			mockTreeComparer := NewMockTreeComparer(gomock.NewController(GinkgoT()))
+			mockTreeComparer.EXPECT().TreesDiffer(originURL, mainReference, targetURL, mainReference, []string{"charts/app", "values"}).Times(1).Return(true, nil)
+
+			remote := repositoryPair{
+				name:         foo,
+				namespace:    defaultNamespace,
+				gitClient:    mockGitClient,
+				treeComparer: mockTreeComparer,
+				kClient:      k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil)
+
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeTrue())
 		})
-		It("updates lastUpdate field when condition still occurs while condition is active", func() {
-			var p api.Pattern
-			firstTimestamp := time.Time{}.Add(1 * time.Second)
-			By("calling the update pattern conditions to add the condition")
-			e := updatePatternConditions(k8sClient, api.GitInSync, foo, defaultNamespace, firstTimestamp)
+		It("skips the tree comparison when the references already match", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:     foo,
+						PollInterval: 30,
+						OriginRepo:   originURL,
+						TargetRepo:   targetURL,
+						PathFilters:  []string{"charts/app"}}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
 			Expect(e).NotTo(HaveOccurred())
-			By("calling the update pattern conditions again to trigger the update of the lastUpdate field")
-			secondTimeStamp := time.Time{}.Add(2 * time.Second)
-			e = updatePatternConditions(k8sClient, api.GitInSync, foo, defaultNamespace, secondTimeStamp)
+
+			remote := repositoryPair{
+				name:         foo,
+				namespace:    defaultNamespace,
+				gitClient:    mockGitClient,
+				treeComparer: NewMockTreeComparer(gomock.NewController(GinkgoT())),
+				kClient:      k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+
+			hasDrifted, e := remote.hasDrifted(context.Background())
 			Expect(e).NotTo(HaveOccurred())
-			By("retrieving the pattern object")
-			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(p).NotTo(BeNil())
-			Expect(p.Status.Conditions).To(HaveLen(1))
-			Expect(p.Status.Conditions[0]).To(BeComparableTo(api.PatternCondition{
-				Type:               api.GitInSync,
-				Status:             v1core.ConditionTrue,
-				LastUpdateTime:     v1.Time{Time: secondTimeStamp},
-				LastTransitionTime: v1.Time{Time: firstTimestamp},
-				Message:            "Git repositories are in sync",
-			}))
+			Expect(hasDrifted).To(BeFalse())
 		})
-		It("transitions to a new condition type as status true", func() {
-			var p api.Pattern
-			firstTimestamp := time.Time{}.Add(1 * time.Second)
-			By("calling the update pattern conditions to add the condition")
-			e := updatePatternConditions(k8sClient, api.GitInSync, foo, defaultNamespace, firstTimestamp)
+		It("accepts origin and target repositories sharing a root commit", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:                 foo,
+						PollInterval:             30,
+						OriginRepo:               originURL,
+						TargetRepo:               targetURL,
+						VerifyRepositoryIdentity: true}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
 			Expect(e).NotTo(HaveOccurred())
-			By("calling the update pattern conditions again to trigger the update of the lastUpdate field")
-			secondTimeStamp := time.Time{}.Add(2 * time.Second)
-			e = updatePatternConditions(k8sClient, api.GitOutOfSync, foo, defaultNamespace, secondTimeStamp)
+
+			mockIdentityResolver := NewMockIdentityResolver(gomock.NewController(GinkgoT()))
+			mockIdentityResolver.EXPECT().RootCommits(originURL, mainReference).Times(1).Return([]plumbing.Hash{plumbing.NewHash(hashCommitTestBranch)}, nil)
+			mockIdentityResolver.EXPECT().RootCommits(targetURL, mainReference).Times(1).Return([]plumbing.Hash{plumbing.NewHash(hashCommitTestBranch)}, nil)
+
+			remote := repositoryPair{
+				name:             foo,
+				namespace:        defaultNamespace,
+				gitClient:        mockGitClient,
+				identityResolver: mockIdentityResolver,
+				kClient:          k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+
+			hasDrifted, e := remote.hasDrifted(context.Background())
 			Expect(e).NotTo(HaveOccurred())
-			By("retrieving the pattern object")
-			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(hasDrifted).To(BeFalse())
+
+			var p api.Pattern
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(p).NotTo(BeNil())
-			Expect(p.Status.Conditions).To(HaveLen(2))
-			Expect(p.Status.Conditions[0]).To(BeComparableTo(api.PatternCondition{
-				Type:               api.GitInSync,
-				Status:             v1core.ConditionFalse,
-				LastUpdateTime:     v1.Time{Time: secondTimeStamp},
-				LastTransitionTime: v1.Time{Time: firstTimestamp},
-				Message:            "Git repositories are in sync",
-			}))
-			Expect(p.Status.Conditions[1]).To(BeComparableTo(api.PatternCondition{
-				Type:               api.GitOutOfSync,
-				Status:             v1core.ConditionTrue,
-				LastUpdateTime:     v1.Time{Time: secondTimeStamp},
-				LastTransitionTime: v1.Time{Time: secondTimeStamp},
-				Message:            "Git repositories are out of sync",
-			}))
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitRepositoryIdentityMismatch)
+			Expect(condition).To(BeNil())
 		})
-		It("transitions back to an existing condition type", func() {
-			var p api.Pattern
-			firstTimestamp := time.Time{}.Add(1 * time.Second)
-			By("calling the update pattern conditions to add the condition")
-			e := updatePatternConditions(k8sClient, api.GitInSync, foo, defaultNamespace, firstTimestamp)
-			Expect(e).NotTo(HaveOccurred())
-			By("calling the update pattern conditions again to trigger the update of the lastUpdate field")
-			secondTimeStamp := time.Time{}.Add(2 * time.Second)
-			e = updatePatternConditions(k8sClient, api.GitOutOfSync, foo, defaultNamespace, secondTimeStamp)
+		It("raises a condition when origin and target share no root commit", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:                 foo,
+						PollInterval:             30,
+						OriginRepo:               originURL,
+						TargetRepo:               targetURL,
+						VerifyRepositoryIdentity: true}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
 			Expect(e).NotTo(HaveOccurred())
-			thirdTimeStamp := time.Time{}.Add(3 * time.Second)
-			e = updatePatternConditions(k8sClient, api.GitInSync, foo, defaultNamespace, thirdTimeStamp)
+
+			mockIdentityResolver := NewMockIdentityResolver(gomock.NewController(GinkgoT()))
+			mockIdentityResolver.EXPECT().RootCommits(originURL, mainReference).Times(1).Return([]plumbing.Hash{plumbing.NewHash(hashCommitTestBranch)}, nil)
+			mockIdentityResolver.EXPECT().RootCommits(targetURL, mainReference).Times(1).Return([]plumbing.Hash{plumbing.NewHash(hashCommitBugfixBranch)}, nil)
+
+			remote := repositoryPair{
+				name:             foo,
+				namespace:        defaultNamespace,
+				gitClient:        mockGitClient,
+				identityResolver: mockIdentityResolver,
+				kClient:          k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+
+			_, e = remote.hasDrifted(context.Background())
 			Expect(e).NotTo(HaveOccurred())
-			By("retrieving the pattern object")
-			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+
+			var p api.Pattern
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(p).NotTo(BeNil())
-			Expect(p.Status.Conditions).To(HaveLen(2))
-			Expect(p.Status.Conditions[0]).To(BeComparableTo(api.PatternCondition{
-				Type:               api.GitInSync,
-				Status:             v1core.ConditionTrue,
-				LastUpdateTime:     v1.Time{Time: thirdTimeStamp},
-				LastTransitionTime: v1.Time{Time: thirdTimeStamp},
-				Message:            "Git repositories are in sync",
-			}))
-			Expect(p.Status.Conditions[1]).To(BeComparableTo(api.PatternCondition{
-				Type:               api.GitOutOfSync,
-				Status:             v1core.ConditionFalse,
-				LastUpdateTime:     v1.Time{Time: thirdTimeStamp},
-				LastTransitionTime: v1.Time{Time: secondTimeStamp},
-				Message:            "Git repositories are out of sync",
-			}))
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitRepositoryIdentityMismatch)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("share no root commit"))
 		})
-	})
+		It("raises a condition when the target commit is not in the trusted origin branch history", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:            foo,
+						PollInterval:        30,
+						OriginRepo:          originURL,
+						TargetRepo:          targetURL,
+						TrustedOriginBranch: "main"}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
 
-})
+			mockAncestryChecker := NewMockAncestryChecker(gomock.NewController(GinkgoT()))
+			mockAncestryChecker.EXPECT().InHistory(originURL, plumbing.NewBranchReferenceName("main"), plumbing.NewHash(hashCommitAmendedHead)).Times(1).Return(false, nil)
 
-var _ = Describe("Drift watcher", func() {
+			remote := repositoryPair{
+				name:            foo,
+				namespace:       defaultNamespace,
+				gitClient:       mockGitClient,
+				ancestryChecker: mockAncestryChecker,
+				kClient:         k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil)
 
-	const ()
-	var _ = Context("When watching for drifts", func() {
-		var (
-			patternFoo                         *api.Pattern
-			ctrl                               *gomock.Controller
-			mockGitClient                      *MockClient
-			mockRemoteOrigin, mockRemoteTarget *MockRemoteClient
-		)
+			_, e = remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
 
-		BeforeEach(func() {
-			ctrl = gomock.NewController(GinkgoT())
+			var p api.Pattern
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.UntrustedTargetCommit)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring(fmt.Sprintf("target commit %s is not part of the history of trusted origin branch main", hashCommitAmendedHead)))
+		})
+		It("aggregates drift across the target branches matching the configured filter", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:     foo,
+						PollInterval: 30,
+						OriginRepo:   originURL,
+						TargetRepo:   targetURL,
+						BranchFilter: "env/*"}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
 
-			mockGitClient = NewMockClient(ctrl)
-			mockRemoteOrigin = NewMockRemoteClient(ctrl)
-			mockRemoteTarget = NewMockRemoteClient(ctrl)
-			// Add the pattern in etcd
-			patternFoo = &api.Pattern{
+			originRefs := []*plumbing.Reference{
+				plumbing.NewSymbolicReference(plumbing.HEAD, mainReference),
+				plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitMainHead)),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("env/dev"), plumbing.NewHash(hashCommitMainHead)),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("env/prod"), plumbing.NewHash(hashCommitMainHead))}
+			targetRefs := []*plumbing.Reference{
+				plumbing.NewSymbolicReference(plumbing.HEAD, mainReference),
+				plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitAmendedHead)),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("env/dev"), plumbing.NewHash(hashCommitMainHead)),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("env/prod"), plumbing.NewHash(hashCommitAmendedHead)),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("env/stage"), plumbing.NewHash(hashCommitTestBranch))}
+
+			remote := repositoryPair{
+				name:      foo,
+				namespace: defaultNamespace,
+				gitClient: mockGitClient,
+				kClient:   k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(originRefs, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(targetRefs, nil)
+
+			// env/dev is in sync, env/prod differs and env/stage is missing from the origin; the
+			// divergence of the default branches is ignored as it does not match the filter
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeTrue())
+			Expect(remote.driftedBranches).To(Equal([]string{"env/prod", "env/stage"}))
+		})
+		It("reports no drift when all filtered branches are in sync", func() {
+			pattern = api.Pattern{
 				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
 				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
-				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:     foo,
+						PollInterval: 30,
+						OriginRepo:   originURL,
+						TargetRepo:   targetURL,
+						BranchFilter: "env/*"}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
 
-			err := k8sClient.Create(context.TODO(), patternFoo)
-			Expect(err).NotTo(HaveOccurred())
+			refs := []*plumbing.Reference{
+				plumbing.NewSymbolicReference(plumbing.HEAD, mainReference),
+				plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitMainHead)),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("env/dev"), plumbing.NewHash(hashCommitMainHead))}
+
+			remote := repositoryPair{
+				name:      foo,
+				namespace: defaultNamespace,
+				gitClient: mockGitClient,
+				kClient:   k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(refs, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(refs, nil)
 
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeFalse())
+			Expect(remote.driftedBranches).To(BeEmpty())
 		})
+		It("records all refs pointing at the resolved target commit and a deterministic primary", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:              foo,
+						PollInterval:          30,
+						OriginRepo:            originURL,
+						TargetRepo:            targetURL,
+						HeadBranchPreferences: []string{"bugfix"}}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
 
-		AfterEach(func() {
+			remote := repositoryPair{
+				name:      foo,
+				namespace: defaultNamespace,
+				gitClient: mockGitClient,
+				kClient:   k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(multipleCommitsReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(multipleCommitsReference, nil)
 
-			err := k8sClient.Delete(context.TODO(), patternFoo)
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeFalse())
+			Expect(remote.targetMatchedRefs).To(Equal([]plumbing.ReferenceName{
+				plumbing.NewBranchReferenceName("bugfix"),
+				mainReference,
+				plumbing.NewBranchReferenceName("test")}))
+			Expect(remote.targetPrimaryRef).To(Equal(plumbing.NewBranchReferenceName("bugfix")))
+		})
+		It("raises a condition when the target repository redirects to a different host", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:       foo,
+						PollInterval:   30,
+						OriginRepo:     originURL,
+						TargetRepo:     targetURL,
+						RedirectPolicy: api.RedirectFollow}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+
+			mockHostResolver := NewMockHostResolver(gomock.NewController(GinkgoT()))
+			mockHostResolver.EXPECT().FinalHost(originURL).Times(1).Return("origin.url", nil)
+			mockHostResolver.EXPECT().FinalHost(targetURL).Times(1).Return("new.target.url", nil)
+
+			remote := repositoryPair{
+				name:         foo,
+				namespace:    defaultNamespace,
+				gitClient:    mockGitClient,
+				hostResolver: mockHostResolver,
+				kClient:      k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+
+			By("running a check with the follow policy")
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeFalse())
+
+			By("validating the informational condition has been added")
+			var p api.Pattern
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
 			Expect(err).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitRepositoryRedirected)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("redirected to host new.target.url"))
 		})
+		It("refuses the check when a redirect is detected and the policy is refuse", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:       foo,
+						PollInterval:   30,
+						OriginRepo:     originURL,
+						TargetRepo:     targetURL,
+						RedirectPolicy: api.RedirectRefuse}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
 
-		It("detects a drift between a pair of git repositories after the second check", func() {
-			var (
-				payloadDelivered bool
-			)
+			mockHostResolver := NewMockHostResolver(gomock.NewController(GinkgoT()))
+			mockHostResolver.EXPECT().FinalHost(originURL).Times(1).Return("new.origin.url", nil)
+
+			remote := repositoryPair{
+				name:         foo,
+				namespace:    defaultNamespace,
+				gitClient:    mockGitClient,
+				hostResolver: mockHostResolver,
+				kClient:      k8sClient,
+			}
+			_, e = remote.hasDrifted(context.Background())
+			Expect(e).To(HaveOccurred())
+			Expect(e.Error()).To(ContainSubstring("refusing drift check per redirect policy"))
+		})
+		It("raises a condition when the default branch is renamed between checks", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:     foo,
+						PollInterval: 30,
+						OriginRepo:   originURL,
+						TargetRepo:   targetURL}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
 
+			remote := repositoryPair{
+				name:      foo,
+				namespace: defaultNamespace,
+				gitClient: mockGitClient,
+				kClient:   k8sClient,
+			}
 			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).DoAndReturn(func(c *config.RemoteConfig) RemoteClient {
 				if c.Name == "origin" {
-					return mockRemoteOrigin
+					return mockRemoteClientOrigin
 				}
-				return mockRemoteTarget
+				return mockRemoteClientTarget
 			}).AnyTimes()
-
-			mockRemoteOrigin.EXPECT().List(gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
-			mockRemoteTarget.EXPECT().List(gomock.Any()).DoAndReturn(func(_ *git.ListOptions) ([]*plumbing.Reference, error) {
-				if !payloadDelivered {
-					payloadDelivered = true
-					return firstCommitReference, nil
+			var originListed bool
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).DoAndReturn(func(_ context.Context, _ *git.ListOptions) ([]*plumbing.Reference, error) {
+				if !originListed {
+					originListed = true
+					return firstCommitReferenceWithMaster, nil
 				}
-				return multipleCommitsWithDifferentHashReference, nil
-			}).AnyTimes()
-			watch, closeCh := newDriftWatcher(k8sClient, logr.New(log.NullLogSink{}), mockGitClient)
+				return firstCommitReference, nil
+			}).Times(2)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Return(firstCommitReference, nil).Times(2)
 
-			// Add the pair
-			timestamp := time.Now()
-			err := watch.add(foo, defaultNamespace, 1)
+			By("running the first check against the original default branch")
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeFalse())
+			Expect(remote.originHeadBranch).To(Equal(plumbing.Master))
+
+			By("running a second check after the default branch has been renamed")
+			hasDrifted, e = remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeFalse())
+			Expect(remote.originHeadBranch).To(Equal(mainReference))
+
+			By("validating the informational condition has been added")
+			var p api.Pattern
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
 			Expect(err).NotTo(HaveOccurred())
-			Eventually(func() bool {
-				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, patternFoo)
-				Expect(err).NotTo(HaveOccurred())
-				return len(patternFoo.Status.Conditions) == 1
-			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(), "expected number of conditions %d but found %d", 1, len(patternFoo.Status.Conditions))
-			// check that the conditions reflect the drift polling
-			Expect(patternFoo.Status.Conditions[0].Type).To(Equal(api.GitInSync))
-			Expect(patternFoo.Status.Conditions[0].Status).To(Equal(v1core.ConditionTrue))
-			Expect(patternFoo.Status.Conditions[0].LastUpdateTime.Time).To(BeTemporally(">", timestamp))
-			Expect(patternFoo.Status.Conditions[0].LastTransitionTime.Time).To(BeTemporally(">", timestamp))
-			// wait for the second check to report the drift
-			Eventually(func() bool {
-				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, patternFoo)
-				Expect(err).NotTo(HaveOccurred())
-				return len(patternFoo.Status.Conditions) == 2
-			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(), "expected number of conditions %d but found %d", 2, len(patternFoo.Status.Conditions))
-			// notify the routine that we're closing so that it doesn't keep checking for more drifts
-			close(closeCh)
-			// retrieve the first element in the slice
-			err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, patternFoo)
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitDefaultBranchChanged)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("changed from master to main"))
+		})
+		It("reports pending sync instead of drift while the origin commit is within the epsilon", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:           foo,
+						PollInterval:       30,
+						OriginRepo:         originURL,
+						TargetRepo:         targetURL,
+						SyncEpsilonSeconds: 300}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+
+			mockCommitTimeResolver := NewMockCommitTimeResolver(gomock.NewController(GinkgoT()))
+			mockCommitTimeResolver.EXPECT().CommitTime(originURL, plumbing.NewHash(hashCommitMainHead), mainReference).Times(1).Return(time.Now().Add(-time.Minute), nil)
+
+			remote := repositoryPair{
+				name:               foo,
+				namespace:          defaultNamespace,
+				gitClient:          mockGitClient,
+				commitTimeResolver: mockCommitTimeResolver,
+				kClient:            k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil)
+
+			By("running a check against a target that lags a fresh origin commit")
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeFalse())
+
+			By("validating the pending sync condition has been added")
+			var p api.Pattern
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
 			Expect(err).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitSyncPending)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("waiting for target"))
+		})
+		It("reports drift when the origin commit is older than the epsilon", func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:           foo,
+						PollInterval:       30,
+						OriginRepo:         originURL,
+						TargetRepo:         targetURL,
+						SyncEpsilonSeconds: 300}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
 
-			// previous condition should have status false
-			Expect(patternFoo.Status.Conditions[0].Type).To(Equal(api.GitInSync))
-			Expect(patternFoo.Status.Conditions[0].Status).To(Equal(v1core.ConditionFalse))
-			Expect(patternFoo.Status.Conditions[0].LastUpdateTime.Time).To(BeTemporally("==", patternFoo.Status.Conditions[1].LastUpdateTime.Time))
-			Expect(patternFoo.Status.Conditions[0].LastTransitionTime.Time).To(BeTemporally("==", patternFoo.Status.Conditions[0].LastUpdateTime.Time.Add(-1*time.Second)))
-			// new condition should show the repositories have drifted
-			Expect(patternFoo.Status.Conditions[1].Type).To(Equal(api.GitOutOfSync))
-			Expect(patternFoo.Status.Conditions[1].Status).To(Equal(v1core.ConditionTrue))
-			Expect(patternFoo.Status.Conditions[1].LastTransitionTime.Time).To(BeTemporally("==", patternFoo.Status.Conditions[1].LastUpdateTime.Time))
-			Expect(patternFoo.Status.Conditions[1].LastUpdateTime.Time).To(BeTemporally("==", patternFoo.Status.Conditions[0].LastTransitionTime.Time.Add(time.Second)))
+			mockCommitTimeResolver := NewMockCommitTimeResolver(gomock.NewController(GinkgoT()))
+			mockCommitTimeResolver.EXPECT().CommitTime(originURL, plumbing.NewHash(hashCommitMainHead), mainReference).Times(1).Return(time.Now().Add(-time.Hour), nil)
+
+			remote := repositoryPair{
+				name:               foo,
+				namespace:          defaultNamespace,
+				gitClient:          mockGitClient,
+				commitTimeResolver: mockCommitTimeResolver,
+				kClient:            k8sClient,
+			}
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteClientTarget)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil)
+
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeTrue())
+		})
+	})
+	var _ = Context("when resolving the revision from an in-cluster resource", func() {
+		var pattern *api.Pattern
+
+		BeforeEach(func() {
+			pattern = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						OriginRepo: originURL,
+						TargetRepo: targetURL,
+						RevisionFrom: &api.RevisionFieldReference{
+							APIVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "release",
+							FieldPath:  "data.revision"}}},
+			}
+		})
+
+		It("reads the revision from the configured field path", func() {
+			release := &v1core.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{Name: "release", Namespace: defaultNamespace},
+				Data:       map[string]string{"revision": hashCommitMainHead}}
+			resolver := newKubeRevisionResolver(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(release).Build())
+			hash, err := resolver.Resolve(pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash).To(Equal(plumbing.NewHash(hashCommitMainHead)))
 		})
 
+		It("errors when the referenced resource is missing", func() {
+			resolver := newKubeRevisionResolver(fake.NewClientBuilder().WithScheme(scheme.Scheme).Build())
+			_, err := resolver.Resolve(pattern)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to fetch revision source ConfigMap release"))
+		})
+
+		It("errors when the referenced field is missing", func() {
+			release := &v1core.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{Name: "release", Namespace: defaultNamespace},
+				Data:       map[string]string{"other": hashCommitMainHead}}
+			resolver := newKubeRevisionResolver(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(release).Build())
+			_, err := resolver.Resolve(pattern)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("field data.revision not found in revision source ConfigMap release"))
+		})
+	})
+
+	var _ = Context("git reference", func() {
+
+		DescribeTable("when retrieving the git reference", func(references []*plumbing.Reference, targetRef plumbing.ReferenceName, expected *plumbing.Reference) {
+			ret := getReferenceByName(references, targetRef)
+			if expected == nil {
+				Expect(ret).To(BeNil())
+				return
+			}
+			Expect(expected).To(Equal(ret))
+		},
+			Entry("When filtering for HEAD symbolic link and is found", firstCommitReference, plumbing.HEAD, plumbing.NewSymbolicReference(plumbing.HEAD, mainReference)),
+			Entry("When filtering for ref/heads/main and is found", firstCommitReference, mainReference, plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitMainHead))),
+
+			// errors
+			Entry("When the symbolic link for HEAD is not found", noHeadReference, plumbing.HEAD, nil),
+			Entry("When the reference is not found", noCommits, mainReference, nil),
+		)
+
+		DescribeTable("when resolving a reference through symbolic refs", func(references []*plumbing.Reference, targetRef plumbing.ReferenceName, expected *plumbing.Reference) {
+			ret := resolveReference(references, targetRef)
+			if expected == nil {
+				Expect(ret).To(BeNil())
+				return
+			}
+			Expect(expected).To(Equal(ret))
+		},
+			Entry("When resolving HEAD to the default branch", firstCommitReference, plumbing.HEAD, plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitMainHead))),
+			Entry("When resolving a custom symref to the underlying branch", symrefToMainHeads, stableReference, plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitMainHead))),
+			Entry("When resolving a hash reference directly", firstCommitReference, mainReference, plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitMainHead))),
+
+			// errors
+			Entry("When the symref target is not found", noCommits, plumbing.HEAD, nil),
+			Entry("When the symbolic references form a cycle", symrefCycleHeads, stableReference, nil),
+		)
+	})
+	var _ = Context("when reducing a very large ref listing", func() {
+
+		It("retains only resolvable refs and caps the total", func() {
+			refs := []*plumbing.Reference{plumbing.NewSymbolicReference(plumbing.HEAD, mainReference)}
+			for i := 0; i < 2*maxRetainedRefs; i++ {
+				refs = append(refs,
+					plumbing.NewHashReference(plumbing.NewBranchReferenceName(fmt.Sprintf("branch-%d", i)), plumbing.NewHash(hashCommitMainHead)),
+					plumbing.NewHashReference(plumbing.ReferenceName(fmt.Sprintf("refs/pull/%d/head", i)), plumbing.NewHash(hashCommitTestBranch)))
+			}
+			retained := relevantRefs(refs)
+			Expect(len(retained)).To(Equal(maxRetainedRefs))
+			for _, ref := range retained {
+				Expect(ref.Name() == plumbing.HEAD || ref.Type() == plumbing.SymbolicReference || ref.Name().IsBranch()).To(BeTrue())
+			}
+			By("keeping the refs needed to resolve the default branch")
+			Expect(getHeadBranch(retained)).NotTo(BeNil())
+		})
+	})
+
+	var _ = Context("when diffing the origin and target ref listings", func() {
+
+		It("categorizes refs present on one side only and refs with differing hashes", func() {
+			originRefs := []*plumbing.Reference{
+				plumbing.NewSymbolicReference(plumbing.HEAD, mainReference),
+				plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitMainHead)),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("test"), plumbing.NewHash(hashCommitTestBranch)),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("release"), plumbing.NewHash(hashCommitMainHead))}
+			targetRefs := []*plumbing.Reference{
+				plumbing.NewSymbolicReference(plumbing.HEAD, mainReference),
+				plumbing.NewHashReference(mainReference, plumbing.NewHash(hashCommitMainHead)),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("test"), plumbing.NewHash(hashCommitAmendedHead)),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("bugfix"), plumbing.NewHash(hashCommitBugfixBranch))}
+
+			pair := repositoryPair{lastOriginRefs: originRefs, lastTargetRefs: targetRefs}
+			diff := pair.refDiff()
+			Expect(diff.OnlyOrigin).To(Equal([]string{"refs/heads/release"}))
+			Expect(diff.OnlyTarget).To(Equal([]string{"refs/heads/bugfix"}))
+			Expect(diff.Changed).To(Equal([]string{fmt.Sprintf("refs/heads/test %s != %s", hashCommitTestBranch, hashCommitAmendedHead)}))
+			Expect(diff.Truncated).To(BeFalse())
+		})
+
+		It("bounds the number of entries per category", func() {
+			originRefs := []*plumbing.Reference{}
+			for i := 0; i < 2*maxDiffEntries; i++ {
+				originRefs = append(originRefs,
+					plumbing.NewHashReference(plumbing.NewBranchReferenceName(fmt.Sprintf("branch-%03d", i)), plumbing.NewHash(hashCommitMainHead)))
+			}
+			diff := diffRefSets(originRefs, []*plumbing.Reference{})
+			Expect(diff.OnlyOrigin).To(HaveLen(maxDiffEntries))
+			Expect(diff.OnlyOrigin[0]).To(Equal("refs/heads/branch-000"))
+			Expect(diff.Truncated).To(BeTrue())
+		})
+	})
+
+	var _ = Context("when selecting the primary ref among several pointing at the same commit", func() {
+
+		var matches = []plumbing.ReferenceName{
+			plumbing.NewBranchReferenceName("bugfix"),
+			mainReference,
+			plumbing.NewBranchReferenceName("test")}
+
+		DescribeTable("applying the configured precedence", func(resolved plumbing.ReferenceName, preferences []string, expected plumbing.ReferenceName) {
+			Expect(primaryRef(matches, resolved, preferences)).To(Equal(expected))
+		},
+			Entry("the first matching preference wins", mainReference, []string{"missing", "test"}, plumbing.NewBranchReferenceName("test")),
+			Entry("the resolved ref wins when no preference matches", mainReference, []string{"missing"}, mainReference),
+			Entry("the resolved ref wins without preferences", mainReference, nil, mainReference),
+			Entry("the lexically-first match is the fallback", plumbing.HEAD, nil, plumbing.NewBranchReferenceName("bugfix")),
+		)
+
+		It("falls back to the resolved ref when nothing matched the commit", func() {
+			Expect(primaryRef(nil, mainReference, nil)).To(Equal(mainReference))
+		})
+	})
+
+	var _ = Context("when selecting a branch for a repository without a symbolic HEAD", func() {
+
+		DescribeTable("applying the headless policy", func(gc api.GitConfig, expected plumbing.ReferenceName) {
+			ret := resolveHeadlessBranch(noHeadMultiBranchReference, gc)
+			if expected == "" {
+				Expect(ret).To(BeNil())
+				return
+			}
+			Expect(ret).NotTo(BeNil())
+			Expect(ret.Name()).To(Equal(expected))
+		},
+			Entry("the default policy selects nothing", api.GitConfig{}, plumbing.ReferenceName("")),
+			Entry("the explicit error policy selects nothing", api.GitConfig{HeadlessPolicy: api.HeadlessError}, plumbing.ReferenceName("")),
+			Entry("the lexically-first policy selects the first branch by name", api.GitConfig{HeadlessPolicy: api.HeadlessLexicalFirst}, plumbing.NewBranchReferenceName("alpha")),
+			Entry("the preference list policy selects the first matching branch", api.GitConfig{HeadlessPolicy: api.HeadlessPreferenceList, HeadBranchPreferences: []string{"missing", "beta"}}, plumbing.NewBranchReferenceName("beta")),
+			Entry("the preference list policy selects nothing when no branch matches", api.GitConfig{HeadlessPolicy: api.HeadlessPreferenceList, HeadBranchPreferences: []string{"missing"}}, plumbing.ReferenceName("")),
+		)
+	})
+
+	var _ = Context("when rendering the drift state as OpenMetrics text", func() {
+
+		It("renders one line per pair and metric", func() {
+			watch := newWatcher(nil)
+			watch.repoPairs = []*repositoryPair{
+				{name: foo, namespace: defaultNamespace, drifted: true, checkCount: 3, lastCheck: time.Unix(1000, 0), schedulingDelay: 1500 * time.Millisecond, executionDuration: 250 * time.Millisecond},
+				{name: bar, namespace: defaultNamespace, drifted: false, checkCount: 7, lastCheck: time.Unix(2000, 0)},
+			}
+			var buffer bytes.Buffer
+			Expect(watch.writeOpenMetrics(&buffer)).NotTo(HaveOccurred())
+			rendered := buffer.String()
+			Expect(rendered).To(ContainSubstring("# TYPE patterns_operator_drift_detected gauge"))
+			Expect(rendered).To(ContainSubstring(`patterns_operator_drift_detected{name="foo",namespace="default"} 1`))
+			Expect(rendered).To(ContainSubstring(`patterns_operator_drift_detected{name="bar",namespace="default"} 0`))
+			Expect(rendered).To(ContainSubstring("# TYPE patterns_operator_drift_checks_total counter"))
+			Expect(rendered).To(ContainSubstring(`patterns_operator_drift_checks_total{name="foo",namespace="default"} 3`))
+			Expect(rendered).To(ContainSubstring(`patterns_operator_drift_checks_total{name="bar",namespace="default"} 7`))
+			Expect(rendered).To(ContainSubstring(`patterns_operator_drift_last_check_timestamp_seconds{name="foo",namespace="default"} 1000`))
+			Expect(rendered).To(ContainSubstring(`patterns_operator_drift_last_check_timestamp_seconds{name="bar",namespace="default"} 2000`))
+			Expect(rendered).To(ContainSubstring(`patterns_operator_drift_check_scheduling_delay_seconds{name="foo",namespace="default"} 1.5`))
+			Expect(rendered).To(ContainSubstring(`patterns_operator_drift_check_duration_seconds{name="foo",namespace="default"} 0.25`))
+			Expect(rendered).To(ContainSubstring(`patterns_operator_drift_check_duration_seconds{name="bar",namespace="default"} 0`))
+		})
+	})
+
+	var _ = Context("when adapting the interval of a failing pair", func() {
+
+		DescribeTable("applying the error-interval policy", func(policy api.ErrorIntervalPolicyType, consecutiveFailures int, expected time.Duration) {
+			Expect(effectiveInterval(time.Minute, policy, consecutiveFailures)).To(Equal(expected))
+		},
+			Entry("no policy keeps the interval fixed across failures", api.ErrorIntervalPolicyType(""), 5, time.Minute),
+			Entry("shrink halves the interval on the first failure", api.ErrorIntervalShrink, 1, 30*time.Second),
+			Entry("shrink clamps at a quarter of the interval", api.ErrorIntervalShrink, 5, 15*time.Second),
+			Entry("grow doubles the interval on the first failure", api.ErrorIntervalGrow, 1, 2*time.Minute),
+			Entry("grow clamps at eight times the interval", api.ErrorIntervalGrow, 5, 8*time.Minute),
+			Entry("shrink snaps back on recovery", api.ErrorIntervalShrink, 0, time.Minute),
+			Entry("grow snaps back on recovery", api.ErrorIntervalGrow, 0, time.Minute),
+		)
+
+		DescribeTable("applying the backoff cap", func(policy api.ErrorIntervalPolicyType, consecutiveFailures int, cap, expected time.Duration) {
+			Expect(backoffInterval(time.Minute, policy, consecutiveFailures, cap, 0)).To(Equal(expected))
+		},
+			Entry("cap bounds the grown interval", api.ErrorIntervalGrow, 5, 3*time.Minute, 3*time.Minute),
+			Entry("cap above the default clamp has no effect", api.ErrorIntervalGrow, 5, 20*time.Minute, 8*time.Minute),
+			Entry("no cap keeps the default clamp", api.ErrorIntervalGrow, 5, time.Duration(0), 8*time.Minute),
+			Entry("cap is ignored while checks succeed", api.ErrorIntervalGrow, 0, 30*time.Second, time.Minute),
+		)
+
+		It("spreads failing retries within the configured jitter", func() {
+			seen := map[time.Duration]bool{}
+			for i := 0; i < 100; i++ {
+				interval := backoffInterval(time.Minute, api.ErrorIntervalGrow, 1, 0, 10*time.Second)
+				Expect(interval).To(BeNumerically(">=", 2*time.Minute))
+				Expect(interval).To(BeNumerically("<", 2*time.Minute+10*time.Second))
+				seen[interval] = true
+			}
+			Expect(len(seen)).To(BeNumerically(">", 1))
+		})
+
+		It("adds no jitter once a check completes", func() {
+			Expect(backoffInterval(time.Minute, api.ErrorIntervalGrow, 0, 0, 10*time.Second)).To(Equal(time.Minute))
+		})
+	})
+
+	var _ = Context("when deriving the timings of a check", func() {
+
+		var scheduled = time.Date(2022, time.November, 1, 10, 0, 0, 0, time.UTC)
+
+		DescribeTable("separating scheduling delay from execution duration", func(started, finished time.Time, expectedDelay, expectedDuration time.Duration) {
+			delay, duration := checkTimings(started, scheduled, finished)
+			Expect(delay).To(Equal(expectedDelay))
+			Expect(duration).To(Equal(expectedDuration))
+		},
+			Entry("check started late and ran for two seconds",
+				scheduled.Add(5*time.Second), scheduled.Add(7*time.Second), 5*time.Second, 2*time.Second),
+			Entry("check started on schedule",
+				scheduled, scheduled.Add(250*time.Millisecond), time.Duration(0), 250*time.Millisecond),
+			Entry("check started ahead of schedule reports no delay",
+				scheduled.Add(-time.Second), scheduled.Add(time.Second), time.Duration(0), 2*time.Second),
+		)
+	})
+
+	var _ = Context("when computing the next check time", func() {
+
+		DescribeTable("scheduling the next check", func(now time.Time, interval time.Duration, aligned bool, expected time.Time) {
+			Expect(nextCheckTime(now, interval, aligned)).To(BeTemporally("==", expected))
+		},
+			Entry("unaligned counts the interval from now",
+				time.Date(2022, time.November, 1, 10, 0, 37, 0, time.UTC), time.Minute, false,
+				time.Date(2022, time.November, 1, 10, 1, 37, 0, time.UTC)),
+			Entry("aligned snaps to the next boundary of the interval",
+				time.Date(2022, time.November, 1, 10, 0, 37, 0, time.UTC), time.Minute, true,
+				time.Date(2022, time.November, 1, 10, 1, 0, 0, time.UTC)),
+			Entry("aligned moves to the following boundary when already on one",
+				time.Date(2022, time.November, 1, 10, 0, 0, 0, time.UTC), time.Minute, true,
+				time.Date(2022, time.November, 1, 10, 1, 0, 0, time.UTC)),
+			Entry("aligned with a five minute interval lands on a multiple of five minutes",
+				time.Date(2022, time.November, 1, 10, 3, 21, 0, time.UTC), 5*time.Minute, true,
+				time.Date(2022, time.November, 1, 10, 5, 0, 0, time.UTC)),
+		)
+	})
+
+	var _ = Context("when jittering the polling interval", func() {
+
+		It("returns the interval unchanged when jitter is disabled", func() {
+			Expect(jitterInterval(time.Minute, 0)).To(Equal(time.Minute))
+			Expect(jitterInterval(time.Minute, -5)).To(Equal(time.Minute))
+		})
+
+		It("extends the interval by at most the configured percentage", func() {
+			for i := 0; i < 100; i++ {
+				jittered := jitterInterval(time.Minute, 20)
+				Expect(jittered).To(BeNumerically(">=", time.Minute))
+				Expect(jittered).To(BeNumerically("<", time.Minute+12*time.Second))
+			}
+		})
+
+		It("treats a percentage above one hundred as one hundred", func() {
+			for i := 0; i < 100; i++ {
+				Expect(jitterInterval(time.Minute, 500)).To(BeNumerically("<", 2*time.Minute))
+			}
+		})
+	})
+
+	var _ = Context("when tolerating clock skew in timestamp decisions", func() {
+
+		var now = time.Date(2022, time.November, 1, 10, 0, 0, 0, time.UTC)
+
+		DescribeTable("evaluating the freshness window", func(timestamp time.Time, window, tolerance time.Duration, expectedWithin, expectedExcessiveSkew bool) {
+			within, excessiveSkew := withinFreshnessWindow(now, timestamp, window, tolerance)
+			Expect(within).To(Equal(expectedWithin))
+			Expect(excessiveSkew).To(Equal(expectedExcessiveSkew))
+		},
+			Entry("timestamp well inside the window",
+				now.Add(-time.Minute), 5*time.Minute, time.Duration(0), true, false),
+			Entry("timestamp outside the window without tolerance",
+				now.Add(-6*time.Minute), 5*time.Minute, time.Duration(0), false, false),
+			Entry("timestamp skewed beyond the window but within the tolerance",
+				now.Add(-6*time.Minute), 5*time.Minute, 2*time.Minute, true, false),
+			Entry("timestamp skewed beyond the window and the tolerance",
+				now.Add(-8*time.Minute), 5*time.Minute, 2*time.Minute, false, false),
+			Entry("timestamp slightly in the future within the tolerance",
+				now.Add(time.Minute), 5*time.Minute, 2*time.Minute, true, false),
+			Entry("timestamp in the future beyond the tolerance is reported as excessive skew",
+				now.Add(3*time.Minute), 5*time.Minute, 2*time.Minute, true, true),
+		)
+	})
+
+	var _ = Context("when formatting the duration a state was held", func() {
+
+		DescribeTable("dropping the trailing zero components", func(held time.Duration, expected string) {
+			Expect(formatHeldDuration(held)).To(Equal(expected))
+		},
+			Entry("hours and minutes", 3*time.Hour+12*time.Minute, "3h12m"),
+			Entry("whole hours", time.Hour, "1h"),
+			Entry("whole minutes", 10*time.Minute, "10m"),
+			Entry("minutes and seconds", 2*time.Minute+5*time.Second, "2m5s"),
+			Entry("seconds ending in zero are kept intact", 10*time.Second, "10s"),
+			Entry("sub-second durations round to zero", 300*time.Millisecond, "0s"),
+			Entry("negative durations clamp to zero", -time.Minute, "0s"),
+		)
+	})
+
+	var _ = Context("when deciding whether drift monitoring is paused", func() {
+
+		DescribeTable("combining the spec field and the annotation", func(spec bool, annotations map[string]string, expected bool) {
+			pattern := &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace, Annotations: annotations},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{DriftPaused: spec}}}
+			Expect(driftMonitoringPaused(pattern)).To(Equal(expected))
+		},
+			Entry("not paused by default", false, nil, false),
+			Entry("paused through the spec field", true, nil, true),
+			Entry("paused through the annotation", false, map[string]string{driftPausedAnnotation: "true"}, true),
+			Entry("paused through the annotation with any value", false, map[string]string{driftPausedAnnotation: ""}, true),
+			Entry("an explicit false annotation does not pause", false, map[string]string{driftPausedAnnotation: "false"}, false),
+			Entry("unrelated annotations do not pause", false, map[string]string{"some-annotation": "true"}, false),
+		)
+	})
+
+	var _ = Context("When interacting with the pair slice", func() {
+
+		var (
+			one   = &repositoryPair{name: "one", namespace: "default", nextCheck: time.Time{}.Add(time.Second)}
+			three = &repositoryPair{name: "three", namespace: "default", nextCheck: time.Time{}.Add(3 * time.Second)}
+			four  = &repositoryPair{name: "four", namespace: "default", nextCheck: time.Time{}.Add(4 * time.Second)}
+			five  = &repositoryPair{name: "second", namespace: "default", nextCheck: time.Time{}.Add(5 * time.Second)}
+		)
+		It("sorts correctly the order", func() {
+			watch := newWatcher(nil)
+			watch.watch()
+			By("adding four elements")
+			watch.repoPairs = []*repositoryPair{five, three, one, four}
+			sort.Sort(watch.repoPairs)
+			Expect(watch.repoPairs).To(HaveLen(4))
+			Expect(watch.repoPairs[0]).To(BeEquivalentTo(one))
+			Expect(watch.repoPairs[1]).To(BeEquivalentTo(three))
+			Expect(watch.repoPairs[2]).To(BeEquivalentTo(four))
+			Expect(watch.repoPairs[3]).To(BeEquivalentTo(five))
+			By("removing the first element")
+			watch.repoPairs = watch.repoPairs[1:]
+			sort.Sort(watch.repoPairs)
+			Expect(watch.repoPairs[0]).To(BeEquivalentTo(three))
+			Expect(watch.repoPairs[1]).To(BeEquivalentTo(four))
+			Expect(watch.repoPairs[2]).To(BeEquivalentTo(five))
+		})
+
+	})
+
+	var _ = Context("When updating the pattern conditions", func() {
+
+		var (
+			ctx     = context.Background()
+			pattern api.Pattern
+		)
+
+		BeforeEach(func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{Hostname: foo, PollInterval: 30}},
+			}
+			e := k8sClient.Create(ctx, &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			e := k8sClient.Delete(ctx, &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+		It("adds the first condition", func() {
+			var p api.Pattern
+			timestamp := time.Time{}.Add(1 * time.Second)
+			By("validating the pattern has no conditions yet")
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).NotTo(BeNil())
+			Expect(p.Status.Conditions).To(HaveLen(0))
+			By("calling the update pattern conditions to add a new condition")
+			e := updatePatternConditions(k8sClient, nil, api.GitInSync, nil, foo, defaultNamespace, timestamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			By("retrieving the pattern object once more and validating that it contains the new condition")
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).NotTo(BeNil())
+			Expect(p.Status.Conditions).To(HaveLen(1))
+			Expect(p.Status.Conditions[0]).To(BeComparableTo(api.PatternCondition{
+				Type:               api.GitInSync,
+				Status:             v1core.ConditionTrue,
+				LastUpdateTime:     v1.Time{Time: timestamp},
+				LastTransitionTime: v1.Time{Time: timestamp},
+				Message:            "Git repositories are in sync",
+			}))
+		})
+		It("updates lastUpdate field when condition still occurs while condition is active", func() {
+			var p api.Pattern
+			firstTimestamp := time.Time{}.Add(1 * time.Second)
+			By("calling the update pattern conditions to add the condition")
+			e := updatePatternConditions(k8sClient, nil, api.GitInSync, nil, foo, defaultNamespace, firstTimestamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			By("calling the update pattern conditions again to trigger the update of the lastUpdate field")
+			secondTimeStamp := time.Time{}.Add(2 * time.Second)
+			e = updatePatternConditions(k8sClient, nil, api.GitInSync, nil, foo, defaultNamespace, secondTimeStamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			By("retrieving the pattern object")
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).NotTo(BeNil())
+			Expect(p.Status.Conditions).To(HaveLen(1))
+			Expect(p.Status.Conditions[0]).To(BeComparableTo(api.PatternCondition{
+				Type:               api.GitInSync,
+				Status:             v1core.ConditionTrue,
+				LastUpdateTime:     v1.Time{Time: secondTimeStamp},
+				LastTransitionTime: v1.Time{Time: firstTimestamp},
+				Message:            "Git repositories are in sync",
+			}))
+		})
+		It("states the previous condition and how long it lasted when detailed messages are enabled", func() {
+			var p api.Pattern
+			By("enabling detailed transition messages on the pattern")
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			p.Spec.GitConfig.DetailedTransitionMessages = true
+			Expect(k8sClient.Update(ctx, &p)).NotTo(HaveOccurred())
+			By("raising the in-sync condition and transitioning to out-of-sync later")
+			firstTimestamp := time.Time{}.Add(1 * time.Second)
+			e := updatePatternConditions(k8sClient, nil, api.GitInSync, nil, foo, defaultNamespace, firstTimestamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			secondTimeStamp := firstTimestamp.Add(3*time.Hour + 12*time.Minute)
+			e = updatePatternConditions(k8sClient, nil, api.GitOutOfSync, nil, foo, defaultNamespace, secondTimeStamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			By("validating the raised condition names the previous state and its duration")
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitOutOfSync)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+			Expect(condition.Message).To(Equal("was GitInSync for 3h12m, now GitOutOfSync"))
+		})
+		It("transitions to a new condition type as status true", func() {
+			var p api.Pattern
+			firstTimestamp := time.Time{}.Add(1 * time.Second)
+			By("calling the update pattern conditions to add the condition")
+			e := updatePatternConditions(k8sClient, nil, api.GitInSync, nil, foo, defaultNamespace, firstTimestamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			By("calling the update pattern conditions again to trigger the update of the lastUpdate field")
+			secondTimeStamp := time.Time{}.Add(2 * time.Second)
+			e = updatePatternConditions(k8sClient, nil, api.GitOutOfSync, nil, foo, defaultNamespace, secondTimeStamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			By("retrieving the pattern object")
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).NotTo(BeNil())
+			Expect(p.Status.Conditions).To(HaveLen(2))
+			Expect(p.Status.Conditions[0]).To(BeComparableTo(api.PatternCondition{
+				Type:               api.GitInSync,
+				Status:             v1core.ConditionFalse,
+				LastUpdateTime:     v1.Time{Time: secondTimeStamp},
+				LastTransitionTime: v1.Time{Time: firstTimestamp},
+				Message:            "Git repositories are in sync",
+			}))
+			Expect(p.Status.Conditions[1]).To(BeComparableTo(api.PatternCondition{
+				Type:               api.GitOutOfSync,
+				Status:             v1core.ConditionTrue,
+				LastUpdateTime:     v1.Time{Time: secondTimeStamp},
+				LastTransitionTime: v1.Time{Time: secondTimeStamp},
+				Message:            "Git repositories are out of sync",
+			}))
+		})
+		It("prunes stale watcher-owned conditions while keeping the active and foreign ones", func() {
+			var p api.Pattern
+			seedTimestamp := time.Time{}.Add(1 * time.Second)
+			By("seeding the pattern with a stale informational condition and a foreign condition")
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			p.Status.Conditions = []api.PatternCondition{
+				{Type: api.GitDefaultBranchChanged, Status: v1core.ConditionFalse, LastUpdateTime: v1.Time{Time: seedTimestamp}, LastTransitionTime: v1.Time{Time: seedTimestamp}, Message: "default branch changed"},
+				{Type: "SomeOtherComponent", Status: v1core.ConditionFalse, LastUpdateTime: v1.Time{Time: seedTimestamp}, LastTransitionTime: v1.Time{Time: seedTimestamp}, Message: "not ours"},
+			}
+			err = k8sClient.Status().Update(ctx, &p)
+			Expect(err).NotTo(HaveOccurred())
+			By("calling the update pattern conditions to add the active drift condition")
+			e := updatePatternConditions(k8sClient, nil, api.GitInSync, nil, foo, defaultNamespace, time.Time{}.Add(2*time.Second), nil)
+			Expect(e).NotTo(HaveOccurred())
+			By("retrieving the pattern object and validating the stale condition has been pruned")
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.Status.Conditions).To(HaveLen(2))
+			Expect(p.Status.Conditions[0].Type).To(Equal(api.PatternConditionType("SomeOtherComponent")))
+			Expect(p.Status.Conditions[1].Type).To(Equal(api.GitInSync))
+			Expect(p.Status.Conditions[1].Status).To(Equal(v1core.ConditionTrue))
+		})
+		It("normalizes an inconsistent state where both drift conditions are true", func() {
+			var p api.Pattern
+			seedTimestamp := time.Time{}.Add(1 * time.Second)
+			By("seeding the pattern with both drift conditions set to true")
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			p.Status.Conditions = []api.PatternCondition{
+				{Type: api.GitInSync, Status: v1core.ConditionTrue, LastUpdateTime: v1.Time{Time: seedTimestamp}, LastTransitionTime: v1.Time{Time: seedTimestamp}, Message: "Git repositories are in sync"},
+				{Type: api.GitOutOfSync, Status: v1core.ConditionTrue, LastUpdateTime: v1.Time{Time: seedTimestamp}, LastTransitionTime: v1.Time{Time: seedTimestamp}, Message: "Git repositories are out of sync"},
+			}
+			err = k8sClient.Status().Update(ctx, &p)
+			Expect(err).NotTo(HaveOccurred())
+			By("calling the update pattern conditions to correct the state")
+			updateTimestamp := time.Time{}.Add(2 * time.Second)
+			e := updatePatternConditions(k8sClient, nil, api.GitInSync, nil, foo, defaultNamespace, updateTimestamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			By("retrieving the pattern object and validating only one drift condition remains true")
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.Status.Conditions).To(HaveLen(2))
+			Expect(p.Status.Conditions[0].Type).To(Equal(api.GitInSync))
+			Expect(p.Status.Conditions[0].Status).To(Equal(v1core.ConditionTrue))
+			Expect(p.Status.Conditions[1].Type).To(Equal(api.GitOutOfSync))
+			Expect(p.Status.Conditions[1].Status).To(Equal(v1core.ConditionFalse))
+			Expect(p.Status.Conditions[1].LastUpdateTime.Time).To(BeTemporally("==", updateTimestamp))
+		})
+		It("transitions back to an existing condition type", func() {
+			var p api.Pattern
+			firstTimestamp := time.Time{}.Add(1 * time.Second)
+			By("calling the update pattern conditions to add the condition")
+			e := updatePatternConditions(k8sClient, nil, api.GitInSync, nil, foo, defaultNamespace, firstTimestamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			By("calling the update pattern conditions again to trigger the update of the lastUpdate field")
+			secondTimeStamp := time.Time{}.Add(2 * time.Second)
+			e = updatePatternConditions(k8sClient, nil, api.GitOutOfSync, nil, foo, defaultNamespace, secondTimeStamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			thirdTimeStamp := time.Time{}.Add(3 * time.Second)
+			e = updatePatternConditions(k8sClient, nil, api.GitInSync, nil, foo, defaultNamespace, thirdTimeStamp, nil)
+			Expect(e).NotTo(HaveOccurred())
+			By("retrieving the pattern object")
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).NotTo(BeNil())
+			Expect(p.Status.Conditions).To(HaveLen(2))
+			Expect(p.Status.Conditions[0]).To(BeComparableTo(api.PatternCondition{
+				Type:               api.GitInSync,
+				Status:             v1core.ConditionTrue,
+				LastUpdateTime:     v1.Time{Time: thirdTimeStamp},
+				LastTransitionTime: v1.Time{Time: thirdTimeStamp},
+				Message:            "Git repositories are in sync",
+			}))
+			Expect(p.Status.Conditions[1]).To(BeComparableTo(api.PatternCondition{
+				Type:               api.GitOutOfSync,
+				Status:             v1core.ConditionFalse,
+				LastUpdateTime:     v1.Time{Time: thirdTimeStamp},
+				LastTransitionTime: v1.Time{Time: secondTimeStamp},
+				Message:            "Git repositories are out of sync",
+			}))
+		})
+	})
+
+})
+
+var _ = Describe("Drift watcher", func() {
+
+	const ()
+	var _ = Context("when emitting events on drift transitions", func() {
+
+		var (
+			ctx      = context.Background()
+			pattern  api.Pattern
+			recorder *record.FakeRecorder
+		)
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{Hostname: foo, PollInterval: 30}},
+			}
+			e := k8sClient.Create(ctx, &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			e := k8sClient.Delete(ctx, &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("emits a warning event when drift is detected and a normal one on recovery", func() {
+			e := updatePatternConditions(k8sClient, recorder, api.GitOutOfSync, nil, foo, defaultNamespace, time.Now(), nil)
+			Expect(e).NotTo(HaveOccurred())
+			var event string
+			Expect(recorder.Events).To(Receive(&event))
+			Expect(event).To(ContainSubstring("Warning"))
+			Expect(event).To(ContainSubstring(eventReasonDriftDetected))
+
+			e = updatePatternConditions(k8sClient, recorder, api.GitInSync, nil, foo, defaultNamespace, time.Now(), nil)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(&event))
+			Expect(event).To(ContainSubstring("Normal"))
+			Expect(event).To(ContainSubstring(eventReasonBackInSync))
+		})
+
+		It("emits no event while the drift state does not transition", func() {
+			e := updatePatternConditions(k8sClient, recorder, api.GitOutOfSync, nil, foo, defaultNamespace, time.Now(), nil)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive())
+
+			e = updatePatternConditions(k8sClient, recorder, api.GitOutOfSync, nil, foo, defaultNamespace, time.Now(), nil)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("emits a warning event carrying the attribution of a failed check", func() {
+			failure := &api.CheckFailure{Side: "origin", Host: foo, Class: failureClassAuthentication}
+			e := updatePatternConditions(k8sClient, recorder, api.GitCheckFailed, nil, foo, defaultNamespace, time.Now(), failure)
+			Expect(e).NotTo(HaveOccurred())
+			var event string
+			Expect(recorder.Events).To(Receive(&event))
+			Expect(event).To(ContainSubstring("Warning"))
+			Expect(event).To(ContainSubstring(eventReasonCheckFailed))
+			Expect(event).To(ContainSubstring(failureClassAuthentication))
+		})
+	})
+
+	var _ = Context("when classifying the side of a drift", func() {
+
+		var (
+			ctx     = context.Background()
+			pattern api.Pattern
+		)
+
+		BeforeEach(func() {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{Hostname: foo, PollInterval: 30}},
+			}
+			e := k8sClient.Create(ctx, &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			e := k8sClient.Delete(ctx, &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		outOfSync := func() api.PatternCondition {
+			var p api.Pattern
+			e := k8sClient.Get(ctx, types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(e).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitOutOfSync)
+			Expect(condition).NotTo(BeNil())
+			return *condition
+		}
+
+		It("reports an upstream that moved ahead with the sync remediation", func() {
+			e := updatePatternConditions(k8sClient, nil, api.GitOutOfSync, classifyDrift(3, 0), foo, defaultNamespace, time.Now(), nil)
+			Expect(e).NotTo(HaveOccurred())
+			condition := outOfSync()
+			Expect(condition.Reason).To(Equal(driftClassUpstreamAhead))
+			Expect(condition.Message).To(ContainSubstring("origin is ahead by 3 commit(s)"))
+			Expect(condition.Message).To(ContainSubstring("syncing the target forward"))
+		})
+
+		It("reports a fork holding commits of its own with the merge remediation", func() {
+			e := updatePatternConditions(k8sClient, nil, api.GitOutOfSync, classifyDrift(0, 2), foo, defaultNamespace, time.Now(), nil)
+			Expect(e).NotTo(HaveOccurred())
+			condition := outOfSync()
+			Expect(condition.Reason).To(Equal(driftClassForkAhead))
+			Expect(condition.Message).To(ContainSubstring("target holds 2 commit(s) of its own"))
+		})
+
+		It("refreshes the classification while the condition stays raised", func() {
+			e := updatePatternConditions(k8sClient, nil, api.GitOutOfSync, classifyDrift(3, 0), foo, defaultNamespace, time.Now(), nil)
+			Expect(e).NotTo(HaveOccurred())
+			e = updatePatternConditions(k8sClient, nil, api.GitOutOfSync, classifyDrift(3, 1), foo, defaultNamespace, time.Now(), nil)
+			Expect(e).NotTo(HaveOccurred())
+			condition := outOfSync()
+			Expect(condition.Reason).To(Equal(driftClassDiverged))
+			Expect(condition.Message).To(ContainSubstring("histories diverged"))
+			Expect(condition.Message).To(ContainSubstring("origin is ahead by 3 commit(s) and target by 1"))
+		})
+
+		It("keeps the generic wording when the divergence could not be counted", func() {
+			Expect(classifyDrift(0, 0)).To(BeNil())
+			e := updatePatternConditions(k8sClient, nil, api.GitOutOfSync, classifyDrift(0, 0), foo, defaultNamespace, time.Now(), nil)
+			Expect(e).NotTo(HaveOccurred())
+			condition := outOfSync()
+			Expect(condition.Reason).To(Equal(eventReasonDriftDetected))
+			Expect(condition.Message).To(Equal("Git repositories are out of sync"))
+		})
+	})
+
+	var _ = Context("When watching for drifts", func() {
+		var (
+			patternFoo                         *api.Pattern
+			ctrl                               *gomock.Controller
+			mockGitClient                      *MockClient
+			mockRemoteOrigin, mockRemoteTarget *MockRemoteClient
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteTarget = NewMockRemoteClient(ctrl)
+			// Add the pattern in etcd
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+
+			err := k8sClient.Create(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+
+		})
+
+		AfterEach(func() {
+
+			err := k8sClient.Delete(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("detects a drift between a pair of git repositories after the second check", func() {
+			var (
+				payloadDelivered bool
+			)
+
+			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).DoAndReturn(func(c *config.RemoteConfig) RemoteClient {
+				if c.Name == "origin" {
+					return mockRemoteOrigin
+				}
+				return mockRemoteTarget
+			}).AnyTimes()
+
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+			mockRemoteTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ *git.ListOptions) ([]*plumbing.Reference, error) {
+				if !payloadDelivered {
+					payloadDelivered = true
+					return firstCommitReference, nil
+				}
+				return multipleCommitsWithDifferentHashReference, nil
+			}).AnyTimes()
+			watch, closeCh := newDriftWatcher(k8sClient, logr.New(log.NullLogSink{}), mockGitClient, nil)
+
+			// Add the pair
+			timestamp := time.Now()
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(func() bool {
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, patternFoo)
+				Expect(err).NotTo(HaveOccurred())
+				return len(patternFoo.Status.Conditions) == 1
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(), "expected number of conditions %d but found %d", 1, len(patternFoo.Status.Conditions))
+			// check that the conditions reflect the drift polling
+			Expect(patternFoo.Status.Conditions[0].Type).To(Equal(api.GitInSync))
+			Expect(patternFoo.Status.Conditions[0].Status).To(Equal(v1core.ConditionTrue))
+			Expect(patternFoo.Status.Conditions[0].LastUpdateTime.Time).To(BeTemporally(">", timestamp))
+			Expect(patternFoo.Status.Conditions[0].LastTransitionTime.Time).To(BeTemporally(">", timestamp))
+			// wait for the second check to report the drift
+			Eventually(func() bool {
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, patternFoo)
+				Expect(err).NotTo(HaveOccurred())
+				return len(patternFoo.Status.Conditions) == 2
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(), "expected number of conditions %d but found %d", 2, len(patternFoo.Status.Conditions))
+			// notify the routine that we're closing so that it doesn't keep checking for more drifts
+			close(closeCh)
+			// retrieve the first element in the slice
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+
+			// previous condition should have status false
+			Expect(patternFoo.Status.Conditions[0].Type).To(Equal(api.GitInSync))
+			Expect(patternFoo.Status.Conditions[0].Status).To(Equal(v1core.ConditionFalse))
+			Expect(patternFoo.Status.Conditions[0].LastUpdateTime.Time).To(BeTemporally("==", patternFoo.Status.Conditions[1].LastUpdateTime.Time))
+			Expect(patternFoo.Status.Conditions[0].LastTransitionTime.Time).To(BeTemporally("==", patternFoo.Status.Conditions[0].LastUpdateTime.Time.Add(-1*time.Second)))
+			// new condition should show the repositories have drifted
+			Expect(patternFoo.Status.Conditions[1].Type).To(Equal(api.GitOutOfSync))
+			Expect(patternFoo.Status.Conditions[1].Status).To(Equal(v1core.ConditionTrue))
+			Expect(patternFoo.Status.Conditions[1].LastTransitionTime.Time).To(BeTemporally("==", patternFoo.Status.Conditions[1].LastUpdateTime.Time))
+			Expect(patternFoo.Status.Conditions[1].LastUpdateTime.Time).To(BeTemporally("==", patternFoo.Status.Conditions[0].LastTransitionTime.Time.Add(time.Second)))
+		})
+
+	})
+
+	var _ = Context("when rebuilding the schedule after a restart", func() {
+
+		It("keeps the slot of a pair that is not yet due", func() {
+			pair := &repositoryPair{interval: time.Hour}
+			lastCheck := time.Now().Add(-10 * time.Minute)
+			restorePairSchedule(pair, lastCheck)
+			Expect(pair.lastCheck).To(BeTemporally("==", lastCheck))
+			Expect(pair.nextCheck).To(BeTemporally("==", lastCheck.Add(time.Hour)))
+		})
+
+		It("staggers a pair that became overdue while the operator was down", func() {
+			now := time.Now()
+			pair := &repositoryPair{interval: time.Minute}
+			restorePairSchedule(pair, now.Add(-2*time.Hour))
+			Expect(pair.nextCheck).To(BeTemporally(">=", now))
+			Expect(pair.nextCheck).To(BeTemporally("<", now.Add(16*time.Second)))
+		})
+
+		It("starts from scratch when no timestamp was persisted", func() {
+			pair := &repositoryPair{interval: time.Minute, nextCheck: time.Now().Add(time.Minute)}
+			original := pair.nextCheck
+			restorePairSchedule(pair, time.Time{})
+			Expect(pair.lastCheck.IsZero()).To(BeTrue())
+			Expect(pair.nextCheck).To(BeTemporally("==", original))
+		})
+
+		It("reads the persisted timestamps of the primary and additional pairs", func() {
+			pattern := &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			Expect(k8sClient.Create(context.Background(), pattern)).NotTo(HaveOccurred())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), pattern)).NotTo(HaveOccurred())
+			}()
+			primaryCheck := v1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+			pairCheck := v1.NewTime(time.Now().Add(-2 * time.Minute).Truncate(time.Second))
+			pattern.Status.DriftStatus = &api.DriftStatus{LastCheck: primaryCheck}
+			pattern.Status.PairDriftStatuses = []api.PairDriftStatus{{Name: bar, LastCheck: pairCheck}}
+			Expect(k8sClient.Status().Update(context.Background(), pattern)).NotTo(HaveOccurred())
+
+			persisted := newWatcher(nil).persistedLastChecks(foo, defaultNamespace)
+			Expect(persisted[""]).To(BeTemporally("==", primaryCheck.Time))
+			Expect(persisted[bar]).To(BeTemporally("==", pairCheck.Time))
+			Expect(newWatcher(nil).persistedLastChecks("absent", defaultNamespace)).To(BeEmpty())
+		})
+	})
+
+	var _ = Context("when renewing the heartbeat lease", func() {
+		var (
+			patternFoo                         *api.Pattern
+			mockGitClient                      *MockClient
+			mockRemoteOrigin, mockRemoteTarget *MockRemoteClient
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteTarget = NewMockRemoteClient(ctrl)
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			err := k8sClient.Create(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Setenv("DRIFT_LEASE_NAME", "drift-watcher")).To(Succeed())
+			Expect(os.Setenv("DRIFT_LEASE_NAMESPACE", defaultNamespace)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.Unsetenv("DRIFT_LEASE_NAME")).To(Succeed())
+			Expect(os.Unsetenv("DRIFT_LEASE_NAMESPACE")).To(Succeed())
+			err := k8sClient.Delete(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+			lease := &coordinationv1.Lease{ObjectMeta: v1.ObjectMeta{Name: "drift-watcher", Namespace: defaultNamespace}}
+			err = k8sClient.Delete(context.TODO(), lease)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("advances the lease renew time while checks run and stalls when the loop stops", func() {
+			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).DoAndReturn(func(c *config.RemoteConfig) RemoteClient {
+				if c.Name == "origin" {
+					return mockRemoteOrigin
+				}
+				return mockRemoteTarget
+			}).AnyTimes()
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+			mockRemoteTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+			watch, closeCh := newDriftWatcher(k8sClient, logr.New(log.NullLogSink{}), mockGitClient, nil)
+
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			By("waiting for the lease to be created and renewed while checks run")
+			var lease coordinationv1.Lease
+			Eventually(func() error {
+				return k8sClient.Get(context.TODO(), types.NamespacedName{Name: "drift-watcher", Namespace: defaultNamespace}, &lease)
+			}).WithPolling(time.Second).WithTimeout(10 * time.Second).Should(Succeed())
+			Expect(lease.Spec.HolderIdentity).NotTo(BeNil())
+			Expect(*lease.Spec.HolderIdentity).To(Equal(driftWatcherLeaseHolder))
+			firstRenewal := lease.Spec.RenewTime.Time
+			Eventually(func() bool {
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "drift-watcher", Namespace: defaultNamespace}, &lease)
+				Expect(err).NotTo(HaveOccurred())
+				return lease.Spec.RenewTime.Time.After(firstRenewal)
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(), "expected the lease renew time to advance while checks run")
+
+			By("validating the renew time stalls once the loop has stopped")
+			close(closeCh)
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: "drift-watcher", Namespace: defaultNamespace}, &lease)
+			Expect(err).NotTo(HaveOccurred())
+			lastRenewal := lease.Spec.RenewTime.Time
+			Consistently(func() time.Time {
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "drift-watcher", Namespace: defaultNamespace}, &lease)
+				Expect(err).NotTo(HaveOccurred())
+				return lease.Spec.RenewTime.Time
+			}).WithPolling(time.Second).WithTimeout(3 * time.Second).Should(BeTemporally("==", lastRenewal))
+		})
+	})
+	var _ = Context("when evaluating the processing order", func() {
+		var (
+			mockGitClient          *MockClient
+			mockRemote             *MockRemoteClient
+			patternBar, patternFoo *api.Pattern
+			ctrl                   *gomock.Controller
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemote = NewMockRemoteClient(ctrl)
+
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			patternBar = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: bar, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			e := k8sClient.Create(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+			e = k8sClient.Create(context.Background(), patternBar)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Delete(context.TODO(), patternBar)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("processes two pairs of git repositories in order of shortest interval", func() {
+			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
+			mockRemote.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+
+			watch := newWatcher(mockGitClient)
+			watch.watch()
+
+			// Add both reference pairs and wait for the drift evaluation to kick in and add the first condition
+			err := watch.add(foo, defaultNamespace, 5, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = watch.add(bar, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			// check the order of processing pairs
+			Expect(watch.repoPairs[0].name).To(Equal(bar))
+			Expect(watch.repoPairs[1].name).To(Equal(foo))
+			Eventually(func() bool {
+				var pFoo, pBar api.Pattern
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pFoo)
+				Expect(err).NotTo(HaveOccurred())
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: bar, Namespace: defaultNamespace}, &pBar)
+				Expect(err).NotTo(HaveOccurred())
+				return len(pFoo.Status.Conditions) == 0 && len(pBar.Status.Conditions) == 1
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(),
+				"expected number of conditions for foo %d and bar %d but found %d and %d respectivelly ", 0, len(patternFoo.Status.Conditions), 1, len(patternBar.Status.Conditions))
+			//confirm the status contains a new condition with type git in sync
+			var pattern api.Pattern
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: bar, Namespace: defaultNamespace}, &pattern)
+			Expect(err).NotTo(HaveOccurred())
+			// check that the conditions reflect the drift polling
+			Expect(pattern.Status.Conditions[0].Type).To(Equal(api.GitInSync))
+			Expect(pattern.Status.Conditions[0].Status).To(Equal(v1core.ConditionTrue))
+		})
+		It("removes the fist pair and adds it again with longer interval to ensure it is requeued the last", func() {
+			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
+			mockRemote.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+
+			watch := newWatcher(mockGitClient)
+			watch.watch()
+			// Add both reference pairs and wait for the drift evaluation to kick in and add the first condition
+			err := watch.add(foo, defaultNamespace, 5, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = watch.add(bar, defaultNamespace, 4, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			// remove the first element
+			err = watch.remove(bar, defaultNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			// readd the first element but with longer interval
+			err = watch.add(bar, defaultNamespace, 5, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			// check the order of processing pairs
+			Expect(watch.repoPairs[0].name).To(Equal(foo))
+			Expect(watch.repoPairs[1].name).To(Equal(bar))
+			// wait for the first element to be processed at least once
+			var pattern api.Pattern
+			Eventually(func() bool {
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pattern)
+				Expect(err).NotTo(HaveOccurred())
+				return len(pattern.Status.Conditions) == 1
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(), "expected number of conditions to be %d but found %d", 1, len(pattern.Status.Conditions))
+		})
+
+		It("updates the interval of an existing repository pair", func() {
+			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
+			mockRemote.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+
+			watch := newWatcher(mockGitClient)
+			watch.watch()
+			// Add both reference pairs and wait for the drift evaluation to kick in and add the first condition
+			err := watch.add(foo, defaultNamespace, 5, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = watch.add(bar, defaultNamespace, 4, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			// update the first element but with longer interval
+			err = watch.updateInterval(bar, defaultNamespace, 6, false)
+			Expect(err).NotTo(HaveOccurred())
+			// check the order of processing pairs
+			Expect(watch.repoPairs[0].name).To(Equal(foo))
+			Expect(watch.repoPairs[1].name).To(Equal(bar))
+			// wait for the first element to be processed at least once
+			var pattern api.Pattern
+			Eventually(func() bool {
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pattern)
+				Expect(err).NotTo(HaveOccurred())
+				return len(pattern.Status.Conditions) == 1
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(), "expected number of conditions to be %d but found %d", 1, len(pattern.Status.Conditions))
+		})
+
+		It("stores the validated comparison mode per pair and rejects unknown modes", func() {
+			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
+			mockRemote.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+
+			watch := newWatcher(mockGitClient)
+			watch.watch()
+			// the empty selector and the explicit hash selector both resolve to the hash comparison
+			err := watch.add(foo, defaultNamespace, 5, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = watch.add(bar, defaultNamespace, 5, false, "hash")
+			Expect(err).NotTo(HaveOccurred())
+			for _, pair := range watch.repoPairs {
+				Expect(pair.comparisonMode).To(Equal(compareHash))
+			}
+			// unknown modes are rejected at add time and the pair is not registered
+			err = watch.add("baz", defaultNamespace, 5, false, "rendered")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`unknown comparison mode "rendered"`))
+			Expect(watch.isWatching("baz", defaultNamespace)).To(BeFalse())
+		})
+	})
+
+	var _ = Context("when a namespace declares a maintenance window", func() {
+		const maintenanceNamespace = "maintenance"
+		var (
+			mockGitClient          *MockClient
+			mockRemote             *MockRemoteClient
+			patternBar, patternFoo *api.Pattern
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemote = NewMockRemoteClient(ctrl)
+
+			namespace := &v1core.Namespace{
+				ObjectMeta: v1.ObjectMeta{
+					Name:        maintenanceNamespace,
+					Annotations: map[string]string{maintenanceAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339)}}}
+			err := k8sClient.Create(context.Background(), namespace)
+			if err != nil {
+				Expect(kerrors.IsAlreadyExists(err)).To(BeTrue())
+			}
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: maintenanceNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			patternBar = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: bar, Namespace: maintenanceNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			e := k8sClient.Create(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+			e = k8sClient.Create(context.Background(), patternBar)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Delete(context.TODO(), patternBar)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("suppresses condition flips for all pairs in the namespace", func() {
+			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
+			mockRemote.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+
+			watch := newWatcher(mockGitClient)
+			watch.watch()
+			err := watch.add(foo, maintenanceNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = watch.add(bar, maintenanceNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Consistently(func() int {
+				var pFoo, pBar api.Pattern
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: maintenanceNamespace}, &pFoo)
+				Expect(err).NotTo(HaveOccurred())
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: bar, Namespace: maintenanceNamespace}, &pBar)
+				Expect(err).NotTo(HaveOccurred())
+				return len(pFoo.Status.Conditions) + len(pBar.Status.Conditions)
+			}).WithPolling(time.Second).WithTimeout(4 * time.Second).Should(Equal(0))
+		})
+	})
+
+	var _ = Context("when a git host suffers an outage", func() {
+		var (
+			mockGitClient          *MockClient
+			mockRemote             *MockRemoteClient
+			patternBar, patternFoo *api.Pattern
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemote = NewMockRemoteClient(ctrl)
+
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{Hostname: foo, OriginRepo: originURL, TargetRepo: targetURL}}}
+			patternBar = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: bar, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{Hostname: foo, OriginRepo: originURL, TargetRepo: targetURL}}}
+			e := k8sClient.Create(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+			e = k8sClient.Create(context.Background(), patternBar)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Delete(context.TODO(), patternBar)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("backs off all pairs on the failing host together and restores them together", func() {
+			var healthy int32
+			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
+			mockRemote.EXPECT().ListContext(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, *git.ListOptions) ([]*plumbing.Reference, error) {
+				if atomic.LoadInt32(&healthy) == 0 {
+					return nil, fmt.Errorf("connection reset by peer")
+				}
+				return firstCommitReference, nil
+			}).AnyTimes()
+
+			watch := newWatcher(mockGitClient)
+			watch.outageInterval = 2 * time.Second
+			watch.watch()
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = watch.add(bar, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			inOutage := func() bool {
+				watch.mutex.Lock()
+				defer watch.mutex.Unlock()
+				return watch.hostOutages[foo]
+			}
+			Eventually(inOutage).WithPolling(time.Second).WithTimeout(15 * time.Second).Should(BeTrue())
+			// both pairs are rescheduled to the slow probing cadence together
+			watch.mutex.Lock()
+			for _, pair := range watch.repoPairs {
+				Expect(time.Until(pair.nextCheck)).To(BeNumerically(">", time.Second))
+			}
+			watch.mutex.Unlock()
+
+			atomic.StoreInt32(&healthy, 1)
+			Eventually(inOutage).WithPolling(time.Second).WithTimeout(15 * time.Second).Should(BeFalse())
+			// the first successful probe restores the normal cadence for both pairs
+			watch.mutex.Lock()
+			for _, pair := range watch.repoPairs {
+				Expect(time.Until(pair.nextCheck)).To(BeNumerically("<=", time.Second))
+			}
+			watch.mutex.Unlock()
+		})
+	})
+
+	var _ = Context("when a check is triggered externally", func() {
+		var (
+			patternFoo    *api.Pattern
+			mockGitClient *MockClient
+			mockRemote    *MockRemoteClient
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemote = NewMockRemoteClient(ctrl)
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			err := k8sClient.Create(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("tags the triggered check with the caller's correlation ID", func() {
+			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
+			mockRemote.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+
+			sink := &captureSink{}
+			watch := newWatcher(mockGitClient)
+			watch.logger = logr.New(sink)
+			watch.watch()
+			// schedule the pair far enough in the future that only the trigger can run the check
+			err := watch.add(foo, defaultNamespace, 300, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = watch.checkNow(foo, defaultNamespace, "trigger-123")
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(func() bool {
+				for _, keysAndValues := range sink.captured() {
+					for i := 0; i+1 < len(keysAndValues); i += 2 {
+						if keysAndValues[i] == "correlationID" && keysAndValues[i+1] == "trigger-123" {
+							return true
+						}
+					}
+				}
+				return false
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(), "expected the triggered check to log with the caller's correlation ID")
+		})
+
+		It("rejects a trigger for a pair that is not being watched", func() {
+			watch := newWatcher(mockGitClient)
+			watch.watch()
+			err := watch.checkNow(bar, defaultNamespace, "trigger-123")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to find git remote pair"))
+		})
+	})
+
+	var _ = Context("when the API becomes unavailable", func() {
+		var patternFoo *api.Pattern
+
+		BeforeEach(func() {
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			err := k8sClient.Create(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("buffers the latest result per pair and flushes it once the API recovers", func() {
+			outage := &outageClient{Client: k8sClient, down: true}
+			watch := newWatcher(NewMockClient(gomock.NewController(GinkgoT())))
+			watch.kClient = outage
+
+			By("failing to persist the outcomes while the API is down")
+			err := watch.persistCondition(foo, defaultNamespace, api.GitInSync, nil, time.Now(), nil)
+			Expect(err).To(HaveOccurred())
+			err = watch.persistCondition(foo, defaultNamespace, api.GitOutOfSync, nil, time.Now(), nil)
+			Expect(err).To(HaveOccurred())
+			// only the latest outcome per pair is retained
+			Expect(watch.pendingConditions).To(HaveLen(1))
+			Expect(watch.pendingConditions[defaultNamespace+"/"+foo].conditionType).To(Equal(api.GitOutOfSync))
+
+			By("flushing the buffered outcome once the API returns")
+			outage.down = false
+			watch.flushPendingConditions()
+			Expect(watch.pendingConditions).To(BeEmpty())
+			var pattern api.Pattern
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pattern)
+			Expect(err).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.GitOutOfSync)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+		})
+	})
+
+	var _ = Context("when the status update hits a resource version conflict", func() {
+		var patternFoo *api.Pattern
+
+		BeforeEach(func() {
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			err := k8sClient.Create(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("retries against the fresh object instead of dropping the outcome", func() {
+			conflicting := &conflictingClient{Client: k8sClient, conflicts: 2}
+			err := updatePatternConditions(conflicting, nil, api.GitOutOfSync, nil, foo, defaultNamespace, time.Now(), nil)
+			Expect(err).NotTo(HaveOccurred())
+			// both injected conflicts were consumed before the update went through
+			Expect(atomic.LoadInt32(&conflicting.conflicts)).To(BeNumerically("<=", 0))
+			var pattern api.Pattern
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pattern)
+			Expect(err).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.GitOutOfSync)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+		})
+
+		It("surfaces the conflict once the retry budget is exhausted", func() {
+			conflicting := &conflictingClient{Client: k8sClient, conflicts: 100}
+			err := updatePatternConditions(conflicting, nil, api.GitOutOfSync, nil, foo, defaultNamespace, time.Now(), nil)
+			Expect(err).To(HaveOccurred())
+			Expect(kerrors.IsConflict(err)).To(BeTrue())
+		})
+
+		It("patches only the fields the watcher owns, leaving reconciler fields alone", func() {
+			var pattern api.Pattern
+			err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pattern)
+			Expect(err).NotTo(HaveOccurred())
+			pattern.Status.LastStep = "install the pattern"
+			err = k8sClient.Status().Update(context.TODO(), &pattern)
+			Expect(err).NotTo(HaveOccurred())
+
+			scoped := &patchScopeClient{Client: k8sClient}
+			err = updatePatternConditions(scoped, nil, api.GitInSync, nil, foo, defaultNamespace, time.Now(), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scoped.patches).To(HaveLen(1))
+			var body map[string]map[string]interface{}
+			Expect(json.Unmarshal(scoped.patches[0], &body)).To(Succeed())
+			Expect(body["status"]).To(HaveKey("conditions"))
+			Expect(body["status"]).NotTo(HaveKey("lastStep"))
+
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pattern.Status.LastStep).To(Equal("install the pattern"))
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.GitInSync)
+			Expect(condition).NotTo(BeNil())
+		})
+	})
+
+	var _ = Context("when applying drift severity thresholds", func() {
+
+		It("keeps the condition quiet below the configured commit distance", func() {
+			pair := &repositoryPair{drifted: true, minCommitsBehind: 5, commitsBehind: 2, commitsAhead: 1}
+			Expect(pair.conditionDrift()).To(BeFalse())
+			pair.commitsBehind = 5
+			Expect(pair.conditionDrift()).To(BeTrue())
+		})
+
+		It("does not suppress the condition when the distance could not be counted", func() {
+			pair := &repositoryPair{drifted: true, minCommitsBehind: 5}
+			Expect(pair.conditionDrift()).To(BeTrue())
+		})
+
+		It("debounces the condition until the drift has been sustained", func() {
+			pair := &repositoryPair{drifted: true, sustainedChecks: 3, consecutiveDrifts: 1}
+			Expect(pair.conditionDrift()).To(BeFalse())
+			pair.consecutiveDrifts = 3
+			Expect(pair.conditionDrift()).To(BeTrue())
+		})
+
+		It("never reports a pair whose last check found no drift", func() {
+			pair := &repositoryPair{drifted: false, consecutiveDrifts: 4}
+			Expect(pair.conditionDrift()).To(BeFalse())
+		})
+	})
+
+	var _ = Context("when only the leader may run checks", func() {
+
+		suspendedState := func(watch *watcher) func() bool {
+			return func() bool {
+				watch.mutex.Lock()
+				defer watch.mutex.Unlock()
+				return watch.suspended
+			}
+		}
+
+		It("schedules no timer while suspended and recovers the schedule on resume", func() {
+			watch, closeCh := newDriftWatcher(k8sClient, logr.New(log.NullLogSink{}), NewMockClient(gomock.NewController(GinkgoT())), nil)
+			defer close(closeCh)
+			impl := watch.(*watcher)
+			watch.suspend()
+
+			Expect(watch.add(foo, defaultNamespace, 300, false, "")).To(Succeed())
+			impl.mutex.Lock()
+			Expect(impl.timer).To(BeNil())
+			impl.mutex.Unlock()
+
+			watch.resume()
+			Eventually(func() *time.Timer {
+				impl.mutex.Lock()
+				defer impl.mutex.Unlock()
+				return impl.timer
+			}).ShouldNot(BeNil())
+		})
+
+		It("resumes the watcher on leadership and suspends it on shutdown", func() {
+			watch, closeCh := newDriftWatcher(k8sClient, logr.New(log.NullLogSink{}), NewMockClient(gomock.NewController(GinkgoT())), nil)
+			defer close(closeCh)
+			impl := watch.(*watcher)
+			watch.suspend()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			elector := &driftWatcherElector{watcher: watch}
+			Expect(elector.NeedLeaderElection()).To(BeTrue())
+			done := make(chan interface{})
+			go func() {
+				defer GinkgoRecover()
+				Expect(elector.Start(ctx)).To(Succeed())
+				close(done)
+			}()
+			Eventually(suspendedState(impl)).Should(BeFalse())
+			cancel()
+			Eventually(suspendedState(impl)).Should(BeTrue())
+			Eventually(done).Should(BeClosed())
+		})
+	})
+
+	var _ = Context("when draining on shutdown", func() {
+		var patternFoo *api.Pattern
+
+		BeforeEach(func() {
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			err := k8sClient.Create(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(context.TODO(), patternFoo)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("flushes the buffered outcomes and exits the loop deterministically", func() {
+			outage := &outageClient{Client: k8sClient, down: true}
+			watch, _ := newDriftWatcher(outage, logr.New(log.NullLogSink{}), NewMockClient(gomock.NewController(GinkgoT())), nil)
+			impl := watch.(*watcher)
+
+			By("buffering an outcome while the API is down")
+			impl.mutex.Lock()
+			err := impl.persistCondition(foo, defaultNamespace, api.GitOutOfSync, nil, time.Now(), nil)
+			impl.mutex.Unlock()
+			Expect(err).To(HaveOccurred())
+
+			By("draining once the API has returned")
+			outage.down = false
+			watch.drain()
+			Expect(atomic.LoadInt32(&impl.loopExited)).To(Equal(int32(1)))
+			Expect(impl.pendingConditions).To(BeEmpty())
+			var pattern api.Pattern
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pattern)
+			Expect(err).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.GitOutOfSync)
+			Expect(condition).NotTo(BeNil())
+
+			// a second drain is a no-op rather than a double close of endCh
+			watch.drain()
+		})
+
+		It("drains the watcher when the manager shuts down", func() {
+			watch, _ := newDriftWatcher(k8sClient, logr.New(log.NullLogSink{}), NewMockClient(gomock.NewController(GinkgoT())), nil)
+			impl := watch.(*watcher)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			drainer := &driftWatcherDrainer{watcher: watch}
+			Expect(drainer.NeedLeaderElection()).To(BeFalse())
+			done := make(chan interface{})
+			go func() {
+				defer GinkgoRecover()
+				Expect(drainer.Start(ctx)).To(Succeed())
+				close(done)
+			}()
+			Consistently(func() int32 { return atomic.LoadInt32(&impl.loopExited) }).Should(Equal(int32(0)))
+			cancel()
+			Eventually(done).Should(BeClosed())
+			Expect(atomic.LoadInt32(&impl.loopExited)).To(Equal(int32(1)))
+		})
+	})
+
+	var _ = Context("when probing the watcher health", func() {
+
+		It("passes for a watcher that is keeping up", func() {
+			watch := newWatcher(NewMockClient(gomock.NewController(GinkgoT())))
+			watch.repoPairs = append(watch.repoPairs, &repositoryPair{
+				name: foo, namespace: defaultNamespace, interval: 10 * time.Second, nextCheck: time.Now().Add(10 * time.Second)})
+			Expect(watch.healthCheck(nil)).To(Succeed())
+		})
+
+		It("fails when a due check has been starved beyond the budget", func() {
+			watch := newWatcher(NewMockClient(gomock.NewController(GinkgoT())))
+			watch.repoPairs = append(watch.repoPairs, &repositoryPair{
+				name: foo, namespace: defaultNamespace, interval: 10 * time.Second, nextCheck: time.Now().Add(-time.Minute)})
+			err := watch.healthCheck(nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("starved"))
+
+			By("tolerating the same delay for a pair whose check is running")
+			watch.repoPairs[0].inFlight = true
+			Expect(watch.healthCheck(nil)).To(Succeed())
+		})
+
+		It("fails while check outcomes cannot reach the API server", func() {
+			watch := newWatcher(NewMockClient(gomock.NewController(GinkgoT())))
+			outage := &outageClient{Client: k8sClient, down: true}
+			watch.kClient = outage
+			Expect(watch.persistCondition(foo, defaultNamespace, api.GitInSync, nil, time.Now(), nil)).NotTo(Succeed())
+			err := watch.healthCheck(nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unreachable"))
+		})
+
+		It("fails once the scheduler loop has exited", func() {
+			watch := newWatcher(NewMockClient(gomock.NewController(GinkgoT())))
+			closeCh := watch.watch()
+			Expect(watch.healthCheck(nil)).To(Succeed())
+			close(closeCh)
+			Eventually(func() error { return watch.healthCheck(nil) }).ShouldNot(Succeed())
+		})
+	})
+
+	var _ = Context("when caching the target listing", func() {
+		var (
+			mockGitClient          *MockClient
+			mockRemoteClientOrigin *MockRemoteClient
+			mockRemoteClientTarget *MockRemoteClient
+			pattern                api.Pattern
+			remote                 repositoryPair
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteClientOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteClientTarget = NewMockRemoteClient(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemoteClientTarget)
+			remote = repositoryPair{
+				name:      foo,
+				namespace: defaultNamespace,
+				gitClient: mockGitClient,
+				kClient:   k8sClient,
+			}
+		})
+
+		createPattern := func(stalenessSeconds int) {
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						OriginRepo:             originURL,
+						TargetRepo:             targetURL,
+						CacheTargetListing:     true,
+						TargetStalenessSeconds: stalenessSeconds}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		}
+
+		AfterEach(func() {
+			e := k8sClient.Delete(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("reuses the cached target listing while the origin is stable", func() {
+			createPattern(3600)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(3).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+
+			for check := 0; check < 3; check++ {
+				hasDrifted, e := remote.hasDrifted(context.Background())
+				Expect(e).NotTo(HaveOccurred())
+				Expect(hasDrifted).To(BeFalse())
+			}
+		})
+
+		It("lists the target again once the cached listing exceeds the staleness bound", func() {
+			createPattern(1)
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(2).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(2).Return(firstCommitReference, nil)
+
+			_, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			time.Sleep(1100 * time.Millisecond)
+			_, e = remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("lists the target again as soon as the origin moves", func() {
+			createPattern(3600)
+			gomock.InOrder(
+				mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(2).Return(firstCommitReference, nil),
+				mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil))
+			gomock.InOrder(
+				mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil),
+				mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil))
+
+			// first check lists both sides and populates the cache, the second reuses it
+			for check := 0; check < 2; check++ {
+				hasDrifted, e := remote.hasDrifted(context.Background())
+				Expect(e).NotTo(HaveOccurred())
+				Expect(hasDrifted).To(BeFalse())
+			}
+			// the origin has moved: the target must be listed again instead of comparing
+			// against the stale cache
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeFalse())
+		})
+	})
+
+	var _ = Context("when listing private remotes with credentials from a secret", func() {
+		const credentialsSecret = "git-credentials"
+
+		// generateSSHKey returns a PEM-encoded private key and a known_hosts entry for its
+		// public half
+		generateSSHKey := func() ([]byte, []byte) {
+			key, e := rsa.GenerateKey(cryptorand.Reader, 2048)
+			Expect(e).NotTo(HaveOccurred())
+			pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+			public, e := gossh.NewPublicKey(&key.PublicKey)
+			Expect(e).NotTo(HaveOccurred())
+			return pemBytes, []byte("github.com " + string(gossh.MarshalAuthorizedKey(public)))
+		}
+
+		createSecret := func(data map[string][]byte) *v1core.Secret {
+			secret := &v1core.Secret{
+				ObjectMeta: v1.ObjectMeta{Name: credentialsSecret, Namespace: defaultNamespace},
+				Data:       data}
+			e := k8sClient.Create(context.Background(), secret)
+			Expect(e).NotTo(HaveOccurred())
+			return secret
+		}
+
+		It("builds the public-key auth method from the referenced secret", func() {
+			pemBytes, hosts := generateSSHKey()
+			secret := createSecret(map[string][]byte{
+				sshPrivateKeySecretKey: pemBytes,
+				knownHostsSecretKey:    hosts})
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), secret)).NotTo(HaveOccurred())
+			}()
+
+			resolver := newCredentialsResolver(k8sClient)
+			auth, e := resolver.Resolve(defaultNamespace, credentialsSecret)
+			Expect(e).NotTo(HaveOccurred())
+			keys, ok := auth.(*gitssh.PublicKeys)
+			Expect(ok).To(BeTrue())
+			Expect(keys.User).To(Equal(defaultSSHUser))
+			Expect(keys.HostKeyCallback).NotTo(BeNil())
+		})
+
+		It("honors the remote user declared in the secret", func() {
+			pemBytes, _ := generateSSHKey()
+			secret := createSecret(map[string][]byte{
+				sshPrivateKeySecretKey: pemBytes,
+				sshUserSecretKey:       []byte("deploy")})
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), secret)).NotTo(HaveOccurred())
+			}()
+
+			auth, e := newCredentialsResolver(k8sClient).Resolve(defaultNamespace, credentialsSecret)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(auth.(*gitssh.PublicKeys).User).To(Equal("deploy"))
+		})
+
+		It("rejects a secret without a private key or token", func() {
+			secret := createSecret(map[string][]byte{"unrelated": []byte("data")})
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), secret)).NotTo(HaveOccurred())
+			}()
+
+			_, e := newCredentialsResolver(k8sClient).Resolve(defaultNamespace, credentialsSecret)
+			Expect(e).To(HaveOccurred())
+			Expect(e.Error()).To(ContainSubstring(sshPrivateKeySecretKey))
+			Expect(e.Error()).To(ContainSubstring(tokenSecretKey))
+		})
+
+		It("builds a basic auth method from a username and token", func() {
+			secret := createSecret(map[string][]byte{
+				usernameSecretKey: []byte("bob"),
+				tokenSecretKey:    []byte("s3cret"),
+			})
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), secret)).NotTo(HaveOccurred())
+			}()
+
+			auth, e := newCredentialsResolver(k8sClient).Resolve(defaultNamespace, credentialsSecret)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(auth).To(Equal(&githttp.BasicAuth{Username: "bob", Password: "s3cret"}))
+		})
+
+		It("defaults the username when the secret only holds a token", func() {
+			secret := createSecret(map[string][]byte{tokenSecretKey: []byte("s3cret")})
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), secret)).NotTo(HaveOccurred())
+			}()
+
+			auth, e := newCredentialsResolver(k8sClient).Resolve(defaultNamespace, credentialsSecret)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(auth).To(Equal(&githttp.BasicAuth{Username: defaultSSHUser, Password: "s3cret"}))
+		})
+
+		It("passes the resolved auth method to both remote listings", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient := NewMockClient(ctrl)
+			mockRemoteOrigin := NewMockRemoteClient(ctrl)
+			mockRemoteTarget := NewMockRemoteClient(ctrl)
+			mockResolver := NewMockCredentialsResolver(ctrl)
+			pattern := api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						OriginRepo:        originURL,
+						TargetRepo:        targetURL,
+						CredentialsSecret: credentialsSecret}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), &pattern)).NotTo(HaveOccurred())
+			}()
+
+			auth := &gitssh.PublicKeys{User: defaultSSHUser}
+			mockResolver.EXPECT().Resolve(defaultNamespace, credentialsSecret).Times(1).Return(auth, nil)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).Times(1).Return(mockRemoteOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).Times(1).Return(mockRemoteTarget)
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{Auth: auth}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{Auth: auth}).Times(1).Return(firstCommitReference, nil)
+
+			remote := repositoryPair{
+				name:                foo,
+				namespace:           defaultNamespace,
+				gitClient:           mockGitClient,
+				kClient:             k8sClient,
+				credentialsResolver: mockResolver,
+			}
+			hasDrifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(hasDrifted).To(BeFalse())
+		})
+	})
+
+	var _ = Context("when maintaining a companion git status resource", func() {
+		var (
+			patternFoo       *api.Pattern
+			mockGitClient    *MockClient
+			mockRemoteOrigin *MockRemoteClient
+			mockRemoteTarget *MockRemoteClient
+			watch            *watcher
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteTarget = NewMockRemoteClient(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemoteOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemoteTarget)
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			e := k8sClient.Create(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+			watch = newWatcher(mockGitClient)
+			watch.companionStatus = true
+			watch.watch()
+		})
+
+		AfterEach(func() {
+			if watch.isWatching(foo, defaultNamespace) {
+				e := watch.remove(foo, defaultNamespace)
+				Expect(e).NotTo(HaveOccurred())
+			}
+			e := k8sClient.Delete(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("creates, updates and cleans up the companion resource with the pair state", func() {
+			var synced int32
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(context.Context, *git.ListOptions) ([]*plumbing.Reference, error) {
+				if atomic.LoadInt32(&synced) == 1 {
+					return firstCommitReference, nil
+				}
+				return firstCommitAmendedReference, nil
+			})
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			By("creating the companion resource after the first check")
+			var companion api.PatternGitStatus
+			Eventually(func() bool {
+				if e := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &companion); e != nil {
+					return false
+				}
+				return companion.Status.CheckCount > 0
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(),
+				"expected the companion resource to be created by the first check")
+			Expect(companion.Spec.PatternName).To(Equal(foo))
+			Expect(companion.Status.Drifted).To(BeTrue())
+			Expect(companion.Status.TargetPrimaryRef).To(Equal(mainReference.String()))
+			Expect(companion.Status.LastCheckFailure).To(BeNil())
+
+			By("updating it once the repositories converge")
+			atomic.StoreInt32(&synced, 1)
+			Eventually(func() bool {
+				if e := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &companion); e != nil {
+					return false
+				}
+				return !companion.Status.Drifted
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(),
+				"expected the companion resource to converge to the in-sync state")
+
+			By("cleaning it up once the pair is removed")
+			err = watch.remove(foo, defaultNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &api.PatternGitStatus{})
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("records the failure attribution of an errored check", func() {
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, transport.ErrAuthenticationRequired)
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			var companion api.PatternGitStatus
+			Eventually(func() bool {
+				if e := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &companion); e != nil {
+					return false
+				}
+				return companion.Status.LastCheckFailure != nil
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(),
+				"expected the companion resource to record the failed check")
+			Expect(companion.Status.LastCheckFailure.Side).To(Equal("origin"))
+			Expect(companion.Status.LastCheckFailure.Class).To(Equal(failureClassAuthentication))
+			Expect(companion.Status.ConsecutiveFailures).To(BeNumerically(">", 0))
+		})
+	})
+
+	var _ = Context("when recording drift status details", func() {
+		var (
+			patternFoo       *api.Pattern
+			mockGitClient    *MockClient
+			mockRemoteOrigin *MockRemoteClient
+			mockRemoteTarget *MockRemoteClient
+			watch            *watcher
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteTarget = NewMockRemoteClient(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemoteOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemoteTarget)
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			e := k8sClient.Create(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+			watch = newWatcher(mockGitClient)
+			watch.watch()
+		})
+
+		AfterEach(func() {
+			if watch.isWatching(foo, defaultNamespace) {
+				e := watch.remove(foo, defaultNamespace)
+				Expect(e).NotTo(HaveOccurred())
+			}
+			e := k8sClient.Delete(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("publishes the resolved commits and divergence of a drifted pair", func() {
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitAmendedReference, nil)
+			mockDivergence := NewMockDivergenceResolver(gomock.NewController(GinkgoT()))
+			mockDivergence.EXPECT().Divergence(originURL, mainReference, plumbing.NewHash(hashCommitMainHead), plumbing.NewHash(hashCommitAmendedHead)).AnyTimes().Return(2, 1, nil)
+			watch.divergenceResolver = mockDivergence
+
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			var p api.Pattern
+			Eventually(func() bool {
+				if e := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p); e != nil {
+					return false
+				}
+				return p.Status.DriftStatus != nil
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(),
+				"expected the drift status to be recorded by the first check")
+			Expect(p.Status.DriftStatus.OriginSHA).To(Equal(hashCommitMainHead))
+			Expect(p.Status.DriftStatus.TargetSHA).To(Equal(hashCommitAmendedHead))
+			Expect(p.Status.DriftStatus.CommitsBehind).To(Equal(2))
+			Expect(p.Status.DriftStatus.CommitsAhead).To(Equal(1))
+			Expect(p.Status.DriftStatus.LastError).To(BeEmpty())
+			Expect(p.Status.DriftStatus.LastCheck.IsZero()).To(BeFalse())
+			Expect(p.Status.DriftRetryState).To(BeNil())
+		})
+
+		It("publishes the merge base when the pattern asks for the full distance", func() {
+			patternFoo.Spec.GitConfig.ComputeMergeBase = true
+			e := k8sClient.Update(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitAmendedReference, nil)
+			mockDistance := NewMockDistanceResolver(gomock.NewController(GinkgoT()))
+			mockDistance.EXPECT().Distance(originURL, mainReference, targetURL, mainReference,
+				plumbing.NewHash(hashCommitMainHead), plumbing.NewHash(hashCommitAmendedHead)).
+				AnyTimes().Return(3, 2, plumbing.NewHash(hashCommitTestBranch), nil)
+			watch.distanceResolver = mockDistance
+
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			var p api.Pattern
+			Eventually(func() bool {
+				if e := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p); e != nil {
+					return false
+				}
+				return p.Status.DriftStatus != nil
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(),
+				"expected the drift status to be recorded by the first check")
+			Expect(p.Status.DriftStatus.CommitsBehind).To(Equal(3))
+			Expect(p.Status.DriftStatus.CommitsAhead).To(Equal(2))
+			Expect(p.Status.DriftStatus.MergeBase).To(Equal(hashCommitTestBranch))
+			Expect(p.Status.DriftStatus.LastError).To(BeEmpty())
+		})
+
+		It("records the error of a failed check without stale commits", func() {
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, transport.ErrAuthenticationRequired)
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			var p api.Pattern
+			Eventually(func() bool {
+				if e := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p); e != nil {
+					return false
+				}
+				return p.Status.DriftStatus != nil
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(),
+				"expected the drift status to be recorded by the failed check")
+			Expect(p.Status.DriftStatus.LastError).To(ContainSubstring(transport.ErrAuthenticationRequired.Error()))
+			Expect(p.Status.DriftStatus.OriginSHA).To(BeEmpty())
+			Expect(p.Status.DriftStatus.TargetSHA).To(BeEmpty())
+			Expect(p.Status.DriftRetryState).NotTo(BeNil())
+			Expect(p.Status.DriftRetryState.ConsecutiveFailures).To(BeNumerically(">", 0))
+			Expect(p.Status.DriftRetryState.NextCheck.Time.After(p.Status.DriftStatus.LastCheck.Time)).To(BeTrue())
+		})
+	})
+
+	var _ = Context("when coalescing update notifications", func() {
+
+		It("absorbs a burst of notifications into a single timer recompute per window", func() {
+			watch := newWatcher(NewMockClient(gomock.NewController(GinkgoT())))
+			watch.coalesceWindow = 300 * time.Millisecond
+			watch.watch()
+
+			// the initial notification sent by watch() opens the first window
+			Eventually(func() int64 {
+				return atomic.LoadInt64(&watch.timerRecomputes)
+			}).WithPolling(50*time.Millisecond).WithTimeout(5*time.Second).Should(Equal(int64(1)),
+				"expected the initial notification to recompute the timer once")
+
+			for notification := 0; notification < 5; notification++ {
+				watch.updateCh <- struct{}{}
+			}
+			Eventually(func() int64 {
+				return atomic.LoadInt64(&watch.timerRecomputes)
+			}).WithPolling(50*time.Millisecond).WithTimeout(5*time.Second).Should(Equal(int64(2)),
+				"expected the burst to coalesce into a single recompute")
+			Consistently(func() int64 {
+				return atomic.LoadInt64(&watch.timerRecomputes)
+			}).WithPolling(100*time.Millisecond).WithTimeout(time.Second).Should(Equal(int64(2)),
+				"expected no further recomputes after the window closed")
+			close(watch.endCh)
+		})
+
+		It("jitters the window within the configured bounds", func() {
+			watch := newWatcher(NewMockClient(gomock.NewController(GinkgoT())))
+			watch.coalesceWindow = 100 * time.Millisecond
+			watch.coalesceJitter = 200 * time.Millisecond
+
+			delays := map[time.Duration]bool{}
+			for sample := 0; sample < 100; sample++ {
+				delay := watch.coalesceDelay()
+				Expect(delay).To(BeNumerically(">=", watch.coalesceWindow))
+				Expect(delay).To(BeNumerically("<", watch.coalesceWindow+watch.coalesceJitter))
+				delays[delay] = true
+			}
+			Expect(len(delays)).To(BeNumerically(">", 1), "expected the delays to actually be jittered")
+		})
+
+		It("recomputes immediately when no window is configured", func() {
+			watch := newWatcher(NewMockClient(gomock.NewController(GinkgoT())))
+			Expect(watch.coalesceDelay()).To(BeZero())
+		})
+	})
+
+	var _ = Context("when running checks through the worker pool", func() {
+		var (
+			mockGitClient          *MockClient
+			mockRemoteFoo          *MockRemoteClient
+			mockRemoteBar          *MockRemoteClient
+			patternFoo, patternBar *api.Pattern
+			watch                  *watcher
+		)
+
+		const (
+			barOriginURL = "https://github.com/hybrid-cloud-patterns/bar-origin"
+			barTargetURL = "https://github.com/hybrid-cloud-patterns/bar-target"
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteFoo = NewMockRemoteClient(ctrl)
+			mockRemoteBar = NewMockRemoteClient(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemoteFoo)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemoteFoo)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{barOriginURL}}).AnyTimes().Return(mockRemoteBar)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{barTargetURL}}).AnyTimes().Return(mockRemoteBar)
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			patternBar = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: bar, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: barOriginURL, TargetRepo: barTargetURL}}}
+			e := k8sClient.Create(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+			e = k8sClient.Create(context.Background(), patternBar)
+			Expect(e).NotTo(HaveOccurred())
+			watch = newWatcher(mockGitClient)
+		})
+
+		AfterEach(func() {
+			e := k8sClient.Delete(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+			e = k8sClient.Delete(context.Background(), patternBar)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("keeps checking other pairs while one remote hangs", func() {
+			release := make(chan interface{})
+			mockRemoteFoo.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(context.Context, *git.ListOptions) ([]*plumbing.Reference, error) {
+				<-release
+				return firstCommitReference, nil
+			})
+			mockRemoteBar.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			watch.watch()
+
+			checkCount := func(name string) int64 {
+				for _, pair := range watch.snapshotPairs() {
+					if pair.name == name {
+						return pair.checkCount
+					}
+				}
+				return 0
+			}
+
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = watch.add(bar, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int64 {
+				return checkCount(bar)
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeNumerically(">", 1),
+				"expected checks of bar to proceed while the remote of foo hangs")
+			Expect(checkCount(foo)).To(BeZero(), "expected the hung check of foo not to complete")
+
+			close(release)
+			Eventually(func() int64 {
+				return checkCount(foo)
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeNumerically(">", 0),
+				"expected the check of foo to complete once its remote answers")
+			close(watch.endCh)
+		})
+
+		It("cancels a check that outlives the configured timeout", func() {
+			mockRemoteFoo.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, _ *git.ListOptions) ([]*plumbing.Reference, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+			watch.checkTimeout = 100 * time.Millisecond
+			watch.watch()
+
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int64 {
+				for _, pair := range watch.snapshotPairs() {
+					if pair.name == foo {
+						return pair.checkCount
+					}
+				}
+				return 0
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeNumerically(">", 0),
+				"expected the hung check to be cancelled and counted instead of blocking the worker")
+			close(watch.endCh)
+		})
+
+		It("bounds the concurrency to the configured number of workers", func() {
+			var active, peak int32
+			slowList := func(*git.ListOptions) ([]*plumbing.Reference, error) {
+				current := atomic.AddInt32(&active, 1)
+				for {
+					observed := atomic.LoadInt32(&peak)
+					if current <= observed || atomic.CompareAndSwapInt32(&peak, observed, current) {
+						break
+					}
+				}
+				time.Sleep(100 * time.Millisecond)
+				atomic.AddInt32(&active, -1)
+				return firstCommitReference, nil
+			}
+			mockRemoteFoo.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(slowList)
+			mockRemoteBar.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(slowList)
+			watch.checkConcurrency = 1
+			watch.watch()
+
+			err := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = watch.add(bar, defaultNamespace, 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int64 {
+				for _, pair := range watch.snapshotPairs() {
+					if pair.name == foo {
+						return pair.checkCount
+					}
+				}
+				return 0
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeNumerically(">", 1),
+				"expected the single worker to keep completing checks of both pairs")
+			Expect(atomic.LoadInt32(&peak)).To(Equal(int32(1)), "expected no two checks to overlap")
+			close(watch.endCh)
+		})
+	})
+
+	var _ = Context("when rate limiting checks per git host", func() {
+
+		var now = time.Date(2022, time.November, 1, 10, 0, 0, 0, time.UTC)
+
+		It("admits checks within the per-minute budget and delays the excess", func() {
+			limiter := newHostRateLimiter(2)
+			for i := 0; i < 2; i++ {
+				admitted, _ := limiter.admit(foo, now)
+				Expect(admitted).To(BeTrue())
+			}
+			admitted, wait := limiter.admit(foo, now)
+			Expect(admitted).To(BeFalse())
+			Expect(wait).To(Equal(30 * time.Second))
+		})
+
+		It("accrues new tokens as time passes", func() {
+			limiter := newHostRateLimiter(2)
+			for i := 0; i < 2; i++ {
+				limiter.admit(foo, now)
+			}
+			admitted, _ := limiter.admit(foo, now.Add(30*time.Second))
+			Expect(admitted).To(BeTrue())
+			admitted, _ = limiter.admit(foo, now.Add(30*time.Second))
+			Expect(admitted).To(BeFalse())
+		})
+
+		It("tracks the budget of every host separately", func() {
+			limiter := newHostRateLimiter(1)
+			admitted, _ := limiter.admit(foo, now)
+			Expect(admitted).To(BeTrue())
+			admitted, _ = limiter.admit(bar, now)
+			Expect(admitted).To(BeTrue())
+			admitted, _ = limiter.admit(foo, now)
+			Expect(admitted).To(BeFalse())
+		})
+
+		It("never limits a pair that has not yet learned its host", func() {
+			limiter := newHostRateLimiter(1)
+			for i := 0; i < 5; i++ {
+				admitted, _ := limiter.admit("", now)
+				Expect(admitted).To(BeTrue())
+			}
+		})
+	})
+
+	var _ = Context("when monitoring several repository pairs for one pattern", func() {
+		var (
+			mockGitClient          *MockClient
+			mockRemotePrimary      *MockRemoteClient
+			mockRemoteValuesOrigin *MockRemoteClient
+			mockRemoteValuesTarget *MockRemoteClient
+			patternFoo             *api.Pattern
+			watch                  *watcher
+		)
+
+		const (
+			valuesOriginURL = "https://github.com/hybrid-cloud-patterns/values-origin"
+			valuesTargetURL = "https://github.com/hybrid-cloud-patterns/values-target"
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemotePrimary = NewMockRemoteClient(ctrl)
+			mockRemoteValuesOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteValuesTarget = NewMockRemoteClient(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemotePrimary)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemotePrimary)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{valuesOriginURL}}).AnyTimes().Return(mockRemoteValuesOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{valuesTargetURL}}).AnyTimes().Return(mockRemoteValuesTarget)
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL,
+					AdditionalPairs: []api.GitRepoPair{{Name: "site-values", OriginRepo: valuesOriginURL, TargetRepo: valuesTargetURL}}}}}
+			e := k8sClient.Create(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+			watch = newWatcher(mockGitClient)
+			watch.watch()
+		})
+
+		AfterEach(func() {
+			if watch.isWatching(foo, defaultNamespace) {
+				e := watch.remove(foo, defaultNamespace)
+				Expect(e).NotTo(HaveOccurred())
+			}
+			e := k8sClient.Delete(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("tracks every declared pair and aggregates their outcomes", func() {
+			mockRemotePrimary.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteValuesOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteValuesTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitAmendedReference, nil)
+
+			e := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(e).NotTo(HaveOccurred())
+			Expect(watch.snapshotPairs()).To(HaveLen(2))
+
+			var p api.Pattern
+			Eventually(func() bool {
+				if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p); err != nil {
+					return false
+				}
+				_, condition := getPatternConditionByType(p.Status.Conditions, api.GitOutOfSync)
+				return condition != nil && condition.Status == v1core.ConditionTrue && len(p.Status.PairDriftStatuses) == 1
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(),
+				"expected the drifted additional pair to flip the aggregated condition and publish its status")
+			Expect(p.Status.PairDriftStatuses[0].Name).To(Equal("site-values"))
+			Expect(p.Status.PairDriftStatuses[0].Drifted).To(BeTrue())
+			Expect(p.Status.PairDriftStatuses[0].LastError).To(BeEmpty())
+
+			e = watch.remove(foo, defaultNamespace)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(watch.isWatching(foo, defaultNamespace)).To(BeFalse())
+			Expect(watch.snapshotPairs()).To(BeEmpty())
+		})
+
+		It("drops pairs that disappear from the configuration", func() {
+			mockRemotePrimary.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteValuesOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteValuesTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+
+			e := watch.add(foo, defaultNamespace, 3600, false, "")
+			Expect(e).NotTo(HaveOccurred())
+			Expect(watch.snapshotPairs()).To(HaveLen(2))
+
+			var p api.Pattern
+			e = k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(e).NotTo(HaveOccurred())
+			p.Spec.GitConfig.AdditionalPairs = nil
+			e = k8sClient.Update(context.Background(), &p)
+			Expect(e).NotTo(HaveOccurred())
+
+			e = watch.updateInterval(foo, defaultNamespace, 3600, false)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(watch.snapshotPairs()).To(HaveLen(1))
+			Expect(watch.snapshotPairs()[0].pairName).To(BeEmpty())
+		})
+	})
+
+	var _ = Context("when monitoring a standalone GitDriftCheck", func() {
+		var (
+			mockGitClient          *MockClient
+			mockRemoteClientOrigin *MockRemoteClient
+			mockRemoteClientTarget *MockRemoteClient
+			check                  *api.GitDriftCheck
+			watch                  *watcher
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteClientOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteClientTarget = NewMockRemoteClient(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemoteClientTarget)
+			check = &api.GitDriftCheck{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "GitDriftCheck", APIVersion: api.GroupVersion.String()},
+				Spec:       api.GitDriftCheckSpec{OriginRepo: originURL, TargetRepo: targetURL}}
+			e := k8sClient.Create(context.Background(), check)
+			Expect(e).NotTo(HaveOccurred())
+			watch = newWatcher(mockGitClient)
+			watch.watch()
+		})
+
+		AfterEach(func() {
+			if watch.isWatchingCheck(foo, defaultNamespace) {
+				e := watch.removeCheck(foo, defaultNamespace)
+				Expect(e).NotTo(HaveOccurred())
+			}
+			e := k8sClient.Delete(context.Background(), check)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("tracks the declared pair and reports the outcome on the resource status", func() {
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitAmendedReference, nil)
+
+			e := watch.addCheck(foo, defaultNamespace, 1, false)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(watch.isWatchingCheck(foo, defaultNamespace)).To(BeTrue())
+			Expect(watch.isWatching(foo, defaultNamespace)).To(BeFalse())
+
+			var c api.GitDriftCheck
+			Eventually(func() bool {
+				if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &c); err != nil {
+					return false
+				}
+				return c.Status.CheckCount > 0
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(),
+				"expected the check outcome to be published on the GitDriftCheck status")
+			Expect(c.Status.Drifted).To(BeTrue())
+			Expect(c.Status.LastError).To(BeEmpty())
+			Expect(c.Status.LastCheck.IsZero()).To(BeFalse())
+
+			e = watch.removeCheck(foo, defaultNamespace)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(watch.snapshotPairs()).To(BeEmpty())
+		})
+
+		It("keeps standalone checks apart from the pairs of a pattern with the same name", func() {
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+
+			e := watch.add(foo, defaultNamespace, 3600, false, "")
+			Expect(e).NotTo(HaveOccurred())
+			e = watch.addCheck(foo, defaultNamespace, 3600, false)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(watch.snapshotPairs()).To(HaveLen(2))
+
+			e = watch.remove(foo, defaultNamespace)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(watch.isWatchingCheck(foo, defaultNamespace)).To(BeTrue())
+			Expect(watch.snapshotPairs()).To(HaveLen(1))
+
+			e = watch.removeCheck(foo, defaultNamespace)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(watch.snapshotPairs()).To(BeEmpty())
+		})
+	})
+
+	var _ = Context("when attributing a failed check to a remote", func() {
+		var (
+			mockGitClient          *MockClient
+			mockRemoteClientOrigin *MockRemoteClient
+			mockRemoteClientTarget *MockRemoteClient
+			pattern                api.Pattern
+			remote                 repositoryPair
+			watch                  *watcher
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteClientOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteClientTarget = NewMockRemoteClient(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemoteClientTarget)
+			remote = repositoryPair{
+				name:      foo,
+				namespace: defaultNamespace,
+				gitClient: mockGitClient,
+				kClient:   k8sClient,
+			}
+			watch = newWatcher(mockGitClient)
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						Hostname:   foo,
+						OriginRepo: originURL,
+						TargetRepo: targetURL}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			e := k8sClient.Delete(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("attributes an origin-side failure with its host and error class", func() {
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(nil, transport.ErrAuthenticationRequired)
+
+			_, e := remote.hasDrifted(context.Background())
+			Expect(e).To(HaveOccurred())
+			failure := attributeCheckError(e)
+			Expect(failure).NotTo(BeNil())
+			Expect(failure.Side).To(Equal("origin"))
+			Expect(failure.Host).To(Equal(foo))
+			Expect(failure.Class).To(Equal(failureClassAuthentication))
+
+			e = watch.persistCondition(foo, defaultNamespace, api.GitCheckFailed, nil, time.Now(), failure)
+			Expect(e).NotTo(HaveOccurred())
+			var p api.Pattern
+			e = k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(p.Status.LastCheckFailure).NotTo(BeNil())
+			Expect(p.Status.LastCheckFailure.Side).To(Equal("origin"))
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitCheckFailed)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("drift check failed on origin (host foo): authentication"))
+		})
+
+		It("attributes a target-side failure distinctly from an origin-side one", func() {
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(nil, transport.ErrRepositoryNotFound)
+
+			_, e := remote.hasDrifted(context.Background())
+			Expect(e).To(HaveOccurred())
+			failure := attributeCheckError(e)
+			Expect(failure).NotTo(BeNil())
+			Expect(failure.Side).To(Equal("target"))
+			Expect(failure.Host).To(Equal(foo))
+			Expect(failure.Class).To(Equal(failureClassNotFound))
+
+			e = watch.persistCondition(foo, defaultNamespace, api.GitCheckFailed, nil, time.Now(), failure)
+			Expect(e).NotTo(HaveOccurred())
+			var p api.Pattern
+			e = k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(p.Status.LastCheckFailure).NotTo(BeNil())
+			Expect(p.Status.LastCheckFailure.Side).To(Equal("target"))
+			Expect(p.Status.LastCheckFailure.Class).To(Equal(failureClassNotFound))
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitCheckFailed)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Message).To(ContainSubstring("drift check failed on target (host foo): repository-not-found"))
+		})
+
+		It("clears the attribution once a check completes again", func() {
+			e := watch.persistCondition(foo, defaultNamespace, api.GitCheckFailed, nil, time.Now(),
+				&api.CheckFailure{Side: "origin", Host: foo, Class: failureClassNetwork, Message: "connection refused"})
+			Expect(e).NotTo(HaveOccurred())
+			e = watch.persistCondition(foo, defaultNamespace, api.GitInSync, nil, time.Now(), nil)
+			Expect(e).NotTo(HaveOccurred())
+
+			var p api.Pattern
+			e = k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(p.Status.LastCheckFailure).To(BeNil())
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitCheckFailed)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionFalse))
+			_, condition = getPatternConditionByType(p.Status.Conditions, api.GitInSync)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+		})
+	})
+
+	var _ = Context("when auto-syncing a drifted target", func() {
+		var (
+			mockGitClient          *MockClient
+			mockRemoteClientOrigin *MockRemoteClient
+			mockRemoteClientTarget *MockRemoteClient
+			mockCredentials        *MockCredentialsResolver
+			mockRemediator         *MockDriftRemediator
+			pattern                api.Pattern
+			remote                 repositoryPair
+		)
+
+		const writeSecret = "write-credentials"
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteClientOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteClientTarget = NewMockRemoteClient(ctrl)
+			mockCredentials = NewMockCredentialsResolver(ctrl)
+			mockRemediator = NewMockDriftRemediator(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemoteClientTarget)
+			remote = repositoryPair{
+				name:                foo,
+				namespace:           defaultNamespace,
+				gitClient:           mockGitClient,
+				credentialsResolver: mockCredentials,
+				remediator:          mockRemediator,
+				kClient:             k8sClient,
+			}
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						OriginRepo:            originURL,
+						TargetRepo:            targetURL,
+						AutoSync:              true,
+						SyncCredentialsSecret: writeSecret}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			e := k8sClient.Delete(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("pushes a fast-forward of the origin commit to the target branch", func() {
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil)
+			mockCredentials.EXPECT().Resolve(defaultNamespace, writeSecret).Times(1).Return(nil, nil)
+			mockRemediator.EXPECT().FastForward(originURL, mainReference, targetURL, mainReference,
+				plumbing.NewHash(hashCommitMainHead), plumbing.NewHash(hashCommitAmendedHead), nil).Times(1).Return(nil)
+
+			drifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(drifted).To(BeTrue())
+		})
+
+		It("records a GitAutoSyncFailed condition when the update is refused", func() {
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil)
+			mockCredentials.EXPECT().Resolve(defaultNamespace, writeSecret).Times(1).Return(nil, nil)
+			mockRemediator.EXPECT().FastForward(originURL, mainReference, targetURL, mainReference,
+				plumbing.NewHash(hashCommitMainHead), plumbing.NewHash(hashCommitAmendedHead), nil).Times(1).
+				Return(fmt.Errorf("refusing non-fast-forward update"))
+
+			drifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(drifted).To(BeTrue())
+
+			var p api.Pattern
+			e = k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(e).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitAutoSyncFailed)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("refusing non-fast-forward update"))
+		})
+
+		It("never pushes when the repositories have not drifted", func() {
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+
+			drifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(drifted).To(BeFalse())
+		})
+	})
+
+	var _ = Context("when consulting the forge hosting the repositories", func() {
+		var (
+			mockGitClient          *MockClient
+			mockRemoteClientOrigin *MockRemoteClient
+			mockRemoteClientTarget *MockRemoteClient
+			mockCredentials        *MockCredentialsResolver
+			mockRemediator         *MockDriftRemediator
+			mockForgeFactory       *MockForgeClientFactory
+			mockForge              *MockForgeClient
+			pattern                api.Pattern
+			remote                 repositoryPair
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteClientOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteClientTarget = NewMockRemoteClient(ctrl)
+			mockCredentials = NewMockCredentialsResolver(ctrl)
+			mockRemediator = NewMockDriftRemediator(ctrl)
+			mockForgeFactory = NewMockForgeClientFactory(ctrl)
+			mockForge = NewMockForgeClient(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemoteClientOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemoteClientTarget)
+			mockForgeFactory.EXPECT().NewForgeClient(gomock.Any(), api.ForgeGitHub, gomock.Any()).AnyTimes().Return(mockForge, nil)
+			remote = repositoryPair{
+				name:                foo,
+				namespace:           defaultNamespace,
+				gitClient:           mockGitClient,
+				credentialsResolver: mockCredentials,
+				remediator:          mockRemediator,
+				forgeFactory:        mockForgeFactory,
+				kClient:             k8sClient,
+			}
+			pattern = api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						OriginRepo: originURL,
+						TargetRepo: targetURL,
+						Forge:      api.ForgeGitHub}},
+			}
+			e := k8sClient.Create(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			e := k8sClient.Delete(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("counts the divergence through the compare API and posts a drift status check", func() {
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil)
+			mockForge.EXPECT().CompareBranches(hashCommitAmendedHead, hashCommitMainHead).Times(1).Return(1, 3, nil)
+			mockForge.EXPECT().PostStatusCheck(hashCommitAmendedHead, "failure", "patterns-operator/drift", gomock.Any()).Times(1).Return(nil)
+
+			drifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(drifted).To(BeTrue())
+			Expect(remote.commitsBehind).To(Equal(3))
+			Expect(remote.commitsAhead).To(Equal(1))
+		})
+
+		It("opens a sync pull request when the fast-forward is refused", func() {
+			pattern.Spec.GitConfig.AutoSync = true
+			pattern.Spec.GitConfig.SyncCredentialsSecret = "write-credentials"
+			e := k8sClient.Update(context.Background(), &pattern)
+			Expect(e).NotTo(HaveOccurred())
+			mockRemoteClientOrigin.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitReference, nil)
+			mockRemoteClientTarget.EXPECT().ListContext(gomock.Any(), &git.ListOptions{}).Times(1).Return(firstCommitAmendedReference, nil)
+			mockForge.EXPECT().CompareBranches(gomock.Any(), gomock.Any()).AnyTimes().Return(0, 0, nil)
+			mockForge.EXPECT().PostStatusCheck(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+			mockCredentials.EXPECT().Resolve(defaultNamespace, "write-credentials").Times(1).Return(nil, nil)
+			mockRemediator.EXPECT().FastForward(originURL, mainReference, targetURL, mainReference,
+				plumbing.NewHash(hashCommitMainHead), plumbing.NewHash(hashCommitAmendedHead), nil).Times(1).
+				Return(fmt.Errorf("%w: the target holds its own commits", errNonFastForward))
+			mockForge.EXPECT().OpenSyncPullRequest("main", "main", gomock.Any(), gomock.Any()).Times(1).
+				Return("https://github.com/some/pull/1", nil)
+
+			drifted, e := remote.hasDrifted(context.Background())
+			Expect(e).NotTo(HaveOccurred())
+			Expect(drifted).To(BeTrue())
+
+			var p api.Pattern
+			e = k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
+			Expect(e).NotTo(HaveOccurred())
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitAutoSyncFailed)
+			Expect(condition).To(BeNil())
+		})
 	})
-	var _ = Context("when evaluating the processing order", func() {
+
+	var _ = Context("when running in read-only mode", func() {
 		var (
-			mockGitClient          *MockClient
-			mockRemote             *MockRemoteClient
-			patternBar, patternFoo *api.Pattern
-			ctrl                   *gomock.Controller
+			patternFoo    *api.Pattern
+			mockGitClient *MockClient
+			mockRemote    *MockRemoteClient
 		)
 
 		BeforeEach(func() {
-			ctrl = gomock.NewController(GinkgoT())
+			ctrl := gomock.NewController(GinkgoT())
 			mockGitClient = NewMockClient(ctrl)
 			mockRemote = NewMockRemoteClient(ctrl)
-
 			patternFoo = &api.Pattern{
 				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
 				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
 				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
-			patternBar = &api.Pattern{
-				ObjectMeta: v1.ObjectMeta{Name: bar, Namespace: defaultNamespace},
-				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
-				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
 			e := k8sClient.Create(context.Background(), patternFoo)
 			Expect(e).NotTo(HaveOccurred())
-			e = k8sClient.Create(context.Background(), patternBar)
-			Expect(e).NotTo(HaveOccurred())
 		})
 
 		AfterEach(func() {
 			err := k8sClient.Delete(context.TODO(), patternFoo)
 			Expect(err).NotTo(HaveOccurred())
-			err = k8sClient.Delete(context.TODO(), patternBar)
-			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("processes two pairs of git repositories in order of shortest interval", func() {
+		It("runs checks without issuing a single write to the cluster", func() {
 			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
-			mockRemote.EXPECT().List(gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+			mockRemote.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
 
+			counting := &writeCountingClient{Client: k8sClient}
 			watch := newWatcher(mockGitClient)
+			watch.kClient = counting
+			watch.readOnly = true
+			watch.leaseName = "drift-watcher"
+			watch.leaseNamespace = defaultNamespace
 			watch.watch()
-
-			// Add both reference pairs and wait for the drift evaluation to kick in and add the first condition
-			err := watch.add(foo, defaultNamespace, 5)
+			err := watch.add(foo, defaultNamespace, 1, false, "")
 			Expect(err).NotTo(HaveOccurred())
-			err = watch.add(bar, defaultNamespace, 1)
-			Expect(err).NotTo(HaveOccurred())
-			// check the order of processing pairs
-			Expect(watch.repoPairs[0].name).To(Equal(bar))
-			Expect(watch.repoPairs[1].name).To(Equal(foo))
-			Eventually(func() bool {
-				var pFoo, pBar api.Pattern
-				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pFoo)
-				Expect(err).NotTo(HaveOccurred())
-				err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: bar, Namespace: defaultNamespace}, &pBar)
-				Expect(err).NotTo(HaveOccurred())
-				return len(pFoo.Status.Conditions) == 0 && len(pBar.Status.Conditions) == 1
-			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(),
-				"expected number of conditions for foo %d and bar %d but found %d and %d respectivelly ", 0, len(patternFoo.Status.Conditions), 1, len(patternBar.Status.Conditions))
-			//confirm the status contains a new condition with type git in sync
-			var pattern api.Pattern
-			err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: bar, Namespace: defaultNamespace}, &pattern)
+
+			Eventually(func() int64 {
+				for _, pair := range watch.snapshotPairs() {
+					if pair.name == foo {
+						return pair.checkCount
+					}
+				}
+				return 0
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeNumerically(">=", 2),
+				"expected at least two checks to complete in read-only mode")
+			Expect(atomic.LoadInt32(&counting.writes)).To(BeZero())
+			var p api.Pattern
+			err = k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
 			Expect(err).NotTo(HaveOccurred())
-			// check that the conditions reflect the drift polling
-			Expect(pattern.Status.Conditions[0].Type).To(Equal(api.GitInSync))
-			Expect(pattern.Status.Conditions[0].Status).To(Equal(v1core.ConditionTrue))
+			Expect(p.Status.Conditions).To(BeEmpty())
 		})
-		It("removes the fist pair and adds it again with longer interval to ensure it is requeued the last", func() {
-			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
-			mockRemote.EXPECT().List(gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+	})
 
-			watch := newWatcher(mockGitClient)
-			watch.watch()
-			// Add both reference pairs and wait for the drift evaluation to kick in and add the first condition
-			err := watch.add(foo, defaultNamespace, 5)
-			Expect(err).NotTo(HaveOccurred())
-			err = watch.add(bar, defaultNamespace, 4)
-			Expect(err).NotTo(HaveOccurred())
-			// remove the first element
-			err = watch.remove(bar, defaultNamespace)
-			Expect(err).NotTo(HaveOccurred())
-			// readd the first element but with longer interval
-			err = watch.add(bar, defaultNamespace, 5)
+	var _ = Context("when a pattern flaps faster than the status write interval", func() {
+		var patternFoo *api.Pattern
+
+		BeforeEach(func() {
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			e := k8sClient.Create(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(context.TODO(), patternFoo)
 			Expect(err).NotTo(HaveOccurred())
-			// check the order of processing pairs
-			Expect(watch.repoPairs[0].name).To(Equal(foo))
-			Expect(watch.repoPairs[1].name).To(Equal(bar))
-			// wait for the first element to be processed at least once
-			var pattern api.Pattern
-			Eventually(func() bool {
-				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pattern)
-				Expect(err).NotTo(HaveOccurred())
-				return len(pattern.Status.Conditions) == 1
-			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(), "expected number of conditions to be %d but found %d", 1, len(pattern.Status.Conditions))
 		})
 
-		It("updates the interval of an existing repository pair", func() {
-			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
-			mockRemote.EXPECT().List(gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+		It("coalesces rapid transitions and converges to the latest state", func() {
+			watch := newWatcher(nil)
+			watch.statusWriteInterval = time.Hour
 
-			watch := newWatcher(mockGitClient)
-			watch.watch()
-			// Add both reference pairs and wait for the drift evaluation to kick in and add the first condition
-			err := watch.add(foo, defaultNamespace, 5)
-			Expect(err).NotTo(HaveOccurred())
-			err = watch.add(bar, defaultNamespace, 4)
+			// the first write lands immediately, the rapid alternation behind it is coalesced
+			Expect(watch.persistCondition(foo, defaultNamespace, api.GitInSync, nil, time.Now(), nil)).NotTo(HaveOccurred())
+			Expect(watch.persistCondition(foo, defaultNamespace, api.GitOutOfSync, nil, time.Now(), nil)).NotTo(HaveOccurred())
+			Expect(watch.persistCondition(foo, defaultNamespace, api.GitInSync, nil, time.Now(), nil)).NotTo(HaveOccurred())
+			Expect(watch.persistCondition(foo, defaultNamespace, api.GitOutOfSync, nil, time.Now(), nil)).NotTo(HaveOccurred())
+
+			var p api.Pattern
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
 			Expect(err).NotTo(HaveOccurred())
-			// update the first element but with longer interval
-			err = watch.updateInterval(bar, defaultNamespace, 6)
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.GitInSync)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
+			// only the latest coalesced outcome is retained for the pattern
+			Expect(watch.throttledConditions).To(HaveLen(1))
+
+			// once the write slot reopens the flush persists the latest outcome
+			watch.lastStatusWrites[defaultNamespace+"/"+foo] = time.Now().Add(-2 * time.Hour)
+			watch.flushThrottledConditions()
+			Expect(watch.throttledConditions).To(BeEmpty())
+			err = k8sClient.Get(context.Background(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &p)
 			Expect(err).NotTo(HaveOccurred())
-			// check the order of processing pairs
-			Expect(watch.repoPairs[0].name).To(Equal(foo))
-			Expect(watch.repoPairs[1].name).To(Equal(bar))
-			// wait for the first element to be processed at least once
-			var pattern api.Pattern
-			Eventually(func() bool {
-				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: foo, Namespace: defaultNamespace}, &pattern)
-				Expect(err).NotTo(HaveOccurred())
-				return len(pattern.Status.Conditions) == 1
-			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeTrue(), "expected number of conditions to be %d but found %d", 1, len(pattern.Status.Conditions))
+			_, condition = getPatternConditionByType(p.Status.Conditions, api.GitOutOfSync)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1core.ConditionTrue))
 		})
 	})
 
@@ -595,9 +3729,9 @@ var _ = Describe("Drift watcher", func() {
 		})
 		It("adds,removes and check for existing pairs in parallel load with random intervals", func() {
 			mockGitClient.EXPECT().NewRemoteClient(gomock.Any()).Return(mockRemote).AnyTimes()
-			mockRemote.EXPECT().List(gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
+			mockRemote.EXPECT().ListContext(gomock.Any(), gomock.Any()).Return(firstCommitReference, nil).AnyTimes()
 
-			watch, _ := newDriftWatcher(k8sClient, logr.New(log.NullLogSink{}), mockGitClient)
+			watch, _ := newDriftWatcher(k8sClient, logr.New(log.NullLogSink{}), mockGitClient, nil)
 			wg := sync.WaitGroup{}
 			wg.Add(2)
 			go func() {
@@ -608,7 +3742,7 @@ var _ = Describe("Drift watcher", func() {
 					for watch.isWatching(name, defaultNamespace) {
 						name = fmt.Sprintf("load-%d", rand.Intn(1000))
 					}
-					Expect(watch.add(name, defaultNamespace, interval)).NotTo(HaveOccurred())
+					Expect(watch.add(name, defaultNamespace, interval, false, "")).NotTo(HaveOccurred())
 				}
 				wg.Done()
 			}()
@@ -628,6 +3762,409 @@ var _ = Describe("Drift watcher", func() {
 	})
 })
 
+var _ = Describe("DNS cache", func() {
+
+	var _ = Context("when resolving host names through the cache", func() {
+		var (
+			cache   *dnsCache
+			lookups int
+		)
+
+		BeforeEach(func() {
+			lookups = 0
+			cache = newDNSCache(time.Minute)
+			cache.lookup = func(_ context.Context, host string) ([]string, error) {
+				lookups++
+				return []string{"192.0.2.1"}, nil
+			}
+		})
+
+		It("reuses the cached resolution for repeated lookups within the TTL", func() {
+			for i := 0; i < 3; i++ {
+				addrs, err := cache.lookupHost(context.Background(), "origin.url")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(addrs).To(Equal([]string{"192.0.2.1"}))
+			}
+			Expect(lookups).To(Equal(1))
+		})
+
+		It("resolves afresh once the entry has expired", func() {
+			cache.ttl = -time.Second
+			for i := 0; i < 2; i++ {
+				_, err := cache.lookupHost(context.Background(), "origin.url")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(lookups).To(Equal(2))
+		})
+
+		It("does not cache failed lookups", func() {
+			cache.lookup = func(_ context.Context, host string) ([]string, error) {
+				lookups++
+				if lookups == 1 {
+					return nil, fmt.Errorf("resolver unavailable")
+				}
+				return []string{"192.0.2.1"}, nil
+			}
+			_, err := cache.lookupHost(context.Background(), "origin.url")
+			Expect(err).To(HaveOccurred())
+			addrs, err := cache.lookupHost(context.Background(), "origin.url")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(addrs).To(Equal([]string{"192.0.2.1"}))
+			Expect(lookups).To(Equal(2))
+		})
+	})
+})
+
+var _ = Describe("Proxy registry", func() {
+
+	var _ = Context("when selecting the proxy for an outbound request", func() {
+		var registry *proxyRegistry
+
+		requestFor := func(rawURL string) *http.Request {
+			parsed, err := url.Parse(rawURL)
+			Expect(err).NotTo(HaveOccurred())
+			return &http.Request{URL: parsed}
+		}
+
+		BeforeEach(func() {
+			registry = newProxyRegistry()
+			registry.operator = proxySettings{
+				httpProxy:  "http://operator-proxy:3128",
+				httpsProxy: "http://operator-proxy:3129",
+				noProxy:    "internal.example.com,.svc"}
+		})
+
+		It("selects the proxy matching the scheme of the request", func() {
+			proxyURL, err := registry.proxyForRequest(requestFor("https://origin.url/repo"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL.String()).To(Equal("http://operator-proxy:3129"))
+
+			proxyURL, err = registry.proxyForRequest(requestFor("http://origin.url/repo"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL.String()).To(Equal("http://operator-proxy:3128"))
+		})
+
+		It("connects directly to hosts matched by the noProxy list", func() {
+			for _, rawURL := range []string{
+				"https://internal.example.com/repo",
+				"https://gitea.apps.svc/repo"} {
+				proxyURL, err := registry.proxyForRequest(requestFor(rawURL))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proxyURL).To(BeNil())
+			}
+			proxyURL, err := registry.proxyForRequest(requestFor("https://external.example.org/repo"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL).NotTo(BeNil())
+		})
+
+		It("bypasses the proxy for every host when noProxy is a wildcard", func() {
+			registry.operator.noProxy = "*"
+			proxyURL, err := registry.proxyForRequest(requestFor("https://origin.url/repo"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL).To(BeNil())
+		})
+
+		It("prefers the settings registered for the host over the operator-level ones", func() {
+			registry.setHostProxy("origin.url", proxySettings{httpsProxy: "http://pattern-proxy:8080"})
+			proxyURL, err := registry.proxyForRequest(requestFor("https://origin.url/repo"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL.String()).To(Equal("http://pattern-proxy:8080"))
+
+			registry.clearHostProxy("origin.url")
+			proxyURL, err = registry.proxyForRequest(requestFor("https://origin.url/repo"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL.String()).To(Equal("http://operator-proxy:3129"))
+		})
+
+		It("reports an unparsable proxy URL instead of silently going direct", func() {
+			registry.operator.httpsProxy = "://broken"
+			_, err := registry.proxyForRequest(requestFor("https://origin.url/repo"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("registers the hosts of a pattern declaring its own proxy", func() {
+			pair := &repositoryPair{}
+			pair.applyProxyConfig(api.GitConfig{
+				OriginRepo: originURL,
+				TargetRepo: targetURL,
+				Proxy:      api.ProxyConfig{HTTPSProxy: "http://pattern-proxy:8080"}})
+			defer func() {
+				pair.applyProxyConfig(api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL})
+			}()
+			Expect(sharedProxyRegistry.settingsForHost("origin.url").httpsProxy).To(Equal("http://pattern-proxy:8080"))
+			Expect(sharedProxyRegistry.settingsForHost("target.url").httpsProxy).To(Equal("http://pattern-proxy:8080"))
+
+			pair.applyProxyConfig(api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL})
+			Expect(sharedProxyRegistry.settingsForHost("origin.url")).To(Equal(sharedProxyRegistry.operator))
+		})
+	})
+})
+
+var _ = Describe("TLS registry", func() {
+
+	var _ = Context("when trusting a private certificate authority", func() {
+
+		// generateCAPEM returns the PEM encoding of a freshly self-signed CA certificate
+		generateCAPEM := func() []byte {
+			key, e := rsa.GenerateKey(cryptorand.Reader, 2048)
+			Expect(e).NotTo(HaveOccurred())
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "private-ca"},
+				NotBefore:             time.Now().Add(-time.Hour),
+				NotAfter:              time.Now().Add(time.Hour),
+				IsCA:                  true,
+				KeyUsage:              x509.KeyUsageCertSign,
+				BasicConstraintsValid: true}
+			der, e := x509.CreateCertificate(cryptorand.Reader, template, template, &key.PublicKey, key)
+			Expect(e).NotTo(HaveOccurred())
+			return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+		}
+
+		It("extends the trusted roots with the bundle registered for the host", func() {
+			registry := newTLSRegistry()
+			registry.setHostTLS("origin.url", tlsSettings{caPEM: generateCAPEM()})
+
+			config, e := registry.clientConfig("origin.url")
+			Expect(e).NotTo(HaveOccurred())
+			Expect(config.ServerName).To(Equal("origin.url"))
+			Expect(config.RootCAs).NotTo(BeNil())
+
+			config, e = registry.clientConfig("other.host")
+			Expect(e).NotTo(HaveOccurred())
+			Expect(config.RootCAs).To(BeNil())
+
+			registry.clearHostTLS("origin.url")
+			config, e = registry.clientConfig("origin.url")
+			Expect(e).NotTo(HaveOccurred())
+			Expect(config.RootCAs).To(BeNil())
+		})
+
+		It("rejects a bundle no certificate can be parsed from", func() {
+			registry := newTLSRegistry()
+			registry.setHostTLS("origin.url", tlsSettings{caPEM: []byte("not a certificate")})
+			_, e := registry.clientConfig("origin.url")
+			Expect(e).To(HaveOccurred())
+		})
+
+		It("registers the bundle declared by a pattern and clears it when removed", func() {
+			secret := &v1core.Secret{
+				ObjectMeta: v1.ObjectMeta{Name: "private-ca", Namespace: defaultNamespace},
+				Data:       map[string][]byte{caBundleSecretKey: generateCAPEM()}}
+			Expect(k8sClient.Create(context.Background(), secret)).NotTo(HaveOccurred())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), secret)).NotTo(HaveOccurred())
+			}()
+
+			pair := &repositoryPair{name: foo, namespace: defaultNamespace, kClient: k8sClient}
+			gc := api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL, CABundleSecret: "private-ca"}
+			Expect(pair.applyTLSConfig(context.Background(), gc)).NotTo(HaveOccurred())
+			config, e := sharedTLSRegistry.clientConfig("origin.url")
+			Expect(e).NotTo(HaveOccurred())
+			Expect(config.RootCAs).NotTo(BeNil())
+
+			gc.CABundleSecret = ""
+			Expect(pair.applyTLSConfig(context.Background(), gc)).NotTo(HaveOccurred())
+			config, e = sharedTLSRegistry.clientConfig("origin.url")
+			Expect(e).NotTo(HaveOccurred())
+			Expect(config.RootCAs).To(BeNil())
+		})
+
+		It("skips certificate validation for a host that opted out", func() {
+			registry := newTLSRegistry()
+			registry.setHostTLS("origin.url", tlsSettings{insecureSkipVerify: true})
+
+			config, e := registry.clientConfig("origin.url")
+			Expect(e).NotTo(HaveOccurred())
+			Expect(config.InsecureSkipVerify).To(BeTrue())
+
+			config, e = registry.clientConfig("other.host")
+			Expect(e).NotTo(HaveOccurred())
+			Expect(config.InsecureSkipVerify).To(BeFalse())
+		})
+
+		It("emits a warning event once when a pattern disables certificate validation", func() {
+			pattern := &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{TargetRepo: targetURL}}}
+			Expect(k8sClient.Create(context.Background(), pattern)).NotTo(HaveOccurred())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), pattern)).NotTo(HaveOccurred())
+			}()
+
+			recorder := record.NewFakeRecorder(10)
+			pair := &repositoryPair{name: foo, namespace: defaultNamespace, kClient: k8sClient, recorder: recorder}
+			gc := api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL, InsecureSkipVerify: true}
+			for i := 0; i < 2; i++ {
+				Expect(pair.applyTLSConfig(context.Background(), gc)).NotTo(HaveOccurred())
+			}
+			defer pair.applyTLSConfig(context.Background(), api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL})
+
+			var event string
+			Expect(recorder.Events).To(Receive(&event))
+			Expect(event).To(ContainSubstring("Warning"))
+			Expect(event).To(ContainSubstring(eventReasonInsecureTLS))
+			Expect(recorder.Events).NotTo(Receive())
+
+			config, e := sharedTLSRegistry.clientConfig("origin.url")
+			Expect(e).NotTo(HaveOccurred())
+			Expect(config.InsecureSkipVerify).To(BeTrue())
+		})
+
+		It("fails the check when the referenced secret does not hold a bundle", func() {
+			secret := &v1core.Secret{
+				ObjectMeta: v1.ObjectMeta{Name: "private-ca", Namespace: defaultNamespace},
+				Data:       map[string][]byte{"unrelated": []byte("data")}}
+			Expect(k8sClient.Create(context.Background(), secret)).NotTo(HaveOccurred())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), secret)).NotTo(HaveOccurred())
+			}()
+
+			pair := &repositoryPair{name: foo, namespace: defaultNamespace, kClient: k8sClient}
+			gc := api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL, CABundleSecret: "private-ca"}
+			e := pair.applyTLSConfig(context.Background(), gc)
+			Expect(e).To(HaveOccurred())
+			Expect(e.Error()).To(ContainSubstring(caBundleSecretKey))
+		})
+	})
+})
+
+// failingResolver is a RevisionResolver that always fails with the configured error
+type failingResolver struct {
+	err error
+}
+
+func (f *failingResolver) Resolve(*api.Pattern) (plumbing.Hash, error) {
+	return plumbing.ZeroHash, f.err
+}
+
+// captureSink records the key/value pairs attached to loggers derived with WithValues
+type captureSink struct {
+	log.NullLogSink
+	mutex  sync.Mutex
+	values [][]interface{}
+}
+
+func (c *captureSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.values = append(c.values, keysAndValues)
+	return c
+}
+
+func (c *captureSink) captured() [][]interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([][]interface{}{}, c.values...)
+}
+
+// outageClient simulates an unreachable API server while down is true
+type outageClient struct {
+	client.Client
+	down bool
+}
+
+func (o *outageClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if o.down {
+		return fmt.Errorf("the API server is unreachable")
+	}
+	return o.Client.Get(ctx, key, obj)
+}
+
+// conflictingClient rejects a configurable number of status updates with a resource version
+// conflict before letting them through, simulating a concurrent status writer
+type conflictingClient struct {
+	client.Client
+	conflicts int32
+}
+
+func (c *conflictingClient) Status() client.StatusWriter {
+	return &conflictingStatusWriter{StatusWriter: c.Client.Status(), conflicts: &c.conflicts}
+}
+
+type conflictingStatusWriter struct {
+	client.StatusWriter
+	conflicts *int32
+}
+
+func (c *conflictingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if atomic.AddInt32(c.conflicts, -1) >= 0 {
+		return kerrors.NewConflict(schema.GroupResource{Group: api.GroupVersion.Group, Resource: "patterns"}, obj.GetName(), fmt.Errorf("the object has been modified"))
+	}
+	return c.StatusWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// patchScopeClient records the body of every status patch so a test can assert which fields the
+// watcher actually writes
+type patchScopeClient struct {
+	client.Client
+	patches [][]byte
+}
+
+func (p *patchScopeClient) Status() client.StatusWriter {
+	return &patchScopeStatusWriter{StatusWriter: p.Client.Status(), patches: &p.patches}
+}
+
+type patchScopeStatusWriter struct {
+	client.StatusWriter
+	patches *[][]byte
+}
+
+func (p *patchScopeStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	*p.patches = append(*p.patches, data)
+	return p.StatusWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// writeCountingClient counts every mutating call that reaches the API server, so that a test can
+// assert a read-only watcher never writes anything
+type writeCountingClient struct {
+	client.Client
+	writes int32
+}
+
+func (w *writeCountingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	atomic.AddInt32(&w.writes, 1)
+	return w.Client.Create(ctx, obj, opts...)
+}
+
+func (w *writeCountingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	atomic.AddInt32(&w.writes, 1)
+	return w.Client.Update(ctx, obj, opts...)
+}
+
+func (w *writeCountingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	atomic.AddInt32(&w.writes, 1)
+	return w.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (w *writeCountingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	atomic.AddInt32(&w.writes, 1)
+	return w.Client.Delete(ctx, obj, opts...)
+}
+
+func (w *writeCountingClient) Status() client.StatusWriter {
+	return &writeCountingStatusWriter{StatusWriter: w.Client.Status(), writes: &w.writes}
+}
+
+type writeCountingStatusWriter struct {
+	client.StatusWriter
+	writes *int32
+}
+
+func (w *writeCountingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	atomic.AddInt32(w.writes, 1)
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func (w *writeCountingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	atomic.AddInt32(w.writes, 1)
+	return w.StatusWriter.Patch(ctx, obj, patch, opts...)
+}
+
 func newWatcher(gitClient GitClient) *watcher {
 
 	return &watcher{
@@ -0,0 +1,75 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// GitDriftCheckReconciler reconciles a GitDriftCheck object, registering and unregistering its
+// repository pair with the drift watcher shared with the Pattern controller
+type GitDriftCheckReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	driftWatcher driftWatcher
+}
+
+//+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=gitdriftchecks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=gitdriftchecks/status,verbs=get;update;patch
+
+// Reconcile keeps the drift watcher in sync with the declared GitDriftCheck resources: a new
+// resource starts monitoring, a spec change reschedules the pair and a deleted resource stops it
+func (r *GitDriftCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	check := &api.GitDriftCheck{}
+	if err := r.Get(ctx, req.NamespacedName, check); err != nil {
+		if kerrors.IsNotFound(err) {
+			if r.driftWatcher.isWatchingCheck(req.Name, req.Namespace) {
+				if rErr := r.driftWatcher.removeCheck(req.Name, req.Namespace); rErr != nil {
+					return ctrl.Result{}, rErr
+				}
+			}
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	// clamp to the same floor the Pattern defaulting applies to its polling interval
+	interval := check.Spec.PollIntervalSeconds
+	if interval < 180 {
+		interval = 180
+	}
+	if !r.driftWatcher.isWatchingCheck(check.Name, check.Namespace) {
+		return ctrl.Result{}, r.driftWatcher.addCheck(check.Name, check.Namespace, interval, check.Spec.AlignChecks)
+	}
+	return ctrl.Result{}, r.driftWatcher.updateCheckInterval(check.Name, check.Namespace, interval, check.Spec.AlignChecks)
+}
+
+// SetupWithManager sets up the controller with the Manager, reusing the drift watcher created by
+// the Pattern controller so both kinds of pairs share one schedule and worker pool
+func (r *GitDriftCheckReconciler) SetupWithManager(mgr ctrl.Manager, watcher driftWatcher) error {
+	r.driftWatcher = watcher
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&api.GitDriftCheck{}).
+		Complete(r)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// RevisionResolver resolves the revision a pattern should be compared against from a source other
+// than the advertised references of the target repository
+type RevisionResolver interface {
+	Resolve(p *api.Pattern) (plumbing.Hash, error)
+}
+
+const githubAPIBaseURL = "https://api.github.com"
+
+type githubDeploymentsResolver struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newGithubDeploymentsResolver() RevisionResolver {
+	return &githubDeploymentsResolver{
+		baseURL: githubAPIBaseURL,
+		client:  &http.Client{Transport: &http.Transport{Proxy: sharedProxyRegistry.proxyForRequest}}}
+}
+
+// githubDeployment is the subset of the GitHub Deployments API response the resolver consumes
+type githubDeployment struct {
+	SHA string `json:"sha"`
+}
+
+// Resolve queries the GitHub Deployments API for the latest deployment to the environment
+// configured in the pattern and returns the deployed commit hash. Authentication uses the token
+// found in the GITHUB_TOKEN environment variable when present
+func (g *githubDeploymentsResolver) Resolve(p *api.Pattern) (plumbing.Hash, error) {
+	environment := p.Spec.GitConfig.GithubDeploymentEnvironment
+	owner, repo, err := parseGithubRepo(p.Spec.GitConfig.OriginRepo)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/deployments?environment=%s&per_page=1", g.baseURL, owner, repo, url.QueryEscape(environment))
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return plumbing.ZeroHash, fmt.Errorf("deployments query for %s/%s returned status %d", owner, repo, resp.StatusCode)
+	}
+	var deployments []githubDeployment
+	if err := json.NewDecoder(resp.Body).Decode(&deployments); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if len(deployments) == 0 {
+		return plumbing.ZeroHash, fmt.Errorf("no deployments found for environment %q in %s/%s", environment, owner, repo)
+	}
+	return plumbing.NewHash(deployments[0].SHA), nil
+}
+
+// parseGithubRepo derives the owner and repository names from a repository URL
+func parseGithubRepo(repoURL string) (string, string, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", err
+	}
+	parts := strings.Split(strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unable to derive owner and repository from %s", repoURL)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+var _ = Describe("GitHub deployments resolver", func() {
+
+	var _ = Context("when resolving the deployed revision", func() {
+		var (
+			server  *httptest.Server
+			handler http.HandlerFunc
+			pattern *api.Pattern
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handler(w, r)
+			}))
+			pattern = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				Spec: api.PatternSpec{
+					GitConfig: api.GitConfig{
+						OriginRepo:                  "https://github.com/some/place.git",
+						TargetRepo:                  targetURL,
+						GithubDeploymentEnvironment: "production"}},
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("returns the SHA of the latest deployment to the environment", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/repos/some/place/deployments"))
+				Expect(r.URL.Query().Get("environment")).To(Equal("production"))
+				fmt.Fprintf(w, `[{"sha": %q}]`, hashCommitMainHead)
+			}
+			resolver := &githubDeploymentsResolver{baseURL: server.URL, client: server.Client()}
+			hash, err := resolver.Resolve(pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash).To(Equal(plumbing.NewHash(hashCommitMainHead)))
+		})
+
+		It("errors when no deployment exists for the environment", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[]`)
+			}
+			resolver := &githubDeploymentsResolver{baseURL: server.URL, client: server.Client()}
+			_, err := resolver.Resolve(pattern)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`no deployments found for environment "production"`))
+		})
+
+		It("errors when the API responds with a non-OK status", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+			resolver := &githubDeploymentsResolver{baseURL: server.URL, client: server.Client()}
+			_, err := resolver.Resolve(pattern)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("returned status 401"))
+		})
+	})
+
+	var _ = Context("when parsing the repository URL", func() {
+
+		DescribeTable("deriving owner and repository", func(repoURL, owner, repo string, expectErr bool) {
+			o, r, err := parseGithubRepo(repoURL)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(o).To(Equal(owner))
+			Expect(r).To(Equal(repo))
+		},
+			Entry("with a plain https URL", "https://github.com/some/place", "some", "place", false),
+			Entry("with a trailing .git suffix", "https://github.com/some/place.git", "some", "place", false),
+			Entry("with a missing repository segment", "https://github.com/some", "", "", true),
+			Entry("with extra path segments", "https://github.com/some/place/extra", "", "", true),
+		)
+	})
+})
@@ -0,0 +1,70 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// defaultStarvationIntervals is how many polling intervals a due check may wait before the
+// watcher is reported unhealthy; overridable through DRIFT_HEALTH_STARVATION_INTERVALS
+const defaultStarvationIntervals = 3
+
+// healthCheck reports whether the drift watcher is still doing its job, so a wedged operator gets
+// restarted instead of silently doing nothing: the scheduler loop must be alive, no due pair may
+// have been starved for longer than the starvation budget, and check outcomes must be reaching
+// the API server
+func (d *watcher) healthCheck(req *http.Request) error {
+	if atomic.LoadInt32(&d.loopExited) == 1 {
+		return fmt.Errorf("the drift watcher scheduler loop has exited")
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.suspended {
+		// a stand-by replica is intentionally quiet; only the leader is held to the schedule
+		return nil
+	}
+	if count := len(d.pendingConditions); count > 0 {
+		return fmt.Errorf("the API server is unreachable, %d check outcomes are pending", count)
+	}
+	intervals := d.starvationIntervals
+	if intervals <= 0 {
+		intervals = defaultStarvationIntervals
+	}
+	now := time.Now()
+	for _, pair := range d.repoPairs {
+		if pair.inFlight || pair.interval <= 0 {
+			continue
+		}
+		if starved := now.Sub(pair.nextCheck); starved > time.Duration(intervals)*pair.interval {
+			return fmt.Errorf("the check for %s in %s has been due for %s, the drift watcher timer is starved",
+				pair.name, pair.namespace, starved.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// DriftWatcherHealthz exposes the drift watcher health as a checker the manager can wire into its
+// healthz and readyz endpoints
+func (r *PatternReconciler) DriftWatcherHealthz() healthz.Checker {
+	return r.driftWatcher.healthCheck
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// per-pattern drift watcher metrics, labelled so alerts can single out the drifting pattern
+var (
+	driftChecksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "patterns_operator_drift_checks_total",
+		Help: "Number of drift checks performed for the pattern",
+	}, []string{"pattern", "namespace"})
+
+	driftCheckErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "patterns_operator_drift_check_errors_total",
+		Help: "Number of drift checks for the pattern that could not be completed",
+	}, []string{"pattern", "namespace"})
+
+	driftDetected = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "patterns_operator_drift_detected",
+		Help: "Whether the repositories of the pattern drifted apart at the last completed check (1) or not (0)",
+	}, []string{"pattern", "namespace"})
+
+	driftCheckDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "patterns_operator_drift_check_duration_seconds",
+		Help:    "Time spent comparing the repositories of the pattern during a single drift check",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"pattern", "namespace"})
+)
+
+// effectiveConfig is a single always-1 series carrying the runtime configuration currently in
+// effect as its labels, so dashboards can display it next to the metrics it influences. The
+// series is replaced, not accumulated, whenever the configuration changes
+var effectiveConfig = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "patterns_operator_effective_config",
+	Help: "Always 1, labelled with the runtime configuration the operator currently runs with",
+}, []string{"log_level", "drift_check_concurrency", "drift_check_timeout_seconds"})
+
+func init() {
+	metrics.Registry.MustRegister(driftChecksTotal, driftCheckErrorsTotal, driftDetected, driftCheckDuration, effectiveConfig)
+}
+
+// recordCheckMetrics publishes the outcome of a completed or failed check of a pair. The drift
+// gauge keeps its previous value across failed checks, since a failure says nothing about whether
+// the repositories converged
+func recordCheckMetrics(pair *repositoryPair, checkErr error) {
+	driftChecksTotal.WithLabelValues(pair.name, pair.namespace).Inc()
+	driftCheckDuration.WithLabelValues(pair.name, pair.namespace).Observe(pair.executionDuration.Seconds())
+	if checkErr != nil {
+		driftCheckErrorsTotal.WithLabelValues(pair.name, pair.namespace).Inc()
+		return
+	}
+	value := 0.0
+	if pair.drifted {
+		value = 1.0
+	}
+	driftDetected.WithLabelValues(pair.name, pair.namespace).Set(value)
+}
+
+// deleteCheckMetrics drops the per-pattern series once the pair is no longer watched, so removed
+// patterns do not linger in the scrape output
+func deleteCheckMetrics(name, namespace string) {
+	labels := prometheus.Labels{"pattern": name, "namespace": namespace}
+	driftChecksTotal.Delete(labels)
+	driftCheckErrorsTotal.Delete(labels)
+	driftDetected.Delete(labels)
+	driftCheckDuration.Delete(labels)
+}
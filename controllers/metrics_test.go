@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/go-git/go-git/v5/config"
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// gatherMetric returns the sample of a registered metric carrying the given pattern and
+// namespace labels, or nil when no such series exists
+func gatherMetric(name, pattern, namespace string) *dto.Metric {
+	families, err := metrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matched := 0
+			for _, label := range metric.GetLabel() {
+				if (label.GetName() == "pattern" && label.GetValue() == pattern) ||
+					(label.GetName() == "namespace" && label.GetValue() == namespace) {
+					matched++
+				}
+			}
+			if matched == 2 {
+				return metric
+			}
+		}
+	}
+	return nil
+}
+
+func counterValue(name, pattern, namespace string) float64 {
+	if metric := gatherMetric(name, pattern, namespace); metric != nil {
+		return metric.GetCounter().GetValue()
+	}
+	return 0
+}
+
+func gaugeValue(name, pattern, namespace string) float64 {
+	if metric := gatherMetric(name, pattern, namespace); metric != nil {
+		return metric.GetGauge().GetValue()
+	}
+	return -1
+}
+
+var _ = Describe("Drift watcher metrics", func() {
+
+	var _ = Context("when exporting drift check metrics", func() {
+		var (
+			patternFoo       *api.Pattern
+			mockGitClient    *MockClient
+			mockRemoteOrigin *MockRemoteClient
+			mockRemoteTarget *MockRemoteClient
+			watch            *watcher
+		)
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteTarget = NewMockRemoteClient(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemoteOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemoteTarget)
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			e := k8sClient.Create(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+			watch = newWatcher(mockGitClient)
+			watch.watch()
+		})
+
+		AfterEach(func() {
+			if watch.isWatching(foo, defaultNamespace) {
+				e := watch.remove(foo, defaultNamespace)
+				Expect(e).NotTo(HaveOccurred())
+			}
+			deleteCheckMetrics(foo, defaultNamespace)
+			e := k8sClient.Delete(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		It("counts completed checks, reports drift and observes durations", func() {
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitAmendedReference, nil)
+			e := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(e).NotTo(HaveOccurred())
+
+			Eventually(func() float64 {
+				return counterValue("patterns_operator_drift_checks_total", foo, defaultNamespace)
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeNumerically(">", 0),
+				"expected the check counter to grow with the first check")
+			Expect(gaugeValue("patterns_operator_drift_detected", foo, defaultNamespace)).To(Equal(1.0))
+			Expect(counterValue("patterns_operator_drift_check_errors_total", foo, defaultNamespace)).To(BeZero())
+			duration := gatherMetric("patterns_operator_drift_check_duration_seconds", foo, defaultNamespace)
+			Expect(duration).NotTo(BeNil())
+			Expect(duration.GetHistogram().GetSampleCount()).To(BeNumerically(">", 0))
+		})
+
+		It("counts failed checks without touching the drift gauge", func() {
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, transport.ErrAuthenticationRequired)
+			e := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(e).NotTo(HaveOccurred())
+
+			Eventually(func() float64 {
+				return counterValue("patterns_operator_drift_check_errors_total", foo, defaultNamespace)
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeNumerically(">", 0),
+				"expected the error counter to grow with the first failed check")
+			Expect(gaugeValue("patterns_operator_drift_detected", foo, defaultNamespace)).To(Equal(-1.0),
+				"expected no drift sample while no check ever completed")
+		})
+
+		It("drops the per-pattern series when the pair is removed", func() {
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			e := watch.add(foo, defaultNamespace, 1, false, "")
+			Expect(e).NotTo(HaveOccurred())
+
+			Eventually(func() float64 {
+				return counterValue("patterns_operator_drift_checks_total", foo, defaultNamespace)
+			}).WithPolling(time.Second).WithTimeout(10*time.Second).Should(BeNumerically(">", 0),
+				"expected the check counter to grow with the first check")
+
+			e = watch.remove(foo, defaultNamespace)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(gatherMetric("patterns_operator_drift_checks_total", foo, defaultNamespace)).To(BeNil())
+			Expect(gatherMetric("patterns_operator_drift_detected", foo, defaultNamespace)).To(BeNil())
+		})
+	})
+})
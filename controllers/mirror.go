@@ -0,0 +1,93 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sort"
+	"strings"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// mirrorMap maps upstream URL or registry prefixes to the internal mirrors that replace them in a
+// disconnected environment. Declared through the GIT_MIRRORS and IMAGE_REGISTRY_MIRRORS
+// environment variables as comma-separated upstream=mirror pairs
+type mirrorMap map[string]string
+
+// parseMirrorMap parses a comma-separated list of upstream=mirror pairs; malformed entries are
+// dropped rather than failing operator startup
+func parseMirrorMap(spec string) mirrorMap {
+	mirrors := mirrorMap{}
+	for _, entry := range strings.Split(spec, ",") {
+		upstream, mirror, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || upstream == "" || mirror == "" {
+			continue
+		}
+		mirrors[upstream] = mirror
+	}
+	if len(mirrors) == 0 {
+		return nil
+	}
+	return mirrors
+}
+
+// resolve rewrites the URL onto its mirror when a declared upstream prefix matches; the longest
+// prefix wins so a repository-level mirror overrides a host-level one. Unmatched URLs pass
+// through unchanged
+func (m mirrorMap) resolve(url string) string {
+	matched := ""
+	for upstream := range m {
+		if strings.HasPrefix(url, upstream) && len(upstream) > len(matched) {
+			matched = upstream
+		}
+	}
+	if matched == "" {
+		return url
+	}
+	return m[matched] + strings.TrimPrefix(url, matched)
+}
+
+// pairs renders the map back into its upstream=mirror form with a stable order, suitable for
+// handing to charts as a single helm parameter
+func (m mirrorMap) pairs() string {
+	upstreams := make([]string, 0, len(m))
+	for upstream := range m {
+		upstreams = append(upstreams, upstream)
+	}
+	sort.Strings(upstreams)
+	entries := make([]string, 0, len(m))
+	for _, upstream := range upstreams {
+		entries = append(entries, upstream+"="+m[upstream])
+	}
+	return strings.Join(entries, ",")
+}
+
+// applyMirrors rewrites the git repositories of the working copy onto their declared mirrors and
+// exposes the registry mirrors to the charts as global.registryMirrors. The stored pattern keeps
+// the upstream URLs, so reconnecting only requires clearing the operator configuration
+func (r *PatternReconciler) applyMirrors(p *api.Pattern) {
+	if len(r.gitMirrors) > 0 {
+		p.Spec.GitConfig.TargetRepo = r.gitMirrors.resolve(p.Spec.GitConfig.TargetRepo)
+		p.Spec.GitConfig.OriginRepo = r.gitMirrors.resolve(p.Spec.GitConfig.OriginRepo)
+	}
+	if len(r.registryMirrors) > 0 {
+		p.Spec.ExtraParameters = append(p.Spec.ExtraParameters, api.PatternParameter{
+			Name:  "global.registryMirrors",
+			Value: r.registryMirrors.pairs(),
+		})
+	}
+}
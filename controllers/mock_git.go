@@ -5,11 +5,14 @@
 package controllers
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	v5 "github.com/go-git/go-git/v5"
 	config "github.com/go-git/go-git/v5/config"
 	plumbing "github.com/go-git/go-git/v5/plumbing"
+	transport "github.com/go-git/go-git/v5/plumbing/transport"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -36,19 +39,19 @@ func (m *MockRemoteClient) EXPECT() *MockRemoteClientMockRecorder {
 	return m.recorder
 }
 
-// List mocks base method.
-func (m *MockRemoteClient) List(o *v5.ListOptions) ([]*plumbing.Reference, error) {
+// ListContext mocks base method.
+func (m *MockRemoteClient) ListContext(ctx context.Context, o *v5.ListOptions) ([]*plumbing.Reference, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", o)
+	ret := m.ctrl.Call(m, "ListContext", ctx, o)
 	ret0, _ := ret[0].([]*plumbing.Reference)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// List indicates an expected call of List.
-func (mr *MockRemoteClientMockRecorder) List(o interface{}) *gomock.Call {
+// ListContext indicates an expected call of ListContext.
+func (mr *MockRemoteClientMockRecorder) ListContext(ctx, o interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRemoteClient)(nil).List), o)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContext", reflect.TypeOf((*MockRemoteClient)(nil).ListContext), ctx, o)
 }
 
 // MockClient is a mock of Client interface.
@@ -150,3 +153,347 @@ func (mr *MockDriftWatcherMockRecorder) Watch() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockDriftWatcher)(nil).Watch))
 }
+
+// MockHostResolver is a mock of HostResolver interface.
+type MockHostResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockHostResolverMockRecorder
+}
+
+// MockHostResolverMockRecorder is the mock recorder for MockHostResolver.
+type MockHostResolverMockRecorder struct {
+	mock *MockHostResolver
+}
+
+// NewMockHostResolver creates a new mock instance.
+func NewMockHostResolver(ctrl *gomock.Controller) *MockHostResolver {
+	mock := &MockHostResolver{ctrl: ctrl}
+	mock.recorder = &MockHostResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHostResolver) EXPECT() *MockHostResolverMockRecorder {
+	return m.recorder
+}
+
+// FinalHost mocks base method.
+func (m *MockHostResolver) FinalHost(repoURL string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinalHost", repoURL)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinalHost indicates an expected call of FinalHost.
+func (mr *MockHostResolverMockRecorder) FinalHost(repoURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalHost", reflect.TypeOf((*MockHostResolver)(nil).FinalHost), repoURL)
+}
+
+// MockCommitTimeResolver is a mock of CommitTimeResolver interface.
+type MockCommitTimeResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommitTimeResolverMockRecorder
+}
+
+// MockCommitTimeResolverMockRecorder is the mock recorder for MockCommitTimeResolver.
+type MockCommitTimeResolverMockRecorder struct {
+	mock *MockCommitTimeResolver
+}
+
+// NewMockCommitTimeResolver creates a new mock instance.
+func NewMockCommitTimeResolver(ctrl *gomock.Controller) *MockCommitTimeResolver {
+	mock := &MockCommitTimeResolver{ctrl: ctrl}
+	mock.recorder = &MockCommitTimeResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommitTimeResolver) EXPECT() *MockCommitTimeResolverMockRecorder {
+	return m.recorder
+}
+
+// CommitTime mocks base method.
+func (m *MockCommitTimeResolver) CommitTime(repoURL string, hash plumbing.Hash, ref plumbing.ReferenceName) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitTime", repoURL, hash, ref)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitTime indicates an expected call of CommitTime.
+func (mr *MockCommitTimeResolverMockRecorder) CommitTime(repoURL, hash, ref interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitTime", reflect.TypeOf((*MockCommitTimeResolver)(nil).CommitTime), repoURL, hash, ref)
+}
+
+// MockAncestryChecker is a mock of AncestryChecker interface.
+type MockAncestryChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockAncestryCheckerMockRecorder
+}
+
+// MockAncestryCheckerMockRecorder is the mock recorder for MockAncestryChecker.
+type MockAncestryCheckerMockRecorder struct {
+	mock *MockAncestryChecker
+}
+
+// NewMockAncestryChecker creates a new mock instance.
+func NewMockAncestryChecker(ctrl *gomock.Controller) *MockAncestryChecker {
+	mock := &MockAncestryChecker{ctrl: ctrl}
+	mock.recorder = &MockAncestryCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAncestryChecker) EXPECT() *MockAncestryCheckerMockRecorder {
+	return m.recorder
+}
+
+// InHistory mocks base method.
+func (m *MockAncestryChecker) InHistory(repoURL string, branch plumbing.ReferenceName, hash plumbing.Hash) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InHistory", repoURL, branch, hash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InHistory indicates an expected call of InHistory.
+func (mr *MockAncestryCheckerMockRecorder) InHistory(repoURL, branch, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InHistory", reflect.TypeOf((*MockAncestryChecker)(nil).InHistory), repoURL, branch, hash)
+}
+
+// MockIdentityResolver is a mock of IdentityResolver interface.
+type MockIdentityResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityResolverMockRecorder
+}
+
+// MockIdentityResolverMockRecorder is the mock recorder for MockIdentityResolver.
+type MockIdentityResolverMockRecorder struct {
+	mock *MockIdentityResolver
+}
+
+// NewMockIdentityResolver creates a new mock instance.
+func NewMockIdentityResolver(ctrl *gomock.Controller) *MockIdentityResolver {
+	mock := &MockIdentityResolver{ctrl: ctrl}
+	mock.recorder = &MockIdentityResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityResolver) EXPECT() *MockIdentityResolverMockRecorder {
+	return m.recorder
+}
+
+// RootCommits mocks base method.
+func (m *MockIdentityResolver) RootCommits(repoURL string, branch plumbing.ReferenceName) ([]plumbing.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RootCommits", repoURL, branch)
+	ret0, _ := ret[0].([]plumbing.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RootCommits indicates an expected call of RootCommits.
+func (mr *MockIdentityResolverMockRecorder) RootCommits(repoURL, branch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RootCommits", reflect.TypeOf((*MockIdentityResolver)(nil).RootCommits), repoURL, branch)
+}
+
+// MockTreeComparer is a mock of TreeComparer interface.
+type MockTreeComparer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTreeComparerMockRecorder
+}
+
+// MockTreeComparerMockRecorder is the mock recorder for MockTreeComparer.
+type MockTreeComparerMockRecorder struct {
+	mock *MockTreeComparer
+}
+
+// NewMockTreeComparer creates a new mock instance.
+func NewMockTreeComparer(ctrl *gomock.Controller) *MockTreeComparer {
+	mock := &MockTreeComparer{ctrl: ctrl}
+	mock.recorder = &MockTreeComparerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTreeComparer) EXPECT() *MockTreeComparerMockRecorder {
+	return m.recorder
+}
+
+// TreesDiffer mocks base method.
+func (m *MockTreeComparer) TreesDiffer(originRepo string, originRef plumbing.ReferenceName, targetRepo string, targetRef plumbing.ReferenceName, paths []string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TreesDiffer", originRepo, originRef, targetRepo, targetRef, paths)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TreesDiffer indicates an expected call of TreesDiffer.
+func (mr *MockTreeComparerMockRecorder) TreesDiffer(originRepo, originRef, targetRepo, targetRef, paths interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TreesDiffer", reflect.TypeOf((*MockTreeComparer)(nil).TreesDiffer), originRepo, originRef, targetRepo, targetRef, paths)
+}
+
+// MockDivergenceResolver is a mock of DivergenceResolver interface.
+type MockDivergenceResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockDivergenceResolverMockRecorder
+}
+
+// MockDivergenceResolverMockRecorder is the mock recorder for MockDivergenceResolver.
+type MockDivergenceResolverMockRecorder struct {
+	mock *MockDivergenceResolver
+}
+
+// NewMockDivergenceResolver creates a new mock instance.
+func NewMockDivergenceResolver(ctrl *gomock.Controller) *MockDivergenceResolver {
+	mock := &MockDivergenceResolver{ctrl: ctrl}
+	mock.recorder = &MockDivergenceResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDivergenceResolver) EXPECT() *MockDivergenceResolverMockRecorder {
+	return m.recorder
+}
+
+// Divergence mocks base method.
+func (m *MockDivergenceResolver) Divergence(repoURL string, ref plumbing.ReferenceName, origin, target plumbing.Hash) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Divergence", repoURL, ref, origin, target)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Divergence indicates an expected call of Divergence.
+func (mr *MockDivergenceResolverMockRecorder) Divergence(repoURL, ref, origin, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Divergence", reflect.TypeOf((*MockDivergenceResolver)(nil).Divergence), repoURL, ref, origin, target)
+}
+
+// MockDistanceResolver is a mock of DistanceResolver interface.
+type MockDistanceResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockDistanceResolverMockRecorder
+}
+
+// MockDistanceResolverMockRecorder is the mock recorder for MockDistanceResolver.
+type MockDistanceResolverMockRecorder struct {
+	mock *MockDistanceResolver
+}
+
+// NewMockDistanceResolver creates a new mock instance.
+func NewMockDistanceResolver(ctrl *gomock.Controller) *MockDistanceResolver {
+	mock := &MockDistanceResolver{ctrl: ctrl}
+	mock.recorder = &MockDistanceResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDistanceResolver) EXPECT() *MockDistanceResolverMockRecorder {
+	return m.recorder
+}
+
+// Distance mocks base method.
+func (m *MockDistanceResolver) Distance(originRepo string, originRef plumbing.ReferenceName, targetRepo string, targetRef plumbing.ReferenceName, origin, target plumbing.Hash) (int, int, plumbing.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Distance", originRepo, originRef, targetRepo, targetRef, origin, target)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(plumbing.Hash)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// Distance indicates an expected call of Distance.
+func (mr *MockDistanceResolverMockRecorder) Distance(originRepo, originRef, targetRepo, targetRef, origin, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Distance", reflect.TypeOf((*MockDistanceResolver)(nil).Distance), originRepo, originRef, targetRepo, targetRef, origin, target)
+}
+
+// MockCredentialsResolver is a mock of CredentialsResolver interface.
+type MockCredentialsResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockCredentialsResolverMockRecorder
+}
+
+// MockCredentialsResolverMockRecorder is the mock recorder for MockCredentialsResolver.
+type MockCredentialsResolverMockRecorder struct {
+	mock *MockCredentialsResolver
+}
+
+// NewMockCredentialsResolver creates a new mock instance.
+func NewMockCredentialsResolver(ctrl *gomock.Controller) *MockCredentialsResolver {
+	mock := &MockCredentialsResolver{ctrl: ctrl}
+	mock.recorder = &MockCredentialsResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCredentialsResolver) EXPECT() *MockCredentialsResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockCredentialsResolver) Resolve(namespace, name string) (transport.AuthMethod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", namespace, name)
+	ret0, _ := ret[0].(transport.AuthMethod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockCredentialsResolverMockRecorder) Resolve(namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockCredentialsResolver)(nil).Resolve), namespace, name)
+}
+
+// MockDriftRemediator is a mock of DriftRemediator interface.
+type MockDriftRemediator struct {
+	ctrl     *gomock.Controller
+	recorder *MockDriftRemediatorMockRecorder
+}
+
+// MockDriftRemediatorMockRecorder is the mock recorder for MockDriftRemediator.
+type MockDriftRemediatorMockRecorder struct {
+	mock *MockDriftRemediator
+}
+
+// NewMockDriftRemediator creates a new mock instance.
+func NewMockDriftRemediator(ctrl *gomock.Controller) *MockDriftRemediator {
+	mock := &MockDriftRemediator{ctrl: ctrl}
+	mock.recorder = &MockDriftRemediatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDriftRemediator) EXPECT() *MockDriftRemediatorMockRecorder {
+	return m.recorder
+}
+
+// FastForward mocks base method.
+func (m *MockDriftRemediator) FastForward(originRepo string, originRef plumbing.ReferenceName, targetRepo string, branch plumbing.ReferenceName, origin, target plumbing.Hash, auth transport.AuthMethod) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FastForward", originRepo, originRef, targetRepo, branch, origin, target, auth)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FastForward indicates an expected call of FastForward.
+func (mr *MockDriftRemediatorMockRecorder) FastForward(originRepo, originRef, targetRepo, branch, origin, target, auth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FastForward", reflect.TypeOf((*MockDriftRemediator)(nil).FastForward), originRepo, originRef, targetRepo, branch, origin, target, auth)
+}
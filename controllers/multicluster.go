@@ -0,0 +1,216 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// Like the hub detection in acm.go, the OCM resources are reached through the dynamic client as
+// the typed APIs are not vendored
+var (
+	managedClusterGVR = schema.GroupVersionResource{Group: "cluster.open-cluster-management.io", Version: "v1", Resource: "managedclusters"}
+	manifestWorkGVR   = schema.GroupVersionResource{Group: "work.open-cluster-management.io", Version: "v1", Resource: "manifestworks"}
+)
+
+// listManagedClusters returns the names of the ManagedClusters matching the label selector; an
+// empty selector matches every registered cluster
+func listManagedClusters(client dynamic.Interface, matchLabels map[string]string) (error, []string) {
+	clusters, err := client.Resource(managedClusterGVR).List(context.Background(), metav1.ListOptions{
+		LabelSelector: labels.Set(matchLabels).AsSelector().String(),
+	})
+	if err != nil {
+		return err, nil
+	}
+	var names []string
+	for _, cluster := range clusters.Items {
+		names = append(names, cluster.GetName())
+	}
+	return nil, names
+}
+
+// newManifestWork wraps the pattern in a ManifestWork so the work agent applies it on the spoke,
+// where the operator running there reconciles it like a locally created pattern. The multiCluster
+// section is dropped from the copy so the spoke does not fan the pattern out again
+func newManifestWork(p api.Pattern) (error, *unstructured.Unstructured) {
+	spoke := api.Pattern{
+		TypeMeta:   metav1.TypeMeta{APIVersion: api.GroupVersion.String(), Kind: "Pattern"},
+		ObjectMeta: metav1.ObjectMeta{Name: p.Name, Namespace: p.Namespace},
+		Spec:       *p.Spec.DeepCopy(),
+	}
+	spoke.Spec.MultiCluster = nil
+
+	manifest, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&spoke)
+	if err != nil {
+		return err, nil
+	}
+	unstructured.RemoveNestedField(manifest, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(manifest, "status")
+
+	work := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": manifestWorkGVR.Group + "/" + manifestWorkGVR.Version,
+			"kind":       "ManifestWork",
+			"metadata": map[string]interface{}{
+				"name": applicationName(p),
+				// carry the pattern label so the works can be swept when the selection changes
+				"labels": map[string]interface{}{"pattern": applicationName(p)},
+			},
+			"spec": map[string]interface{}{
+				"workload": map[string]interface{}{
+					"manifests": []interface{}{manifest},
+				},
+			},
+		},
+	}
+	return nil, &work
+}
+
+func getManifestWork(client dynamic.Interface, cluster, name string) (error, *unstructured.Unstructured) {
+	if work, err := client.Resource(manifestWorkGVR).Namespace(cluster).Get(context.Background(), name, metav1.GetOptions{}); err != nil {
+		return err, nil
+	} else {
+		return nil, work
+	}
+}
+
+func createManifestWork(client dynamic.Interface, cluster string, work *unstructured.Unstructured) error {
+	_, err := client.Resource(manifestWorkGVR).Namespace(cluster).Create(context.Background(), work, metav1.CreateOptions{})
+	return err
+}
+
+func updateManifestWork(client dynamic.Interface, cluster string, target, current *unstructured.Unstructured) (error, bool) {
+	if current == nil {
+		return fmt.Errorf("current manifestwork was nil"), false
+	} else if target == nil {
+		return fmt.Errorf("target manifestwork was nil"), false
+	}
+
+	if reflect.DeepEqual(target.Object["spec"], current.Object["spec"]) {
+		return nil, false
+	}
+
+	current.Object["spec"] = target.Object["spec"]
+	_, err := client.Resource(manifestWorkGVR).Namespace(cluster).Update(context.Background(), current, metav1.UpdateOptions{})
+	return err, true
+}
+
+func removeManifestWork(client dynamic.Interface, cluster, name string) error {
+	return client.Resource(manifestWorkGVR).Namespace(cluster).Delete(context.Background(), name, metav1.DeleteOptions{})
+}
+
+// listPatternManifestWorks returns the works created for the pattern across all spoke cluster
+// namespaces of the hub
+func listPatternManifestWorks(client dynamic.Interface, pattern string) (error, []unstructured.Unstructured) {
+	works, err := client.Resource(manifestWorkGVR).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("pattern=%s", pattern),
+	})
+	if err != nil {
+		return err, nil
+	}
+	return nil, works.Items
+}
+
+// spokeStatus reads the Applied and Available conditions the work agent reports back onto the
+// ManifestWork, so the hub pattern reflects the propagation state of each spoke
+func spokeStatus(work *unstructured.Unstructured) api.PatternSpokeStatus {
+	status := api.PatternSpokeStatus{Name: work.GetNamespace()}
+	conditions, _, _ := unstructured.NestedSlice(work.Object, "status", "conditions")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _ := condition["type"].(string)
+		conditionStatus, _ := condition["status"].(string)
+		switch conditionType {
+		case "Applied":
+			status.Applied = conditionStatus
+		case "Available":
+			status.Available = conditionStatus
+		}
+	}
+	return status
+}
+
+// propagateToSpokes reconciles the ManifestWorks wrapping the pattern with the currently matching
+// ManagedClusters and refreshes the in-memory spoke statuses, persisted with the next status
+// update. The returned flag reports whether a work was created, updated or swept
+func (r *PatternReconciler) propagateToSpokes(p *api.Pattern) (error, bool) {
+	err, clusters := listManagedClusters(r.dynamicClient, p.Spec.MultiCluster.MatchLabels)
+	if err != nil {
+		return err, false
+	}
+
+	err, target := newManifestWork(*p)
+	if err != nil {
+		return err, false
+	}
+
+	changed := false
+	matched := map[string]bool{}
+	for _, cluster := range clusters {
+		matched[cluster] = true
+		_, work := getManifestWork(r.dynamicClient, cluster, applicationName(*p))
+		if work == nil {
+			log.Printf("Propagating pattern to managed cluster %q\n", cluster)
+			if err := createManifestWork(r.dynamicClient, cluster, target); err != nil {
+				return err, false
+			}
+			changed = true
+			continue
+		}
+		if err, updated := updateManifestWork(r.dynamicClient, cluster, target, work); err != nil {
+			return err, false
+		} else if updated {
+			changed = true
+		}
+	}
+
+	// sweep works for clusters that no longer match and aggregate the remaining spoke statuses
+	err, works := listPatternManifestWorks(r.dynamicClient, applicationName(*p))
+	if err != nil {
+		return err, changed
+	}
+	var spokes []api.PatternSpokeStatus
+	for i := range works {
+		cluster := works[i].GetNamespace()
+		if !matched[cluster] {
+			log.Printf("Removing pattern from managed cluster %q\n", cluster)
+			if err := removeManifestWork(r.dynamicClient, cluster, works[i].GetName()); err != nil {
+				return err, changed
+			}
+			changed = true
+			continue
+		}
+		spokes = append(spokes, spokeStatus(&works[i]))
+	}
+	p.Status.Spokes = spokes
+	return nil, changed
+}
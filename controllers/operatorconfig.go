@@ -0,0 +1,54 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// operatorConfig reads the cluster-scoped PatternsOperatorConfig singleton, so operator-wide
+// settings take effect on the next reconcile without a restart. A cluster without the singleton
+// runs on the built-in defaults. The observed generation is recorded on its status, so admins
+// can tell when an edit has been taken into account
+func (r *PatternReconciler) operatorConfig() api.PatternsOperatorConfigSpec {
+	var config api.PatternsOperatorConfig
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: api.PatternsOperatorConfigName}, &config); err != nil {
+		return api.PatternsOperatorConfigSpec{}
+	}
+	if config.Status.ObservedGeneration != config.Generation {
+		config.Status.ObservedGeneration = config.Generation
+		config.Status.LastApplied = metav1.Time{Time: time.Now()}
+		_ = r.Client.Status().Update(context.Background(), &config)
+	}
+	return config.Spec
+}
+
+// applyOperatorConfig applies the settings that act on the operator itself rather than on one
+// pattern: the telemetry opt-out takes effect immediately and the drift check concurrency
+// resizes the running worker pool of the watcher
+func (r *PatternReconciler) applyOperatorConfig(config api.PatternsOperatorConfigSpec) {
+	r.telemetry.setDisabled(config.TelemetryOptOut)
+	if w, ok := r.driftWatcher.(*watcher); ok {
+		w.setCheckConcurrency(config.MaxConcurrentDriftChecks)
+	}
+}
@@ -20,24 +20,32 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/go-errors/errors"
 	"github.com/go-logr/logr"
+	uberzap "go.uber.org/zap"
 
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 
 	"k8s.io/client-go/dynamic"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	klog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	argoclient "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
@@ -45,8 +53,9 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	argoapi "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
 
-	//	olmapi "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	olmapi "github.com/operator-framework/api/pkg/operators/v1alpha1"
 
 	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
 	operatorclient "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
@@ -56,6 +65,9 @@ import (
 type PatternReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// handle on the level of the operator log, so the runtime configuration ConfigMap can change
+	// the verbosity without a pod restart; a nil handle leaves the level alone
+	LogLevel *uberzap.AtomicLevel
 
 	logger logr.Logger
 
@@ -67,19 +79,49 @@ type PatternReconciler struct {
 	dynamicClient  dynamic.Interface
 	operatorClient operatorclient.OperatorV1Interface
 	driftWatcher   driftWatcher
+	recorder       record.EventRecorder
+	fileChecker    FileExistenceChecker
+	// upstream to mirror mappings for disconnected environments, read from the operator
+	// environment at startup
+	gitMirrors      mirrorMap
+	registryMirrors mirrorMap
+	// address of the vault API used by the Vault secret backend; derived from the secret store
+	// configuration when empty
+	vaultAddr string
+	// whether the cluster exposes the OpenShift configuration APIs; detected at startup and
+	// selecting between the OpenShift and the vanilla Kubernetes code paths
+	isOpenShift bool
+	// reports anonymized pattern lifecycle events; nil or endpoint-less reporters swallow them
+	telemetry *telemetryReporter
+	// location the Argo CD install manifests are fetched from on clusters without OLM; the
+	// pinned upstream release when empty
+	gitOpsManifests string
+	// resource version of the runtime configuration ConfigMap last applied, so an unchanged
+	// ConfigMap is not re-applied and re-logged on every reconcile
+	runtimeConfigVersion string
 }
 
 //+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=patterns,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=patterns/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=patterns/finalizers,verbs=update
+//+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=patterngitstatuses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=patterngitstatuses/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=patternsoperatorconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=patternsoperatorconfigs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=list;get
 //+kubebuilder:rbac:groups=config.openshift.io,resources=ingresses,verbs=list;get
 //+kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=list;get
-//+kubebuilder:rbac:groups="",resources=namespaces,verbs=list;get
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=list;get;create;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=list;get;create;update;patch;delete
 //+kubebuilder:rbac:groups=operators.coreos.com,resources=subscriptions,verbs=list;get;create;update;patch;delete
-//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;create;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;create;delete
 //+kubebuilder:rbac:groups="operator.open-cluster-management.io",resources=multiclusterhubs,verbs=get;list
+//+kubebuilder:rbac:groups="cluster.open-cluster-management.io",resources=managedclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups="work.open-cluster-management.io",resources=manifestworks,verbs=get;list;create;update;patch;delete
 //+kubebuilder:rbac:groups=operator.openshift.io,resources="openshiftcontrollermanagers",resources=openshiftcontrollermanagers,verbs=get;list
 //
 
@@ -143,6 +185,10 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return reconcile.Result{}, nil
 	}
 
+	// -- Operator-wide settings (the PatternsOperatorConfig singleton and the runtime ConfigMap)
+	r.applyOperatorConfig(r.operatorConfig())
+	r.applyRuntimeConfig()
+
 	// -- Fill in defaults (changes made to a copy and not persisted)
 	err, qualifiedInstance := r.applyDefaults(instance)
 	if err != nil {
@@ -153,20 +199,31 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return r.actionPerformed(qualifiedInstance, "prerequisite validation", err)
 	}
 
+	// -- Parameter sources (resolved on the copy, the stored pattern keeps the references)
+	if err := r.resolveParameters(qualifiedInstance); err != nil {
+		return r.actionPerformed(qualifiedInstance, "resolve parameter sources", err)
+	}
+
+	// -- Disconnected mirrors (rewritten on the copy, the stored pattern keeps the upstream URLs)
+	r.applyMirrors(qualifiedInstance)
+
 	// -- Git Drift monitoring
 	gitConfig := qualifiedInstance.Spec.GitConfig
-	// if both git repositories are defined in the pattern's git configuration and the polling interval is not set to disable watching
-	if gitConfig.OriginRepo != "" && gitConfig.TargetRepo != "" && gitConfig.PollInterval != -1 {
+	paused := driftMonitoringPaused(qualifiedInstance)
+	// if both git repositories are defined in the pattern's git configuration, the polling interval is not set to disable watching and monitoring is not paused
+	if gitConfig.OriginRepo != "" && gitConfig.TargetRepo != "" && gitConfig.PollInterval != -1 && !paused {
 		if !r.driftWatcher.isWatching(qualifiedInstance.Name, qualifiedInstance.Namespace) {
 			// start monitoring drifts for this pattern
 			err := r.driftWatcher.add(qualifiedInstance.Name,
 				qualifiedInstance.Namespace,
-				gitConfig.PollInterval)
+				gitConfig.PollInterval,
+				gitConfig.AlignChecks,
+				gitConfig.ComparisonMode)
 			if err != nil {
 				return r.actionPerformed(qualifiedInstance, "add pattern to git drift watcher", err)
 			}
 		} else {
-			err := r.driftWatcher.updateInterval(qualifiedInstance.Name, qualifiedInstance.Namespace, gitConfig.PollInterval)
+			err := r.driftWatcher.updateInterval(qualifiedInstance.Name, qualifiedInstance.Namespace, gitConfig.PollInterval, gitConfig.AlignChecks)
 			if err != nil {
 				return r.actionPerformed(qualifiedInstance, "update the watch interval to git drift watcher", err)
 			}
@@ -178,28 +235,48 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			return r.actionPerformed(qualifiedInstance, "remove pattern from git drift watcher", err)
 		}
 	}
+	if paused && gitConfig.OriginRepo != "" && gitConfig.TargetRepo != "" {
+		// surface the pause on the status so a quiet watcher is distinguishable from a broken one
+		if err := updatePatternConditions(r.Client, nil, api.GitDriftMonitoringPaused, nil, qualifiedInstance.Name, qualifiedInstance.Namespace, time.Now(), nil); err != nil {
+			return r.actionPerformed(qualifiedInstance, "record the drift monitoring pause", err)
+		}
+	}
+
+	if r.isOpenShift {
+		// -- GitOps Subscription
+		targetSub := newSubscription(*qualifiedInstance)
+		_ = controllerutil.SetOwnerReference(qualifiedInstance, targetSub, r.Scheme)
+
+		_, sub := getSubscription(r.olmClient, targetSub.Name, targetSub.Namespace)
+		if sub == nil {
+			err := createSubscription(r.olmClient, targetSub)
+			return r.actionPerformed(qualifiedInstance, "create gitops subscription", err)
+		} else if ownedBySame(targetSub, sub) {
+			// Check version/channel etc
+			// Dangerous if multiple patterns do not agree, or automatic upgrades are in place...
+			err, changed := updateSubscription(r.olmClient, targetSub, sub)
+			if changed {
+				return r.actionPerformed(qualifiedInstance, "update gitops subscription", err)
+			}
+		} else {
+			logOnce("The gitops subscription is not owned by us, leaving untouched")
+		}
 
-	// -- GitOps Subscription
-	targetSub := newSubscription(*qualifiedInstance)
-	_ = controllerutil.SetOwnerReference(qualifiedInstance, targetSub, r.Scheme)
+		logOnce("subscription found")
 
-	_, sub := getSubscription(r.olmClient, targetSub.Name, targetSub.Namespace)
-	if sub == nil {
-		err := createSubscription(r.olmClient, targetSub)
-		return r.actionPerformed(qualifiedInstance, "create gitops subscription", err)
-	} else if ownedBySame(targetSub, sub) {
-		// Check version/channel etc
-		// Dangerous if multiple patterns do not agree, or automatic upgrades are in place...
-		err, changed := updateSubscription(r.olmClient, targetSub, sub)
-		if changed {
-			return r.actionPerformed(qualifiedInstance, "update gitops subscription", err)
+		// -- GitOps InstallPlan approval
+		if sub != nil {
+			if err := r.checkInstallPlanApproval(qualifiedInstance, sub); err != nil {
+				return r.actionPerformed(qualifiedInstance, "check gitops install plan approval", err)
+			}
 		}
 	} else {
-		logOnce("The gitops subscription is not owned by us, leaving untouched")
+		// -- Plain Argo CD install (no OLM on vanilla Kubernetes or kind)
+		if err := r.ensureGitOpsOnKubernetes(qualifiedInstance, r.installGitOpsManifests); err != nil {
+			return r.actionPerformed(qualifiedInstance, "install gitops", err)
+		}
 	}
 
-	logOnce("subscription found")
-
 	// -- GitOps Namespace (created by the gitops operator)
 	if !haveNamespace(r.Client, applicationNamespace) {
 		return r.actionPerformed(qualifiedInstance, "check application namespace", fmt.Errorf("waiting for creation"))
@@ -207,6 +284,90 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	logOnce("namespace found")
 
+	// -- Dependencies on other patterns
+	if err := r.dependenciesReady(qualifiedInstance); err != nil {
+		return r.actionPerformed(qualifiedInstance, "wait for pattern dependencies", err)
+	}
+
+	// -- Declared namespaces and RBAC
+	if err := r.provisionResources(qualifiedInstance); err != nil {
+		return r.actionPerformed(qualifiedInstance, "provision pattern resources", err)
+	}
+
+	// -- Layered values files
+	if err := r.validateValueFiles(qualifiedInstance); err != nil {
+		return r.actionPerformed(qualifiedInstance, "validate values files", err)
+	}
+
+	// -- Secret store bootstrap
+	if err := r.reconcileSecretStore(qualifiedInstance); err != nil {
+		return r.actionPerformed(qualifiedInstance, "reconcile secret store", err)
+	}
+
+	// -- Values secrets loading
+	if err := r.loadPatternSecrets(qualifiedInstance); err != nil {
+		return r.actionPerformed(qualifiedInstance, "load pattern secrets", err)
+	}
+
+	// -- Sync windows
+	if err := r.enforceSyncWindows(qualifiedInstance); err != nil {
+		return r.actionPerformed(qualifiedInstance, "evaluate sync windows", err)
+	}
+
+	// -- Staged revision rollout
+	if inProgress, err := r.orchestrateUpgrade(qualifiedInstance); err != nil {
+		return r.actionPerformed(qualifiedInstance, "orchestrate upgrade", err)
+	} else if inProgress {
+		// the rollout owns the application revisions until it completes or rolls back
+		logOnce("staged rollout in progress, holding the regular application updates")
+		return ctrl.Result{RequeueAfter: upgradeRecheckInterval}, nil
+	}
+
+	// -- Multi-cluster propagation
+	if qualifiedInstance.Spec.MultiCluster != nil {
+		if err, changed := r.propagateToSpokes(qualifiedInstance); err != nil || changed {
+			return r.actionPerformed(qualifiedInstance, "propagate pattern to spoke clusters", err)
+		}
+	}
+
+	// -- ArgoCD ApplicationSet
+	if qualifiedInstance.Spec.ApplicationSet != nil {
+		err, targetAppSet := newApplicationSet(*qualifiedInstance)
+		if err != nil {
+			return r.actionPerformed(qualifiedInstance, "render applicationset", err)
+		}
+
+		_, appSet := getApplicationSet(r.dynamicClient, applicationName(*qualifiedInstance))
+		if appSet == nil {
+			log.Printf("ApplicationSet not found: %s\n", applicationName(*qualifiedInstance))
+			err := createApplicationSet(r.dynamicClient, targetAppSet)
+			return r.actionPerformed(qualifiedInstance, "create applicationset", err)
+		}
+		if err, changed := updateApplicationSet(r.dynamicClient, targetAppSet, appSet); changed {
+			return r.actionPerformed(qualifiedInstance, "updated applicationset", err)
+		}
+
+		if err := r.postValidation(qualifiedInstance); err != nil {
+			return r.actionPerformed(qualifiedInstance, "validation", err)
+		}
+
+		// roll the state of the generated applications up through the pattern label they carry
+		err, apps := listPatternApplications(r.argoClient, applicationName(*qualifiedInstance))
+		if err != nil {
+			return r.actionPerformed(qualifiedInstance, "list applicationset applications", err)
+		}
+		rollupApplicationStatus(qualifiedInstance, apps...)
+		if err := r.guardKnownGoodRevision(qualifiedInstance, apps, r.setApplicationRevision); err != nil {
+			return r.actionPerformed(qualifiedInstance, "guard known-good revision", err)
+		}
+		if err := r.updateReadyCondition(qualifiedInstance, apps...); err != nil {
+			return r.actionPerformed(qualifiedInstance, "update ready condition", err)
+		}
+
+		log.Printf("\x1b[32;1m\tReconcile complete\x1b[0m\n")
+		return ctrl.Result{}, nil
+	}
+
 	// -- ArgoCD Application
 	targetApp := newApplication(*qualifiedInstance)
 	_ = controllerutil.SetOwnerReference(qualifiedInstance, targetApp, r.Scheme)
@@ -214,6 +375,18 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	//log.Printf("Targeting: %s\n", objectYaml(targetApp))
 
 	err, app := getApplication(r.argoClient, applicationName(*qualifiedInstance))
+
+	// -- Dry-run preview
+	if qualifiedInstance.Annotations[previewAnnotation] == "true" {
+		if err := r.renderPreview(qualifiedInstance, app); err != nil {
+			return r.actionPerformed(qualifiedInstance, "render preview", err)
+		}
+		// hold the application until the user has inspected the diff; dropping the annotation
+		// applies the change
+		logOnce("preview rendered, leaving the application untouched until the annotation is removed")
+		return ctrl.Result{}, nil
+	}
+
 	if app == nil {
 		log.Printf("App not found: %s\n", err.Error())
 		err := createApplication(r.argoClient, targetApp)
@@ -238,6 +411,15 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err := r.postValidation(qualifiedInstance); err != nil {
 		return r.actionPerformed(qualifiedInstance, "validation", err)
 	}
+
+	// -- Application status roll-up and readiness
+	rollupApplicationStatus(qualifiedInstance, app)
+	if err := r.guardKnownGoodRevision(qualifiedInstance, []*argoapi.Application{app}, r.setApplicationRevision); err != nil {
+		return r.actionPerformed(qualifiedInstance, "guard known-good revision", err)
+	}
+	if err := r.updateReadyCondition(qualifiedInstance, app); err != nil {
+		return r.actionPerformed(qualifiedInstance, "update ready condition", err)
+	}
 	// Report statistics
 
 	log.Printf("\x1b[32;1m\tReconcile complete\x1b[0m\n")
@@ -281,6 +463,24 @@ func (r *PatternReconciler) applyDefaults(input *api.Pattern) (error, *api.Patte
 
 	output := input.DeepCopy()
 
+	// Cluster facts shared by both flavours: the node architecture of the API server and
+	// whether the cluster was installed in FIPS mode, both templated on by the pattern charts
+	output.Status.ClusterArchitecture = r.detectArchitecture()
+	if fips, err := r.detectFIPS(); err != nil {
+		return err, output
+	} else {
+		output.Status.FIPSEnabled = fips
+	}
+
+	if !r.isOpenShift {
+		// the OpenShift configuration APIs are not served; derive the cluster facts from what
+		// vanilla Kubernetes offers instead
+		if err := r.applyKubernetesDefaults(output); err != nil {
+			return err, output
+		}
+		return r.applyCommonDefaults(output), output
+	}
+
 	// Cluster ID:
 	// oc get clusterversion -o jsonpath='{.items[].spec.clusterID}{"\n"}'
 	// oc get clusterversion/version -o jsonpath='{.spec.clusterID}'
@@ -343,6 +543,16 @@ func (r *PatternReconciler) applyDefaults(input *api.Pattern) (error, *api.Patte
 	output.Status.AppClusterDomain = clusterIngress.Spec.Domain
 	output.Status.ClusterDomain = strings.Join(ss[1:], ".")
 
+	return r.applyCommonDefaults(output), output
+}
+
+// applyCommonDefaults fills in the spec defaults shared by the OpenShift and the vanilla
+// Kubernetes code paths, once the cluster facts have been derived. Operator-wide defaults from
+// the PatternsOperatorConfig singleton slot in between what the pattern declares and the
+// built-in fallbacks
+func (r *PatternReconciler) applyCommonDefaults(output *api.Pattern) error {
+	operatorDefaults := r.operatorConfig()
+
 	if output.Spec.GitOpsConfig == nil {
 		output.Spec.GitOpsConfig = &api.GitOpsConfig{}
 	}
@@ -362,6 +572,9 @@ func (r *PatternReconciler) applyDefaults(input *api.Pattern) (error, *api.Patte
 
 	if len(output.Spec.GitOpsConfig.OperatorChannel) == 0 {
 		output.Spec.GitOpsConfig.OperatorChannel = "stable"
+		if operatorDefaults.DefaultGitOpsChannel != "" {
+			output.Spec.GitOpsConfig.OperatorChannel = operatorDefaults.DefaultGitOpsChannel
+		}
 	}
 
 	if len(output.Spec.GitOpsConfig.OperatorSource) == 0 {
@@ -374,13 +587,53 @@ func (r *PatternReconciler) applyDefaults(input *api.Pattern) (error, *api.Patte
 		output.Spec.ClusterGroupName = "default"
 	}
 
+	if output.Spec.SecretStore != nil {
+		if len(output.Spec.SecretStore.VaultNamespace) == 0 {
+			output.Spec.SecretStore.VaultNamespace = defaultVaultNamespace
+		}
+		if len(output.Spec.SecretStore.ExternalSecretsNamespace) == 0 {
+			output.Spec.SecretStore.ExternalSecretsNamespace = defaultExternalSecretsNamespace
+		}
+	}
+
+	if output.Spec.GitConfig.PollInterval == 0 && operatorDefaults.DefaultPollIntervalSeconds > 0 {
+		output.Spec.GitConfig.PollInterval = operatorDefaults.DefaultPollIntervalSeconds
+	}
+
+	if output.Spec.GitConfig.Proxy == (api.ProxyConfig{}) && operatorDefaults.Proxy != nil {
+		output.Spec.GitConfig.Proxy = *operatorDefaults.Proxy
+	}
+
 	// interval cannot be less than 180 seconds to avoid drowning the API server in requests
 	// value of -1 effectivelly disables the watch for this pattern.
 	if output.Spec.GitConfig.PollInterval > -1 && output.Spec.GitConfig.PollInterval < 180 {
 		output.Spec.GitConfig.PollInterval = 180
 	}
 
-	return nil, output
+	return nil
+}
+
+// Teardown behavior when a pattern is deleted, selected through the teardown-mode annotation.
+// The default removes the ArgoCD application and lets the deletion cascade into everything it
+// instantiated; "orphan" leaves the deployed resources in place and "delete-and-wait"
+// additionally holds the finalizer until the workload namespaces are gone
+const (
+	teardownModeAnnotation    = "gitops.hybrid-cloud-patterns.io/teardown-mode"
+	teardownModeDelete        = "delete"
+	teardownModeOrphan        = "orphan"
+	teardownModeDeleteAndWait = "delete-and-wait"
+)
+
+// teardownMode returns the teardown behavior selected on the pattern, falling back to the
+// default cascading delete for a missing or unknown annotation value
+func teardownMode(p *api.Pattern) string {
+	switch p.Annotations[teardownModeAnnotation] {
+	case teardownModeOrphan:
+		return teardownModeOrphan
+	case teardownModeDeleteAndWait:
+		return teardownModeDeleteAndWait
+	}
+	return teardownModeDelete
 }
 
 func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
@@ -398,6 +651,48 @@ func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
 			return nil
 		}
 
+		if r.driftWatcher.isWatching(qualifiedInstance.Name, qualifiedInstance.Namespace) {
+			// Stop watching for drifts in the pattern's git repositories
+			if err := r.driftWatcher.remove(instance.Name, instance.Namespace); err != nil {
+				return err
+			}
+		}
+
+		if teardownMode(instance) != teardownModeOrphan {
+			if err := r.pruneProvisionedResources(qualifiedInstance); err != nil {
+				return err
+			}
+		}
+
+		if qualifiedInstance.Spec.MultiCluster != nil && teardownMode(instance) != teardownModeOrphan {
+			if err, works := listPatternManifestWorks(r.dynamicClient, applicationName(*qualifiedInstance)); err == nil && len(works) > 0 {
+				log.Printf("Removing the pattern from %d spoke clusters\n", len(works))
+				for i := range works {
+					if err := removeManifestWork(r.dynamicClient, works[i].GetNamespace(), works[i].GetName()); err != nil {
+						return err
+					}
+				}
+				return fmt.Errorf("waiting for the spoke clusters to drop pattern %q", qualifiedInstance.Name)
+			}
+		}
+
+		if qualifiedInstance.Spec.ApplicationSet != nil {
+			if teardownMode(instance) == teardownModeOrphan {
+				log.Printf("Leaving applicationset %q and the applications it generated in place\n", applicationName(*qualifiedInstance))
+				return nil
+			}
+			_, appSet := getApplicationSet(r.dynamicClient, applicationName(*qualifiedInstance))
+			if appSet == nil {
+				log.Printf("ApplicationSet has already been removed\n")
+				return nil
+			}
+			log.Printf("Removing the applicationset, and cascading to the applications it generated")
+			if err := removeApplicationSet(r.dynamicClient, applicationName(*qualifiedInstance)); err != nil {
+				return err
+			}
+			return fmt.Errorf("waiting for applicationset %q to be removed", applicationName(*qualifiedInstance))
+		}
+
 		targetApp := newApplication(*qualifiedInstance)
 		_ = controllerutil.SetOwnerReference(qualifiedInstance, targetApp, r.Scheme)
 
@@ -412,12 +707,13 @@ func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
 			return nil
 		}
 
-		if r.driftWatcher.isWatching(qualifiedInstance.Name, qualifiedInstance.Namespace) {
-			// Stop watching for drifts in the pattern's git repositories
-			if err := r.driftWatcher.remove(instance.Name, instance.Namespace); err != nil {
-				return err
-			}
+		if teardownMode(instance) == teardownModeOrphan {
+			// Leave the application and everything it deployed in place, only cutting the
+			// ownership link so the pattern can go away on its own
+			log.Printf("Orphaning application %q and the resources it deployed\n", app.Name)
+			return orphanApplication(r.argoClient, instance, app)
 		}
+
 		if _, changed := updateApplication(r.argoClient, targetApp, app); changed {
 			return fmt.Errorf("updated application %q for removal\n", app.Name)
 		}
@@ -434,6 +730,14 @@ func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
 		if err := removeApplication(r.argoClient, app.Name); err != nil {
 			return err
 		}
+
+		if teardownMode(instance) == teardownModeDeleteAndWait {
+			for _, ns := range applicationWorkloadNamespaces(app) {
+				if haveNamespace(r.Client, ns) {
+					return fmt.Errorf("waiting for workload namespace %q to be cleaned up", ns)
+				}
+			}
+		}
 		return fmt.Errorf("waiting for application %q to be removed\n", app.Name)
 	}
 
@@ -444,6 +748,7 @@ func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
 func (r *PatternReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	var err error
 	r.config = mgr.GetConfig()
+	r.recorder = mgr.GetEventRecorderFor("pattern-controller")
 
 	if r.configClient, err = configclient.NewForConfig(r.config); err != nil {
 		return err
@@ -461,6 +766,13 @@ func (r *PatternReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if r.isOpenShift, err = detectOpenShift(r.fullClient.Discovery()); err != nil {
+		return err
+	}
+	if !r.isOpenShift {
+		log.Printf("the OpenShift configuration APIs are not served; using the vanilla Kubernetes code paths")
+	}
+
 	if r.dynamicClient, err = dynamic.NewForConfig(r.config); err != nil {
 		return err
 	}
@@ -468,15 +780,365 @@ func (r *PatternReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.operatorClient, err = operatorclient.NewForConfig(r.config); err != nil {
 		return err
 	}
-	r.driftWatcher, _ = newDriftWatcher(r.Client, mgr.GetLogger(), newGitClient())
+	gitClient := newGitClient()
+	if ttl, err := strconv.Atoi(os.Getenv("GIT_DNS_CACHE_TTL_SECONDS")); err == nil && ttl > 0 {
+		gitClient = newGitClientWithDNSCache(time.Duration(ttl) * time.Second)
+	}
+	r.driftWatcher, _ = newDriftWatcher(r.Client, mgr.GetLogger(), gitClient, mgr.GetEventRecorderFor("drift-watcher"))
+	// every replica builds a watcher, but only the elected leader runs its timers; stand-by
+	// replicas keep theirs suspended until they win the election
+	r.driftWatcher.suspend()
+	if err := mgr.Add(&driftWatcherElector{watcher: r.driftWatcher}); err != nil {
+		return err
+	}
+	if err := mgr.Add(&driftWatcherDrainer{watcher: r.driftWatcher}); err != nil {
+		return err
+	}
+	r.fileChecker = newFileExistenceChecker()
+	analyticsEndpoint := os.Getenv(analyticsEndpointEnv)
+	if os.Getenv(analyticsDisabledEnv) != "" {
+		analyticsEndpoint = ""
+	}
+	r.telemetry = newTelemetryReporter(analyticsEndpoint)
+	r.gitMirrors = parseMirrorMap(os.Getenv("GIT_MIRRORS"))
+	r.registryMirrors = parseMirrorMap(os.Getenv("IMAGE_REGISTRY_MIRRORS"))
+	r.logger = mgr.GetLogger()
+	if addr := os.Getenv("STATUS_API_ADDR"); addr != "" {
+		go r.serveStatusAPI(addr)
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&api.Pattern{}).
+		Watches(&source.Kind{Type: &argoapi.Application{}}, handler.EnqueueRequestsFromMapFunc(r.mapApplicationToPattern)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.mapParameterSourceToPattern)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.mapRuntimeConfigMap)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.mapParameterSourceToPattern)).
 		Complete(r)
 }
 
+// mapApplicationToPattern enqueues the pattern an ArgoCD application was created for, so sync and
+// health transitions reported by Argo CD roll up into the pattern status without waiting for the
+// next periodic reconcile. Owner references do not carry the namespace of the pattern, so the
+// mapping goes through the application naming convention instead
+func (r *PatternReconciler) mapApplicationToPattern(object client.Object) []reconcile.Request {
+	app, ok := object.(*argoapi.Application)
+	if !ok || app.Namespace != applicationNamespace {
+		return nil
+	}
+	var patterns api.PatternList
+	if err := r.Client.List(context.Background(), &patterns); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range patterns.Items {
+		// the stored pattern may predate defaulting, so apply the same fallback the
+		// application name was derived with
+		named := patterns.Items[i]
+		if named.Spec.ClusterGroupName == "" {
+			named.Spec.ClusterGroupName = "default"
+		}
+		if applicationName(named) == app.Name {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      named.Name,
+				Namespace: named.Namespace,
+			}})
+		}
+	}
+	return requests
+}
+
+// validateValueFiles checks that the layered values files not marked optional exist in the
+// target repository and maintains the ValuesFileMissing condition. Optional files are left to
+// helm, which tolerates their absence through IgnoreMissingValueFiles
+func (r *PatternReconciler) validateValueFiles(p *api.Pattern) error {
+	var required []string
+	for _, file := range p.Spec.ValueFiles {
+		if !file.Optional {
+			required = append(required, file.Path)
+		}
+	}
+	if len(required) == 0 {
+		return nil
+	}
+
+	missing, err := r.fileChecker.MissingFiles(p.Spec.GitConfig.TargetRepo, p.Spec.GitConfig.TargetRevision, required)
+	if err != nil {
+		return err
+	}
+	timestamp := metav1.Time{Time: time.Now()}
+	if len(missing) > 0 {
+		setControllerCondition(p, api.ValuesFileMissing, corev1.ConditionTrue,
+			fmt.Sprintf("required values files missing from the target repository: %s", strings.Join(missing, ", ")), timestamp)
+		if err := r.Client.Status().Update(context.TODO(), p); err != nil {
+			return err
+		}
+		return fmt.Errorf("required values files missing from the target repository: %s", strings.Join(missing, ", "))
+	}
+	if _, condition := getPatternConditionByType(p.Status.Conditions, api.ValuesFileMissing); condition != nil && condition.Status != corev1.ConditionFalse {
+		setControllerCondition(p, api.ValuesFileMissing, corev1.ConditionFalse, "all required values files are present", timestamp)
+		return r.Client.Status().Update(context.TODO(), p)
+	}
+	return nil
+}
+
+// enforceSyncWindows evaluates the declared maintenance windows and, while they are closed,
+// forces manual sync on the working copy so the application loses its automated sync policy.
+// Drift remediation honours the same windows through the drift watcher. The SyncWindowClosed
+// condition tracks the outcome
+func (r *PatternReconciler) enforceSyncWindows(p *api.Pattern) error {
+	if p.Spec.SyncPolicy == nil || len(p.Spec.SyncPolicy.Windows) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	closed, err := syncWindowsClosed(p, now)
+	if err != nil {
+		return err
+	}
+	timestamp := metav1.Time{Time: now}
+	if closed {
+		p.Spec.GitOpsConfig.ManualSync = true
+		if _, condition := getPatternConditionByType(p.Status.Conditions, api.SyncWindowClosed); condition != nil && condition.Status == corev1.ConditionTrue {
+			return nil
+		}
+		setControllerCondition(p, api.SyncWindowClosed, corev1.ConditionTrue,
+			"automated sync is suspended outside the declared sync windows", timestamp)
+		return r.Client.Status().Update(context.TODO(), p)
+	}
+	if _, condition := getPatternConditionByType(p.Status.Conditions, api.SyncWindowClosed); condition != nil && condition.Status != corev1.ConditionFalse {
+		setControllerCondition(p, api.SyncWindowClosed, corev1.ConditionFalse, "a sync window is open", timestamp)
+		return r.Client.Status().Update(context.TODO(), p)
+	}
+	return nil
+}
+
+// checkInstallPlanApproval surfaces a gitops subscription blocked on a manual InstallPlan through
+// the SubscriptionUpgradePending condition and, when the pattern opts in, approves the pending
+// plan so the operator upgrade proceeds without a human in the loop
+func (r *PatternReconciler) checkInstallPlanApproval(p *api.Pattern, sub *olmapi.Subscription) error {
+	plan, err := pendingInstallPlan(r.olmClient, sub)
+	if err != nil {
+		return err
+	}
+	timestamp := metav1.Time{Time: time.Now()}
+	if plan != nil {
+		if p.Spec.GitOpsConfig.AutoApproveInstallPlans {
+			log.Printf("approving pending install plan %s for the gitops subscription\n", plan.Name)
+			return approveInstallPlan(r.olmClient, plan)
+		}
+		if _, condition := getPatternConditionByType(p.Status.Conditions, api.SubscriptionUpgradePending); condition != nil && condition.Status == corev1.ConditionTrue {
+			return nil
+		}
+		setControllerCondition(p, api.SubscriptionUpgradePending, corev1.ConditionTrue,
+			fmt.Sprintf("gitops subscription is waiting for manual approval of install plan %s", plan.Name), timestamp)
+		return r.Client.Status().Update(context.TODO(), p)
+	}
+	if _, condition := getPatternConditionByType(p.Status.Conditions, api.SubscriptionUpgradePending); condition != nil && condition.Status != corev1.ConditionFalse {
+		setControllerCondition(p, api.SubscriptionUpgradePending, corev1.ConditionFalse, "no install plan awaits approval", timestamp)
+		return r.Client.Status().Update(context.TODO(), p)
+	}
+	return nil
+}
+
+// resolveParameters fills in the extra parameters declared with a valueFrom reference by reading
+// the key from the ConfigMap or Secret in the pattern namespace
+func (r *PatternReconciler) resolveParameters(p *api.Pattern) error {
+	for i, param := range p.Spec.ExtraParameters {
+		if param.ValueFrom == nil {
+			continue
+		}
+		value, err := r.readParameterSource(p.Namespace, param.ValueFrom)
+		if err != nil {
+			return fmt.Errorf("parameter %q: %s", param.Name, err.Error())
+		}
+		p.Spec.ExtraParameters[i].Value = value
+	}
+	return nil
+}
+
+func (r *PatternReconciler) readParameterSource(namespace string, source *api.ParameterSource) (string, error) {
+	switch {
+	case source.ConfigMapName != "":
+		cm := &corev1.ConfigMap{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: source.ConfigMapName, Namespace: namespace}, cm); err != nil {
+			return "", err
+		}
+		if value, ok := cm.Data[source.Key]; ok {
+			return value, nil
+		}
+		return "", fmt.Errorf("key %q not found in configmap %q", source.Key, source.ConfigMapName)
+	case source.SecretName != "":
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: source.SecretName, Namespace: namespace}, secret); err != nil {
+			return "", err
+		}
+		if value, ok := secret.Data[source.Key]; ok {
+			return string(value), nil
+		}
+		return "", fmt.Errorf("key %q not found in secret %q", source.Key, source.SecretName)
+	}
+	return "", fmt.Errorf("neither a configmap nor a secret is referenced")
+}
+
+// mapParameterSourceToPattern enqueues the patterns whose extra parameters read from the changed
+// ConfigMap or Secret, so edits to the source objects re-render the application
+func (r *PatternReconciler) mapParameterSourceToPattern(object client.Object) []reconcile.Request {
+	var patterns api.PatternList
+	if err := r.Client.List(context.Background(), &patterns, client.InNamespace(object.GetNamespace())); err != nil {
+		return nil
+	}
+	_, isSecret := object.(*corev1.Secret)
+	var requests []reconcile.Request
+	for _, p := range patterns.Items {
+		for _, param := range p.Spec.ExtraParameters {
+			if param.ValueFrom == nil {
+				continue
+			}
+			name := param.ValueFrom.ConfigMapName
+			if isSecret {
+				name = param.ValueFrom.SecretName
+			}
+			if name == object.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      p.Name,
+					Namespace: p.Namespace,
+				}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// DriftWatcher exposes the watcher created during setup so the GitDriftCheck controller can
+// register its pairs with the same schedule and worker pool
+func (r *PatternReconciler) DriftWatcher() driftWatcher {
+	return r.driftWatcher
+}
+
+// dependenciesReady verifies that every pattern listed in dependsOn reports a true Ready
+// condition, so foundational patterns are fully applied before the application of a dependent
+// pattern is created
+func (r *PatternReconciler) dependenciesReady(p *api.Pattern) error {
+	for _, name := range p.Spec.DependsOn {
+		dep := &api.Pattern{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: p.Namespace}, dep); err != nil {
+			return fmt.Errorf("dependency pattern %q: %w", name, err)
+		}
+		_, condition := getPatternConditionByType(dep.Status.Conditions, api.Ready)
+		if condition == nil || condition.Status != corev1.ConditionTrue {
+			return fmt.Errorf("waiting for dependency pattern %q to become ready", name)
+		}
+	}
+	return nil
+}
+
+// updateReadyCondition rolls the state of the gitops deployment and the drift monitoring up into
+// a single Ready condition, so `kubectl get patterns` answers whether the pattern is usable
+// without reading the detailed conditions
+func (r *PatternReconciler) updateReadyCondition(p *api.Pattern, apps ...*argoapi.Application) error {
+	status, message := patternReadiness(p, apps...)
+	setControllerCondition(p, api.Ready, status, message, metav1.Time{Time: time.Now()})
+	p.Status.ObservedGeneration = p.Generation
+	return r.Client.Status().Update(context.TODO(), p)
+}
+
+// setControllerCondition adds or refreshes a condition maintained by the reconciler on the
+// in-memory status; the caller persists the status afterwards. The condition is written through
+// the standard metav1.Condition helpers so it carries the Reason and ObservedGeneration tooling
+// like kstatus expects
+func setControllerCondition(p *api.Pattern, conditionType api.PatternConditionType, status corev1.ConditionStatus, message string, timestamp metav1.Time) {
+	api.SetStatusCondition(&p.Status.Conditions, metav1.Condition{
+		Type:               string(conditionType),
+		Status:             metav1.ConditionStatus(status),
+		Reason:             conditionReason(conditionType, status),
+		Message:            message,
+		ObservedGeneration: p.Generation,
+		LastTransitionTime: timestamp,
+	}, timestamp)
+}
+
+// conditionReason derives the machine-readable reason recorded on a condition from its type and
+// status; the condition types are already CamelCase summaries of what is being reported
+func conditionReason(conditionType api.PatternConditionType, status corev1.ConditionStatus) string {
+	if status == corev1.ConditionTrue {
+		return string(conditionType)
+	}
+	return "Not" + string(conditionType)
+}
+
+// rollupApplicationStatus mirrors the sync and health state of the applications deployed for the
+// pattern onto its status and maintains the ApplicationDegraded condition. The condition is only
+// created once an application actually degrades, so healthy patterns do not accumulate noise
+func rollupApplicationStatus(p *api.Pattern, apps ...*argoapi.Application) {
+	var statuses []api.PatternApplicationStatus
+	degraded := ""
+	for _, app := range apps {
+		if app == nil {
+			continue
+		}
+		statuses = append(statuses, api.PatternApplicationStatus{
+			Name:               app.Name,
+			SyncStatus:         string(app.Status.Sync.Status),
+			HealthStatus:       string(app.Status.Health.Status),
+			LastSyncedRevision: app.Status.Sync.Revision,
+		})
+		if app.Status.Health.Status == health.HealthStatusDegraded {
+			degraded = app.Name
+		}
+	}
+	p.Status.Applications = statuses
+	timestamp := metav1.Time{Time: time.Now()}
+	if degraded != "" {
+		setControllerCondition(p, api.ApplicationDegraded, corev1.ConditionTrue,
+			fmt.Sprintf("application %s is degraded", degraded), timestamp)
+		return
+	}
+	if _, condition := getPatternConditionByType(p.Status.Conditions, api.ApplicationDegraded); condition != nil {
+		setControllerCondition(p, api.ApplicationDegraded, corev1.ConditionFalse,
+			"no application reports a degraded health state", timestamp)
+	}
+}
+
+// patternReadiness derives the Ready status from the health and sync states of the ArgoCD
+// applications and the drift conditions maintained by the watcher. The gitops operator itself is
+// covered implicitly: the reconciler only reaches the readiness update once the subscription and
+// the application namespace exist
+func patternReadiness(p *api.Pattern, apps ...*argoapi.Application) (corev1.ConditionStatus, string) {
+	deployed := 0
+	for _, app := range apps {
+		if app == nil {
+			continue
+		}
+		deployed++
+		if healthStatus := app.Status.Health.Status; healthStatus != "" && healthStatus != health.HealthStatusHealthy {
+			return corev1.ConditionFalse, fmt.Sprintf("application %s is %s", app.Name, healthStatus)
+		}
+		if syncStatus := app.Status.Sync.Status; syncStatus != "" && syncStatus != argoapi.SyncStatusCodeSynced {
+			return corev1.ConditionFalse, fmt.Sprintf("application %s is %s", app.Name, syncStatus)
+		}
+	}
+	if deployed == 0 {
+		return corev1.ConditionFalse, "waiting for the ArgoCD application to be created"
+	}
+	for _, condition := range p.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case api.GitOutOfSync:
+			return corev1.ConditionFalse, "the monitored git repositories have drifted apart"
+		case api.GitCheckFailed:
+			return corev1.ConditionFalse, "the last git drift check did not complete"
+		}
+	}
+	return corev1.ConditionTrue, "pattern deployed and in sync"
+}
+
 func (r *PatternReconciler) onReconcileErrorWithRequeue(p *api.Pattern, reason string, err error, duration *time.Duration) (reconcile.Result, error) {
 	// err is logged by the reconcileHandler
 	p.Status.LastStep = reason
+	// the status being written was computed from the spec at this generation
+	p.Status.ObservedGeneration = p.Generation
 	if err != nil {
 		p.Status.LastError = err.Error()
 		log.Printf("\x1b[31;1m\tReconcile step %q failed: %s\x1b[0m\n", reason, err.Error())
@@ -18,8 +18,14 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"time"
 
+	argoapi "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
 	"github.com/go-logr/logr"
 	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
@@ -28,16 +34,27 @@ import (
 	operatorv1 "github.com/openshift/api/operator/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned/fake"
 	operatorclient "github.com/openshift/client-go/operator/clientset/versioned/fake"
+	olmapi "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	olmclient "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	kversion "k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 const (
@@ -159,8 +176,1257 @@ var _ = Describe("pattern controller", func() {
 			Expect(watch.repoPairs[0].interval).To(Equal(defaultInterval))
 		})
 	})
+
+	var _ = Context("dependencies", func() {
+		var reconciler *PatternReconciler
+
+		BeforeEach(func() {
+			nsOperators := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+			dep := buildPatternManifest(10)
+			dep.Name = bar
+			reconciler = newFakeReconciler(nsOperators, buildPatternManifest(10), dep)
+		})
+
+		It("holds the pattern until its dependencies are ready", func() {
+			p := &api.Pattern{}
+			Expect(reconciler.Client.Get(context.Background(), patternNamespaced, p)).To(Succeed())
+			p.Spec.DependsOn = []string{bar}
+
+			By("waiting while the dependency has no Ready condition")
+			err := reconciler.dependenciesReady(p)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("waiting for dependency"))
+
+			By("continuing once the dependency reports ready")
+			dep := &api.Pattern{}
+			Expect(reconciler.Client.Get(context.Background(), types.NamespacedName{Name: bar, Namespace: namespace}, dep)).To(Succeed())
+			dep.Status.Conditions = []api.PatternCondition{{Type: api.Ready, Status: corev1.ConditionTrue}}
+			Expect(reconciler.Client.Status().Update(context.Background(), dep)).To(Succeed())
+			Expect(reconciler.dependenciesReady(p)).To(Succeed())
+
+			By("failing for a dependency that does not exist")
+			p.Spec.DependsOn = []string{"missing"}
+			Expect(reconciler.dependenciesReady(p)).NotTo(Succeed())
+		})
+	})
+
+	var _ = Context("readiness", func() {
+		var app *argoapi.Application
+
+		BeforeEach(func() {
+			app = &argoapi.Application{ObjectMeta: metav1.ObjectMeta{Name: foo}}
+			app.Status.Health.Status = health.HealthStatusHealthy
+			app.Status.Sync.Status = argoapi.SyncStatusCodeSynced
+		})
+
+		It("is not ready without an application", func() {
+			status, message := patternReadiness(&api.Pattern{}, nil)
+			Expect(status).To(Equal(corev1.ConditionFalse))
+			Expect(message).To(ContainSubstring("waiting for the ArgoCD application"))
+		})
+
+		It("is not ready while the application is degraded or out of sync", func() {
+			app.Status.Health.Status = health.HealthStatusDegraded
+			status, message := patternReadiness(&api.Pattern{}, app)
+			Expect(status).To(Equal(corev1.ConditionFalse))
+			Expect(message).To(ContainSubstring("Degraded"))
+
+			app.Status.Health.Status = health.HealthStatusHealthy
+			app.Status.Sync.Status = argoapi.SyncStatusCodeOutOfSync
+			status, message = patternReadiness(&api.Pattern{}, app)
+			Expect(status).To(Equal(corev1.ConditionFalse))
+			Expect(message).To(ContainSubstring("OutOfSync"))
+		})
+
+		It("is not ready while the git repositories have drifted", func() {
+			p := &api.Pattern{}
+			p.Status.Conditions = []api.PatternCondition{{Type: api.GitOutOfSync, Status: corev1.ConditionTrue}}
+			status, message := patternReadiness(p, app)
+			Expect(status).To(Equal(corev1.ConditionFalse))
+			Expect(message).To(ContainSubstring("drifted"))
+		})
+
+		It("mirrors the application states onto the status and flags degradation", func() {
+			p := &api.Pattern{}
+			app.Status.Sync.Revision = "abc123"
+			rollupApplicationStatus(p, app)
+			Expect(p.Status.Applications).To(HaveLen(1))
+			Expect(p.Status.Applications[0].Name).To(Equal(foo))
+			Expect(p.Status.Applications[0].SyncStatus).To(Equal("Synced"))
+			Expect(p.Status.Applications[0].HealthStatus).To(Equal("Healthy"))
+			Expect(p.Status.Applications[0].LastSyncedRevision).To(Equal("abc123"))
+			By("not creating the degraded condition while everything is healthy")
+			Expect(p.Status.Conditions).To(BeEmpty())
+
+			By("raising the condition when the application degrades")
+			app.Status.Health.Status = health.HealthStatusDegraded
+			rollupApplicationStatus(p, app)
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.ApplicationDegraded)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+
+			By("demoting the condition once the application recovers")
+			app.Status.Health.Status = health.HealthStatusHealthy
+			rollupApplicationStatus(p, app)
+			_, condition = getPatternConditionByType(p.Status.Conditions, api.ApplicationDegraded)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		})
+
+		It("is ready when the application is healthy, synced and in sync with git", func() {
+			p := &api.Pattern{}
+			p.Status.Conditions = []api.PatternCondition{{Type: api.GitInSync, Status: corev1.ConditionTrue}}
+			status, _ := patternReadiness(p, app)
+			Expect(status).To(Equal(corev1.ConditionTrue))
+		})
+	})
+
+	var _ = Context("observed generation", func() {
+		It("stamps the status with the generation it was computed from", func() {
+			nsOperators := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+			pattern := buildPatternManifest(10)
+			pattern.Generation = 4
+			reconciler := newFakeReconciler(nsOperators, pattern)
+			p := &api.Pattern{}
+			Expect(reconciler.Client.Get(context.Background(), patternNamespaced, p)).To(Succeed())
+			_, err := reconciler.actionPerformed(p, "updating status", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconciler.Client.Get(context.Background(), patternNamespaced, p)).To(Succeed())
+			Expect(p.Status.ObservedGeneration).To(Equal(p.Generation))
+			Expect(p.Status.LastStep).To(Equal("updating status"))
+		})
+	})
+
+	var _ = Context("condition model", func() {
+		It("records the reason and observed generation alongside the legacy fields", func() {
+			p := &api.Pattern{}
+			p.Generation = 7
+			first := metav1.Time{Time: time.Now().Add(-time.Minute).Truncate(time.Second)}
+			setControllerCondition(p, api.Ready, corev1.ConditionFalse, "waiting for the application", first)
+			Expect(p.Status.Conditions).To(HaveLen(1))
+			condition := p.Status.Conditions[0]
+			Expect(condition.Reason).To(Equal("NotReady"))
+			Expect(condition.ObservedGeneration).To(Equal(int64(7)))
+			Expect(condition.LastTransitionTime).To(Equal(first))
+
+			By("only moving the transition time when the status actually changes")
+			second := metav1.Time{Time: time.Now().Truncate(time.Second)}
+			setControllerCondition(p, api.Ready, corev1.ConditionFalse, "still waiting", second)
+			condition = p.Status.Conditions[0]
+			Expect(condition.LastTransitionTime).To(Equal(first))
+			Expect(condition.LastUpdateTime).To(Equal(second))
+			Expect(condition.Message).To(Equal("still waiting"))
+
+			setControllerCondition(p, api.Ready, corev1.ConditionTrue, "pattern is ready", second)
+			condition = p.Status.Conditions[0]
+			Expect(condition.Reason).To(Equal("Ready"))
+			Expect(condition.LastTransitionTime).To(Equal(second))
+		})
+
+		It("leaves the other conditions untouched when one is set", func() {
+			p := &api.Pattern{}
+			earlier := metav1.Time{Time: time.Now().Add(-time.Hour).Truncate(time.Second)}
+			setControllerCondition(p, api.ApplicationDegraded, corev1.ConditionTrue, "application foo is degraded", earlier)
+			setControllerCondition(p, api.Ready, corev1.ConditionFalse, "application foo is degraded", metav1.Time{Time: time.Now().Truncate(time.Second)})
+			_, condition := getPatternConditionByType(p.Status.Conditions, api.ApplicationDegraded)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.LastUpdateTime).To(Equal(earlier))
+			Expect(condition.Message).To(Equal("application foo is degraded"))
+		})
+
+		It("renders the conditions in the standard metav1 shape for external tooling", func() {
+			conditions := []api.PatternCondition{
+				{Type: api.GitOutOfSync, Status: corev1.ConditionTrue, Reason: "GitDriftDetected", ObservedGeneration: 3},
+				// a condition written before reasons were recorded
+				{Type: api.Ready, Status: corev1.ConditionFalse}}
+			rendered := api.AsMetaConditions(conditions)
+			Expect(rendered).To(HaveLen(2))
+			Expect(rendered[0].Type).To(Equal("GitOutOfSync"))
+			Expect(rendered[0].Status).To(Equal(metav1.ConditionTrue))
+			Expect(rendered[0].Reason).To(Equal("GitDriftDetected"))
+			Expect(rendered[0].ObservedGeneration).To(Equal(int64(3)))
+			Expect(rendered[1].Reason).To(Equal("Ready"), "the type backfills a missing reason")
+		})
+	})
+
+	var _ = Context("preview", func() {
+		It("publishes the effective source and its diff in a configmap", func() {
+			pattern := buildPatternManifest(10)
+			pattern.Spec.ClusterGroupName = "default"
+			pattern.Spec.GitConfig.TargetRevision = "v2"
+			reconciler := newFakeReconciler(pattern)
+
+			current := newApplication(*pattern)
+			current.Spec.Source.TargetRevision = "v1"
+
+			Expect(reconciler.renderPreview(pattern, current)).To(Succeed())
+			cm := &corev1.ConfigMap{}
+			Expect(reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "foo-preview", Namespace: namespace}, cm)).To(Succeed())
+			Expect(cm.Data["source"]).To(ContainSubstring("targetRevision: v2"))
+			Expect(cm.Data["diff"]).To(ContainSubstring("-targetRevision: v1"))
+			Expect(cm.Data["diff"]).To(ContainSubstring("+targetRevision: v2"))
+
+			By("refreshing the configmap on a later preview")
+			pattern.Spec.GitConfig.TargetRevision = "v3"
+			Expect(reconciler.renderPreview(pattern, current)).To(Succeed())
+			Expect(reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "foo-preview", Namespace: namespace}, cm)).To(Succeed())
+			Expect(cm.Data["diff"]).To(ContainSubstring("+targetRevision: v3"))
+		})
+	})
+
+	var _ = Context("layered values files", func() {
+		var reconciler *PatternReconciler
+		var pattern *api.Pattern
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Spec.ValueFiles = []api.PatternValueFile{
+				{Path: "/values-region.yaml"},
+				{Path: "/values-tuning.yaml", Optional: true},
+			}
+			reconciler = newFakeReconciler(pattern)
+		})
+
+		It("appends the layered files after the extra value files", func() {
+			pattern.Spec.ExtraValueFiles = []string{"/values-extra.yaml"}
+			files := newApplicationValueFiles(*pattern)
+			Expect(files[len(files)-3]).To(Equal("/values-extra.yaml"))
+			Expect(files[len(files)-2]).To(Equal("/values-region.yaml"))
+			Expect(files[len(files)-1]).To(Equal("/values-tuning.yaml"))
+		})
+
+		It("raises the condition when a required file is missing and demotes it once present", func() {
+			checker := &fakeFileChecker{missing: []string{"/values-region.yaml"}}
+			reconciler.fileChecker = checker
+
+			err := reconciler.validateValueFiles(pattern)
+			Expect(err).To(HaveOccurred())
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.ValuesFileMissing)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("/values-region.yaml"))
+			By("only checking the files that are not optional")
+			Expect(checker.checked).To(Equal([]string{"/values-region.yaml"}))
+
+			checker.missing = nil
+			Expect(reconciler.validateValueFiles(pattern)).To(Succeed())
+			_, condition = getPatternConditionByType(pattern.Status.Conditions, api.ValuesFileMissing)
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		})
+	})
+
+	var _ = Context("sync windows", func() {
+		var reconciler *PatternReconciler
+		var pattern *api.Pattern
+		// a Wednesday at 10:30
+		noon := time.Date(2022, time.June, 15, 10, 30, 0, 0, time.UTC)
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Spec.GitOpsConfig = &api.GitOpsConfig{}
+			reconciler = newFakeReconciler(pattern)
+		})
+
+		It("reports whether a window is active", func() {
+			window := api.SyncWindow{Kind: api.SyncWindowAllow, Schedule: "0 10 * * *", Duration: "1h"}
+			active, err := windowActive(window, noon)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeTrue())
+
+			window.Duration = "15m"
+			active, err = windowActive(window, noon)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeFalse())
+
+			window.Schedule = "not a schedule"
+			_, err = windowActive(window, noon)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("closes outside the allow windows and on active deny windows", func() {
+			pattern.Spec.SyncPolicy = &api.SyncPolicyConfig{Windows: []api.SyncWindow{
+				{Kind: api.SyncWindowAllow, Schedule: "0 10 * * *", Duration: "2h"},
+			}}
+			closed, err := syncWindowsClosed(pattern, noon)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(closed).To(BeFalse())
+
+			closed, err = syncWindowsClosed(pattern, noon.Add(4*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(closed).To(BeTrue())
+
+			pattern.Spec.SyncPolicy.Windows = append(pattern.Spec.SyncPolicy.Windows,
+				api.SyncWindow{Kind: api.SyncWindowDeny, Schedule: "0 10 * * 3", Duration: "1h"})
+			closed, err = syncWindowsClosed(pattern, noon)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(closed).To(BeTrue())
+		})
+
+		It("forces manual sync and maintains the condition", func() {
+			pattern.Spec.SyncPolicy = &api.SyncPolicyConfig{Windows: []api.SyncWindow{
+				{Kind: api.SyncWindowDeny, Schedule: "* * * * *", Duration: "1h"},
+			}}
+			Expect(reconciler.enforceSyncWindows(pattern)).To(Succeed())
+			Expect(pattern.Spec.GitOpsConfig.ManualSync).To(BeTrue())
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.SyncWindowClosed)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+
+			By("demoting the condition once the windows reopen")
+			pattern.Spec.SyncPolicy.Windows[0].Kind = api.SyncWindowAllow
+			Expect(reconciler.enforceSyncWindows(pattern)).To(Succeed())
+			_, condition = getPatternConditionByType(pattern.Status.Conditions, api.SyncWindowClosed)
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		})
+	})
+
+	var _ = Context("install plan approval", func() {
+		var reconciler *PatternReconciler
+		var pattern *api.Pattern
+		var sub *olmapi.Subscription
+		var plan *olmapi.InstallPlan
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Spec.GitOpsConfig = &api.GitOpsConfig{ManualApproval: true}
+			reconciler = newFakeReconciler(pattern)
+			plan = &olmapi.InstallPlan{
+				ObjectMeta: metav1.ObjectMeta{Name: "install-abcde", Namespace: namespace},
+				Spec:       olmapi.InstallPlanSpec{Approval: olmapi.ApprovalManual, Approved: false},
+			}
+			sub = &olmapi.Subscription{
+				ObjectMeta: metav1.ObjectMeta{Name: "openshift-gitops-operator", Namespace: namespace},
+				Status: olmapi.SubscriptionStatus{
+					State:          olmapi.SubscriptionStateUpgradePending,
+					InstallPlanRef: &corev1.ObjectReference{Name: plan.Name, Namespace: plan.Namespace},
+				},
+			}
+			reconciler.olmClient = olmclient.NewSimpleClientset(sub, plan)
+		})
+
+		It("raises the condition while a manual plan awaits approval and demotes it afterwards", func() {
+			Expect(reconciler.checkInstallPlanApproval(pattern, sub)).To(Succeed())
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.SubscriptionUpgradePending)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring(plan.Name))
+
+			By("demoting the condition once the plan has been approved out of band")
+			plan.Spec.Approved = true
+			_, err := reconciler.olmClient.OperatorsV1alpha1().InstallPlans(namespace).Update(context.Background(), plan, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconciler.checkInstallPlanApproval(pattern, sub)).To(Succeed())
+			_, condition = getPatternConditionByType(pattern.Status.Conditions, api.SubscriptionUpgradePending)
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		})
+
+		It("approves the pending plan on behalf of the user when the pattern opts in", func() {
+			pattern.Spec.GitOpsConfig.AutoApproveInstallPlans = true
+			Expect(reconciler.checkInstallPlanApproval(pattern, sub)).To(Succeed())
+
+			approved, err := reconciler.olmClient.OperatorsV1alpha1().InstallPlans(namespace).Get(context.Background(), plan.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(approved.Spec.Approved).To(BeTrue())
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.SubscriptionUpgradePending)
+			Expect(condition).To(BeNil())
+		})
+
+		It("leaves the conditions untouched while no upgrade is pending", func() {
+			sub.Status.State = olmapi.SubscriptionStateAtLatest
+			Expect(reconciler.checkInstallPlanApproval(pattern, sub)).To(Succeed())
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.SubscriptionUpgradePending)
+			Expect(condition).To(BeNil())
+		})
+	})
+
+	var _ = Context("vanilla Kubernetes support", func() {
+		var reconciler *PatternReconciler
+
+		It("tells the cluster flavours apart through the discovery API", func() {
+			clientset := kubefake.NewSimpleClientset()
+			openshift, err := detectOpenShift(clientset.Discovery())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(openshift).To(BeFalse())
+
+			discovery := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+			discovery.Resources = []*metav1.APIResourceList{{GroupVersion: "config.openshift.io/v1"}}
+			openshift, err = detectOpenShift(clientset.Discovery())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(openshift).To(BeTrue())
+		})
+
+		It("derives the cluster facts without the OpenShift configuration APIs", func() {
+			kubeSystem := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "abc-123"}}
+			reconciler = newFakeReconciler(kubeSystem, buildPatternManifest(10))
+			reconciler.isOpenShift = false
+			clientset := kubefake.NewSimpleClientset()
+			clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &kversion.Info{GitVersion: "v1.24.7"}
+			reconciler.fullClient = clientset
+
+			err, qualified := reconciler.applyDefaults(buildPatternManifest(10))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(qualified.Status.ClusterID).To(Equal("abc-123"))
+			Expect(qualified.Status.ClusterPlatform).To(Equal(kubernetesPlatform))
+			Expect(qualified.Status.ClusterVersion).To(Equal("1.24"))
+			Expect(qualified.Spec.ClusterGroupName).To(Equal("default"))
+		})
+
+		It("installs Argo CD from the manifests when OLM is absent", func() {
+			reconciler = newFakeReconciler(buildPatternManifest(10))
+			reconciler.isOpenShift = false
+			installed := 0
+			install := func() error { installed++; return nil }
+
+			err := reconciler.ensureGitOpsOnKubernetes(buildPatternManifest(10), install)
+			Expect(err).To(MatchError(ContainSubstring("waiting for the Argo CD install")))
+			Expect(installed).To(Equal(1))
+			Expect(haveNamespace(reconciler.Client, applicationNamespace)).To(BeTrue())
+
+			server := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: gitOpsServerDeployment, Namespace: applicationNamespace},
+				Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+			}
+			Expect(reconciler.Client.Create(context.Background(), server)).To(Succeed())
+			Expect(reconciler.ensureGitOpsOnKubernetes(buildPatternManifest(10), install)).To(Succeed())
+			Expect(installed).To(Equal(1))
+		})
+
+		It("splits the install manifests into their objects", func() {
+			manifests := []byte("apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: argocd-server\n---\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: argocd-server\n")
+			objects, err := decodeManifests(manifests)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objects).To(HaveLen(2))
+			Expect(objects[0].GetKind()).To(Equal("ServiceAccount"))
+			Expect(objects[1].GetKind()).To(Equal("Deployment"))
+		})
+	})
+
+	var _ = Context("cluster platform detection", func() {
+		var reconciler *PatternReconciler
+
+		It("reads the FIPS mode from the install config", func() {
+			clusterConfig := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-config-v1", Namespace: "kube-system"},
+				Data:       map[string]string{"install-config": "baseDomain: example.com\nfips: true\n"},
+			}
+			reconciler = newFakeReconciler(clusterConfig)
+			fips, err := reconciler.detectFIPS()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fips).To(BeTrue())
+		})
+
+		It("treats clusters without an install config as non-FIPS", func() {
+			reconciler = newFakeReconciler()
+			fips, err := reconciler.detectFIPS()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fips).To(BeFalse())
+		})
+
+		It("takes the architecture from the platform of the API server", func() {
+			reconciler = newFakeReconciler()
+			clientset := kubefake.NewSimpleClientset()
+			clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &kversion.Info{Platform: "linux/arm64"}
+			reconciler.fullClient = clientset
+			Expect(reconciler.detectArchitecture()).To(Equal("arm64"))
+		})
+
+		It("injects the detected facts as helm parameters", func() {
+			pattern := buildPatternManifest(10)
+			pattern.Status.ClusterArchitecture = "arm64"
+			pattern.Status.FIPSEnabled = true
+			parameters := newApplicationParameters(*pattern)
+			values := map[string]string{}
+			for _, parameter := range parameters {
+				values[parameter.Name] = parameter.Value
+			}
+			Expect(values).To(HaveKeyWithValue("global.clusterArchitecture", "arm64"))
+			Expect(values).To(HaveKeyWithValue("global.fipsEnabled", "true"))
+		})
+	})
+
+	var _ = Context("operator configuration", func() {
+		var reconciler *PatternReconciler
+		var config *api.PatternsOperatorConfig
+
+		BeforeEach(func() {
+			config = &api.PatternsOperatorConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: api.PatternsOperatorConfigName, Generation: 2},
+				Spec: api.PatternsOperatorConfigSpec{
+					DefaultPollIntervalSeconds: 300,
+					DefaultGitOpsChannel:       "gitops-1.8",
+					Proxy:                      &api.ProxyConfig{HTTPProxy: "http://proxy.corp:3128"},
+				},
+			}
+		})
+
+		It("slots the operator-wide defaults under what the pattern declares", func() {
+			pattern := buildPatternManifest(0)
+			reconciler = newFakeReconciler(config, pattern)
+			err, qualified := reconciler.applyDefaults(pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(qualified.Spec.GitOpsConfig.OperatorChannel).To(Equal("gitops-1.8"))
+			Expect(qualified.Spec.GitConfig.PollInterval).To(Equal(300))
+			Expect(qualified.Spec.GitConfig.Proxy.HTTPProxy).To(Equal("http://proxy.corp:3128"))
+		})
+
+		It("lets the pattern win over the operator-wide defaults", func() {
+			pattern := buildPatternManifest(240)
+			pattern.Spec.GitOpsConfig = &api.GitOpsConfig{OperatorChannel: "stable"}
+			pattern.Spec.GitConfig.Proxy = api.ProxyConfig{HTTPProxy: "http://other.corp:3128"}
+			reconciler = newFakeReconciler(config, pattern)
+			err, qualified := reconciler.applyDefaults(pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(qualified.Spec.GitOpsConfig.OperatorChannel).To(Equal("stable"))
+			Expect(qualified.Spec.GitConfig.PollInterval).To(Equal(240))
+			Expect(qualified.Spec.GitConfig.Proxy.HTTPProxy).To(Equal("http://other.corp:3128"))
+		})
+
+		It("applies the telemetry opt-out and drift concurrency live", func() {
+			reconciler = newFakeReconciler(config)
+			reconciler.telemetry = newTelemetryReporter("http://analytics.invalid")
+
+			config.Spec.TelemetryOptOut = true
+			config.Spec.MaxConcurrentDriftChecks = 2
+			reconciler.applyOperatorConfig(config.Spec)
+			reconciler.telemetry.record(telemetryEventInstalled, buildPatternManifest(10))
+			Expect(reconciler.telemetry.queue).To(BeEmpty())
+			Expect(reconciler.driftWatcher.(*watcher).checkConcurrency).To(Equal(2))
+
+			reconciler.applyOperatorConfig(api.PatternsOperatorConfigSpec{})
+			reconciler.telemetry.record(telemetryEventInstalled, buildPatternManifest(10))
+			Expect(reconciler.telemetry.queue).To(HaveLen(1))
+		})
+
+		It("records the generation it applied on the status", func() {
+			reconciler = newFakeReconciler(config)
+			_ = reconciler.operatorConfig()
+			var live api.PatternsOperatorConfig
+			Expect(reconciler.Client.Get(context.Background(), types.NamespacedName{Name: api.PatternsOperatorConfigName}, &live)).To(Succeed())
+			Expect(live.Status.ObservedGeneration).To(Equal(int64(2)))
+		})
+	})
+
+	var _ = Context("runtime configuration reload", func() {
+		var reconciler *PatternReconciler
+		var configMap *corev1.ConfigMap
+		var level uberzap.AtomicLevel
+
+		BeforeEach(func() {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: runtimeConfigMapName, Namespace: namespace},
+				Data: map[string]string{
+					runtimeConfigLogLevel:         "error",
+					runtimeConfigDriftConcurrency: "2",
+					runtimeConfigDriftTimeout:     "10",
+				},
+			}
+			level = uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
+		})
+
+		It("applies the log level and the drift check limits without a restart", func() {
+			reconciler = newFakeReconciler(configMap)
+			reconciler.LogLevel = &level
+			reconciler.applyRuntimeConfig()
+			Expect(level.Level()).To(Equal(zapcore.ErrorLevel))
+			Expect(reconciler.driftWatcher.(*watcher).checkConcurrency).To(Equal(2))
+			Expect(reconciler.driftWatcher.(*watcher).checkTimeout).To(Equal(10 * time.Second))
+		})
+
+		It("leaves values without a key alone and skips unchanged versions", func() {
+			configMap.Data = map[string]string{runtimeConfigDriftTimeout: "10"}
+			reconciler = newFakeReconciler(configMap)
+			reconciler.LogLevel = &level
+			reconciler.applyRuntimeConfig()
+			Expect(level.Level()).To(Equal(zapcore.InfoLevel))
+			Expect(reconciler.driftWatcher.(*watcher).checkConcurrency).To(Equal(defaultCheckConcurrency))
+
+			reconciler.driftWatcher.(*watcher).checkTimeout = time.Minute
+			reconciler.applyRuntimeConfig()
+			Expect(reconciler.driftWatcher.(*watcher).checkTimeout).To(Equal(time.Minute),
+				"expected an unchanged ConfigMap not to be re-applied")
+		})
+
+		It("ignores an invalid log level and applies the rest", func() {
+			configMap.Data[runtimeConfigLogLevel] = "chatty"
+			reconciler = newFakeReconciler(configMap)
+			reconciler.LogLevel = &level
+			reconciler.applyRuntimeConfig()
+			Expect(level.Level()).To(Equal(zapcore.InfoLevel))
+			Expect(reconciler.driftWatcher.(*watcher).checkConcurrency).To(Equal(2))
+		})
+
+		It("re-applies through the ConfigMap watch and publishes the effective configuration", func() {
+			reconciler = newFakeReconciler(configMap)
+			reconciler.LogLevel = &level
+			Expect(reconciler.mapRuntimeConfigMap(configMap)).To(BeEmpty())
+			Expect(effectiveConfigLabels()).To(HaveKeyWithValue("log_level", "error"))
+
+			configMap.Data[runtimeConfigLogLevel] = "debug"
+			Expect(reconciler.Client.Update(context.Background(), configMap)).To(Succeed())
+			Expect(reconciler.mapRuntimeConfigMap(configMap)).To(BeEmpty())
+			Expect(level.Level()).To(Equal(zapcore.DebugLevel))
+			Expect(effectiveConfigLabels()).To(HaveKeyWithValue("log_level", "debug"))
+			Expect(effectiveConfigLabels()).To(HaveKeyWithValue("drift_check_concurrency", "2"))
+		})
+	})
+
+	var _ = Context("telemetry reporting", func() {
+		var pattern *api.Pattern
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Status.ClusterID = "cluster-10"
+			pattern.Status.ClusterPlatform = "AWS"
+		})
+
+		It("sends anonymized events in one batch", func() {
+			var received map[string][]telemetryEvent
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				Expect(json.Unmarshal(body, &received)).To(Succeed())
+			}))
+			defer server.Close()
+
+			reporter := newTelemetryReporter(server.URL)
+			reporter.record(telemetryEventInstalled, pattern)
+			reporter.record(telemetryEventUpgraded, pattern)
+			Expect(reporter.flush()).To(Succeed())
+
+			Expect(received["batch"]).To(HaveLen(2))
+			Expect(received["batch"][0].Event).To(Equal(telemetryEventInstalled))
+			Expect(received["batch"][0].AnonymousID).NotTo(ContainSubstring("cluster-10"))
+			Expect(received["batch"][0].Properties).To(HaveKeyWithValue("platform", "AWS"))
+		})
+
+		It("honors the per-pattern opt-out annotation", func() {
+			reporter := newTelemetryReporter("http://analytics.invalid")
+			pattern.Annotations = map[string]string{analyticsOptOutAnnotation: "true"}
+			reporter.record(telemetryEventInstalled, pattern)
+			Expect(reporter.queue).To(BeEmpty())
+		})
+
+		It("swallows events when no endpoint is configured", func() {
+			reporter := newTelemetryReporter("")
+			reporter.record(telemetryEventInstalled, pattern)
+			Expect(reporter.queue).To(BeEmpty())
+		})
+
+		It("keeps undelivered events for the next flush", func() {
+			failures := 1
+			delivered := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if failures > 0 {
+					failures--
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				var batch map[string][]telemetryEvent
+				body, _ := io.ReadAll(r.Body)
+				Expect(json.Unmarshal(body, &batch)).To(Succeed())
+				delivered = len(batch["batch"])
+			}))
+			defer server.Close()
+
+			reporter := newTelemetryReporter(server.URL)
+			reporter.record(telemetryEventInstalled, pattern)
+			Expect(reporter.flush()).NotTo(Succeed())
+			Expect(reporter.queue).To(HaveLen(1))
+			Expect(reporter.flush()).To(Succeed())
+			Expect(delivered).To(Equal(1))
+		})
+	})
+
+	var _ = Context("resource provisioning", func() {
+		var reconciler *PatternReconciler
+		var pattern *api.Pattern
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Spec.Provision = &api.ProvisionConfig{
+				Namespaces: []string{"workload"},
+				ServiceAccounts: []api.ProvisionedServiceAccount{
+					{Name: "deployer", Namespace: "workload"},
+				},
+				RoleBindings: []api.ProvisionedRoleBinding{
+					{
+						Name:           "deployer-edit",
+						Namespace:      "workload",
+						ClusterRole:    "edit",
+						ServiceAccount: api.ProvisionedServiceAccount{Name: "deployer", Namespace: "workload"},
+					},
+				},
+			}
+		})
+
+		It("creates the declared resources with the pattern label", func() {
+			reconciler = newFakeReconciler(pattern)
+			Expect(reconciler.provisionResources(pattern)).To(Succeed())
+
+			var ns corev1.Namespace
+			Expect(reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "workload"}, &ns)).To(Succeed())
+			Expect(ns.Labels).To(HaveKeyWithValue("pattern", "foo-default"))
+
+			var sa corev1.ServiceAccount
+			Expect(reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "deployer", Namespace: "workload"}, &sa)).To(Succeed())
+			Expect(sa.Labels).To(HaveKeyWithValue("pattern", "foo-default"))
+
+			var binding rbacv1.RoleBinding
+			Expect(reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "deployer-edit", Namespace: "workload"}, &binding)).To(Succeed())
+			Expect(binding.RoleRef.Kind).To(Equal("ClusterRole"))
+			Expect(binding.RoleRef.Name).To(Equal("edit"))
+			Expect(binding.Subjects).To(HaveLen(1))
+			Expect(binding.Subjects[0].Name).To(Equal("deployer"))
+		})
+
+		It("leaves resources that already exist untouched", func() {
+			existing := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "workload", Labels: map[string]string{"owner": "someone-else"}},
+			}
+			reconciler = newFakeReconciler(pattern, existing)
+			Expect(reconciler.provisionResources(pattern)).To(Succeed())
+
+			var ns corev1.Namespace
+			Expect(reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "workload"}, &ns)).To(Succeed())
+			Expect(ns.Labels).To(HaveKeyWithValue("owner", "someone-else"))
+			Expect(ns.Labels).NotTo(HaveKey("pattern"))
+		})
+
+		It("prunes the declared resources and tolerates ones already gone", func() {
+			reconciler = newFakeReconciler(pattern)
+			Expect(reconciler.provisionResources(pattern)).To(Succeed())
+			Expect(reconciler.pruneProvisionedResources(pattern)).To(Succeed())
+
+			var binding rbacv1.RoleBinding
+			err := reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "deployer-edit", Namespace: "workload"}, &binding)
+			Expect(err).To(HaveOccurred())
+
+			// a second prune finds nothing left and still succeeds
+			Expect(reconciler.pruneProvisionedResources(pattern)).To(Succeed())
+		})
+
+		It("does nothing for patterns without provisioned resources", func() {
+			pattern.Spec.Provision = nil
+			reconciler = newFakeReconciler(pattern)
+			Expect(reconciler.provisionResources(pattern)).To(Succeed())
+			Expect(reconciler.pruneProvisionedResources(pattern)).To(Succeed())
+		})
+	})
+
+	var _ = Context("secret store bootstrap", func() {
+		var reconciler *PatternReconciler
+		var pattern *api.Pattern
+		var vault *appsv1.StatefulSet
+		var eso *appsv1.Deployment
+		var valuesSecret *corev1.Secret
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Spec.SecretStore = &api.SecretStoreConfig{
+				VaultNamespace:           defaultVaultNamespace,
+				ExternalSecretsNamespace: defaultExternalSecretsNamespace,
+				ValuesSecretName:         "values-secret",
+			}
+			vault = &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: vaultStatefulSetName, Namespace: defaultVaultNamespace},
+				Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+			}
+			eso = &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: externalSecretsDeploymentName, Namespace: defaultExternalSecretsNamespace},
+				Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+			}
+			valuesSecret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "values-secret", Namespace: namespace},
+				Data:       map[string][]byte{valuesSecretKey: []byte("secrets:\n")},
+			}
+		})
+
+		outcome := func() *api.PatternCondition {
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.SecretStoreReady)
+			return condition
+		}
+
+		It("reports the store ready when all components are in place", func() {
+			reconciler = newFakeReconciler(pattern, vault, eso, valuesSecret)
+			Expect(reconciler.reconcileSecretStore(pattern)).To(Succeed())
+			condition := outcome()
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		})
+
+		It("lists every missing component as a blocker", func() {
+			reconciler = newFakeReconciler(pattern)
+			Expect(reconciler.reconcileSecretStore(pattern)).To(Succeed())
+			condition := outcome()
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("no vault instance found in namespace vault"))
+			Expect(condition.Message).To(ContainSubstring("external secrets operator not found"))
+			Expect(condition.Message).To(ContainSubstring("values secret values-secret not found"))
+		})
+
+		It("flags a deployed but unready vault and a secret without the expected key", func() {
+			vault.Status.ReadyReplicas = 0
+			valuesSecret.Data = map[string][]byte{"other.yaml": []byte("")}
+			reconciler = newFakeReconciler(pattern, vault, eso, valuesSecret)
+			Expect(reconciler.reconcileSecretStore(pattern)).To(Succeed())
+			condition := outcome()
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("sealed or not yet initialized"))
+			Expect(condition.Message).To(ContainSubstring("does not hold a values-secret.yaml entry"))
+		})
+
+		It("does nothing for patterns without a secret store", func() {
+			pattern.Spec.SecretStore = nil
+			reconciler = newFakeReconciler(pattern)
+			Expect(reconciler.reconcileSecretStore(pattern)).To(Succeed())
+			Expect(outcome()).To(BeNil())
+		})
+	})
+
+	var _ = Context("staged revision rollout", func() {
+		var reconciler *PatternReconciler
+		var pattern *api.Pattern
+		var apps []*argoapi.Application
+		var moved map[string]string
+
+		app := func(name, wave, revision string) *argoapi.Application {
+			return &argoapi.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        name,
+					Namespace:   applicationNamespace,
+					Annotations: map[string]string{syncWaveAnnotation: wave},
+				},
+				Spec: argoapi.ApplicationSpec{Source: argoapi.ApplicationSource{TargetRevision: revision}},
+				Status: argoapi.ApplicationStatus{
+					Health: argoapi.HealthStatus{Status: health.HealthStatusHealthy},
+					Sync:   argoapi.SyncStatus{Status: argoapi.SyncStatusCodeSynced},
+				},
+			}
+		}
+
+		setRevision := func(a *argoapi.Application, revision string) error {
+			moved[a.Name] = revision
+			a.Spec.Source.TargetRevision = revision
+			return nil
+		}
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Spec.GitConfig.TargetRevision = "v2"
+			pattern.Status.AppliedRevision = "v1"
+			reconciler = newFakeReconciler(pattern)
+			apps = []*argoapi.Application{app("infra", "1", "v1"), app("workload", "2", "v1")}
+			moved = map[string]string{}
+		})
+
+		It("initializes the applied revision on the first deployment", func() {
+			pattern.Status.AppliedRevision = ""
+			inProgress, err := reconciler.orchestrateUpgrade(pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inProgress).To(BeFalse())
+			Expect(pattern.Status.AppliedRevision).To(Equal("v2"))
+		})
+
+		It("moves the applications wave by wave and completes once all are healthy", func() {
+			inProgress, err := reconciler.advanceUpgrade(pattern, apps, setRevision)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inProgress).To(BeTrue())
+			Expect(moved).To(Equal(map[string]string{"infra": "v2"}))
+			Expect(pattern.Status.Upgrade).NotTo(BeNil())
+			Expect(pattern.Status.Upgrade.Wave).To(Equal(1))
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.PatternUpgrading)
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+
+			By("following with the next wave once the first is healthy again")
+			inProgress, err = reconciler.advanceUpgrade(pattern, apps, setRevision)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inProgress).To(BeTrue())
+			Expect(moved["workload"]).To(Equal("v2"))
+
+			By("completing once every wave reports healthy on the new revision")
+			inProgress, err = reconciler.advanceUpgrade(pattern, apps, setRevision)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inProgress).To(BeFalse())
+			Expect(pattern.Status.AppliedRevision).To(Equal("v2"))
+			Expect(pattern.Status.Upgrade).To(BeNil())
+			_, condition = getPatternConditionByType(pattern.Status.Conditions, api.PatternUpgrading)
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		})
+
+		It("waits on a degraded application within the timeout", func() {
+			_, err := reconciler.advanceUpgrade(pattern, apps, setRevision)
+			Expect(err).NotTo(HaveOccurred())
+			apps[0].Status.Health.Status = health.HealthStatusDegraded
+
+			inProgress, err := reconciler.advanceUpgrade(pattern, apps, setRevision)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inProgress).To(BeTrue())
+			Expect(moved).NotTo(HaveKey("workload"))
+			Expect(pattern.Spec.GitConfig.TargetRevision).To(Equal("v2"))
+		})
+
+		It("rolls the revision back when an application stays degraded past the timeout", func() {
+			_, err := reconciler.advanceUpgrade(pattern, apps, setRevision)
+			Expect(err).NotTo(HaveOccurred())
+			apps[0].Status.Health.Status = health.HealthStatusDegraded
+			pattern.Status.Upgrade.StartTime = metav1.Time{Time: time.Now().Add(-20 * time.Minute)}
+
+			inProgress, err := reconciler.advanceUpgrade(pattern, apps, setRevision)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inProgress).To(BeTrue())
+			Expect(moved["infra"]).To(Equal("v1"))
+			Expect(pattern.Spec.GitConfig.TargetRevision).To(Equal("v1"))
+			Expect(pattern.Status.Upgrade).To(BeNil())
+
+			var current api.Pattern
+			Expect(reconciler.Client.Get(context.Background(), patternNamespaced, &current)).To(Succeed())
+			Expect(current.Spec.GitConfig.TargetRevision).To(Equal("v1"))
+			_, condition := getPatternConditionByType(pattern.Status.Conditions, api.UpgradeRolledBack)
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("infra"))
+		})
+	})
+
+	var _ = Context("known-good revision guard", func() {
+		var reconciler *PatternReconciler
+		var pattern *api.Pattern
+		var apps []*argoapi.Application
+		var moved map[string]string
+
+		app := func(name, revision string) *argoapi.Application {
+			return &argoapi.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: applicationNamespace},
+				Spec:       argoapi.ApplicationSpec{Source: argoapi.ApplicationSource{TargetRevision: revision}},
+				Status: argoapi.ApplicationStatus{
+					Health: argoapi.HealthStatus{Status: health.HealthStatusHealthy},
+					Sync:   argoapi.SyncStatus{Status: argoapi.SyncStatusCodeSynced},
+				},
+			}
+		}
+
+		setRevision := func(a *argoapi.Application, revision string) error {
+			moved[a.Name] = revision
+			a.Spec.Source.TargetRevision = revision
+			return nil
+		}
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Spec.GitConfig.TargetRevision = "v2"
+			pattern.Status.AppliedRevision = "v2"
+			pattern.Status.LastKnownGoodRevision = "v1"
+			reconciler = newFakeReconciler(pattern)
+			apps = []*argoapi.Application{app("infra", "v2"), app("workload", "v2")}
+			moved = map[string]string{}
+		})
+
+		It("records the applied revision as known good once everything settles", func() {
+			Expect(reconciler.guardKnownGoodRevision(pattern, apps, setRevision)).To(Succeed())
+			Expect(pattern.Status.LastKnownGoodRevision).To(Equal("v2"))
+			Expect(moved).To(BeEmpty())
+		})
+
+		It("counts unhealthy applications towards the deadline without acting early", func() {
+			apps[0].Status.Sync.Status = argoapi.SyncStatusCodeOutOfSync
+			Expect(reconciler.guardKnownGoodRevision(pattern, apps, setRevision)).To(Succeed())
+			Expect(pattern.Status.UnhealthySince).NotTo(BeNil())
+
+			Expect(reconciler.guardKnownGoodRevision(pattern, apps, setRevision)).To(Succeed())
+			Expect(moved).To(BeEmpty())
+			Expect(pattern.Spec.GitConfig.TargetRevision).To(Equal("v2"))
+		})
+
+		It("reverts to the last known-good revision past the deadline and emits an event", func() {
+			apps[1].Status.Health.Status = health.HealthStatusDegraded
+			pattern.Status.UnhealthySince = &metav1.Time{Time: time.Now().Add(-20 * time.Minute)}
+
+			Expect(reconciler.guardKnownGoodRevision(pattern, apps, setRevision)).To(Succeed())
+			Expect(moved).To(Equal(map[string]string{"infra": "v1", "workload": "v1"}))
+			Expect(pattern.Status.AppliedRevision).To(Equal("v1"))
+			Expect(pattern.Status.UnhealthySince).To(BeNil())
+			Expect(pattern.Spec.GitConfig.TargetRevision).To(Equal("v1"))
+
+			var event string
+			Eventually(reconciler.recorder.(*record.FakeRecorder).Events).Should(Receive(&event))
+			Expect(event).To(ContainSubstring(eventReasonRevisionRolledBack))
+			Expect(event).To(ContainSubstring("workload"))
+		})
+
+		It("recovers the unhealthy marker once the applications settle again", func() {
+			pattern.Status.UnhealthySince = &metav1.Time{Time: time.Now()}
+			Expect(reconciler.guardKnownGoodRevision(pattern, apps, setRevision)).To(Succeed())
+			Expect(pattern.Status.UnhealthySince).To(BeNil())
+			Expect(pattern.Status.LastKnownGoodRevision).To(Equal("v2"))
+		})
+	})
+
+	var _ = Context("values secret loading", func() {
+		var reconciler *PatternReconciler
+		var pattern *api.Pattern
+		var source *corev1.Secret
+
+		document := "secrets:\n  aws:\n    access_key_id: AKIA\n    secret_access_key: hush\n"
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Spec.ClusterGroupName = "default"
+			pattern.Spec.GitConfig.SecretsRef = "pattern-secrets"
+			source = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "pattern-secrets", Namespace: namespace},
+				Data:       map[string][]byte{valuesSecretKey: []byte(document)},
+			}
+		})
+
+		It("materializes the groups as Secrets and pushes again only on change", func() {
+			reconciler = newFakeReconciler(pattern, source)
+			Expect(reconciler.loadPatternSecrets(pattern)).To(Succeed())
+
+			var pushed corev1.Secret
+			Expect(reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "foo-default-aws", Namespace: namespace}, &pushed)).To(Succeed())
+			Expect(pushed.Data["access_key_id"]).To(Equal([]byte("AKIA")))
+			Expect(pushed.Labels["pattern"]).To(Equal("foo-default"))
+			version := pattern.Status.LoadedSecretsVersion
+			Expect(version).NotTo(BeEmpty())
+
+			By("skipping the push while the document is unchanged")
+			Expect(reconciler.loadPatternSecrets(pattern)).To(Succeed())
+			Expect(pattern.Status.LoadedSecretsVersion).To(Equal(version))
+
+			By("pushing the updated entries when the document changes")
+			source.Data[valuesSecretKey] = []byte("secrets:\n  aws:\n    access_key_id: ROTATED\n")
+			Expect(reconciler.Client.Update(context.Background(), source)).To(Succeed())
+			Expect(reconciler.loadPatternSecrets(pattern)).To(Succeed())
+			Expect(reconciler.Client.Get(context.Background(), types.NamespacedName{Name: "foo-default-aws", Namespace: namespace}, &pushed)).To(Succeed())
+			Expect(pushed.Data["access_key_id"]).To(Equal([]byte("ROTATED")))
+			Expect(pattern.Status.LoadedSecretsVersion).NotTo(Equal(version))
+		})
+
+		It("writes the groups through the vault API when the Vault backend is selected", func() {
+			var gotPath, gotToken string
+			var gotBody map[string]map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				gotToken = r.Header.Get("X-Vault-Token")
+				body, _ := io.ReadAll(r.Body)
+				Expect(json.Unmarshal(body, &gotBody)).To(Succeed())
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			pattern.Spec.SecretStore = &api.SecretStoreConfig{Backend: secretBackendVault, VaultNamespace: defaultVaultNamespace}
+			token := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: vaultTokenSecret, Namespace: defaultVaultNamespace},
+				Data:       map[string][]byte{vaultTokenKey: []byte("s.roottoken")},
+			}
+			reconciler = newFakeReconciler(pattern, source, token)
+			reconciler.vaultAddr = server.URL
+
+			Expect(reconciler.loadPatternSecrets(pattern)).To(Succeed())
+			Expect(gotPath).To(Equal("/v1/secret/data/hub/aws"))
+			Expect(gotToken).To(Equal("s.roottoken"))
+			Expect(gotBody["data"]).To(HaveKeyWithValue("access_key_id", "AKIA"))
+		})
+
+		It("fails when the referenced Secret misses the document", func() {
+			source.Data = map[string][]byte{"other.yaml": []byte("")}
+			reconciler = newFakeReconciler(pattern, source)
+			err := reconciler.loadPatternSecrets(pattern)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(valuesSecretKey))
+		})
+	})
+
+	var _ = Context("parameter sources", func() {
+		var reconciler *PatternReconciler
+		var pattern *api.Pattern
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Spec.ExtraParameters = []api.PatternParameter{
+				{Name: "global.token", ValueFrom: &api.ParameterSource{SecretName: bar, Key: "token"}},
+				{Name: "global.region", ValueFrom: &api.ParameterSource{ConfigMapName: bar, Key: "region"}},
+			}
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: bar, Namespace: namespace},
+				Data:       map[string]string{"region": "eu-west-1"},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: bar, Namespace: namespace},
+				Data:       map[string][]byte{"token": []byte("hush")},
+			}
+			reconciler = newFakeReconciler(pattern, cm, secret)
+		})
+
+		It("resolves values from the referenced objects", func() {
+			Expect(reconciler.resolveParameters(pattern)).To(Succeed())
+			Expect(pattern.Spec.ExtraParameters[0].Value).To(Equal("hush"))
+			Expect(pattern.Spec.ExtraParameters[1].Value).To(Equal("eu-west-1"))
+		})
+
+		It("fails when the referenced key is missing", func() {
+			pattern.Spec.ExtraParameters[1].ValueFrom.Key = "unknown"
+			err := reconciler.resolveParameters(pattern)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("global.region"))
+		})
+
+		It("enqueues the referencing pattern when a source object changes", func() {
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: bar, Namespace: namespace}}
+			Expect(reconciler.mapParameterSourceToPattern(cm)).To(HaveLen(1))
+
+			By("ignoring objects no parameter reads from")
+			other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: namespace}}
+			Expect(reconciler.mapParameterSourceToPattern(other)).To(BeEmpty())
+		})
+	})
+
+	var _ = Context("disconnected mirrors", func() {
+		It("rewrites matching prefixes, preferring the longest one", func() {
+			mirrors := parseMirrorMap("https://github.com=https://git.internal, https://github.com/acme=https://git.internal/acme-mirror,broken entry")
+			Expect(mirrors).To(HaveLen(2))
+			Expect(mirrors.resolve("https://github.com/foo/bar.git")).To(Equal("https://git.internal/foo/bar.git"))
+			Expect(mirrors.resolve("https://github.com/acme/bar.git")).To(Equal("https://git.internal/acme-mirror/bar.git"))
+			Expect(mirrors.resolve("https://gitlab.com/foo/bar.git")).To(Equal("https://gitlab.com/foo/bar.git"))
+			Expect(parseMirrorMap("")).To(BeNil())
+		})
+
+		It("rewrites the working copy and exposes the registry mirrors", func() {
+			pattern := buildPatternManifest(10)
+			pattern.Spec.GitConfig.OriginRepo = originURL
+			reconciler := newFakeReconciler(pattern)
+			reconciler.gitMirrors = parseMirrorMap("https://origin.url=https://mirror.internal/origin,https://target.url=https://mirror.internal/target")
+			reconciler.registryMirrors = parseMirrorMap("quay.io=registry.internal/quay,registry.redhat.io=registry.internal/redhat")
+
+			reconciler.applyMirrors(pattern)
+			Expect(pattern.Spec.GitConfig.TargetRepo).To(Equal("https://mirror.internal/target"))
+			Expect(pattern.Spec.GitConfig.OriginRepo).To(Equal("https://mirror.internal/origin"))
+			Expect(pattern.Spec.ExtraParameters).To(HaveLen(1))
+			Expect(pattern.Spec.ExtraParameters[0].Name).To(Equal("global.registryMirrors"))
+			Expect(pattern.Spec.ExtraParameters[0].Value).To(Equal("quay.io=registry.internal/quay,registry.redhat.io=registry.internal/redhat"))
+		})
+	})
+
+	var _ = Context("multi-cluster propagation", func() {
+		It("wraps the pattern in a manifestwork without the fan-out section", func() {
+			pattern := buildPatternManifest(10)
+			pattern.Spec.ClusterGroupName = "default"
+			pattern.Spec.MultiCluster = &api.MultiClusterConfig{MatchLabels: map[string]string{"env": "prod"}}
+
+			err, work := newManifestWork(*pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(work.GetKind()).To(Equal("ManifestWork"))
+			Expect(work.GetName()).To(Equal("foo-default"))
+
+			manifests, _, _ := unstructured.NestedSlice(work.Object, "spec", "workload", "manifests")
+			Expect(manifests).To(HaveLen(1))
+			manifest := manifests[0].(map[string]interface{})
+			Expect(manifest["kind"]).To(Equal("Pattern"))
+			Expect(manifest["apiVersion"]).To(Equal(api.GroupVersion.String()))
+			By("dropping the multiCluster section so the spoke does not fan out again")
+			_, found, _ := unstructured.NestedMap(manifest, "spec", "multiCluster")
+			Expect(found).To(BeFalse())
+			repo, _, _ := unstructured.NestedString(manifest, "spec", "gitSpec", "targetRepo")
+			Expect(repo).To(Equal(targetURL))
+		})
+
+		It("reads the propagation state back from the work conditions", func() {
+			work := &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo-default", "namespace": "spoke1"},
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Applied", "status": "True"},
+						map[string]interface{}{"type": "Available", "status": "False"},
+					},
+				},
+			}}
+			status := spokeStatus(work)
+			Expect(status.Name).To(Equal("spoke1"))
+			Expect(status.Applied).To(Equal("True"))
+			Expect(status.Available).To(Equal("False"))
+		})
+	})
+
+	var _ = Context("applicationsets", func() {
+		var pattern *api.Pattern
+
+		BeforeEach(func() {
+			pattern = buildPatternManifest(10)
+			pattern.Spec.ClusterGroupName = "default"
+		})
+
+		It("renders cluster and list generators and targets the generated cluster", func() {
+			pattern.Spec.ApplicationSet = &api.ApplicationSetConfig{Generators: []api.ApplicationSetGenerator{
+				{Clusters: &api.ClusterGenerator{MatchLabels: map[string]string{"env": "prod"}}},
+				{List: &api.ListGenerator{Elements: []map[string]string{{"name": "in-cluster"}}}},
+			}}
+			err, appset := newApplicationSet(*pattern)
+			Expect(err).NotTo(HaveOccurred())
+
+			generators, _, _ := unstructured.NestedSlice(appset.Object, "spec", "generators")
+			Expect(generators).To(HaveLen(2))
+			clusters := generators[0].(map[string]interface{})["clusters"].(map[string]interface{})
+			labels, _, _ := unstructured.NestedStringMap(clusters, "selector", "matchLabels")
+			Expect(labels).To(HaveKeyWithValue("env", "prod"))
+
+			name, _, _ := unstructured.NestedString(appset.Object, "spec", "template", "metadata", "name")
+			Expect(name).To(Equal("foo-default-{{name}}"))
+			server, _, _ := unstructured.NestedString(appset.Object, "spec", "template", "spec", "destination", "server")
+			Expect(server).To(Equal("{{server}}"))
+		})
+
+		It("templates the source path for git directory generators", func() {
+			pattern.Spec.GitConfig.TargetRevision = "main"
+			pattern.Spec.ApplicationSet = &api.ApplicationSetConfig{Generators: []api.ApplicationSetGenerator{
+				{GitDirectories: &api.GitDirectoryGenerator{Paths: []string{"applications/*"}}},
+			}}
+			err, appset := newApplicationSet(*pattern)
+			Expect(err).NotTo(HaveOccurred())
+
+			generators, _, _ := unstructured.NestedSlice(appset.Object, "spec", "generators")
+			Expect(generators).To(HaveLen(1))
+			git := generators[0].(map[string]interface{})["git"].(map[string]interface{})
+			By("defaulting the repository and revision from the pattern")
+			Expect(git["repoURL"]).To(Equal(targetURL))
+			Expect(git["revision"]).To(Equal("main"))
+
+			name, _, _ := unstructured.NestedString(appset.Object, "spec", "template", "metadata", "name")
+			Expect(name).To(Equal("foo-default-{{path.basename}}"))
+			path, _, _ := unstructured.NestedString(appset.Object, "spec", "template", "spec", "source", "path")
+			Expect(path).To(Equal("{{path}}"))
+			destination, _, _ := unstructured.NestedString(appset.Object, "spec", "template", "spec", "destination", "name")
+			Expect(destination).To(Equal("in-cluster"))
+		})
+	})
 })
 
+type fakeFileChecker struct {
+	missing []string
+	checked []string
+}
+
+func (f *fakeFileChecker) MissingFiles(repoURL string, revision string, paths []string) ([]string, error) {
+	f.checked = paths
+	return f.missing, nil
+}
+
+// effectiveConfigLabels returns the labels of the single patterns_operator_effective_config
+// series, or nil when none was published yet
+func effectiveConfigLabels() map[string]string {
+	families, err := metrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+	for _, family := range families {
+		if family.GetName() != "patterns_operator_effective_config" {
+			continue
+		}
+		Expect(family.GetMetric()).To(HaveLen(1))
+		labels := map[string]string{}
+		for _, label := range family.GetMetric()[0].GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		return labels
+	}
+	return nil
+}
+
 func newFakeReconciler(initObjects ...runtime.Object) *PatternReconciler {
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(initObjects...).Build()
 	clusterVersion := &v1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}, Spec: v1.ClusterVersionSpec{ClusterID: "10"}}
@@ -170,11 +1436,13 @@ func newFakeReconciler(initObjects ...runtime.Object) *PatternReconciler {
 		Spec:       operatorv1.OpenShiftControllerManagerSpec{},
 		Status:     operatorv1.OpenShiftControllerManagerStatus{OperatorStatus: operatorv1.OperatorStatus{Version: "4.10.3"}}}
 	ingress := &v1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Spec: v1.IngressSpec{Domain: "hello.world"}}
-	watcher, _ := newDriftWatcher(fakeClient, logr.New(log.NullLogSink{}), newGitClient())
+	watcher, _ := newDriftWatcher(fakeClient, logr.New(log.NullLogSink{}), newGitClient(), nil)
 	return &PatternReconciler{
 		Scheme:         scheme.Scheme,
 		Client:         fakeClient,
+		isOpenShift:    true,
 		olmClient:      olmclient.NewSimpleClientset(),
+		recorder:       record.NewFakeRecorder(20),
 		driftWatcher:   watcher,
 		configClient:   configclient.NewSimpleClientset(clusterVersion, clusterInfra, ingress),
 		operatorClient: operatorclient.NewSimpleClientset(osControlManager).OperatorV1(),
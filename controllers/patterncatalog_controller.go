@@ -0,0 +1,184 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// catalogIndexFile is the document read from the root of an index served as a git repository
+const catalogIndexFile = "index.json"
+
+// defaultCatalogSyncIntervalSeconds paces catalog syncs when the resource does not set one
+const defaultCatalogSyncIntervalSeconds = 1800
+
+// CatalogIndexFetcher retrieves the raw index document a catalog points at
+type CatalogIndexFetcher interface {
+	FetchIndex(url, revision string) ([]byte, error)
+}
+
+type catalogIndexFetcher struct {
+	client *http.Client
+}
+
+func newCatalogIndexFetcher() CatalogIndexFetcher {
+	return &catalogIndexFetcher{
+		client: &http.Client{Transport: &http.Transport{Proxy: sharedProxyRegistry.proxyForRequest}}}
+}
+
+// FetchIndex reads the index document: a URL ending in .git is shallow-cloned into memory and the
+// index.json at the root of the revision is returned, anything else is fetched over HTTP
+func (f *catalogIndexFetcher) FetchIndex(url, revision string) ([]byte, error) {
+	if strings.HasSuffix(url, ".git") {
+		refName := plumbing.HEAD
+		switch {
+		case revision == "" || revision == "HEAD":
+		case strings.HasPrefix(revision, "refs/"):
+			refName = plumbing.ReferenceName(revision)
+		default:
+			refName = plumbing.NewBranchReferenceName(revision)
+		}
+		tree, err := headTree(url, refName)
+		if err != nil {
+			return nil, err
+		}
+		file, err := tree.File(catalogIndexFile)
+		if err != nil {
+			return nil, fmt.Errorf("no %s found at the root of %s", catalogIndexFile, url)
+		}
+		contents, err := file.Contents()
+		if err != nil {
+			return nil, err
+		}
+		return []byte(contents), nil
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("index query for %s returned status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// catalogIndex is the document the index publishes
+type catalogIndex struct {
+	Patterns []api.PatternCatalogEntry `json:"patterns"`
+}
+
+// parseCatalogIndex decodes the index document into catalog entries with a stable order
+func parseCatalogIndex(raw []byte) ([]api.PatternCatalogEntry, error) {
+	var index catalogIndex
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, fmt.Errorf("unable to parse the catalog index: %s", err.Error())
+	}
+	entries := make([]api.PatternCatalogEntry, 0, len(index.Patterns))
+	for _, entry := range index.Patterns {
+		if entry.Name == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// PatternCatalogReconciler reconciles a PatternCatalog object, syncing the index it points at on
+// the declared interval and publishing the available patterns on its status
+type PatternCatalogReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	fetcher CatalogIndexFetcher
+}
+
+//+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=patterncatalogs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=patterncatalogs/status,verbs=get;update;patch
+
+// Reconcile syncs the catalog against its index and schedules the next sync. A failing sync is
+// recorded on the status and retried on the same cadence, keeping the last successful entries
+// visible in the meantime
+func (r *PatternCatalogReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	catalog := &api.PatternCatalog{}
+	if err := r.Get(ctx, req.NamespacedName, catalog); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	interval := catalog.Spec.SyncIntervalSeconds
+	if interval <= 0 {
+		interval = defaultCatalogSyncIntervalSeconds
+	}
+	requeue := ctrl.Result{RequeueAfter: time.Duration(interval) * time.Second}
+	if catalog.Spec.IndexURL == "" {
+		return requeue, r.recordSyncFailure(ctx, catalog, fmt.Errorf("catalog does not declare an index URL"))
+	}
+	raw, err := r.fetcher.FetchIndex(catalog.Spec.IndexURL, catalog.Spec.IndexRevision)
+	if err != nil {
+		return requeue, r.recordSyncFailure(ctx, catalog, err)
+	}
+	entries, err := parseCatalogIndex(raw)
+	if err != nil {
+		return requeue, r.recordSyncFailure(ctx, catalog, err)
+	}
+	catalog.Status.Entries = entries
+	catalog.Status.LastSync = metav1.Time{Time: time.Now()}
+	catalog.Status.LastError = ""
+	return requeue, r.Status().Update(ctx, catalog)
+}
+
+// recordSyncFailure surfaces the sync error on the status without discarding the entries of the
+// last successful sync
+func (r *PatternCatalogReconciler) recordSyncFailure(ctx context.Context, catalog *api.PatternCatalog, err error) error {
+	if catalog.Status.LastError == err.Error() {
+		return nil
+	}
+	catalog.Status.LastError = err.Error()
+	return r.Status().Update(ctx, catalog)
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *PatternCatalogReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.fetcher = newCatalogIndexFetcher()
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&api.PatternCatalog{}).
+		Complete(r)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+type fakeIndexFetcher struct {
+	raw []byte
+	err error
+	url string
+}
+
+func (f *fakeIndexFetcher) FetchIndex(url, revision string) ([]byte, error) {
+	f.url = url
+	return f.raw, f.err
+}
+
+var _ = Describe("pattern catalog controller", func() {
+
+	var reconciler *PatternCatalogReconciler
+	var fetcher *fakeIndexFetcher
+	var catalog *api.PatternCatalog
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: foo, Namespace: namespace}}
+
+	BeforeEach(func() {
+		catalog = &api.PatternCatalog{
+			ObjectMeta: metav1.ObjectMeta{Name: foo, Namespace: namespace},
+			Spec:       api.PatternCatalogSpec{IndexURL: "https://index.url/index.json"}}
+		fetcher = &fakeIndexFetcher{}
+		reconciler = &PatternCatalogReconciler{
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(catalog).Build(),
+			Scheme:  scheme.Scheme,
+			fetcher: fetcher}
+	})
+
+	It("publishes the index entries on the status in a stable order", func() {
+		fetcher.raw = []byte(`{"patterns": [
+			{"name": "medical-diagnosis", "versions": ["1.0.0"]},
+			{"name": "industrial-edge", "description": "Edge manufacturing", "repo": "https://github.com/org/industrial-edge.git", "versions": ["1.0.0", "1.1.0"]},
+			{"description": "nameless entries are dropped"}]}`)
+
+		result, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter.Seconds()).To(BeEquivalentTo(defaultCatalogSyncIntervalSeconds))
+		Expect(fetcher.url).To(Equal("https://index.url/index.json"))
+
+		synced := &api.PatternCatalog{}
+		Expect(reconciler.Get(context.Background(), request.NamespacedName, synced)).To(Succeed())
+		Expect(synced.Status.Entries).To(HaveLen(2))
+		Expect(synced.Status.Entries[0].Name).To(Equal("industrial-edge"))
+		Expect(synced.Status.Entries[1].Name).To(Equal("medical-diagnosis"))
+		Expect(synced.Status.Entries[0].Versions).To(Equal([]string{"1.0.0", "1.1.0"}))
+		Expect(synced.Status.LastSync.IsZero()).To(BeFalse())
+		Expect(synced.Status.LastError).To(BeEmpty())
+	})
+
+	It("records a failing sync without discarding the previous entries", func() {
+		catalog.Status.Entries = []api.PatternCatalogEntry{{Name: "industrial-edge"}}
+		Expect(reconciler.Status().Update(context.Background(), catalog)).To(Succeed())
+		fetcher.err = fmt.Errorf("index unreachable")
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).NotTo(HaveOccurred())
+
+		synced := &api.PatternCatalog{}
+		Expect(reconciler.Get(context.Background(), request.NamespacedName, synced)).To(Succeed())
+		Expect(synced.Status.LastError).To(ContainSubstring("index unreachable"))
+		Expect(synced.Status.Entries).To(HaveLen(1))
+	})
+
+	It("fails the sync on a malformed index document", func() {
+		fetcher.raw = []byte("not json")
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).NotTo(HaveOccurred())
+
+		synced := &api.PatternCatalog{}
+		Expect(reconciler.Get(context.Background(), request.NamespacedName, synced)).To(Succeed())
+		Expect(synced.Status.LastError).To(ContainSubstring("unable to parse the catalog index"))
+	})
+})
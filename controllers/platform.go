@@ -0,0 +1,241 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/ghodss/yaml"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/restmapper"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+const (
+	// platform reported on patterns running outside of OpenShift
+	kubernetesPlatform = "Kubernetes"
+	// the OpenShift configuration API group; its presence is how the cluster flavour is told apart
+	openshiftConfigGroup = "config.openshift.io"
+	// upstream Argo CD install manifests applied on clusters without OLM, pinned to the release
+	// the operator is built against
+	gitOpsManifestsLocation = "https://raw.githubusercontent.com/argoproj/argo-cd/v2.3.0/manifests/install.yaml"
+	// deployment the upstream manifests bring up; its readiness gates the rest of the reconcile
+	gitOpsServerDeployment = "argocd-server"
+)
+
+// detectOpenShift probes the discovery API for the OpenShift configuration group. Patterns on
+// vanilla Kubernetes and kind clusters take the alternate code paths: no ClusterVersion or
+// Ingress configuration lookups, and a plain Argo CD install instead of the OLM subscription
+func detectOpenShift(dc discovery.DiscoveryInterface) (bool, error) {
+	groups, err := dc.ServerGroups()
+	if err != nil {
+		return false, err
+	}
+	for _, group := range groups.Groups {
+		if group.Name == openshiftConfigGroup {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// applyKubernetesDefaults fills the cluster facts of the status on clusters without the
+// OpenShift configuration APIs: the cluster identity falls back to the UID of the kube-system
+// namespace, the version to the version of the API server and the domains to the in-cluster
+// service domain, which kind and most vanilla installs resolve
+func (r *PatternReconciler) applyKubernetesDefaults(output *api.Pattern) error {
+	var kubeSystem corev1.Namespace
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: "kube-system"}, &kubeSystem); err != nil {
+		return err
+	}
+	output.Status.ClusterID = string(kubeSystem.UID)
+	output.Status.ClusterPlatform = kubernetesPlatform
+
+	if r.fullClient != nil {
+		version, err := r.fullClient.Discovery().ServerVersion()
+		if err != nil {
+			return err
+		}
+		v, err := semver.NewVersion(version.GitVersion)
+		if err != nil {
+			return err
+		}
+		output.Status.ClusterVersion = fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+	}
+
+	output.Status.ClusterName = "in-cluster"
+	output.Status.ClusterDomain = "cluster.local"
+	output.Status.AppClusterDomain = "apps.cluster.local"
+	return nil
+}
+
+// detectArchitecture reports the processor architecture of the cluster, taken from the platform
+// the API server was built for. Empty when the discovery API is not reachable
+func (r *PatternReconciler) detectArchitecture() string {
+	if r.fullClient == nil {
+		return ""
+	}
+	version, err := r.fullClient.Discovery().ServerVersion()
+	if err != nil {
+		return ""
+	}
+	// "linux/amd64"
+	if parts := strings.Split(version.Platform, "/"); len(parts) == 2 {
+		return parts[1]
+	}
+	return version.Platform
+}
+
+// detectFIPS reports whether the cluster was installed in FIPS mode, read from the
+// install-config the installer leaves in the cluster-config-v1 ConfigMap. Clusters without that
+// ConfigMap, like kind, are not FIPS installs
+func (r *PatternReconciler) detectFIPS() (bool, error) {
+	var clusterConfig corev1.ConfigMap
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: "cluster-config-v1", Namespace: "kube-system"}, &clusterConfig); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	installConfig, ok := clusterConfig.Data["install-config"]
+	if !ok {
+		return false, nil
+	}
+	var parsed struct {
+		FIPS bool `json:"fips"`
+	}
+	if err := yaml.Unmarshal([]byte(installConfig), &parsed); err != nil {
+		return false, err
+	}
+	return parsed.FIPS, nil
+}
+
+// ensureGitOpsOnKubernetes stands in for the OLM subscription on clusters without OLM: the
+// gitops namespace is created and the upstream Argo CD manifests are applied into it through
+// the given installer, then the reconciliation waits for the server deployment to come up
+func (r *PatternReconciler) ensureGitOpsOnKubernetes(p *api.Pattern, install func() error) error {
+	if !haveNamespace(r.Client, applicationNamespace) {
+		ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: applicationNamespace}}
+		if err := r.Client.Create(context.Background(), &ns); err != nil && !kerrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	var server appsv1.Deployment
+	err := r.Client.Get(context.Background(), types.NamespacedName{Name: gitOpsServerDeployment, Namespace: applicationNamespace}, &server)
+	if kerrors.IsNotFound(err) {
+		log.Printf("no OLM on this cluster; installing Argo CD into namespace %s from the upstream manifests\n", applicationNamespace)
+		if err := install(); err != nil {
+			return err
+		}
+		return fmt.Errorf("waiting for the Argo CD install in namespace %s to come up", applicationNamespace)
+	}
+	if err != nil {
+		return err
+	}
+	if server.Status.ReadyReplicas == 0 {
+		return fmt.Errorf("waiting for deployment %s in namespace %s to become ready", gitOpsServerDeployment, applicationNamespace)
+	}
+	return nil
+}
+
+// decodeManifests splits a multi-document manifest stream into the objects it declares
+func decodeManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	decoder := kyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(obj); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			// tolerate empty documents, the upstream manifests carry a few
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// installGitOpsManifests fetches the pinned upstream Argo CD manifests and applies them into the
+// gitops namespace through the dynamic client, leaving objects that already exist untouched
+func (r *PatternReconciler) installGitOpsManifests() error {
+	location := r.gitOpsManifests
+	if location == "" {
+		location = gitOpsManifestsLocation
+	}
+	resp, err := http.Get(location)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching the Argo CD manifests from %s returned status %d", location, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	objects, err := decodeManifests(data)
+	if err != nil {
+		return err
+	}
+
+	resources, err := restmapper.GetAPIGroupResources(r.fullClient.Discovery())
+	if err != nil {
+		return err
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(resources)
+	for _, obj := range objects {
+		mapping, err := mapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+		if err != nil {
+			return err
+		}
+		resource := r.dynamicClient.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			namespace := obj.GetNamespace()
+			if namespace == "" {
+				namespace = applicationNamespace
+			}
+			_, err = resource.Namespace(namespace).Create(context.Background(), obj, metav1.CreateOptions{})
+		} else {
+			_, err = resource.Create(context.Background(), obj, metav1.CreateOptions{})
+		}
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	log.Printf("applied %d objects of the Argo CD install manifests\n", len(objects))
+	return nil
+}
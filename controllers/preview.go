@@ -0,0 +1,107 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	argoapi "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/ghodss/yaml"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// previewAnnotation puts the pattern into a dry-run mode: the application is neither created nor
+// updated while it is set; instead the effective source and its diff against what is currently
+// applied are published in a ConfigMap next to the pattern. Removing the annotation applies the
+// change
+const previewAnnotation = "gitops.hybrid-cloud-patterns.io/preview"
+
+func previewConfigMapName(p *api.Pattern) string {
+	return fmt.Sprintf("%s-preview", p.Name)
+}
+
+// renderPreview stores the application source the pattern would be applied with — the repository,
+// revision, value files and parameters helm is invoked with after defaulting and parameter
+// resolution — together with its diff against the currently applied source
+func (r *PatternReconciler) renderPreview(p *api.Pattern, current *argoapi.Application) error {
+	goal, err := yaml.Marshal(newApplication(*p).Spec.Source)
+	if err != nil {
+		return err
+	}
+	applied := []byte{}
+	if current != nil {
+		if applied, err = yaml.Marshal(current.Spec.Source); err != nil {
+			return err
+		}
+	}
+
+	preview := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      previewConfigMapName(p),
+			Namespace: p.Namespace,
+		},
+		Data: map[string]string{
+			"source": string(goal),
+			"diff":   sourceDiff(string(applied), string(goal)),
+		},
+	}
+	_ = controllerutil.SetOwnerReference(p, preview, r.Scheme)
+
+	existing := &corev1.ConfigMap{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: preview.Name, Namespace: preview.Namespace}, existing)
+	if kerrors.IsNotFound(err) {
+		return r.Client.Create(context.TODO(), preview)
+	} else if err != nil {
+		return err
+	}
+	existing.Data = preview.Data
+	return r.Client.Update(context.TODO(), existing)
+}
+
+// sourceDiff renders a line diff between the applied and the goal source, prefixing removed
+// lines with "-" and added lines with "+"
+func sourceDiff(applied, goal string) string {
+	dmp := diffmatchpatch.New()
+	fromChars, toChars, lines := dmp.DiffLinesToChars(applied, goal)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(fromChars, toChars, false), lines)
+
+	var rendered strings.Builder
+	for _, diff := range diffs {
+		prefix := " "
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(diff.Text, "\n"), "\n") {
+			rendered.WriteString(prefix)
+			rendered.WriteString(line)
+			rendered.WriteString("\n")
+		}
+	}
+	return rendered.String()
+}
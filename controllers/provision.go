@@ -0,0 +1,133 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"log"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// provisionResources creates the namespaces, service accounts and role bindings declared under
+// spec.provision, so the clustergroup chart does not have to bootstrap its own RBAC with
+// cluster-admin. Everything carries the usual pattern label; existing resources are left
+// untouched, so a second pattern or an administrator owning one of them does not get it
+// rewritten. Namespaces are cluster-scoped and cannot carry an owner reference to the pattern,
+// which is why the finalizer prunes the declared resources explicitly
+func (r *PatternReconciler) provisionResources(p *api.Pattern) error {
+	if p.Spec.Provision == nil {
+		return nil
+	}
+	labels := map[string]string{"pattern": applicationName(*p)}
+
+	for _, name := range p.Spec.Provision.Namespaces {
+		ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		if err := r.createIfMissing(&ns, "namespace"); err != nil {
+			return err
+		}
+	}
+
+	for _, declared := range p.Spec.Provision.ServiceAccounts {
+		sa := corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name:      declared.Name,
+			Namespace: declared.Namespace,
+			Labels:    labels,
+		}}
+		if sa.Namespace == p.Namespace {
+			_ = controllerutil.SetOwnerReference(p, &sa, r.Scheme)
+		}
+		if err := r.createIfMissing(&sa, "service account"); err != nil {
+			return err
+		}
+	}
+
+	for _, declared := range p.Spec.Provision.RoleBindings {
+		binding := rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      declared.Name,
+				Namespace: declared.Namespace,
+				Labels:    labels,
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     declared.ClusterRole,
+			},
+			Subjects: []rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      declared.ServiceAccount.Name,
+				Namespace: declared.ServiceAccount.Namespace,
+			}},
+		}
+		if binding.Namespace == p.Namespace {
+			_ = controllerutil.SetOwnerReference(p, &binding, r.Scheme)
+		}
+		if err := r.createIfMissing(&binding, "role binding"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createIfMissing creates the object unless it already exists; the role bindings especially
+// cannot be updated in place as their role reference is immutable
+func (r *PatternReconciler) createIfMissing(obj client.Object, kind string) error {
+	existing := obj.DeepCopyObject().(client.Object)
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, existing)
+	if kerrors.IsNotFound(err) {
+		log.Printf("provisioning %s %s for the pattern\n", kind, obj.GetName())
+		return r.Client.Create(context.TODO(), obj)
+	}
+	return err
+}
+
+// pruneProvisionedResources deletes the resources declared under spec.provision when the pattern
+// goes away. Role bindings and service accounts go first so nothing keeps permissions while the
+// namespaces drain; resources already gone are skipped
+func (r *PatternReconciler) pruneProvisionedResources(p *api.Pattern) error {
+	if p.Spec.Provision == nil {
+		return nil
+	}
+	for _, declared := range p.Spec.Provision.RoleBindings {
+		binding := rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: declared.Name, Namespace: declared.Namespace}}
+		if err := r.Client.Delete(context.TODO(), &binding); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+	for _, declared := range p.Spec.Provision.ServiceAccounts {
+		sa := corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: declared.Name, Namespace: declared.Namespace}}
+		if err := r.Client.Delete(context.TODO(), &sa); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+	for _, name := range p.Spec.Provision.Namespaces {
+		ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if err := r.Client.Delete(context.TODO(), &ns); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
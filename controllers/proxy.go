@@ -0,0 +1,142 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// proxySettings is the resolved proxy configuration applied to outbound connections, carrying the
+// semantics of the conventional HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+type proxySettings struct {
+	httpProxy  string
+	httpsProxy string
+	noProxy    string
+}
+
+func (s proxySettings) empty() bool {
+	return s.httpProxy == "" && s.httpsProxy == "" && s.noProxy == ""
+}
+
+// proxyForURL returns the proxy to dial for the URL, or nil for a direct connection. The proxy is
+// selected by the scheme of the request and suppressed for hosts matched by noProxy
+func (s proxySettings) proxyForURL(u *url.URL) (*url.URL, error) {
+	if s.bypassed(u.Hostname()) {
+		return nil, nil
+	}
+	raw := s.httpProxy
+	if u.Scheme == "https" {
+		raw = s.httpsProxy
+	}
+	if raw == "" {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", raw, err)
+	}
+	return proxyURL, nil
+}
+
+// bypassed returns true when the host is matched by an entry of the noProxy list, either exactly
+// or as a domain suffix. A single "*" matches every host
+func (s proxySettings) bypassed(host string) bool {
+	for _, entry := range strings.Split(s.noProxy, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if entry == "*" || strings.EqualFold(entry, host) {
+			return true
+		}
+		suffix := "." + strings.TrimPrefix(entry, ".")
+		if strings.HasSuffix(strings.ToLower(host), strings.ToLower(suffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyRegistry selects the proxy for an outbound request, preferring the settings a pattern
+// declared for the host of the request over the operator-level settings read from the
+// environment. Per-host entries are registered from the pattern spec on every drift check, so a
+// change to the declaration takes effect on the next check; when two patterns declare different
+// proxies for the same host the last one checked wins
+type proxyRegistry struct {
+	mutex    sync.Mutex
+	operator proxySettings
+	hosts    map[string]proxySettings
+}
+
+// sharedProxyRegistry backs the http transports installed for the whole process, which go-git
+// shares per protocol, so a single registry consulted per request is the only way to honor
+// per-pattern settings
+var sharedProxyRegistry = newProxyRegistry()
+
+func newProxyRegistry() *proxyRegistry {
+	return &proxyRegistry{
+		operator: proxySettings{
+			httpProxy:  firstEnv("HTTP_PROXY", "http_proxy"),
+			httpsProxy: firstEnv("HTTPS_PROXY", "https_proxy"),
+			noProxy:    firstEnv("NO_PROXY", "no_proxy")},
+		hosts: map[string]proxySettings{}}
+}
+
+// firstEnv returns the value of the first environment variable of the list that is set
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// setHostProxy registers the settings consulted for requests to the host, replacing any previous
+// registration
+func (p *proxyRegistry) setHostProxy(host string, settings proxySettings) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.hosts[host] = settings
+}
+
+// clearHostProxy drops the per-host registration, falling back to the operator-level settings
+func (p *proxyRegistry) clearHostProxy(host string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.hosts, host)
+}
+
+// settingsForHost returns the settings governing requests to the host
+func (p *proxyRegistry) settingsForHost(host string) proxySettings {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if settings, found := p.hosts[host]; found {
+		return settings
+	}
+	return p.operator
+}
+
+// proxyForRequest is the Proxy callback of the http transports the operator installs
+func (p *proxyRegistry) proxyForRequest(req *http.Request) (*url.URL, error) {
+	return p.settingsForHost(req.URL.Hostname()).proxyForURL(req.URL)
+}
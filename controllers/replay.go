@@ -0,0 +1,114 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// driftTransition records the moment the drift state of a pattern flipped
+type driftTransition struct {
+	At      time.Time
+	Drifted bool
+}
+
+// timeWindow is a closed interval within the replayed history
+type timeWindow struct {
+	From, To time.Time
+}
+
+// driftTimeline is the reconstructed drift history of a single pattern: when it transitioned,
+// how long it was out of sync and the windows during which its checks were failing
+type driftTimeline struct {
+	Transitions  []driftTransition
+	OutOfSync    time.Duration
+	ErrorWindows []timeWindow
+	Checks       int
+}
+
+// readDriftReports decodes a stream of JSON drift reports as written by the archival sink
+func readDriftReports(r io.Reader) ([]DriftReport, error) {
+	reports := []DriftReport{}
+	decoder := json.NewDecoder(r)
+	for {
+		var report DriftReport
+		if err := decoder.Decode(&report); err == io.EOF {
+			return reports, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to decode drift report %d: %w", len(reports)+1, err)
+		}
+		reports = append(reports, report)
+	}
+}
+
+// replayDriftReports reconstructs the per-pattern drift timeline from an audit stream of
+// archived reports, keyed by namespace/name. The stream may interleave patterns and arrive out
+// of order; records are sorted by check timestamp before replaying. Errored checks do not change
+// the drift state but open an error window that the next completed check closes
+func replayDriftReports(reports []DriftReport) map[string]driftTimeline {
+	sorted := make([]DriftReport, len(reports))
+	copy(sorted, reports)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CheckedAt.Before(sorted[j].CheckedAt) })
+
+	timelines := map[string]driftTimeline{}
+	driftSince := map[string]time.Time{}
+	errorSince := map[string]time.Time{}
+	lastSeen := map[string]time.Time{}
+	for _, report := range sorted {
+		key := fmt.Sprintf("%s/%s", report.Namespace, report.Name)
+		timeline := timelines[key]
+		timeline.Checks++
+		lastSeen[key] = report.CheckedAt
+		if report.Error != "" {
+			if _, open := errorSince[key]; !open {
+				errorSince[key] = report.CheckedAt
+			}
+			timelines[key] = timeline
+			continue
+		}
+		if from, open := errorSince[key]; open {
+			timeline.ErrorWindows = append(timeline.ErrorWindows, timeWindow{From: from, To: report.CheckedAt})
+			delete(errorSince, key)
+		}
+		if len(timeline.Transitions) == 0 || timeline.Transitions[len(timeline.Transitions)-1].Drifted != report.Drifted {
+			timeline.Transitions = append(timeline.Transitions, driftTransition{At: report.CheckedAt, Drifted: report.Drifted})
+			if report.Drifted {
+				driftSince[key] = report.CheckedAt
+			} else if from, drifting := driftSince[key]; drifting {
+				timeline.OutOfSync += report.CheckedAt.Sub(from)
+				delete(driftSince, key)
+			}
+		}
+		timelines[key] = timeline
+	}
+	// close the windows still open at the end of the stream against the last record seen
+	for key, from := range driftSince {
+		timeline := timelines[key]
+		timeline.OutOfSync += lastSeen[key].Sub(from)
+		timelines[key] = timeline
+	}
+	for key, from := range errorSince {
+		timeline := timelines[key]
+		timeline.ErrorWindows = append(timeline.ErrorWindows, timeWindow{From: from, To: lastSeen[key]})
+		timelines[key] = timeline
+	}
+	return timelines
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drift report replay", func() {
+
+	var start = time.Date(2022, time.November, 1, 10, 0, 0, 0, time.UTC)
+
+	at := func(minutes int) time.Time {
+		return start.Add(time.Duration(minutes) * time.Minute)
+	}
+	report := func(name string, minutes int, drifted bool, checkError string) DriftReport {
+		return DriftReport{Name: name, Namespace: defaultNamespace, Drifted: drifted, CheckedAt: at(minutes), Error: checkError}
+	}
+
+	var _ = Context("when ingesting the audit stream", func() {
+
+		It("decodes a stream of JSON reports as written by the sink", func() {
+			var buffer bytes.Buffer
+			for _, item := range []DriftReport{report(foo, 0, false, ""), report(bar, 1, true, "")} {
+				Expect(json.NewEncoder(&buffer).Encode(item)).NotTo(HaveOccurred())
+			}
+			reports, err := readDriftReports(&buffer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports).To(HaveLen(2))
+			Expect(reports[0].Name).To(Equal(foo))
+			Expect(reports[1].Drifted).To(BeTrue())
+		})
+
+		It("rejects a corrupt stream", func() {
+			_, err := readDriftReports(bytes.NewBufferString(`{"name": "foo"} not json`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to decode drift report 2"))
+		})
+	})
+
+	var _ = Context("when reconstructing the drift timeline", func() {
+
+		It("derives transitions and out-of-sync duration per pattern", func() {
+			reports := []DriftReport{
+				report(foo, 0, false, ""),
+				report(bar, 1, true, ""),
+				report(foo, 2, true, ""),
+				report(foo, 4, true, ""),
+				report(foo, 7, false, ""),
+				report(bar, 8, true, ""),
+			}
+			timelines := replayDriftReports(reports)
+			Expect(timelines).To(HaveLen(2))
+
+			timeline := timelines[fmt.Sprintf("%s/%s", defaultNamespace, foo)]
+			Expect(timeline.Checks).To(Equal(4))
+			Expect(timeline.Transitions).To(Equal([]driftTransition{
+				{At: at(0), Drifted: false},
+				{At: at(2), Drifted: true},
+				{At: at(7), Drifted: false}}))
+			Expect(timeline.OutOfSync).To(Equal(5 * time.Minute))
+
+			// bar never recovered: its drift window stays open until its last record
+			timeline = timelines[fmt.Sprintf("%s/%s", defaultNamespace, bar)]
+			Expect(timeline.Transitions).To(Equal([]driftTransition{{At: at(1), Drifted: true}}))
+			Expect(timeline.OutOfSync).To(Equal(7 * time.Minute))
+		})
+
+		It("orders an interleaved out-of-order stream before replaying", func() {
+			reports := []DriftReport{
+				report(foo, 7, false, ""),
+				report(foo, 0, false, ""),
+				report(foo, 2, true, ""),
+			}
+			timeline := replayDriftReports(reports)[fmt.Sprintf("%s/%s", defaultNamespace, foo)]
+			Expect(timeline.Transitions).To(HaveLen(3))
+			Expect(timeline.OutOfSync).To(Equal(5 * time.Minute))
+		})
+
+		It("reconstructs the error windows without flipping the drift state", func() {
+			reports := []DriftReport{
+				report(foo, 0, true, ""),
+				report(foo, 1, true, "connection reset by peer"),
+				report(foo, 3, true, "connection reset by peer"),
+				report(foo, 5, true, ""),
+				report(foo, 6, true, "connection reset by peer"),
+			}
+			timeline := replayDriftReports(reports)[fmt.Sprintf("%s/%s", defaultNamespace, foo)]
+			Expect(timeline.Transitions).To(Equal([]driftTransition{{At: at(0), Drifted: true}}))
+			// the first window is closed by the completed check, the second by the end of the stream
+			Expect(timeline.ErrorWindows).To(Equal([]timeWindow{
+				{From: at(1), To: at(5)},
+				{From: at(6), To: at(6)}}))
+			Expect(timeline.OutOfSync).To(Equal(6 * time.Minute))
+		})
+	})
+})
@@ -0,0 +1,119 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// name of the ConfigMap carrying the runtime tunables of the operator process, looked up in
+	// the operator namespace. Unlike the PatternsOperatorConfig singleton, which holds defaults
+	// for patterns, these act on the running binary: log verbosity and drift check limits
+	runtimeConfigMapName = "patterns-operator-config"
+	// environment variable naming the namespace the operator runs in, usually set through the
+	// downward API; the subscription namespace is assumed when unset
+	operatorNamespaceEnv = "OPERATOR_NAMESPACE"
+
+	// keys of the ConfigMap; a key the ConfigMap does not carry leaves the current value alone
+	runtimeConfigLogLevel         = "log-level"
+	runtimeConfigDriftConcurrency = "drift-check-concurrency"
+	runtimeConfigDriftTimeout     = "drift-check-timeout-seconds"
+)
+
+// operatorNamespace returns the namespace the operator pod runs in, where the runtime
+// configuration ConfigMap is expected
+func operatorNamespace() string {
+	if ns := os.Getenv(operatorNamespaceEnv); ns != "" {
+		return ns
+	}
+	return subscriptionNamespace
+}
+
+// applyRuntimeConfig reads the runtime tunables from the patterns-operator-config ConfigMap and
+// applies them without a pod restart: the log level takes effect immediately, the drift check
+// timeout on the next check and the drift check concurrency resizes the running worker pool.
+// Absent keys leave the current values alone, so the environment knobs and the
+// PatternsOperatorConfig singleton keep their say. The effective configuration is dumped to the
+// log and published as the labels of the patterns_operator_effective_config metric; an unchanged
+// ConfigMap is not re-applied
+func (r *PatternReconciler) applyRuntimeConfig() {
+	version := "none"
+	data := map[string]string{}
+	var configMap corev1.ConfigMap
+	err := r.Client.Get(context.Background(), types.NamespacedName{Name: runtimeConfigMapName, Namespace: operatorNamespace()}, &configMap)
+	switch {
+	case err == nil:
+		version = configMap.ResourceVersion
+		data = configMap.Data
+	case !kerrors.IsNotFound(err):
+		return
+	}
+	if version == r.runtimeConfigVersion {
+		return
+	}
+	r.runtimeConfigVersion = version
+
+	level := ""
+	if r.LogLevel != nil {
+		if value, ok := data[runtimeConfigLogLevel]; ok {
+			if err := r.LogLevel.UnmarshalText([]byte(value)); err != nil {
+				log.Printf("ignoring invalid %s %q in %s: %s\n", runtimeConfigLogLevel, value, runtimeConfigMapName, err.Error())
+			}
+		}
+		level = r.LogLevel.String()
+	}
+
+	concurrency := defaultCheckConcurrency
+	timeout := defaultCheckTimeout
+	if w, ok := r.driftWatcher.(*watcher); ok {
+		if value, err := strconv.Atoi(data[runtimeConfigDriftConcurrency]); err == nil {
+			w.setCheckConcurrency(value)
+		}
+		if value, err := strconv.Atoi(data[runtimeConfigDriftTimeout]); err == nil {
+			w.setCheckTimeout(time.Duration(value) * time.Second)
+		}
+		concurrency, timeout = w.checkSettings()
+	}
+
+	log.Printf("effective runtime configuration: %s=%s %s=%d %s=%d\n",
+		runtimeConfigLogLevel, level,
+		runtimeConfigDriftConcurrency, concurrency,
+		runtimeConfigDriftTimeout, int(timeout/time.Second))
+	effectiveConfig.Reset()
+	effectiveConfig.WithLabelValues(level, strconv.Itoa(concurrency), strconv.Itoa(int(timeout/time.Second))).Set(1)
+}
+
+// mapRuntimeConfigMap applies the runtime configuration as soon as its ConfigMap changes,
+// instead of waiting for the next pattern reconcile. The settings act on the operator process
+// rather than on one pattern, so nothing is enqueued
+func (r *PatternReconciler) mapRuntimeConfigMap(object client.Object) []reconcile.Request {
+	if object.GetName() == runtimeConfigMapName && object.GetNamespace() == operatorNamespace() {
+		r.applyRuntimeConfig()
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	argoapi "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/ghodss/yaml"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+const (
+	// where the validated patterns framework deploys the secret-store components by default
+	defaultVaultNamespace           = "vault"
+	defaultExternalSecretsNamespace = "external-secrets"
+	// names of the workloads the bootstrap looks for: the statefulset of the HashiCorp vault
+	// chart and the deployment of the external secrets operator
+	vaultStatefulSetName          = "vault"
+	externalSecretsDeploymentName = "external-secrets"
+	// key of the values-secret.yaml payload inside the referenced Secret
+	valuesSecretKey = "values-secret.yaml"
+	// chart the vault application deploys when the operator manages Vault itself
+	vaultChartRepo = "https://helm.releases.hashicorp.com"
+	vaultChartName = "vault"
+	// backends the entries of the secretsRef document can be pushed to
+	secretBackendKubernetes = "Kubernetes"
+	secretBackendVault      = "Vault"
+	// name and key of the Secret in the vault namespace holding the token the Vault backend
+	// authenticates with
+	vaultTokenSecret = "vault-token"
+	vaultTokenKey    = "token"
+)
+
+// vaultApplicationName names the ArgoCD application deploying Vault for the pattern
+func vaultApplicationName(p api.Pattern) string {
+	return fmt.Sprintf("%s-vault", applicationName(p))
+}
+
+// vaultApplication renders the ArgoCD application deploying the HashiCorp vault chart into the
+// configured namespace. It carries the usual pattern label, so it is listed and pruned together
+// with the other applications of the pattern
+func vaultApplication(p api.Pattern) *argoapi.Application {
+	spec := argoapi.ApplicationSpec{
+		Source: argoapi.ApplicationSource{
+			RepoURL: vaultChartRepo,
+			Chart:   vaultChartName,
+			// the chart version intentionally floats on HEAD of the repository index; patterns
+			// pinning vault deploy it through their own applications instead
+			TargetRevision: "*",
+		},
+		Destination: argoapi.ApplicationDestination{
+			Name:      "in-cluster",
+			Namespace: p.Spec.SecretStore.VaultNamespace,
+		},
+		Project: "default",
+		SyncPolicy: &argoapi.SyncPolicy{
+			Automated:   &argoapi.SyncPolicyAutomated{},
+			SyncOptions: []string{"CreateNamespace=true"},
+		},
+	}
+	return &argoapi.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vaultApplicationName(p),
+			Namespace: applicationNamespace,
+			Labels:    map[string]string{"pattern": applicationName(p)},
+		},
+		Spec: spec,
+	}
+}
+
+// vaultReady looks up the vault statefulset in the configured namespace. A deployed but unready
+// vault typically still needs to be initialized or unsealed
+func (r *PatternReconciler) vaultReady(namespace string) (found, ready bool, err error) {
+	var sts appsv1.StatefulSet
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: vaultStatefulSetName, Namespace: namespace}, &sts); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return true, sts.Status.ReadyReplicas > 0, nil
+}
+
+// externalSecretsReady looks up the deployment of the external secrets operator bridging Vault
+// into the cluster
+func (r *PatternReconciler) externalSecretsReady(namespace string) (found, ready bool, err error) {
+	var deployment appsv1.Deployment
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: externalSecretsDeploymentName, Namespace: namespace}, &deployment); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return true, deployment.Status.ReadyReplicas > 0, nil
+}
+
+// reconcileSecretStore drives the secret backend bootstrap declared by the pattern: Vault is
+// deployed through an ArgoCD application when the pattern manages it, the components and the
+// local values Secret are verified and the outcome lands on the SecretStoreReady condition.
+// Blockers hold the condition at False without failing the reconciliation, as the components
+// usually come up through applications that are still syncing
+func (r *PatternReconciler) reconcileSecretStore(p *api.Pattern) error {
+	if p.Spec.SecretStore == nil {
+		return nil
+	}
+	store := p.Spec.SecretStore
+	var blockers []string
+
+	vaultFound, vaultUp, err := r.vaultReady(store.VaultNamespace)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !vaultFound && store.ManageVault && r.argoClient != nil:
+		target := vaultApplication(*p)
+		if err, app := getApplication(r.argoClient, target.Name); app == nil && err != nil {
+			log.Printf("deploying vault into namespace %s through application %s\n", store.VaultNamespace, target.Name)
+			if err := createApplication(r.argoClient, target); err != nil {
+				return err
+			}
+		}
+		blockers = append(blockers, fmt.Sprintf("vault is being deployed into namespace %s", store.VaultNamespace))
+	case !vaultFound:
+		blockers = append(blockers, fmt.Sprintf("no vault instance found in namespace %s", store.VaultNamespace))
+	case !vaultUp:
+		blockers = append(blockers, "vault is deployed but not ready, possibly sealed or not yet initialized")
+	}
+
+	esoFound, esoUp, err := r.externalSecretsReady(store.ExternalSecretsNamespace)
+	if err != nil {
+		return err
+	}
+	if !esoFound {
+		blockers = append(blockers, fmt.Sprintf("external secrets operator not found in namespace %s", store.ExternalSecretsNamespace))
+	} else if !esoUp {
+		blockers = append(blockers, "external secrets operator is not ready")
+	}
+
+	if store.ValuesSecretName != "" {
+		var secret corev1.Secret
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: store.ValuesSecretName, Namespace: p.Namespace}, &secret); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return err
+			}
+			blockers = append(blockers, fmt.Sprintf("values secret %s not found in namespace %s", store.ValuesSecretName, p.Namespace))
+		} else if _, ok := secret.Data[valuesSecretKey]; !ok {
+			blockers = append(blockers, fmt.Sprintf("values secret %s does not hold a %s entry", store.ValuesSecretName, valuesSecretKey))
+		}
+	}
+
+	status := corev1.ConditionTrue
+	message := "the secret store is ready to serve the pattern secrets"
+	if len(blockers) > 0 {
+		status = corev1.ConditionFalse
+		message = strings.Join(blockers, "; ")
+	}
+	if _, condition := getPatternConditionByType(p.Status.Conditions, api.SecretStoreReady); condition != nil && condition.Status == status && condition.Message == message {
+		return nil
+	}
+	setControllerCondition(p, api.SecretStoreReady, status, message, metav1.Time{Time: time.Now()})
+	return r.Client.Status().Update(context.TODO(), p)
+}
+
+// patternSecrets is the shape of the values-secret.yaml document: named groups of key/value
+// entries, each group landing at one path of the backend
+type patternSecrets struct {
+	Secrets map[string]map[string]string `json:"secrets"`
+}
+
+// loadPatternSecrets parses the values-secret.yaml document of the Secret named by
+// spec.gitSpec.secretsRef and pushes its entries into the configured backend, replacing the
+// manual make-based loading workflow. The content hash of the last pushed document is kept in
+// the status, so the entries are pushed exactly once per edit of the referenced Secret
+func (r *PatternReconciler) loadPatternSecrets(p *api.Pattern) error {
+	if p.Spec.GitConfig.SecretsRef == "" {
+		return nil
+	}
+	var source corev1.Secret
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: p.Spec.GitConfig.SecretsRef, Namespace: p.Namespace}, &source); err != nil {
+		return err
+	}
+	payload, ok := source.Data[valuesSecretKey]
+	if !ok {
+		return fmt.Errorf("secret %s does not hold a %s entry", p.Spec.GitConfig.SecretsRef, valuesSecretKey)
+	}
+	version := fmt.Sprintf("%x", sha256.Sum256(payload))
+	if p.Status.LoadedSecretsVersion == version {
+		return nil
+	}
+	var parsed patternSecrets
+	if err := yaml.Unmarshal(payload, &parsed); err != nil {
+		return fmt.Errorf("parsing %s of secret %s: %s", valuesSecretKey, p.Spec.GitConfig.SecretsRef, err.Error())
+	}
+	backend := secretBackendKubernetes
+	if p.Spec.SecretStore != nil && p.Spec.SecretStore.Backend != "" {
+		backend = p.Spec.SecretStore.Backend
+	}
+	for group, entries := range parsed.Secrets {
+		var err error
+		switch backend {
+		case secretBackendVault:
+			err = r.pushVaultSecret(p, group, entries)
+		case secretBackendKubernetes:
+			err = r.pushKubernetesSecret(p, group, entries)
+		default:
+			err = fmt.Errorf("unknown secret backend %q", backend)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("loaded %d secret group(s) from %s into the %s backend\n", len(parsed.Secrets), p.Spec.GitConfig.SecretsRef, backend)
+	p.Status.LoadedSecretsVersion = version
+	return r.Client.Status().Update(context.TODO(), p)
+}
+
+// pushKubernetesSecret materializes one group of the document as a Secret next to the pattern,
+// created or updated in place and owned by the pattern so deletion cascades
+func (r *PatternReconciler) pushKubernetesSecret(p *api.Pattern, group string, entries map[string]string) error {
+	data := map[string][]byte{}
+	for key, value := range entries {
+		data[key] = []byte(value)
+	}
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", applicationName(*p), group),
+			Namespace: p.Namespace,
+			Labels:    map[string]string{"pattern": applicationName(*p)},
+		},
+		Data: data,
+	}
+	_ = controllerutil.SetOwnerReference(p, &secret, r.Scheme)
+	var existing corev1.Secret
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, &existing)
+	if kerrors.IsNotFound(err) {
+		return r.Client.Create(context.TODO(), &secret)
+	}
+	if err != nil {
+		return err
+	}
+	existing.Data = data
+	return r.Client.Update(context.TODO(), &existing)
+}
+
+// pushVaultSecret writes one group of the document through the KV version 2 HTTP API of vault,
+// under secret/data/hub/<group> where the clustergroup chart expects the entries, authenticating
+// with the token found in the vault-token Secret of the vault namespace
+func (r *PatternReconciler) pushVaultSecret(p *api.Pattern, group string, entries map[string]string) error {
+	store := p.Spec.SecretStore
+	if store == nil {
+		return fmt.Errorf("the %s secret backend requires a secret store configuration", secretBackendVault)
+	}
+	var tokenSecret corev1.Secret
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: vaultTokenSecret, Namespace: store.VaultNamespace}, &tokenSecret); err != nil {
+		return err
+	}
+	token, ok := tokenSecret.Data[vaultTokenKey]
+	if !ok {
+		return fmt.Errorf("secret %s in namespace %s does not hold a %s entry", vaultTokenSecret, store.VaultNamespace, vaultTokenKey)
+	}
+	addr := r.vaultAddr
+	if addr == "" {
+		addr = fmt.Sprintf("http://%s.%s.svc:8200", vaultStatefulSetName, store.VaultNamespace)
+	}
+	body, err := json.Marshal(map[string]interface{}{"data": entries})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v1/secret/data/hub/%s", addr, group), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", string(token))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("vault write of group %s returned status %d", group, resp.StatusCode)
+	}
+	return nil
+}
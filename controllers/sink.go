@@ -0,0 +1,106 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DriftReport is the structured outcome of a drift check archived for audit retention beyond what
+// Events and status conditions provide
+type DriftReport struct {
+	Name          string    `json:"name"`
+	Namespace     string    `json:"namespace"`
+	Drifted       bool      `json:"drifted"`
+	CorrelationID string    `json:"correlationID,omitempty"`
+	CheckedAt     time.Time `json:"checkedAt"`
+	// error the check failed with; empty for completed checks
+	Error string `json:"error,omitempty"`
+}
+
+// DriftSink archives drift reports to durable storage
+type DriftSink interface {
+	Archive(report DriftReport) error
+}
+
+// objectStore abstracts the S3-compatible storage the reports are written to so tests can
+// substitute an in-memory implementation
+type objectStore interface {
+	Put(key string, body []byte) error
+}
+
+type objectStoreSink struct {
+	store objectStore
+}
+
+// newObjectStoreSink returns a DriftSink that archives reports to the S3-compatible endpoint and
+// bucket configured through the DRIFT_REPORT_ENDPOINT and DRIFT_REPORT_BUCKET environment
+// variables, or nil when either is unset
+func newObjectStoreSink() DriftSink {
+	endpoint := os.Getenv("DRIFT_REPORT_ENDPOINT")
+	bucket := os.Getenv("DRIFT_REPORT_BUCKET")
+	if endpoint == "" || bucket == "" {
+		return nil
+	}
+	return &objectStoreSink{
+		store: &httpObjectStore{
+			client: &http.Client{
+				Timeout:   30 * time.Second,
+				Transport: &http.Transport{Proxy: sharedProxyRegistry.proxyForRequest}},
+			endpoint: endpoint,
+			bucket:   bucket}}
+}
+
+// Archive writes the report as JSON keyed by pattern and check timestamp, so successive reports
+// for the same pattern sort chronologically within its prefix
+func (s *objectStoreSink) Archive(report DriftReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s/%s/%s.json", report.Namespace, report.Name, report.CheckedAt.UTC().Format(time.RFC3339))
+	return s.store.Put(key, body)
+}
+
+// httpObjectStore uploads objects with plain PUT requests against an S3-compatible endpoint
+type httpObjectStore struct {
+	client           *http.Client
+	endpoint, bucket string
+}
+
+func (s *httpObjectStore) Put(key string, body []byte) error {
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.endpoint, "/"), s.bucket, key), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("report upload for %s returned status %d", key, response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// memoryObjectStore is an in-memory object store standing in for the S3-compatible backend
+type memoryObjectStore struct {
+	mutex   sync.Mutex
+	objects map[string][]byte
+	err     error
+}
+
+func (s *memoryObjectStore) Put(key string, body []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if s.objects == nil {
+		s.objects = map[string][]byte{}
+	}
+	s.objects[key] = body
+	return nil
+}
+
+var _ = Describe("Drift report sink", func() {
+
+	var _ = Context("when archiving reports to object storage", func() {
+		var (
+			store *memoryObjectStore
+			sink  DriftSink
+		)
+
+		BeforeEach(func() {
+			store = &memoryObjectStore{}
+			sink = &objectStoreSink{store: store}
+		})
+
+		It("writes the report keyed by pattern and timestamp", func() {
+			checked := time.Date(2022, time.November, 1, 10, 30, 0, 0, time.UTC)
+			report := DriftReport{
+				Name:          foo,
+				Namespace:     defaultNamespace,
+				Drifted:       true,
+				CorrelationID: "trigger-1",
+				CheckedAt:     checked}
+			Expect(sink.Archive(report)).NotTo(HaveOccurred())
+
+			key := fmt.Sprintf("%s/%s/%s.json", defaultNamespace, foo, checked.Format(time.RFC3339))
+			body, found := store.objects[key]
+			Expect(found).To(BeTrue())
+			var archived DriftReport
+			Expect(json.Unmarshal(body, &archived)).NotTo(HaveOccurred())
+			Expect(archived.Name).To(Equal(foo))
+			Expect(archived.Namespace).To(Equal(defaultNamespace))
+			Expect(archived.Drifted).To(BeTrue())
+			Expect(archived.CorrelationID).To(Equal("trigger-1"))
+			Expect(archived.CheckedAt).To(Equal(checked))
+		})
+
+		It("keeps reports of successive checks for the same pattern", func() {
+			checked := time.Date(2022, time.November, 1, 10, 30, 0, 0, time.UTC)
+			for i := 0; i < 3; i++ {
+				report := DriftReport{Name: foo, Namespace: defaultNamespace, CheckedAt: checked.Add(time.Duration(i) * time.Minute)}
+				Expect(sink.Archive(report)).NotTo(HaveOccurred())
+			}
+			Expect(store.objects).To(HaveLen(3))
+		})
+
+		It("propagates write failures to the caller", func() {
+			store.err = fmt.Errorf("bucket unavailable")
+			report := DriftReport{Name: foo, Namespace: defaultNamespace, CheckedAt: time.Now()}
+			err := sink.Archive(report)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("bucket unavailable"))
+		})
+	})
+
+	var _ = Context("when the check loop archives through the watcher", func() {
+
+		It("accounts failed writes in the metrics output", func() {
+			store := &memoryObjectStore{err: fmt.Errorf("bucket unavailable")}
+			watch := newWatcher(nil)
+			watch.driftSink = &objectStoreSink{store: store}
+			watch.archiveReport(watch.logger, DriftReport{Name: foo, Namespace: defaultNamespace, CheckedAt: time.Now()})
+
+			var buffer bytes.Buffer
+			Expect(watch.writeOpenMetrics(&buffer)).NotTo(HaveOccurred())
+			Expect(buffer.String()).To(ContainSubstring("patterns_operator_drift_report_write_failures_total 1"))
+		})
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+	"github.com/hybrid-cloud-patterns/patterns-operator/version"
+)
+
+// patternReport is the read-only view of one pattern served by the status API, flattening the
+// fields external dashboards consume so they do not have to list and interpret the CRs themselves
+type patternReport struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// Status of the Ready condition, Unknown when the condition has not been written yet
+	Ready string `json:"ready"`
+	// Message of the Ready condition, explaining a False status
+	Message  string `json:"message,omitempty"`
+	LastStep string `json:"lastStep,omitempty"`
+	// Repository and revision the pattern deploys from
+	TargetRepo     string `json:"targetRepo,omitempty"`
+	TargetRevision string `json:"targetRevision,omitempty"`
+	// Outcome of the most recent drift check, absent while drift monitoring is not active
+	Drift *api.DriftStatus `json:"drift,omitempty"`
+	// Sync and health state of the deployed ArgoCD applications
+	Applications []api.PatternApplicationStatus `json:"applications,omitempty"`
+}
+
+// versionReport identifies the operator build serving the API
+type versionReport struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildDate string `json:"buildDate"`
+}
+
+// handlePatterns serves the report of every pattern as JSON, optionally restricted to one
+// pattern through the name and namespace query parameters
+func (r *PatternReconciler) handlePatterns(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "only GET is accepted", http.StatusMethodNotAllowed)
+		return
+	}
+	name := req.URL.Query().Get("name")
+	namespace := req.URL.Query().Get("namespace")
+	if (name == "") != (namespace == "") {
+		http.Error(w, "name and namespace must be given together", http.StatusBadRequest)
+		return
+	}
+	var patterns api.PatternList
+	if err := r.Client.List(req.Context(), &patterns); err != nil {
+		http.Error(w, "unable to list the patterns", http.StatusInternalServerError)
+		return
+	}
+	reports := []patternReport{}
+	for i := range patterns.Items {
+		pattern := &patterns.Items[i]
+		if name != "" && (pattern.Name != name || pattern.Namespace != namespace) {
+			continue
+		}
+		report := patternReport{
+			Name:           pattern.Name,
+			Namespace:      pattern.Namespace,
+			Ready:          "Unknown",
+			LastStep:       pattern.Status.LastStep,
+			TargetRepo:     pattern.Spec.GitConfig.TargetRepo,
+			TargetRevision: pattern.Spec.GitConfig.TargetRevision,
+			Drift:          pattern.Status.DriftStatus,
+			Applications:   pattern.Status.Applications,
+		}
+		if _, condition := getPatternConditionByType(pattern.Status.Conditions, api.Ready); condition != nil {
+			report.Ready = string(condition.Status)
+			report.Message = condition.Message
+		}
+		reports = append(reports, report)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(reports); err != nil {
+		r.logger.Error(err, "failed to serve the pattern reports")
+	}
+}
+
+// handleVersion serves the operator build information as JSON
+func (r *PatternReconciler) handleVersion(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "only GET is accepted", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(versionReport{
+		Version:   version.Version,
+		GitCommit: version.GitCommit,
+		BuildDate: version.BuildDate,
+	}); err != nil {
+		r.logger.Error(err, "failed to serve the version report")
+	}
+}
+
+// serveStatusAPI exposes the read-only pattern reports and the build information on the given
+// address. The server lives for the lifetime of the operator, so shutdown is left to process exit
+func (r *PatternReconciler) serveStatusAPI(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/patterns", http.HandlerFunc(r.handlePatterns))
+	mux.Handle("/version", http.HandlerFunc(r.handleVersion))
+	server := &http.Server{Addr: addr, Handler: mux}
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		r.logger.Error(err, "status API server terminated")
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Status API", func() {
+
+	var reconciler *PatternReconciler
+
+	get := func(target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		reconciler.handlePatterns(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		return recorder
+	}
+
+	BeforeEach(func() {
+		pattern := buildPatternManifest(10)
+		pattern.Status.LastStep = "update application"
+		pattern.Status.DriftStatus = &api.DriftStatus{OriginSHA: "abc", TargetSHA: "def", CommitsBehind: 2}
+		pattern.Status.Applications = []api.PatternApplicationStatus{{Name: "foo-default", SyncStatus: "Synced", HealthStatus: "Healthy"}}
+		pattern.Status.Conditions = []api.PatternCondition{{Type: api.Ready, Status: corev1.ConditionFalse, Message: "application degraded", LastUpdateTime: metav1.Now()}}
+		reconciler = newFakeReconciler(pattern)
+	})
+
+	It("reports the flattened pattern state as JSON", func() {
+		recorder := get("/patterns")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var reports []patternReport
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &reports)).To(Succeed())
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Name).To(Equal(foo))
+		Expect(reports[0].Ready).To(Equal("False"))
+		Expect(reports[0].Message).To(Equal("application degraded"))
+		Expect(reports[0].Drift.CommitsBehind).To(Equal(2))
+		Expect(reports[0].Applications[0].HealthStatus).To(Equal("Healthy"))
+	})
+
+	It("filters on the name and namespace query parameters", func() {
+		Expect(get("/patterns?name=foo&namespace=" + namespace).Body.String()).To(ContainSubstring(foo))
+
+		recorder := get("/patterns?name=other&namespace=" + namespace)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(MatchJSON("[]"))
+
+		Expect(get("/patterns?name=foo").Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("serves the operator build information", func() {
+		recorder := httptest.NewRecorder()
+		reconciler.handleVersion(recorder, httptest.NewRequest(http.MethodGet, "/version", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var report versionReport
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &report)).To(Succeed())
+		Expect(report.Version).NotTo(BeEmpty())
+	})
+})
@@ -55,7 +55,7 @@ func newSubscription(p api.Pattern) *operatorv1alpha1.Subscription {
 	//          value: "*"
 
 	spec := &operatorv1alpha1.SubscriptionSpec{
-		CatalogSource:          "redhat-operators",
+		CatalogSource:          p.Spec.GitOpsConfig.OperatorSource,
 		CatalogSourceNamespace: "openshift-marketplace",
 		Channel:                p.Spec.GitOpsConfig.OperatorChannel,
 		Package:                "openshift-gitops-operator",
@@ -87,6 +87,29 @@ func newSubscription(p api.Pattern) *operatorv1alpha1.Subscription {
 	}
 }
 
+// pendingInstallPlan returns the install plan the subscription is blocked on, or nil when no plan
+// awaits a manual approval
+func pendingInstallPlan(client olmclient.Interface, sub *operatorv1alpha1.Subscription) (*operatorv1alpha1.InstallPlan, error) {
+	if sub.Status.State != operatorv1alpha1.SubscriptionStateUpgradePending || sub.Status.InstallPlanRef == nil {
+		return nil, nil
+	}
+	plan, err := client.OperatorsV1alpha1().InstallPlans(sub.Status.InstallPlanRef.Namespace).Get(context.Background(), sub.Status.InstallPlanRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if plan.Spec.Approval == operatorv1alpha1.ApprovalManual && !plan.Spec.Approved {
+		return plan, nil
+	}
+	return nil, nil
+}
+
+// approveInstallPlan marks the pending plan approved so the upgrade proceeds
+func approveInstallPlan(client olmclient.Interface, plan *operatorv1alpha1.InstallPlan) error {
+	plan.Spec.Approved = true
+	_, err := client.OperatorsV1alpha1().InstallPlans(plan.Namespace).Update(context.Background(), plan, metav1.UpdateOptions{})
+	return err
+}
+
 func getSubscription(client olmclient.Interface, name, namespace string) (error, *operatorv1alpha1.Subscription) {
 
 	sub, err := client.OperatorsV1alpha1().Subscriptions(subscriptionNamespace).Get(context.Background(), name, metav1.GetOptions{})
@@ -0,0 +1,72 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// windowActive reports whether the window is open at the given time, i.e. the last scheduled
+// start lies no longer than the duration in the past
+func windowActive(window api.SyncWindow, now time.Time) (bool, error) {
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil {
+		return false, fmt.Errorf("window duration %q: %s", window.Duration, err.Error())
+	}
+	if duration <= 0 {
+		return false, fmt.Errorf("window duration %q must be positive", window.Duration)
+	}
+	schedule, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return false, fmt.Errorf("window schedule %q: %s", window.Schedule, err.Error())
+	}
+	start := schedule.Next(now.Add(-duration))
+	return !start.After(now), nil
+}
+
+// syncWindowsClosed evaluates the declared maintenance windows at the given time. An active deny
+// window always closes the sync; when allow windows are declared, one of them must be active for
+// the sync to stay open. A pattern without windows is never closed
+func syncWindowsClosed(p *api.Pattern, now time.Time) (bool, error) {
+	if p.Spec.SyncPolicy == nil {
+		return false, nil
+	}
+	hasAllowWindow := false
+	inAllowWindow := false
+	for _, window := range p.Spec.SyncPolicy.Windows {
+		active, err := windowActive(window, now)
+		if err != nil {
+			return false, err
+		}
+		if window.Kind == api.SyncWindowDeny {
+			if active {
+				return true, nil
+			}
+			continue
+		}
+		hasAllowWindow = true
+		if active {
+			inAllowWindow = true
+		}
+	}
+	return hasAllowWindow && !inAllowWindow, nil
+}
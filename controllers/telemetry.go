@@ -0,0 +1,172 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+const (
+	// environment variables the reporting is configured through; no endpoint means no reporting
+	analyticsEndpointEnv = "ANALYTICS_ENDPOINT"
+	analyticsDisabledEnv = "ANALYTICS_DISABLED"
+	// per-pattern opt-out, set as an annotation on the pattern
+	analyticsOptOutAnnotation = "gitops.hybrid-cloud-patterns.io/analytics-opt-out"
+	// events are sent in batches of up to telemetryBatchSize, at most every
+	// telemetryFlushInterval; undelivered events are retried on the next flush, dropping the
+	// oldest ones beyond telemetryQueueCap
+	telemetryBatchSize     = 20
+	telemetryFlushInterval = 5 * time.Minute
+	telemetryQueueCap      = 100
+	// events the operator reports
+	telemetryEventInstalled  = "pattern-installed"
+	telemetryEventUpgraded   = "pattern-upgraded"
+	telemetryEventRolledBack = "pattern-rolled-back"
+)
+
+// telemetryEvent is the Segment-style track payload of one reported event. Nothing in it
+// identifies the cluster or the pattern content: the anonymous id is a hash of the cluster id
+// and the properties carry only the cluster facts and the event name
+type telemetryEvent struct {
+	Event       string            `json:"event"`
+	AnonymousID string            `json:"anonymousId"`
+	Properties  map[string]string `json:"properties"`
+	Timestamp   time.Time         `json:"timestamp"`
+}
+
+// telemetryReporter batches anonymized pattern lifecycle events towards the configured
+// endpoint, so the validated patterns team can measure adoption. A reporter without an endpoint
+// swallows every event, which is how the operator-level opt-out is implemented
+type telemetryReporter struct {
+	endpoint string
+	mutex    sync.Mutex
+	queue    []telemetryEvent
+	// the operator-wide opt-out of the PatternsOperatorConfig singleton, toggled live
+	disabled bool
+	// stubbed in tests to avoid waiting for the flush interval
+	now func() time.Time
+}
+
+// newTelemetryReporter builds a reporter towards the given endpoint and starts its flush loop;
+// an empty endpoint disables reporting entirely
+func newTelemetryReporter(endpoint string) *telemetryReporter {
+	reporter := &telemetryReporter{endpoint: endpoint, now: time.Now}
+	if endpoint != "" {
+		go func() {
+			for range time.Tick(telemetryFlushInterval) {
+				if err := reporter.flush(); err != nil {
+					log.Printf("telemetry flush failed, will retry: %s\n", err.Error())
+				}
+			}
+		}()
+	}
+	return reporter
+}
+
+// setDisabled toggles the operator-wide opt-out without tearing the reporter down
+func (t *telemetryReporter) setDisabled(disabled bool) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	t.disabled = disabled
+	t.mutex.Unlock()
+}
+
+// record queues one event for the pattern unless reporting is disabled operator-wide or the
+// pattern opted out through its annotation. The batch is flushed as soon as it is full
+func (t *telemetryReporter) record(event string, p *api.Pattern) {
+	if t == nil || t.endpoint == "" {
+		return
+	}
+	t.mutex.Lock()
+	disabled := t.disabled
+	t.mutex.Unlock()
+	if disabled {
+		return
+	}
+	if p.Annotations[analyticsOptOutAnnotation] == "true" {
+		return
+	}
+	queued := telemetryEvent{
+		Event:       event,
+		AnonymousID: fmt.Sprintf("%x", sha256.Sum256([]byte(p.Status.ClusterID))),
+		Properties: map[string]string{
+			"platform":     p.Status.ClusterPlatform,
+			"version":      p.Status.ClusterVersion,
+			"architecture": p.Status.ClusterArchitecture,
+		},
+		Timestamp: t.now(),
+	}
+
+	t.mutex.Lock()
+	t.queue = append(t.queue, queued)
+	if len(t.queue) > telemetryQueueCap {
+		t.queue = t.queue[len(t.queue)-telemetryQueueCap:]
+	}
+	full := len(t.queue) >= telemetryBatchSize
+	t.mutex.Unlock()
+
+	if full {
+		if err := t.flush(); err != nil {
+			log.Printf("telemetry flush failed, will retry: %s\n", err.Error())
+		}
+	}
+}
+
+// flush sends the queued events as one batch; on failure the queue is kept for the next attempt
+func (t *telemetryReporter) flush() error {
+	t.mutex.Lock()
+	pending := t.queue
+	t.queue = nil
+	t.mutex.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"batch": pending})
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(t.endpoint, "application/json", bytes.NewReader(body))
+	if err == nil {
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+			return nil
+		}
+		err = fmt.Errorf("telemetry endpoint returned status %d", resp.StatusCode)
+	}
+
+	// put the batch back in front of anything recorded meanwhile, dropping the oldest events
+	// beyond the cap like record does
+	t.mutex.Lock()
+	t.queue = append(pending, t.queue...)
+	if len(t.queue) > telemetryQueueCap {
+		t.queue = t.queue[len(t.queue)-telemetryQueueCap:]
+	}
+	t.mutex.Unlock()
+	return err
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+)
+
+// tlsSettings is the TLS trust configuration applied to connections to a git host
+type tlsSettings struct {
+	// PEM-encoded CA certificates trusted in addition to the system roots
+	caPEM []byte
+	// skip certificate validation entirely, for lab environments without a usable CA
+	insecureSkipVerify bool
+}
+
+func (s tlsSettings) empty() bool {
+	return len(s.caPEM) == 0 && !s.insecureSkipVerify
+}
+
+// tlsRegistry selects the TLS configuration for connections to a host, combining the
+// operator-level CA bundle read from the file named by the DRIFT_CA_BUNDLE environment variable
+// (typically a mounted ConfigMap) with the bundles patterns declare through caBundleSecret.
+// Per-host entries are refreshed from the pattern spec on every drift check
+type tlsRegistry struct {
+	mutex       sync.Mutex
+	operatorPEM []byte
+	hosts       map[string]tlsSettings
+}
+
+// sharedTLSRegistry backs the dialer of the http transports installed for the whole process, for
+// the same reason the proxy registry is shared: go-git keeps one transport per protocol
+var sharedTLSRegistry = newTLSRegistry()
+
+func newTLSRegistry() *tlsRegistry {
+	registry := &tlsRegistry{hosts: map[string]tlsSettings{}}
+	if bundle := os.Getenv("DRIFT_CA_BUNDLE"); bundle != "" {
+		if pem, err := os.ReadFile(bundle); err == nil {
+			registry.operatorPEM = pem
+		}
+	}
+	return registry
+}
+
+// setHostTLS registers the settings applied to connections to the host, replacing any previous
+// registration
+func (t *tlsRegistry) setHostTLS(host string, settings tlsSettings) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.hosts[host] = settings
+}
+
+// clearHostTLS drops the per-host registration, falling back to the operator-level bundle
+func (t *tlsRegistry) clearHostTLS(host string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.hosts, host)
+}
+
+// clientConfig builds the TLS configuration for a connection to the host. The trusted roots are
+// the system pool extended with the operator-level bundle and any bundle registered for the host
+func (t *tlsRegistry) clientConfig(host string) (*tls.Config, error) {
+	t.mutex.Lock()
+	operatorPEM := t.operatorPEM
+	settings := t.hosts[host]
+	t.mutex.Unlock()
+	if settings.insecureSkipVerify {
+		// the host opted out of validation; trust settings are irrelevant
+		return &tls.Config{ServerName: host, InsecureSkipVerify: true}, nil
+	}
+	config := &tls.Config{ServerName: host}
+	if len(operatorPEM) == 0 && settings.empty() {
+		return config, nil
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if len(operatorPEM) > 0 && !roots.AppendCertsFromPEM(operatorPEM) {
+		return nil, fmt.Errorf("no certificate could be parsed from the operator CA bundle")
+	}
+	if len(settings.caPEM) > 0 && !roots.AppendCertsFromPEM(settings.caPEM) {
+		return nil, fmt.Errorf("no certificate could be parsed from the CA bundle registered for host %s", host)
+	}
+	config.RootCAs = roots
+	return config, nil
+}
+
+// dialTLS wraps a plain dialer into the DialTLSContext callback of an http transport, so each
+// handshake picks up the trust settings currently registered for the host being dialed
+func (t *tlsRegistry) dialTLS(dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		config, err := t.clientConfig(host)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+}
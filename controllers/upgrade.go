@@ -0,0 +1,291 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	argoapi "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+const (
+	// defaultUpgradeTimeoutSeconds bounds how long a staged rollout waits for a degraded
+	// application to recover before rolling the revision back
+	defaultUpgradeTimeoutSeconds = 600
+	// upgradeRecheckInterval paces the reconciles driving a rollout forward
+	upgradeRecheckInterval = 30 * time.Second
+	// eventReasonRevisionRolledBack is emitted when applications are reverted to the last
+	// known-good revision
+	eventReasonRevisionRolledBack = "RevisionRolledBack"
+)
+
+// applicationWave reads the sync-wave annotation of an application; applications without one
+// roll out in wave 0, matching the argo default
+func applicationWave(app *argoapi.Application) int {
+	wave, err := strconv.Atoi(app.Annotations[syncWaveAnnotation])
+	if err != nil {
+		return 0
+	}
+	return wave
+}
+
+func upgradeTimeout(p *api.Pattern) time.Duration {
+	if p.Spec.GitConfig.UpgradeTimeoutSeconds > 0 {
+		return time.Duration(p.Spec.GitConfig.UpgradeTimeoutSeconds) * time.Second
+	}
+	return defaultUpgradeTimeoutSeconds * time.Second
+}
+
+func rollbackDeadline(p *api.Pattern) time.Duration {
+	if p.Spec.GitConfig.RollbackDeadlineSeconds > 0 {
+		return time.Duration(p.Spec.GitConfig.RollbackDeadlineSeconds) * time.Second
+	}
+	return defaultUpgradeTimeoutSeconds * time.Second
+}
+
+// orchestrateUpgrade notices a changed target revision and stages the rollout over the
+// applications of the pattern instead of letting the regular reconcile flip them all at once.
+// It reports whether a rollout is in progress, in which case the caller holds off the regular
+// application updates and requeues
+func (r *PatternReconciler) orchestrateUpgrade(p *api.Pattern) (bool, error) {
+	target := p.Spec.GitConfig.TargetRevision
+	if p.Status.AppliedRevision == "" {
+		// first deployment, nothing to stage the rollout over
+		p.Status.AppliedRevision = target
+		r.telemetry.record(telemetryEventInstalled, p)
+		return false, r.Client.Status().Update(context.TODO(), p)
+	}
+	if p.Status.Upgrade == nil && target == p.Status.AppliedRevision {
+		return false, nil
+	}
+	err, apps := listPatternApplications(r.argoClient, applicationName(*p))
+	if err != nil {
+		return true, err
+	}
+	return r.advanceUpgrade(p, apps, r.setApplicationRevision)
+}
+
+// setApplicationRevision moves one application to the given revision through the argo API
+func (r *PatternReconciler) setApplicationRevision(app *argoapi.Application, revision string) error {
+	app.Spec.Source.TargetRevision = revision
+	_, err := r.argoClient.ArgoprojV1alpha1().Applications(applicationNamespace).Update(context.Background(), app, metav1.UpdateOptions{})
+	return err
+}
+
+// advanceUpgrade performs one step of the staged rollout: the applications of the current sync
+// wave are moved to the new revision, later waves follow once every earlier wave reports
+// healthy and synced again, and an application staying degraded past the upgrade timeout rolls
+// the revision field back to where the rollout started
+func (r *PatternReconciler) advanceUpgrade(p *api.Pattern, apps []*argoapi.Application, setRevision func(*argoapi.Application, string) error) (bool, error) {
+	if p.Status.Upgrade == nil {
+		p.Status.Upgrade = &api.PatternUpgradeStatus{
+			FromRevision: p.Status.AppliedRevision,
+			ToRevision:   p.Spec.GitConfig.TargetRevision,
+			StartTime:    metav1.Time{Time: time.Now()},
+		}
+		setControllerCondition(p, api.PatternUpgrading, corev1.ConditionTrue,
+			fmt.Sprintf("rolling out revision %s over revision %s", p.Status.Upgrade.ToRevision, p.Status.Upgrade.FromRevision),
+			metav1.Time{Time: time.Now()})
+		log.Printf("staged rollout of revision %s started from revision %s\n", p.Status.Upgrade.ToRevision, p.Status.Upgrade.FromRevision)
+	}
+	upgrade := p.Status.Upgrade
+
+	waves := map[int][]*argoapi.Application{}
+	var order []int
+	for _, app := range apps {
+		wave := applicationWave(app)
+		if _, seen := waves[wave]; !seen {
+			order = append(order, wave)
+		}
+		waves[wave] = append(waves[wave], app)
+	}
+	sort.Ints(order)
+
+	for _, wave := range order {
+		moved := false
+		for _, app := range waves[wave] {
+			if app.Spec.Source.TargetRevision == upgrade.ToRevision {
+				continue
+			}
+			logUpgradeDiff(upgrade, app, wave)
+			if err := setRevision(app, upgrade.ToRevision); err != nil {
+				return true, err
+			}
+			moved = true
+		}
+		if moved {
+			upgrade.Wave = wave
+			return true, r.Client.Status().Update(context.TODO(), p)
+		}
+		for _, app := range waves[wave] {
+			if app.Status.Health.Status == health.HealthStatusDegraded {
+				if time.Since(upgrade.StartTime.Time) > upgradeTimeout(p) {
+					return true, r.rollbackUpgrade(p, apps, app.Name, setRevision)
+				}
+				return true, nil
+			}
+			if app.Status.Health.Status != health.HealthStatusHealthy || app.Status.Sync.Status != argoapi.SyncStatusCodeSynced {
+				// the wave is still syncing; later waves wait for it
+				return true, nil
+			}
+		}
+	}
+
+	// every wave is on the new revision and healthy: the rollout is complete
+	p.Status.AppliedRevision = upgrade.ToRevision
+	p.Status.Upgrade = nil
+	setControllerCondition(p, api.PatternUpgrading, corev1.ConditionFalse,
+		fmt.Sprintf("revision %s rolled out", p.Status.AppliedRevision), metav1.Time{Time: time.Now()})
+	log.Printf("staged rollout of revision %s complete\n", p.Status.AppliedRevision)
+	r.telemetry.record(telemetryEventUpgraded, p)
+	return false, r.Client.Status().Update(context.TODO(), p)
+}
+
+// logUpgradeDiff renders the source change the rollout is about to apply to one application,
+// reusing the line diff of the preview mode
+func logUpgradeDiff(upgrade *api.PatternUpgradeStatus, app *argoapi.Application, wave int) {
+	goal := app.Spec.Source.DeepCopy()
+	goal.TargetRevision = upgrade.ToRevision
+	applied, _ := yaml.Marshal(app.Spec.Source)
+	target, _ := yaml.Marshal(goal)
+	log.Printf("staged rollout of revision %s: updating application %s (wave %d)\n%s",
+		upgrade.ToRevision, app.Name, wave, sourceDiff(string(applied), string(target)))
+}
+
+// rollbackUpgrade reverts the revision field of the pattern and the applications already moved,
+// recording the outcome on the UpgradeRolledBack condition
+func (r *PatternReconciler) rollbackUpgrade(p *api.Pattern, apps []*argoapi.Application, degraded string, setRevision func(*argoapi.Application, string) error) error {
+	upgrade := p.Status.Upgrade
+	log.Printf("application %s stayed degraded past the upgrade timeout, rolling revision %s back to %s\n",
+		degraded, upgrade.ToRevision, upgrade.FromRevision)
+	for _, app := range apps {
+		if app.Spec.Source.TargetRevision == upgrade.ToRevision {
+			if err := setRevision(app, upgrade.FromRevision); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := r.revertRevisionField(p, upgrade.FromRevision); err != nil {
+		return err
+	}
+
+	timestamp := metav1.Time{Time: time.Now()}
+	setControllerCondition(p, api.UpgradeRolledBack, corev1.ConditionTrue,
+		fmt.Sprintf("application %s stayed degraded past the upgrade timeout; revision rolled back from %s to %s",
+			degraded, upgrade.ToRevision, upgrade.FromRevision), timestamp)
+	setControllerCondition(p, api.PatternUpgrading, corev1.ConditionFalse, "the rollout was rolled back", timestamp)
+	p.Status.Upgrade = nil
+	r.telemetry.record(telemetryEventRolledBack, p)
+	return r.Client.Status().Update(context.TODO(), p)
+}
+
+// revertRevisionField moves the revision field back on the live pattern, not on the defaulted
+// working copy, so the next reconcile does not immediately re-apply the reverted revision
+func (r *PatternReconciler) revertRevisionField(p *api.Pattern, revision string) error {
+	var current api.Pattern
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: p.Name, Namespace: p.Namespace}, &current); err != nil {
+		return err
+	}
+	current.Spec.GitConfig.TargetRevision = revision
+	if err := r.Client.Update(context.TODO(), &current); err != nil {
+		return err
+	}
+	p.Spec.GitConfig.TargetRevision = revision
+	return nil
+}
+
+// guardKnownGoodRevision tracks the last revision every application of the pattern reported
+// healthy and synced on. When a newly applied revision leaves applications Degraded or OutOfSync
+// past the rollback deadline, the applications are reverted to that revision, the revision field
+// follows and an event explains the rollback. Staged rollouts handle their own recovery
+func (r *PatternReconciler) guardKnownGoodRevision(p *api.Pattern, apps []*argoapi.Application, setRevision func(*argoapi.Application, string) error) error {
+	if len(apps) == 0 || p.Status.Upgrade != nil {
+		return nil
+	}
+	unhealthy := ""
+	settled := true
+	for _, app := range apps {
+		if app == nil {
+			continue
+		}
+		if app.Status.Health.Status == health.HealthStatusDegraded || app.Status.Sync.Status == argoapi.SyncStatusCodeOutOfSync {
+			unhealthy = app.Name
+		}
+		if app.Status.Health.Status != health.HealthStatusHealthy || app.Status.Sync.Status != argoapi.SyncStatusCodeSynced {
+			settled = false
+		}
+	}
+	if settled {
+		if p.Status.LastKnownGoodRevision == p.Status.AppliedRevision && p.Status.UnhealthySince == nil {
+			return nil
+		}
+		p.Status.LastKnownGoodRevision = p.Status.AppliedRevision
+		p.Status.UnhealthySince = nil
+		return r.Client.Status().Update(context.TODO(), p)
+	}
+	if unhealthy == "" {
+		// still progressing; neither known-good nor counting towards the deadline
+		return nil
+	}
+	lastGood := p.Status.LastKnownGoodRevision
+	if lastGood == "" || lastGood == p.Status.AppliedRevision {
+		// nothing safe to fall back to
+		return nil
+	}
+	if p.Status.UnhealthySince == nil {
+		p.Status.UnhealthySince = &metav1.Time{Time: time.Now()}
+		return r.Client.Status().Update(context.TODO(), p)
+	}
+	if time.Since(p.Status.UnhealthySince.Time) < rollbackDeadline(p) {
+		return nil
+	}
+
+	log.Printf("application %s stayed unhealthy past the rollback deadline on revision %s, reverting to last known-good revision %s\n",
+		unhealthy, p.Status.AppliedRevision, lastGood)
+	for _, app := range apps {
+		if app != nil && app.Spec.Source.TargetRevision != lastGood {
+			if err := setRevision(app, lastGood); err != nil {
+				return err
+			}
+		}
+	}
+	if err := r.revertRevisionField(p, lastGood); err != nil {
+		return err
+	}
+	if r.recorder != nil {
+		r.recorder.Event(p, corev1.EventTypeWarning, eventReasonRevisionRolledBack,
+			fmt.Sprintf("application %s stayed Degraded or OutOfSync for more than %s on revision %s; applications reverted to last known-good revision %s",
+				unhealthy, rollbackDeadline(p), p.Status.AppliedRevision, lastGood))
+	}
+	p.Status.AppliedRevision = lastGood
+	p.Status.UnhealthySince = nil
+	r.telemetry.record(telemetryEventRolledBack, p)
+	return r.Client.Status().Update(context.TODO(), p)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// maxWebhookPayloadBytes bounds the size of an accepted push event payload
+const maxWebhookPayloadBytes = 1 << 20
+
+// webhookRepository carries the repository URLs a push event may spell out. The field set covers
+// the GitHub, GitLab and Gitea payload formats, which disagree on naming but all identify the
+// repository by at least one URL
+type webhookRepository struct {
+	CloneURL   string `json:"clone_url"`
+	HTMLURL    string `json:"html_url"`
+	SSHURL     string `json:"ssh_url"`
+	GitHTTPURL string `json:"git_http_url"`
+	GitSSHURL  string `json:"git_ssh_url"`
+	WebURL     string `json:"web_url"`
+	URL        string `json:"url"`
+}
+
+// webhookPayload is the subset of a push event needed to identify the pushed repository. GitHub
+// and Gitea nest it under "repository", GitLab under "project"
+type webhookPayload struct {
+	Repository *webhookRepository `json:"repository"`
+	Project    *webhookRepository `json:"project"`
+}
+
+// repositoryURLs collects every repository URL the payload spells out
+func (p *webhookPayload) repositoryURLs() []string {
+	urls := []string{}
+	for _, repository := range []*webhookRepository{p.Repository, p.Project} {
+		if repository == nil {
+			continue
+		}
+		for _, candidate := range []string{repository.CloneURL, repository.HTMLURL, repository.SSHURL,
+			repository.GitHTTPURL, repository.GitSSHURL, repository.WebURL, repository.URL} {
+			if candidate != "" {
+				urls = append(urls, candidate)
+			}
+		}
+	}
+	return urls
+}
+
+// normalizeRepositoryURL reduces a repository URL to a comparable host/path form, accepting the
+// http, https, git and scp-like ssh spellings and ignoring a trailing ".git"
+func normalizeRepositoryURL(raw string) string {
+	trimmed := strings.TrimSuffix(strings.TrimSuffix(raw, "/"), ".git")
+	if at := strings.Index(trimmed, "@"); at != -1 && !strings.Contains(trimmed, "://") {
+		// scp-like spelling, e.g. git@host:org/repo
+		return strings.ToLower(strings.Replace(trimmed[at+1:], ":", "/", 1))
+	}
+	if parsed, err := url.Parse(trimmed); err == nil && parsed.Host != "" {
+		return strings.ToLower(parsed.Host + parsed.Path)
+	}
+	return strings.ToLower(trimmed)
+}
+
+// repositoryMatches reports whether any of the pushed repository URLs identifies the repository
+func repositoryMatches(pushed []string, repository string) bool {
+	if repository == "" {
+		return false
+	}
+	normalized := normalizeRepositoryURL(repository)
+	for _, candidate := range pushed {
+		if normalizeRepositoryURL(candidate) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNowByRepository reschedules an immediate check for every watched pattern whose origin or
+// target repository is among the pushed URLs, returning how many pairs were triggered
+func (d *watcher) checkNowByRepository(urls []string) (int, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var patterns api.PatternList
+	if err := d.kClient.List(ctx, &patterns); err != nil {
+		return 0, err
+	}
+	matched := 0
+	for i := range patterns.Items {
+		pattern := &patterns.Items[i]
+		if !repositoryMatches(urls, pattern.Spec.GitConfig.OriginRepo) && !repositoryMatches(urls, pattern.Spec.GitConfig.TargetRepo) {
+			continue
+		}
+		if !d.isWatching(pattern.Name, pattern.Namespace) {
+			continue
+		}
+		if err := d.checkNow(pattern.Name, pattern.Namespace, ""); err != nil {
+			return matched, err
+		}
+		matched++
+	}
+	return matched, nil
+}
+
+// handleWebhook accepts push event payloads in the GitHub, GitLab and Gitea formats and
+// reschedules an immediate drift check for every watched pattern referencing the pushed
+// repository, bypassing the polling interval
+func (d *watcher) handleWebhook(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "only POST is accepted", http.StatusMethodNotAllowed)
+		return
+	}
+	var payload webhookPayload
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxWebhookPayloadBytes)).Decode(&payload); err != nil {
+		http.Error(w, fmt.Sprintf("unable to decode the push event payload: %s", err), http.StatusBadRequest)
+		return
+	}
+	urls := payload.repositoryURLs()
+	if len(urls) == 0 {
+		http.Error(w, "the payload does not identify a repository", http.StatusBadRequest)
+		return
+	}
+	matched, err := d.checkNowByRepository(urls)
+	if err != nil {
+		d.logger.Error(err, "failed to trigger checks for a push event")
+		http.Error(w, "unable to trigger the drift checks", http.StatusInternalServerError)
+		return
+	}
+	d.logger.V(1).Info(fmt.Sprintf("push event for %s triggered %d drift checks", urls[0], matched))
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, "triggered %d drift checks\n", matched)
+}
+
+// handleHistory serves the bounded in-memory history of check outcomes as JSON, optionally
+// restricted to one pattern through the name and namespace query parameters, so operators can
+// answer when two repositories actually diverged without trawling logs
+func (d *watcher) handleHistory(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "only GET is accepted", http.StatusMethodNotAllowed)
+		return
+	}
+	name := req.URL.Query().Get("name")
+	namespace := req.URL.Query().Get("namespace")
+	if (name == "") != (namespace == "") {
+		http.Error(w, "name and namespace must be given together", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(d.historySnapshot(name, namespace)); err != nil {
+		d.logger.Error(err, "failed to serve the check history")
+	}
+}
+
+// serveWebhook exposes the push event receiver and the check history on the given address. The
+// server lives for the lifetime of the operator, so shutdown is left to process exit
+func (d *watcher) serveWebhook(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", http.HandlerFunc(d.handleWebhook))
+	mux.Handle("/history", http.HandlerFunc(d.handleHistory))
+	server := &http.Server{Addr: addr, Handler: mux}
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		d.logger.Error(err, "webhook receiver terminated")
+	}
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/config"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Drift webhook receiver", func() {
+
+	var _ = Context("when normalizing repository URLs", func() {
+		DescribeTable("reduces the spellings of a repository to the same form", func(raw, expected string) {
+			Expect(normalizeRepositoryURL(raw)).To(Equal(expected))
+		},
+			Entry("https URL", "https://github.com/org/repo", "github.com/org/repo"),
+			Entry("https URL with .git suffix", "https://github.com/org/repo.git", "github.com/org/repo"),
+			Entry("https URL with trailing slash", "https://github.com/org/repo/", "github.com/org/repo"),
+			Entry("scp-like ssh spelling", "git@github.com:org/repo.git", "github.com/org/repo"),
+			Entry("ssh URL with user", "ssh://git@github.com/org/repo.git", "github.com/org/repo"),
+			Entry("mixed case host", "https://GitHub.com/Org/Repo", "github.com/org/repo"),
+		)
+	})
+
+	var _ = Context("when receiving push events", func() {
+		var (
+			patternFoo       *api.Pattern
+			mockGitClient    *MockClient
+			mockRemoteOrigin *MockRemoteClient
+			mockRemoteTarget *MockRemoteClient
+			watch            *watcher
+		)
+
+		post := func(body string) *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+			watch.handleWebhook(recorder, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body)))
+			return recorder
+		}
+
+		BeforeEach(func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockGitClient = NewMockClient(ctrl)
+			mockRemoteOrigin = NewMockRemoteClient(ctrl)
+			mockRemoteTarget = NewMockRemoteClient(ctrl)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "origin", URLs: []string{originURL}}).AnyTimes().Return(mockRemoteOrigin)
+			mockGitClient.EXPECT().NewRemoteClient(&config.RemoteConfig{Name: "target", URLs: []string{targetURL}}).AnyTimes().Return(mockRemoteTarget)
+			mockRemoteOrigin.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			mockRemoteTarget.EXPECT().ListContext(gomock.Any(), gomock.Any()).AnyTimes().Return(firstCommitReference, nil)
+			patternFoo = &api.Pattern{
+				ObjectMeta: v1.ObjectMeta{Name: foo, Namespace: defaultNamespace},
+				TypeMeta:   v1.TypeMeta{Kind: "Pattern", APIVersion: api.GroupVersion.String()},
+				Spec:       api.PatternSpec{GitConfig: api.GitConfig{OriginRepo: originURL, TargetRepo: targetURL}}}
+			e := k8sClient.Create(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+			watch = newWatcher(mockGitClient)
+			watch.watch()
+			// a long interval ensures only the webhook can trigger a check within the test
+			e = watch.add(foo, defaultNamespace, 3600, false, "")
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			if watch.isWatching(foo, defaultNamespace) {
+				e := watch.remove(foo, defaultNamespace)
+				Expect(e).NotTo(HaveOccurred())
+			}
+			e := k8sClient.Delete(context.Background(), patternFoo)
+			Expect(e).NotTo(HaveOccurred())
+		})
+
+		checkCount := func() int64 {
+			for _, pair := range watch.snapshotPairs() {
+				if pair.name == foo && pair.namespace == defaultNamespace {
+					return pair.checkCount
+				}
+			}
+			return 0
+		}
+
+		It("triggers an immediate check for a GitHub push to the origin repository", func() {
+			recorder := post(`{"repository": {"clone_url": "` + originURL + `.git"}}`)
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
+			Expect(recorder.Body.String()).To(ContainSubstring("triggered 1 drift checks"))
+			Eventually(checkCount).WithPolling(100*time.Millisecond).WithTimeout(10*time.Second).Should(BeNumerically(">", 0),
+				"expected the push event to bypass the polling interval")
+		})
+
+		It("triggers an immediate check for a GitLab push to the target repository", func() {
+			recorder := post(`{"project": {"git_http_url": "` + targetURL + `"}}`)
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
+			Expect(recorder.Body.String()).To(ContainSubstring("triggered 1 drift checks"))
+			Eventually(checkCount).WithPolling(100*time.Millisecond).WithTimeout(10*time.Second).Should(BeNumerically(">", 0),
+				"expected the push event to bypass the polling interval")
+		})
+
+		It("accepts a push to a repository no pattern references without triggering checks", func() {
+			recorder := post(`{"repository": {"clone_url": "https://github.com/org/unrelated.git"}}`)
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
+			Expect(recorder.Body.String()).To(ContainSubstring("triggered 0 drift checks"))
+			Expect(checkCount()).To(BeZero())
+		})
+
+		It("rejects a payload that does not identify a repository", func() {
+			Expect(post(`{"pusher": {"name": "someone"}}`).Code).To(Equal(http.StatusBadRequest))
+			Expect(post(`not json`).Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("rejects methods other than POST", func() {
+			recorder := httptest.NewRecorder()
+			watch.handleWebhook(recorder, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+
+	var _ = Context("when serving the check history", func() {
+		var watch *watcher
+
+		record := func(name, pairName string, checkedAt time.Time, drifted bool, checkErr error) {
+			pair := &repositoryPair{name: name, namespace: defaultNamespace, pairName: pairName, lastCheck: checkedAt, drifted: drifted}
+			watch.mutex.Lock()
+			watch.appendHistory(pair, "", checkErr)
+			watch.mutex.Unlock()
+		}
+
+		get := func(target string) (*httptest.ResponseRecorder, []checkRecord) {
+			recorder := httptest.NewRecorder()
+			watch.handleHistory(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+			records := []checkRecord{}
+			if recorder.Code == http.StatusOK {
+				Expect(json.NewDecoder(recorder.Body).Decode(&records)).NotTo(HaveOccurred())
+			}
+			return recorder, records
+		}
+
+		BeforeEach(func() {
+			watch = newWatcher(nil)
+		})
+
+		It("returns the recorded outcomes of a pattern oldest first", func() {
+			now := time.Now().Truncate(time.Second)
+			record(foo, "", now.Add(-2*time.Minute), false, nil)
+			record(foo, "", now.Add(-time.Minute), true, nil)
+			record(bar, "", now, false, errors.New("origin unreachable"))
+
+			recorder, records := get("/history?name=" + foo + "&namespace=" + defaultNamespace)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(records).To(HaveLen(2))
+			Expect(records[0].CheckedAt.Before(records[1].CheckedAt)).To(BeTrue())
+			Expect(records[0].Drifted).To(BeFalse())
+			Expect(records[1].Drifted).To(BeTrue())
+
+			_, records = get("/history")
+			Expect(records).To(HaveLen(3))
+			Expect(records[2].Error).To(ContainSubstring("origin unreachable"))
+		})
+
+		It("drops the oldest entries beyond the configured limit", func() {
+			watch.historyLimit = 3
+			now := time.Now().Truncate(time.Second)
+			for i := 0; i < 5; i++ {
+				record(foo, "", now.Add(time.Duration(i)*time.Second), false, nil)
+			}
+			_, records := get("/history")
+			Expect(records).To(HaveLen(3))
+			Expect(records[0].CheckedAt).To(BeTemporally("==", now.Add(2*time.Second)))
+		})
+
+		It("rejects a request naming only one of name and namespace", func() {
+			recorder, _ := get("/history?name=" + foo)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("rejects methods other than GET", func() {
+			recorder := httptest.NewRecorder()
+			watch.handleHistory(recorder, httptest.NewRequest(http.MethodPost, "/history", nil))
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+})
@@ -26,6 +26,9 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	argoapi "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -34,6 +37,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	gitopsv1alpha1 "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+	gitopsv1beta1 "github.com/hybrid-cloud-patterns/patterns-operator/api/v1beta1"
 	"github.com/hybrid-cloud-patterns/patterns-operator/controllers"
 	"github.com/hybrid-cloud-patterns/patterns-operator/version"
 	//+kubebuilder:scaffold:imports
@@ -47,7 +51,9 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
+	utilruntime.Must(argoapi.AddToScheme(scheme))
 	utilruntime.Must(gitopsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(gitopsv1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -60,8 +66,12 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	// the level is shared with the pattern reconciler, so the runtime configuration ConfigMap
+	// can change the verbosity without a restart; development mode logs at debug by default
+	logLevel := uberzap.NewAtomicLevelAt(zapcore.DebugLevel)
 	opts := zap.Options{
 		Development: true,
+		Level:       &logLevel,
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
@@ -84,23 +94,53 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controllers.PatternReconciler{
+	patternReconciler := &controllers.PatternReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		LogLevel: &logLevel,
+	}
+	if err = patternReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Pattern")
+		os.Exit(1)
+	}
+	if err = (&controllers.GitDriftCheckReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr, patternReconciler.DriftWatcher()); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GitDriftCheck")
+		os.Exit(1)
+	}
+	if err = (&controllers.PatternCatalogReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Pattern")
+		setupLog.Error(err, "unable to create controller", "controller", "PatternCatalog")
 		os.Exit(1)
 	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&gitopsv1alpha1.Pattern{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pattern")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	if err := mgr.AddHealthzCheck("drift-watcher", patternReconciler.DriftWatcherHealthz()); err != nil {
+		setupLog.Error(err, "unable to set up drift watcher health check")
+		os.Exit(1)
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("drift-watcher", patternReconciler.DriftWatcherHealthz()); err != nil {
+		setupLog.Error(err, "unable to set up drift watcher ready check")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {